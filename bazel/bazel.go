@@ -0,0 +1,177 @@
+// Package bazel builds Go applications with a Bazel target that uses rules_go,
+// keeping the Bazel output base in a cache layer, and finds the binaries the
+// target produced.
+package bazel
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+const (
+	// TargetEnv names the Bazel target that builds the application, such as
+	// //cmd/server. Nothing is detected without it.
+	TargetEnv = "BP_GO_BAZEL_TARGET"
+
+	// BuildFlagsEnv holds additional flags for bazel build, such as
+	// --config=release.
+	BuildFlagsEnv = "BP_GO_BAZEL_BUILD_FLAGS"
+)
+
+var (
+	moduleDep     = regexp.MustCompile(`bazel_dep\(\s*name\s*=\s*"rules_go"`)
+	workspaceRepo = regexp.MustCompile(`name\s*=\s*"(io_bazel_rules_go|rules_go)"`)
+)
+
+// Plan is a target to build an application with.
+type Plan struct {
+	// File is the MODULE.bazel or WORKSPACE file that brings in rules_go.
+	File string
+
+	// Bzlmod is true when rules_go is a bazel_dep of MODULE.bazel.
+	Bzlmod bool
+
+	Target string
+}
+
+// Detect looks for a MODULE.bazel with a rules_go bazel_dep, or a WORKSPACE
+// that declares a rules_go repository, in dir.
+func Detect(dir, target string) (Plan, bool, error) {
+	if target == "" {
+		return Plan{}, false, nil
+	}
+
+	for _, candidate := range []struct {
+		file    string
+		bzlmod  bool
+		pattern *regexp.Regexp
+	}{
+		{"MODULE.bazel", true, moduleDep},
+		{"WORKSPACE.bazel", false, workspaceRepo},
+		{"WORKSPACE", false, workspaceRepo},
+	} {
+		content, err := os.ReadFile(filepath.Join(dir, candidate.file))
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			return Plan{}, false, err
+		}
+
+		if candidate.pattern.Match(content) {
+			return Plan{File: candidate.file, Bzlmod: candidate.bzlmod, Target: target}, true, nil
+		}
+	}
+
+	return Plan{}, false, nil
+}
+
+// Bazel runs bazel commands with an output base that outlives the build.
+type Bazel struct {
+	// Executable is the bazel or bazelisk executable.
+	Executable string
+
+	// OutputBase holds everything Bazel caches between builds, including the
+	// external repositories and the action cache. It should be in a cache
+	// layer.
+	OutputBase string
+
+	// Path holds directories that are put in front of PATH, such as the bin
+	// directory of the Go distribution.
+	Path []string
+
+	// Env holds additional variables, as KEY=VALUE, such as BAZELISK_HOME.
+	Env []string
+
+	Stdout io.Writer
+	Stderr io.Writer
+}
+
+// Build runs bazel build for the target of a plan.
+func (b Bazel) Build(dir string, plan Plan, flags []string) error {
+	args := append(append([]string{"build"}, flags...), plan.Target)
+	err := b.run(dir, b.Stdout, args...)
+	if err != nil {
+		return fmt.Errorf("failed to run bazel build %s: %w", plan.Target, err)
+	}
+
+	return nil
+}
+
+// Outputs returns the executable files the target of a plan produced, relative
+// to the returned execution root. The flags must match the ones given to Build
+// so that the same configuration is queried.
+func (b Bazel) Outputs(dir string, plan Plan, flags []string) ([]string, string, error) {
+	var info bytes.Buffer
+	err := b.run(dir, &info, append(append([]string{"info"}, flags...), "execution_root")...)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to run bazel info: %w", err)
+	}
+	execRoot := strings.TrimSpace(info.String())
+
+	var files bytes.Buffer
+	err = b.run(dir, &files, append(append([]string{"cquery", "--output=files"}, flags...), plan.Target)...)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to run bazel cquery %s: %w", plan.Target, err)
+	}
+
+	var outputs []string
+	for _, file := range strings.Split(strings.TrimSpace(files.String()), "\n") {
+		if file == "" {
+			continue
+		}
+
+		info, err := os.Stat(filepath.Join(execRoot, file))
+		if err != nil {
+			return nil, "", err
+		}
+
+		if info.Mode().IsRegular() && info.Mode()&0111 != 0 {
+			outputs = append(outputs, file)
+		}
+	}
+
+	if len(outputs) == 0 {
+		return nil, "", fmt.Errorf("target %s produced no executable", plan.Target)
+	}
+
+	return outputs, execRoot, nil
+}
+
+// Shutdown stops the Bazel server of the output base, which would otherwise
+// keep running after the build.
+func (b Bazel) Shutdown(dir string) error {
+	err := b.run(dir, b.Stdout, "shutdown")
+	if err != nil {
+		return fmt.Errorf("failed to run bazel shutdown: %w", err)
+	}
+
+	return nil
+}
+
+func (b Bazel) run(dir string, stdout io.Writer, args ...string) error {
+	environ := os.Environ()
+	for i, variable := range environ {
+		if strings.HasPrefix(variable, "PATH=") {
+			environ = append(environ[:i:i], environ[i+1:]...)
+			break
+		}
+	}
+	environ = append(environ, "PATH="+strings.Join(append(b.Path, os.Getenv("PATH")), string(os.PathListSeparator)))
+	environ = append(environ, b.Env...)
+
+	command := exec.Command(b.Executable, append([]string{"--output_base=" + b.OutputBase}, args...)...)
+	command.Dir = dir
+	command.Env = environ
+	command.Stdout = stdout
+	command.Stderr = b.Stderr
+
+	return command.Run()
+}
@@ -0,0 +1,157 @@
+package bazel_test
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/paketo-buildpacks/go/bazel"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testBazel(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		dir string
+	)
+
+	it.Before(func() {
+		dir = t.TempDir()
+	})
+
+	context("Detect", func() {
+		it("finds rules_go in MODULE.bazel", func() {
+			Expect(os.WriteFile(filepath.Join(dir, "MODULE.bazel"), []byte("module(name = \"app\")\n\nbazel_dep(\n    name = \"rules_go\",\n    version = \"0.50.1\",\n)\n"), 0644)).To(Succeed())
+
+			plan, ok, err := bazel.Detect(dir, "//cmd/server")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ok).To(BeTrue())
+			Expect(plan).To(Equal(bazel.Plan{File: "MODULE.bazel", Bzlmod: true, Target: "//cmd/server"}))
+		})
+
+		it("finds rules_go in a WORKSPACE when MODULE.bazel does not use it", func() {
+			Expect(os.WriteFile(filepath.Join(dir, "MODULE.bazel"), []byte("module(name = \"app\")\n"), 0644)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(dir, "WORKSPACE"), []byte("http_archive(\n    name = \"io_bazel_rules_go\",\n)\n"), 0644)).To(Succeed())
+
+			plan, ok, err := bazel.Detect(dir, "//cmd/server")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ok).To(BeTrue())
+			Expect(plan).To(Equal(bazel.Plan{File: "WORKSPACE", Target: "//cmd/server"}))
+		})
+
+		it("does not detect without a target or without rules_go", func() {
+			Expect(os.WriteFile(filepath.Join(dir, "MODULE.bazel"), []byte("bazel_dep(name = \"rules_go\", version = \"0.50.1\")\n"), 0644)).To(Succeed())
+
+			_, ok, err := bazel.Detect(dir, "")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ok).To(BeFalse())
+
+			Expect(os.WriteFile(filepath.Join(dir, "MODULE.bazel"), []byte("bazel_dep(name = \"rules_rust\", version = \"0.50.1\")\n"), 0644)).To(Succeed())
+
+			_, ok, err = bazel.Detect(dir, "//cmd/server")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ok).To(BeFalse())
+		})
+	})
+
+	context("Bazel", func() {
+		var (
+			b        bazel.Bazel
+			execRoot string
+			log      string
+			stdout   *bytes.Buffer
+		)
+
+		it.Before(func() {
+			execRoot = t.TempDir()
+			Expect(os.MkdirAll(filepath.Join(execRoot, "bazel-out", "bin", "cmd", "server", "server_"), os.ModePerm)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(execRoot, "bazel-out", "bin", "cmd", "server", "server_", "server"), []byte("server"), 0755)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(execRoot, "bazel-out", "bin", "cmd", "server", "server.x"), []byte("archive"), 0644)).To(Succeed())
+
+			// a stand-in for bazel that records every invocation and answers
+			// info and cquery like bazel does
+			log = filepath.Join(t.TempDir(), "bazel.log")
+			executable := filepath.Join(t.TempDir(), "bazel")
+			Expect(os.WriteFile(executable, fmt.Appendf(nil, `#!/bin/sh
+echo "$@" >> %[1]s
+echo "PATH=$PATH" >> %[1]s
+echo "BAZELISK_HOME=$BAZELISK_HOME" >> %[1]s
+case "$2" in
+  info) echo %[2]s ;;
+  cquery) printf 'bazel-out/bin/cmd/server/server.x\nbazel-out/bin/cmd/server/server_/server\n' ;;
+  build) [ "$3" = "--fail" ] && exit 1 ;;
+esac
+exit 0
+`, log, execRoot), 0755)).To(Succeed())
+
+			stdout = bytes.NewBuffer(nil)
+			b = bazel.Bazel{
+				Executable: executable,
+				OutputBase: "/cache/output-base",
+				Path:       []string{"/go/bin"},
+				Env:        []string{"BAZELISK_HOME=/cache/bazelisk"},
+				Stdout:     stdout,
+				Stderr:     stdout,
+			}
+		})
+
+		context("Build", func() {
+			it("builds the target with the output base and PATH", func() {
+				Expect(b.Build(dir, bazel.Plan{Target: "//cmd/server"}, []string{"--config=release"})).To(Succeed())
+
+				content, err := os.ReadFile(log)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(content)).To(HavePrefix("--output_base=/cache/output-base build --config=release //cmd/server\nPATH=/go/bin:"))
+			})
+
+			it("runs bazel with the additional variables", func() {
+				Expect(b.Build(dir, bazel.Plan{Target: "//cmd/server"}, nil)).To(Succeed())
+
+				content, err := os.ReadFile(log)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(content)).To(ContainSubstring("BAZELISK_HOME=/cache/bazelisk\n"))
+			})
+
+			it("returns an error when the build fails", func() {
+				err := b.Build(dir, bazel.Plan{Target: "//cmd/server"}, []string{"--fail"})
+				Expect(err).To(MatchError("failed to run bazel build //cmd/server: exit status 1"))
+			})
+		})
+
+		context("Outputs", func() {
+			it("returns the executables of the target", func() {
+				outputs, root, err := b.Outputs(dir, bazel.Plan{Target: "//cmd/server"}, []string{"--config=release"})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(root).To(Equal(execRoot))
+				Expect(outputs).To(Equal([]string{"bazel-out/bin/cmd/server/server_/server"}))
+
+				content, err := os.ReadFile(log)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(content)).To(ContainSubstring("--output_base=/cache/output-base info --config=release execution_root\n"))
+				Expect(string(content)).To(ContainSubstring("--output_base=/cache/output-base cquery --output=files --config=release //cmd/server\n"))
+			})
+
+			it("returns an error when the target produced no executable", func() {
+				Expect(os.Chmod(filepath.Join(execRoot, "bazel-out", "bin", "cmd", "server", "server_", "server"), 0644)).To(Succeed())
+
+				_, _, err := b.Outputs(dir, bazel.Plan{Target: "//cmd/server"}, nil)
+				Expect(err).To(MatchError("target //cmd/server produced no executable"))
+			})
+		})
+
+		context("Shutdown", func() {
+			it("stops the server of the output base", func() {
+				Expect(b.Shutdown(dir)).To(Succeed())
+
+				content, err := os.ReadFile(log)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(content)).To(HavePrefix("--output_base=/cache/output-base shutdown\n"))
+			})
+		})
+	})
+}
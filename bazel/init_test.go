@@ -0,0 +1,14 @@
+package bazel_test
+
+import (
+	"testing"
+
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+)
+
+func TestUnitBazel(t *testing.T) {
+	suite := spec.New("bazel", spec.Report(report.Terminal{}), spec.Parallel())
+	suite("Bazel", testBazel)
+	suite.Run(t)
+}
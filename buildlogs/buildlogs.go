@@ -0,0 +1,149 @@
+// Package buildlogs measures how long the phases of a pack build and the
+// buildpacks that ran during it took, from the logs of the build.
+package buildlogs
+
+import (
+	"bufio"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// TimestampFormat is the format of the timestamps pack prefixes its log lines
+// with when given --timestamps.
+const TimestampFormat = "2006/01/02 15:04:05.000000"
+
+var (
+	linePattern      = regexp.MustCompile(`^(?:(\d{4}/\d{2}/\d{2} \d{2}:\d{2}:\d{2}(?:\.\d+)?) )?(?:\[\w+\] )?(.*)$`)
+	phasePattern     = regexp.MustCompile(`^===> ([A-Z]+)`)
+	buildpackPattern = regexp.MustCompile(`^(\S.*Buildpack for .*?)(?: v?\d+\.\d+\.\d+\S*)?\s*$`)
+	completedPattern = regexp.MustCompile(`^\s+Completed in (\S+)`)
+)
+
+// Timing is the duration of a phase or a buildpack.
+type Timing struct {
+	Name     string
+	Duration time.Duration
+}
+
+// Timings are the durations measured from the logs of a build.
+type Timings struct {
+	// Phases are the lifecycle phases, such as DETECTING and BUILDING. They are
+	// only measured when the logs are timestamped.
+	Phases []Timing
+
+	// Buildpacks are the log sections of the buildpacks, such as "Paketo
+	// Buildpack for Go Build", in the order in which they ran. A section is
+	// measured from its timestamps when the logs are timestamped, and
+	// otherwise from the sum of the "Completed in" durations the buildpack
+	// reports.
+	Buildpacks []Timing
+}
+
+type section struct {
+	name      string
+	start     time.Time
+	end       time.Time
+	completed time.Duration
+}
+
+func (s section) timing() Timing {
+	if !s.start.IsZero() && !s.end.IsZero() {
+		return Timing{Name: s.name, Duration: s.end.Sub(s.start)}
+	}
+
+	return Timing{Name: s.name, Duration: s.completed}
+}
+
+// Parse measures the phases and buildpacks in the logs of a pack build.
+// Lines may be prefixed with a pack timestamp and with the name of the
+// lifecycle container that wrote them, such as [builder].
+func Parse(logs string) Timings {
+	var phases, buildpacks []section
+	var phase, buildpack *section
+	var last time.Time
+
+	closeSection := func(s *section, at time.Time) {
+		if s != nil && s.end.IsZero() {
+			s.end = at
+		}
+	}
+
+	scanner := bufio.NewScanner(strings.NewReader(logs))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		matches := linePattern.FindStringSubmatch(scanner.Text())
+
+		var at time.Time
+		if matches[1] != "" {
+			var err error
+			at, err = time.Parse(TimestampFormat, normalizeTimestamp(matches[1]))
+			if err == nil {
+				last = at
+			}
+		}
+
+		line := matches[2]
+
+		if m := phasePattern.FindStringSubmatch(line); m != nil {
+			closeSection(phase, at)
+			closeSection(buildpack, at)
+			buildpack = nil
+
+			phases = append(phases, section{name: m[1], start: at})
+			phase = &phases[len(phases)-1]
+			continue
+		}
+
+		if phase != nil && phase.name == "BUILDING" {
+			if m := buildpackPattern.FindStringSubmatch(line); m != nil {
+				closeSection(buildpack, at)
+
+				buildpacks = append(buildpacks, section{name: m[1], start: at})
+				buildpack = &buildpacks[len(buildpacks)-1]
+				continue
+			}
+		}
+
+		if buildpack != nil {
+			if m := completedPattern.FindStringSubmatch(line); m != nil {
+				duration, err := time.ParseDuration(m[1])
+				if err == nil {
+					buildpack.completed += duration
+				}
+			}
+		}
+	}
+
+	closeSection(phase, last)
+	closeSection(buildpack, last)
+
+	var timings Timings
+	for _, s := range phases {
+		if s.start.IsZero() {
+			continue
+		}
+		timings.Phases = append(timings.Phases, s.timing())
+	}
+
+	for _, s := range buildpacks {
+		timings.Buildpacks = append(timings.Buildpacks, s.timing())
+	}
+
+	return timings
+}
+
+// normalizeTimestamp pads the fractional seconds of a timestamp to the six
+// digits of TimestampFormat.
+func normalizeTimestamp(timestamp string) string {
+	seconds, fraction, ok := strings.Cut(timestamp, ".")
+	if !ok {
+		return seconds + ".000000"
+	}
+
+	for len(fraction) < 6 {
+		fraction += "0"
+	}
+
+	return seconds + "." + fraction[:6]
+}
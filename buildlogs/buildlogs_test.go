@@ -0,0 +1,70 @@
+package buildlogs_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/paketo-buildpacks/go/buildlogs"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testBuildLogs(t *testing.T, context spec.G, it spec.S) {
+	var Expect = NewWithT(t).Expect
+
+	context("Parse", func() {
+		it("measures phases and buildpack sections from timestamped logs", func() {
+			timings := buildlogs.Parse(`2026/10/19 12:00:00.000000 ===> DETECTING
+2026/10/19 12:00:01.500000 [detector] 3 of 8 buildpacks participating
+2026/10/19 12:00:02.000000 ===> BUILDING
+2026/10/19 12:00:02.000000 [builder] Paketo Buildpack for Go Distribution 2.10.9
+2026/10/19 12:00:02.100000 [builder]   Executing build process
+2026/10/19 12:00:02.200000 [builder]     Installing Go 1.26.3
+2026/10/19 12:00:07.000000 [builder]       Completed in 4.8s
+2026/10/19 12:00:07.000000 [builder] Paketo Buildpack for Go Build 2.2.1
+2026/10/19 12:00:07.100000 [builder]   Building go binaries
+2026/10/19 12:00:37.000000 [builder]       Completed in 29.9s
+2026/10/19 12:00:37.000000 ===> EXPORTING
+2026/10/19 12:00:40.250000 [exporter] Saving some-image...
+`)
+
+			Expect(timings.Phases).To(Equal([]buildlogs.Timing{
+				{Name: "DETECTING", Duration: 2 * time.Second},
+				{Name: "BUILDING", Duration: 35 * time.Second},
+				{Name: "EXPORTING", Duration: 3250 * time.Millisecond},
+			}))
+
+			Expect(timings.Buildpacks).To(Equal([]buildlogs.Timing{
+				{Name: "Paketo Buildpack for Go Distribution", Duration: 5 * time.Second},
+				{Name: "Paketo Buildpack for Go Build", Duration: 30 * time.Second},
+			}))
+		})
+
+		it("falls back to the reported durations without timestamps", func() {
+			timings := buildlogs.Parse(`===> BUILDING
+[builder] Paketo Buildpack for Go Mod Vendor 1.1.21
+[builder]   Executing build process
+[builder]       Completed in 1.5s
+[builder]       Completed in 500ms
+[builder] Paketo Buildpack for Procfile 5.11.3
+[builder]   Procfile contents
+===> EXPORTING
+`)
+
+			Expect(timings.Phases).To(BeEmpty())
+			Expect(timings.Buildpacks).To(Equal([]buildlogs.Timing{
+				{Name: "Paketo Buildpack for Go Mod Vendor", Duration: 2 * time.Second},
+				{Name: "Paketo Buildpack for Procfile", Duration: 0},
+			}))
+		})
+
+		it("only looks for buildpack sections while building", func() {
+			timings := buildlogs.Parse(`===> DETECTING
+[detector] Paketo Buildpack for Go Build 2.2.1
+`)
+
+			Expect(timings.Buildpacks).To(BeEmpty())
+		})
+	})
+}
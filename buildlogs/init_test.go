@@ -0,0 +1,14 @@
+package buildlogs_test
+
+import (
+	"testing"
+
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+)
+
+func TestUnitBuildLogs(t *testing.T) {
+	suite := spec.New("buildlogs", spec.Report(report.Terminal{}), spec.Parallel())
+	suite("BuildLogs", testBuildLogs)
+	suite.Run(t)
+}
@@ -0,0 +1,116 @@
+package gobazel
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/paketo-buildpacks/go/bazel"
+	"github.com/paketo-buildpacks/go/buildtool"
+	"github.com/paketo-buildpacks/packit/v2"
+	"github.com/paketo-buildpacks/packit/v2/scribe"
+)
+
+const (
+	// CacheLayerName is the name of the cache layer holding the Bazel output
+	// base and the Bazel versions Bazelisk downloaded.
+	CacheLayerName = "bazel"
+
+	// LayerName is the name of the launch layer the binaries are copied to.
+	LayerName = "targets"
+)
+
+// Build runs bazel build for the target of BP_GO_BAZEL_TARGET with the
+// bazelisk the buildpack ships, keeping the output base in a cache layer so
+// that rebuilds reuse the action cache. It copies the executables of the
+// target to a launch layer and assigns a process type to each of them.
+func Build(logger scribe.Emitter) packit.BuildFunc {
+	return func(context packit.BuildContext) (packit.BuildResult, error) {
+		logger.Title("%s %s", context.BuildpackInfo.Name, context.BuildpackInfo.Version)
+
+		plan, ok, err := bazel.Detect(context.WorkingDir, os.Getenv(bazel.TargetEnv))
+		if err != nil {
+			return packit.BuildResult{}, err
+		}
+
+		if !ok {
+			return packit.BuildResult{}, fmt.Errorf("no MODULE.bazel or WORKSPACE uses rules_go, or %s is not set", bazel.TargetEnv)
+		}
+
+		cache, err := context.Layers.Get(CacheLayerName)
+		if err != nil {
+			return packit.BuildResult{}, err
+		}
+		cache.Cache = true
+
+		layer, err := context.Layers.Get(LayerName)
+		if err != nil {
+			return packit.BuildResult{}, err
+		}
+
+		layer, err = layer.Reset()
+		if err != nil {
+			return packit.BuildResult{}, err
+		}
+		layer.Launch = true
+
+		tools := filepath.Join(context.CNBPath, "bin", "linux-"+runtime.GOARCH, "tools")
+		b := bazel.Bazel{
+			Executable: filepath.Join(tools, "bazelisk"),
+			OutputBase: filepath.Join(cache.Path, "output-base"),
+			Path:       []string{tools},
+			Env:        []string{"BAZELISK_HOME=" + filepath.Join(cache.Path, "bazelisk")},
+			Stdout:     logger.ActionWriter,
+			Stderr:     logger.ActionWriter,
+		}
+
+		// the convenience symlinks would point from the application into the
+		// cache layer, which is not part of the image
+		flags := append([]string{"--symlink_prefix=/"}, strings.Fields(os.Getenv(bazel.BuildFlagsEnv))...)
+
+		logger.Process("Running bazel build %s from %s", plan.Target, plan.File)
+		err = b.Build(context.WorkingDir, plan, flags)
+		if err != nil {
+			return packit.BuildResult{}, err
+		}
+
+		outputs, execRoot, err := b.Outputs(context.WorkingDir, plan, flags)
+		if err != nil {
+			return packit.BuildResult{}, err
+		}
+
+		err = b.Shutdown(context.WorkingDir)
+		if err != nil {
+			return packit.BuildResult{}, err
+		}
+		logger.Break()
+
+		collected, err := buildtool.Collect(execRoot, outputs, layer.Path)
+		if err != nil {
+			return packit.BuildResult{}, err
+		}
+
+		var processes []packit.Process
+		logger.Process("Assigning launch processes:")
+		for _, process := range collected {
+			logger.Subprocess("%s: %s", process.Type, process.Command)
+
+			processes = append(processes, packit.Process{
+				Type:    process.Type,
+				Command: process.Command,
+				Direct:  process.Direct,
+				Default: process.Default,
+			})
+		}
+		logger.Break()
+
+		return packit.BuildResult{
+			Layers: []packit.Layer{cache, layer},
+			Launch: packit.LaunchMetadata{
+				Processes: processes,
+			},
+		}, nil
+	}
+}
@@ -0,0 +1,153 @@
+package gobazel_test
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	gobazel "github.com/paketo-buildpacks/go/buildpacks/go-bazel"
+	"github.com/paketo-buildpacks/packit/v2"
+	"github.com/paketo-buildpacks/packit/v2/scribe"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testBuild(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		layersDir  string
+		workingDir string
+		execRoot   string
+		log        string
+		buffer     *bytes.Buffer
+		build      packit.BuildFunc
+		buildCtx   packit.BuildContext
+	)
+
+	it.Before(func() {
+		layersDir = t.TempDir()
+		workingDir = t.TempDir()
+		cnbDir := t.TempDir()
+
+		Expect(os.WriteFile(filepath.Join(workingDir, "MODULE.bazel"), []byte("bazel_dep(name = \"rules_go\", version = \"0.50.1\")\n"), 0644)).To(Succeed())
+
+		execRoot = t.TempDir()
+		Expect(os.MkdirAll(filepath.Join(execRoot, "bazel-out", "bin", "cmd", "server", "server_"), os.ModePerm)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(execRoot, "bazel-out", "bin", "cmd", "server", "server_", "server"), []byte("server"), 0755)).To(Succeed())
+
+		// a stand-in for the bazelisk the buildpack ships, which records every
+		// invocation and answers info and cquery like bazel does
+		log = filepath.Join(t.TempDir(), "bazelisk.log")
+		tools := filepath.Join(cnbDir, "bin", "linux-"+runtime.GOARCH, "tools")
+		Expect(os.MkdirAll(tools, os.ModePerm)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(tools, "bazelisk"), fmt.Appendf(nil, `#!/bin/sh
+echo "$@" >> %[1]s
+echo "BAZELISK_HOME=$BAZELISK_HOME" >> %[1]s
+case "$2" in
+  info) echo %[2]s ;;
+  cquery) echo bazel-out/bin/cmd/server/server_/server ;;
+  build) [ "$4" = "--fail" ] && exit 1 ;;
+esac
+exit 0
+`, log, execRoot), 0755)).To(Succeed())
+
+		t.Setenv("BP_GO_BAZEL_TARGET", "//cmd/server")
+		t.Setenv("BP_GO_BAZEL_BUILD_FLAGS", "--config=release")
+
+		buffer = bytes.NewBuffer(nil)
+		build = gobazel.Build(scribe.NewEmitter(buffer))
+		buildCtx = packit.BuildContext{
+			BuildpackInfo: packit.BuildpackInfo{
+				Name:    "Some Buildpack",
+				Version: "some-version",
+			},
+			CNBPath:    cnbDir,
+			WorkingDir: workingDir,
+			Layers:     packit.Layers{Path: layersDir},
+		}
+	})
+
+	it("builds the target with the output base in a cache layer and the binary in a launch layer", func() {
+		result, err := build(buildCtx)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(result.Layers).To(HaveLen(2))
+
+		cache := result.Layers[0]
+		Expect(cache.Name).To(Equal("bazel"))
+		Expect(cache.Cache).To(BeTrue())
+		Expect(cache.Build).To(BeFalse())
+		Expect(cache.Launch).To(BeFalse())
+
+		layer := result.Layers[1]
+		Expect(layer.Name).To(Equal("targets"))
+		Expect(layer.Launch).To(BeTrue())
+		Expect(layer.Cache).To(BeFalse())
+		Expect(os.ReadFile(filepath.Join(layer.Path, "bin", "server"))).To(Equal([]byte("server")))
+
+		Expect(result.Launch.Processes).To(Equal([]packit.Process{
+			{Type: "server", Command: filepath.Join(layersDir, "targets", "bin", "server"), Direct: true, Default: true},
+		}))
+
+		outputBase := filepath.Join(layersDir, "bazel", "output-base")
+		content, err := os.ReadFile(log)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(content)).To(Equal(fmt.Sprintf(`--output_base=%[1]s build --symlink_prefix=/ --config=release //cmd/server
+BAZELISK_HOME=%[2]s
+--output_base=%[1]s info --symlink_prefix=/ --config=release execution_root
+BAZELISK_HOME=%[2]s
+--output_base=%[1]s cquery --output=files --symlink_prefix=/ --config=release //cmd/server
+BAZELISK_HOME=%[2]s
+--output_base=%[1]s shutdown
+BAZELISK_HOME=%[2]s
+`, outputBase, filepath.Join(layersDir, "bazel", "bazelisk"))))
+
+		Expect(buffer.String()).To(ContainSubstring("Some Buildpack some-version"))
+		Expect(buffer.String()).To(ContainSubstring("Running bazel build //cmd/server from MODULE.bazel"))
+		Expect(buffer.String()).To(ContainSubstring("server: " + filepath.Join(layersDir, "targets", "bin", "server")))
+	})
+
+	context("when the cache layer is restored", func() {
+		it.Before(func() {
+			Expect(os.MkdirAll(filepath.Join(layersDir, "bazel", "output-base"), os.ModePerm)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(layersDir, "bazel", "output-base", "action-cache"), nil, 0644)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(layersDir, "bazel.toml"), []byte("[types]\n  cache = true\n"), 0644)).To(Succeed())
+		})
+
+		it("keeps the output base", func() {
+			_, err := build(buildCtx)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(filepath.Join(layersDir, "bazel", "output-base", "action-cache")).To(BeARegularFile())
+		})
+	})
+
+	context("failure cases", func() {
+		context("when the build fails", func() {
+			it.Before(func() {
+				t.Setenv("BP_GO_BAZEL_BUILD_FLAGS", "--fail")
+			})
+
+			it("returns an error", func() {
+				_, err := build(buildCtx)
+				Expect(err).To(MatchError("failed to run bazel build //cmd/server: exit status 1"))
+			})
+		})
+
+		context("when the target produced no executable", func() {
+			it.Before(func() {
+				Expect(os.Chmod(filepath.Join(execRoot, "bazel-out", "bin", "cmd", "server", "server_", "server"), 0644)).To(Succeed())
+			})
+
+			it("returns an error", func() {
+				_, err := build(buildCtx)
+				Expect(err).To(MatchError("target //cmd/server produced no executable"))
+			})
+		})
+	})
+}
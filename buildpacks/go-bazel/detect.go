@@ -0,0 +1,42 @@
+package gobazel
+
+import (
+	"os"
+
+	"github.com/paketo-buildpacks/go/bazel"
+	"github.com/paketo-buildpacks/packit/v2"
+)
+
+// Detect passes when BP_GO_BAZEL_TARGET is set and the MODULE.bazel or
+// WORKSPACE of the application uses rules_go, and requires go during the
+// build for the toolchains that use the Go distribution on PATH.
+func Detect() packit.DetectFunc {
+	return func(context packit.DetectContext) (packit.DetectResult, error) {
+		target := os.Getenv(bazel.TargetEnv)
+		if target == "" {
+			return packit.DetectResult{}, packit.Fail.WithMessage("%s is not set", bazel.TargetEnv)
+		}
+
+		_, ok, err := bazel.Detect(context.WorkingDir, target)
+		if err != nil {
+			return packit.DetectResult{}, err
+		}
+
+		if !ok {
+			return packit.DetectResult{}, packit.Fail.WithMessage("no MODULE.bazel or WORKSPACE uses rules_go")
+		}
+
+		return packit.DetectResult{
+			Plan: packit.BuildPlan{
+				Requires: []packit.BuildPlanRequirement{
+					{
+						Name: "go",
+						Metadata: map[string]interface{}{
+							"build": true,
+						},
+					},
+				},
+			},
+		}, nil
+	}
+}
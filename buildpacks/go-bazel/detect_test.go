@@ -0,0 +1,71 @@
+package gobazel_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	gobazel "github.com/paketo-buildpacks/go/buildpacks/go-bazel"
+	"github.com/paketo-buildpacks/packit/v2"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testDetect(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		workingDir string
+		detect     packit.DetectFunc
+	)
+
+	it.Before(func() {
+		workingDir = t.TempDir()
+		Expect(os.WriteFile(filepath.Join(workingDir, "MODULE.bazel"), []byte("bazel_dep(name = \"rules_go\", version = \"0.50.1\")\n"), 0644)).To(Succeed())
+
+		detect = gobazel.Detect()
+	})
+
+	it("requires go during the build when the workspace uses rules_go", func() {
+		t.Setenv("BP_GO_BAZEL_TARGET", "//cmd/server")
+
+		result, err := detect(packit.DetectContext{WorkingDir: workingDir})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Plan).To(Equal(packit.BuildPlan{
+			Requires: []packit.BuildPlanRequirement{
+				{Name: "go", Metadata: map[string]interface{}{"build": true}},
+			},
+		}))
+	})
+
+	it("fails when BP_GO_BAZEL_TARGET is not set", func() {
+		t.Setenv("BP_GO_BAZEL_TARGET", "")
+
+		_, err := detect(packit.DetectContext{WorkingDir: workingDir})
+		Expect(err).To(MatchError(packit.Fail.WithMessage("BP_GO_BAZEL_TARGET is not set")))
+	})
+
+	it("fails when the workspace does not use rules_go", func() {
+		t.Setenv("BP_GO_BAZEL_TARGET", "//cmd/server")
+		Expect(os.WriteFile(filepath.Join(workingDir, "MODULE.bazel"), []byte("bazel_dep(name = \"rules_rust\", version = \"0.50.1\")\n"), 0644)).To(Succeed())
+
+		_, err := detect(packit.DetectContext{WorkingDir: workingDir})
+		Expect(err).To(MatchError(packit.Fail.WithMessage("no MODULE.bazel or WORKSPACE uses rules_go")))
+	})
+
+	context("failure cases", func() {
+		context("when MODULE.bazel cannot be read", func() {
+			it.Before(func() {
+				t.Setenv("BP_GO_BAZEL_TARGET", "//cmd/server")
+				Expect(os.Remove(filepath.Join(workingDir, "MODULE.bazel"))).To(Succeed())
+				Expect(os.Mkdir(filepath.Join(workingDir, "MODULE.bazel"), os.ModePerm)).To(Succeed())
+			})
+
+			it("returns an error", func() {
+				_, err := detect(packit.DetectContext{WorkingDir: workingDir})
+				Expect(err).To(HaveOccurred())
+			})
+		})
+	})
+}
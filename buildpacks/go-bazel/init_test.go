@@ -0,0 +1,17 @@
+package gobazel_test
+
+import (
+	"testing"
+
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+)
+
+func TestUnitGoBazel(t *testing.T) {
+	// the target and flags are read from the environment, which the specs
+	// set with t.Setenv
+	suite := spec.New("go-bazel", spec.Report(report.Terminal{}), spec.Sequential())
+	suite("Build", testBuild)
+	suite("Detect", testDetect)
+	suite.Run(t)
+}
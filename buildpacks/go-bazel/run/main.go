@@ -0,0 +1,16 @@
+package main
+
+import (
+	"os"
+
+	gobazel "github.com/paketo-buildpacks/go/buildpacks/go-bazel"
+	"github.com/paketo-buildpacks/packit/v2"
+	"github.com/paketo-buildpacks/packit/v2/scribe"
+)
+
+func main() {
+	packit.Run(
+		gobazel.Detect(),
+		gobazel.Build(scribe.NewEmitter(os.Stdout).WithLevel(os.Getenv("BP_LOG_LEVEL"))),
+	)
+}
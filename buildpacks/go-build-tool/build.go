@@ -0,0 +1,86 @@
+package gobuildtool
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"github.com/paketo-buildpacks/go/buildtool"
+	"github.com/paketo-buildpacks/packit/v2"
+	"github.com/paketo-buildpacks/packit/v2/scribe"
+)
+
+// LayerName is the name of the launch layer the binaries are copied to.
+const LayerName = "targets"
+
+// Build runs the make or Task target of BP_GO_BUILD_TOOL_TARGET, copies the
+// binaries listed in BP_GO_BUILD_TOOL_OUTPUTS to a launch layer and assigns a
+// process type to each of them. The tools the buildpack ships, such as task,
+// are found in bin/linux-<arch>/tools of the buildpack.
+func Build(logger scribe.Emitter) packit.BuildFunc {
+	return func(context packit.BuildContext) (packit.BuildResult, error) {
+		logger.Title("%s %s", context.BuildpackInfo.Name, context.BuildpackInfo.Version)
+
+		target := os.Getenv(buildtool.TargetEnv)
+		outputs := buildtool.ParseOutputs(os.Getenv(buildtool.OutputsEnv))
+		if len(outputs) == 0 {
+			return packit.BuildResult{}, fmt.Errorf("%s must list the binaries the %s target produces", buildtool.OutputsEnv, target)
+		}
+
+		plan, ok, err := buildtool.Detect(context.WorkingDir, target)
+		if err != nil {
+			return packit.BuildResult{}, err
+		}
+
+		if !ok {
+			return packit.BuildResult{}, fmt.Errorf("no Makefile or Taskfile defines a %q target", target)
+		}
+
+		layer, err := context.Layers.Get(LayerName)
+		if err != nil {
+			return packit.BuildResult{}, err
+		}
+
+		layer, err = layer.Reset()
+		if err != nil {
+			return packit.BuildResult{}, err
+		}
+		layer.Launch = true
+
+		logger.Process("Running %s %s from %s", plan.Tool, plan.Target, plan.File)
+
+		tools := filepath.Join(context.CNBPath, "bin", "linux-"+runtime.GOARCH, "tools")
+		err = plan.Run(context.WorkingDir, []string{tools}, logger.ActionWriter, logger.ActionWriter)
+		if err != nil {
+			return packit.BuildResult{}, err
+		}
+		logger.Break()
+
+		collected, err := buildtool.Collect(context.WorkingDir, outputs, layer.Path)
+		if err != nil {
+			return packit.BuildResult{}, err
+		}
+
+		var processes []packit.Process
+		logger.Process("Assigning launch processes:")
+		for _, process := range collected {
+			logger.Subprocess("%s: %s", process.Type, process.Command)
+
+			processes = append(processes, packit.Process{
+				Type:    process.Type,
+				Command: process.Command,
+				Direct:  process.Direct,
+				Default: process.Default,
+			})
+		}
+		logger.Break()
+
+		return packit.BuildResult{
+			Layers: []packit.Layer{layer},
+			Launch: packit.LaunchMetadata{
+				Processes: processes,
+			},
+		}, nil
+	}
+}
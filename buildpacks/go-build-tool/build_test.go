@@ -0,0 +1,137 @@
+package gobuildtool_test
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	gobuildtool "github.com/paketo-buildpacks/go/buildpacks/go-build-tool"
+	"github.com/paketo-buildpacks/packit/v2"
+	"github.com/paketo-buildpacks/packit/v2/scribe"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testBuild(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		layersDir  string
+		workingDir string
+		cnbDir     string
+		buffer     *bytes.Buffer
+		build      packit.BuildFunc
+		buildCtx   packit.BuildContext
+	)
+
+	it.Before(func() {
+		layersDir = t.TempDir()
+		workingDir = t.TempDir()
+		cnbDir = t.TempDir()
+
+		t.Setenv("BP_GO_BUILD_TOOL_TARGET", "build")
+		t.Setenv("BP_GO_BUILD_TOOL_OUTPUTS", "bin/server")
+
+		buffer = bytes.NewBuffer(nil)
+		build = gobuildtool.Build(scribe.NewEmitter(buffer))
+		buildCtx = packit.BuildContext{
+			BuildpackInfo: packit.BuildpackInfo{
+				Name:    "Some Buildpack",
+				Version: "some-version",
+			},
+			CNBPath:    cnbDir,
+			WorkingDir: workingDir,
+			Layers:     packit.Layers{Path: layersDir},
+		}
+	})
+
+	context("when the app is built with a make target", func() {
+		it.Before(func() {
+			_, err := exec.LookPath("make")
+			if err != nil {
+				t.Skip("make is not installed")
+			}
+
+			Expect(os.WriteFile(filepath.Join(workingDir, "Makefile"), []byte("build:\n\tmkdir -p bin\n\tprintf '#!/bin/sh\\necho server\\n' > bin/server\n\tchmod +x bin/server\n"), 0644)).To(Succeed())
+		})
+
+		it("runs the target and assigns a process type to each output in a launch layer", func() {
+			result, err := build(buildCtx)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(result.Layers).To(HaveLen(1))
+			layer := result.Layers[0]
+			Expect(layer.Name).To(Equal("targets"))
+			Expect(layer.Launch).To(BeTrue())
+			Expect(filepath.Join(layer.Path, "bin", "server")).To(BeARegularFile())
+
+			Expect(result.Launch.Processes).To(Equal([]packit.Process{
+				{Type: "server", Command: filepath.Join(layersDir, "targets", "bin", "server"), Direct: true, Default: true},
+			}))
+
+			Expect(buffer.String()).To(ContainSubstring("Some Buildpack some-version"))
+			Expect(buffer.String()).To(ContainSubstring("Running make build from Makefile"))
+			Expect(buffer.String()).To(ContainSubstring("server: " + filepath.Join(layersDir, "targets", "bin", "server")))
+		})
+	})
+
+	context("when the app is built with a Task target", func() {
+		it.Before(func() {
+			Expect(os.WriteFile(filepath.Join(workingDir, "Taskfile.yml"), []byte("version: '3'\n\ntasks:\n  build:\n    cmds:\n      - go build -o bin/server .\n"), 0644)).To(Succeed())
+
+			// a stand-in for the task the buildpack ships, which records that it
+			// was the one that ran
+			tools := filepath.Join(cnbDir, "bin", "linux-"+runtime.GOARCH, "tools")
+			Expect(os.MkdirAll(tools, os.ModePerm)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(tools, "task"), []byte("#!/bin/sh\nmkdir -p bin\necho \"$@\" > bin/server\nchmod +x bin/server\n"), 0755)).To(Succeed())
+		})
+
+		it("runs the target with the task of the buildpack", func() {
+			result, err := build(buildCtx)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(os.ReadFile(filepath.Join(layersDir, "targets", "bin", "server"))).To(Equal([]byte("--taskfile Taskfile.yml build\n")))
+			Expect(result.Launch.Processes).To(HaveLen(1))
+			Expect(buffer.String()).To(ContainSubstring("Running task build from Taskfile.yml"))
+		})
+	})
+
+	context("failure cases", func() {
+		context("when BP_GO_BUILD_TOOL_OUTPUTS is not set", func() {
+			it.Before(func() {
+				t.Setenv("BP_GO_BUILD_TOOL_OUTPUTS", "")
+			})
+
+			it("returns an error", func() {
+				_, err := build(buildCtx)
+				Expect(err).To(MatchError("BP_GO_BUILD_TOOL_OUTPUTS must list the binaries the build target produces"))
+			})
+		})
+
+		context("when the target does not produce a declared output", func() {
+			it.Before(func() {
+				Expect(os.WriteFile(filepath.Join(workingDir, "Makefile"), []byte("build:\n\ttrue\n"), 0644)).To(Succeed())
+			})
+
+			it("returns an error", func() {
+				_, err := build(buildCtx)
+				Expect(err).To(MatchError("output bin/server was not produced"))
+			})
+		})
+
+		context("when the target fails", func() {
+			it.Before(func() {
+				Expect(os.WriteFile(filepath.Join(workingDir, "Makefile"), []byte("build:\n\tfalse\n"), 0644)).To(Succeed())
+			})
+
+			it("returns an error", func() {
+				_, err := build(buildCtx)
+				Expect(err).To(MatchError(ContainSubstring("failed to run make build")))
+			})
+		})
+	})
+}
@@ -0,0 +1,41 @@
+package gobuildtool
+
+import (
+	"os"
+
+	"github.com/paketo-buildpacks/go/buildtool"
+	"github.com/paketo-buildpacks/packit/v2"
+)
+
+// Detect passes when BP_GO_BUILD_TOOL_TARGET names a target of the Makefile
+// or Taskfile of the application, and requires go during the build.
+func Detect() packit.DetectFunc {
+	return func(context packit.DetectContext) (packit.DetectResult, error) {
+		target := os.Getenv(buildtool.TargetEnv)
+		if target == "" {
+			return packit.DetectResult{}, packit.Fail.WithMessage("%s is not set", buildtool.TargetEnv)
+		}
+
+		_, ok, err := buildtool.Detect(context.WorkingDir, target)
+		if err != nil {
+			return packit.DetectResult{}, err
+		}
+
+		if !ok {
+			return packit.DetectResult{}, packit.Fail.WithMessage("no Makefile or Taskfile defines a %q target", target)
+		}
+
+		return packit.DetectResult{
+			Plan: packit.BuildPlan{
+				Requires: []packit.BuildPlanRequirement{
+					{
+						Name: "go",
+						Metadata: map[string]interface{}{
+							"build": true,
+						},
+					},
+				},
+			},
+		}, nil
+	}
+}
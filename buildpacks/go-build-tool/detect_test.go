@@ -0,0 +1,70 @@
+package gobuildtool_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	gobuildtool "github.com/paketo-buildpacks/go/buildpacks/go-build-tool"
+	"github.com/paketo-buildpacks/packit/v2"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testDetect(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		workingDir string
+		detect     packit.DetectFunc
+	)
+
+	it.Before(func() {
+		workingDir = t.TempDir()
+		Expect(os.WriteFile(filepath.Join(workingDir, "Makefile"), []byte("build:\n\tgo build -o bin/server .\n"), 0644)).To(Succeed())
+
+		detect = gobuildtool.Detect()
+	})
+
+	it("requires go during the build when the target is defined", func() {
+		t.Setenv("BP_GO_BUILD_TOOL_TARGET", "build")
+
+		result, err := detect(packit.DetectContext{WorkingDir: workingDir})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Plan).To(Equal(packit.BuildPlan{
+			Requires: []packit.BuildPlanRequirement{
+				{Name: "go", Metadata: map[string]interface{}{"build": true}},
+			},
+		}))
+	})
+
+	it("fails when BP_GO_BUILD_TOOL_TARGET is not set", func() {
+		t.Setenv("BP_GO_BUILD_TOOL_TARGET", "")
+
+		_, err := detect(packit.DetectContext{WorkingDir: workingDir})
+		Expect(err).To(MatchError(packit.Fail.WithMessage("BP_GO_BUILD_TOOL_TARGET is not set")))
+	})
+
+	it("fails when no Makefile or Taskfile defines the target", func() {
+		t.Setenv("BP_GO_BUILD_TOOL_TARGET", "release")
+
+		_, err := detect(packit.DetectContext{WorkingDir: workingDir})
+		Expect(err).To(MatchError(packit.Fail.WithMessage(`no Makefile or Taskfile defines a "release" target`)))
+	})
+
+	context("failure cases", func() {
+		context("when the Taskfile cannot be parsed", func() {
+			it.Before(func() {
+				Expect(os.Remove(filepath.Join(workingDir, "Makefile"))).To(Succeed())
+				Expect(os.WriteFile(filepath.Join(workingDir, "Taskfile.yml"), []byte("tasks: ["), 0644)).To(Succeed())
+				t.Setenv("BP_GO_BUILD_TOOL_TARGET", "build")
+			})
+
+			it("returns an error", func() {
+				_, err := detect(packit.DetectContext{WorkingDir: workingDir})
+				Expect(err).To(MatchError(ContainSubstring("failed to parse Taskfile.yml")))
+			})
+		})
+	})
+}
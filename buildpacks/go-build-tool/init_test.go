@@ -0,0 +1,17 @@
+package gobuildtool_test
+
+import (
+	"testing"
+
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+)
+
+func TestUnitGoBuildTool(t *testing.T) {
+	// the target and outputs are read from the environment, which the specs
+	// set with t.Setenv
+	suite := spec.New("go-build-tool", spec.Report(report.Terminal{}), spec.Sequential())
+	suite("Build", testBuild)
+	suite("Detect", testDetect)
+	suite.Run(t)
+}
@@ -0,0 +1,16 @@
+package main
+
+import (
+	"os"
+
+	gobuildtool "github.com/paketo-buildpacks/go/buildpacks/go-build-tool"
+	"github.com/paketo-buildpacks/packit/v2"
+	"github.com/paketo-buildpacks/packit/v2/scribe"
+)
+
+func main() {
+	packit.Run(
+		gobuildtool.Detect(),
+		gobuildtool.Build(scribe.NewEmitter(os.Stdout).WithLevel(os.Getenv("BP_LOG_LEVEL"))),
+	)
+}
@@ -0,0 +1,154 @@
+package godebugsymbols
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/paketo-buildpacks/go/debugsymbols"
+	"github.com/paketo-buildpacks/go/gobuild"
+	"github.com/paketo-buildpacks/packit/v2"
+	"github.com/paketo-buildpacks/packit/v2/scribe"
+)
+
+const (
+	// LayerName is the name of the build-only layer objcopy writes the
+	// symbols directory to.
+	LayerName = "symbols"
+
+	// LaunchLayerName is the name of the launch layer the symbols directory
+	// is exported in, at debugsymbols.LaunchDir.
+	LaunchLayerName = "debug-symbols"
+)
+
+// Build runs after go-build. It moves the DWARF debug information of the
+// binaries of go-build into symbol files named after their Go build IDs, in a
+// build-only layer, and strips the binaries in place in the targets layer of
+// go-build, see the gobuild package. The symbols directory is then exported in
+// a launch layer of its own, so that it ends up in a separate image layer that
+// debugsymbols.Unpack turns into a debug image, and the image is labelled with
+// the build IDs.
+func Build(logger scribe.Emitter) packit.BuildFunc {
+	return func(context packit.BuildContext) (packit.BuildResult, error) {
+		logger.Title("%s %s", context.BuildpackInfo.Name, context.BuildpackInfo.Version)
+
+		executable, err := exec.LookPath("objcopy")
+		if err != nil {
+			return packit.BuildResult{}, fmt.Errorf("objcopy is not installed: %s needs objcopy from GNU binutils or LLVM on the builder", debugsymbols.Env)
+		}
+
+		dir := gobuild.Targets(context.Layers.Path)
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return packit.BuildResult{}, fmt.Errorf("go-build left no binaries in %s", dir)
+			}
+			return packit.BuildResult{}, err
+		}
+
+		layer, err := context.Layers.Get(LayerName)
+		if err != nil {
+			return packit.BuildResult{}, err
+		}
+
+		layer, err = layer.Reset()
+		if err != nil {
+			return packit.BuildResult{}, err
+		}
+		layer.Build = true
+
+		objcopy := debugsymbols.Objcopy{
+			Executable: executable,
+			Stdout:     logger.ActionWriter,
+			Stderr:     logger.ActionWriter,
+		}
+
+		var manifest debugsymbols.Manifest
+		logger.Process("Extracting the debug symbols of go-build")
+		for _, entry := range entries {
+			if !entry.Type().IsRegular() {
+				continue
+			}
+
+			symbols, err := objcopy.Extract(filepath.Join(dir, entry.Name()), layer.Path)
+			if err != nil {
+				return packit.BuildResult{}, err
+			}
+
+			logger.Subprocess("Extracted the debug symbols of %s (build ID %s) to %s", symbols.Binary, symbols.BuildID, symbols.File)
+			manifest.Add(symbols)
+		}
+		logger.Break()
+
+		if len(manifest.Symbols) == 0 {
+			return packit.BuildResult{}, fmt.Errorf("go-build left no binaries in %s", dir)
+		}
+
+		err = manifest.Write(layer.Path)
+		if err != nil {
+			return packit.BuildResult{}, err
+		}
+
+		launchLayer, err := context.Layers.Get(LaunchLayerName)
+		if err != nil {
+			return packit.BuildResult{}, err
+		}
+
+		launchLayer, err = launchLayer.Reset()
+		if err != nil {
+			return packit.BuildResult{}, err
+		}
+		launchLayer.Launch = true
+
+		logger.Process("Exporting the symbols to %s", debugsymbols.LaunchDir)
+		files := []string{debugsymbols.ManifestFile}
+		var buildIDs []string
+		for _, symbols := range manifest.Symbols {
+			files = append(files, symbols.File)
+			buildIDs = append(buildIDs, symbols.BuildID)
+		}
+
+		for _, file := range files {
+			err = copyFile(filepath.Join(layer.Path, file), filepath.Join(launchLayer.Path, file))
+			if err != nil {
+				return packit.BuildResult{}, err
+			}
+		}
+		logger.Subprocess("%s: %s", debugsymbols.BuildIDsLabel, strings.Join(buildIDs, ","))
+		logger.Break()
+
+		return packit.BuildResult{
+			Layers: []packit.Layer{layer, launchLayer},
+			Launch: packit.LaunchMetadata{
+				Labels: map[string]string{
+					debugsymbols.BuildIDsLabel: strings.Join(buildIDs, ","),
+				},
+			},
+		}, nil
+	}
+}
+
+func copyFile(source, destination string) error {
+	in, err := os.Open(source)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(destination, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	_, err = io.Copy(out, in)
+	if err != nil {
+		return err
+	}
+
+	return out.Close()
+}
@@ -0,0 +1,148 @@
+package godebugsymbols_test
+
+import (
+	"bytes"
+	"debug/elf"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	godebugsymbols "github.com/paketo-buildpacks/go/buildpacks/go-debug-symbols"
+	"github.com/paketo-buildpacks/go/debugsymbols"
+	"github.com/paketo-buildpacks/packit/v2"
+	"github.com/paketo-buildpacks/packit/v2/scribe"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testBuild(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		layersDir string
+		targets   string
+		binary    string
+		buffer    *bytes.Buffer
+		build     packit.BuildFunc
+		buildCtx  packit.BuildContext
+	)
+
+	// compile builds a program into the targets of go-build with the given
+	// ldflags.
+	compile := func(ldflags string) {
+		dir := t.TempDir()
+		Expect(os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n\nfunc main() {}\n"), 0644)).To(Succeed())
+
+		output, err := exec.Command("go", "build", "-C", dir, "-o", binary, "-ldflags", ldflags, "main.go").CombinedOutput()
+		Expect(err).NotTo(HaveOccurred(), string(output))
+	}
+
+	it.Before(func() {
+		_, err := exec.LookPath("objcopy")
+		Expect(err).NotTo(HaveOccurred(), "the specs need objcopy from GNU binutils or LLVM")
+
+		layersDir = t.TempDir()
+		targets = filepath.Join(layersDir, "paketo-buildpacks_go-build", "targets", "bin")
+		Expect(os.MkdirAll(targets, os.ModePerm)).To(Succeed())
+		binary = filepath.Join(targets, "server")
+
+		buffer = bytes.NewBuffer(nil)
+		build = godebugsymbols.Build(scribe.NewEmitter(buffer))
+		buildCtx = packit.BuildContext{
+			BuildpackInfo: packit.BuildpackInfo{
+				Name:    "Some Buildpack",
+				Version: "some-version",
+			},
+			WorkingDir: t.TempDir(),
+			Layers:     packit.Layers{Path: filepath.Join(layersDir, "paketo-buildpacks_go-debug-symbols")},
+		}
+	})
+
+	it("strips the binaries and exports their symbols keyed by build ID", func() {
+		compile("")
+		id, err := debugsymbols.ReadBuildID(binary)
+		Expect(err).NotTo(HaveOccurred())
+
+		result, err := build(buildCtx)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(result.Layers).To(HaveLen(2))
+
+		layer := result.Layers[0]
+		Expect(layer.Name).To(Equal("symbols"))
+		Expect(layer.Build).To(BeTrue())
+		Expect(layer.Launch).To(BeFalse())
+		Expect(layer.Cache).To(BeFalse())
+		Expect(filepath.Join(layer.Path, id.Key()+".debug")).To(BeARegularFile())
+
+		launchLayer := result.Layers[1]
+		Expect(launchLayer.Name).To(Equal("debug-symbols"))
+		Expect(launchLayer.Build).To(BeFalse())
+		Expect(launchLayer.Launch).To(BeTrue())
+		Expect(launchLayer.Cache).To(BeFalse())
+
+		content, err := os.ReadFile(filepath.Join(launchLayer.Path, "symbols.json"))
+		Expect(err).NotTo(HaveOccurred())
+
+		var manifest debugsymbols.Manifest
+		Expect(json.Unmarshal(content, &manifest)).To(Succeed())
+		symbols, ok := manifest.Find(id.Go)
+		Expect(ok).To(BeTrue())
+		Expect(symbols.Binary).To(Equal("server"))
+		Expect(debugsymbols.ReadBuildID(filepath.Join(launchLayer.Path, symbols.File))).To(Equal(id))
+
+		Expect(result.Launch.Labels).To(Equal(map[string]string{
+			"io.paketo.go.debug-symbols.build-ids": id.Go,
+		}))
+
+		// the binary in the targets of go-build is stripped and keeps its
+		// build ID
+		file, err := elf.Open(binary)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(file.Section(".debug_info")).To(BeNil())
+		Expect(file.Close()).To(Succeed())
+		Expect(debugsymbols.ReadBuildID(binary)).To(Equal(id))
+
+		Expect(buffer.String()).To(ContainSubstring("Some Buildpack some-version"))
+		Expect(buffer.String()).To(ContainSubstring("Extracted the debug symbols of server (build ID %s) to %s.debug", id.Go, id.Key()))
+		Expect(buffer.String()).To(ContainSubstring("Exporting the symbols to /layers/paketo-buildpacks_go-debug-symbols/debug-symbols"))
+	})
+
+	context("failure cases", func() {
+		context("when objcopy is not installed", func() {
+			it.Before(func() {
+				t.Setenv("PATH", "")
+			})
+
+			it("returns an error", func() {
+				_, err := build(buildCtx)
+				Expect(err).To(MatchError("objcopy is not installed: BP_GO_DEBUG_SYMBOLS needs objcopy from GNU binutils or LLVM on the builder"))
+			})
+		})
+
+		context("when go-build left no binaries", func() {
+			it.Before(func() {
+				Expect(os.RemoveAll(targets)).To(Succeed())
+			})
+
+			it("returns an error", func() {
+				_, err := build(buildCtx)
+				Expect(err).To(MatchError("go-build left no binaries in " + targets))
+			})
+		})
+
+		context("when a binary is built without DWARF", func() {
+			it.Before(func() {
+				compile("-s -w")
+			})
+
+			it("returns an error", func() {
+				_, err := build(buildCtx)
+				Expect(err).To(MatchError("server has no DWARF debug information, remove -w from BP_GO_BUILD_LDFLAGS"))
+			})
+		})
+	})
+}
@@ -0,0 +1,40 @@
+package godebugsymbols
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/paketo-buildpacks/go/debugsymbols"
+	"github.com/paketo-buildpacks/packit/v2"
+)
+
+// Detect passes when BP_GO_DEBUG_SYMBOLS is set.
+func Detect() packit.DetectFunc {
+	return func(context packit.DetectContext) (packit.DetectResult, error) {
+		enabled, err := enabled()
+		if err != nil {
+			return packit.DetectResult{}, err
+		}
+
+		if !enabled {
+			return packit.DetectResult{}, packit.Fail.WithMessage("%s is not set", debugsymbols.Env)
+		}
+
+		return packit.DetectResult{}, nil
+	}
+}
+
+func enabled() (bool, error) {
+	value, ok := os.LookupEnv(debugsymbols.Env)
+	if !ok || value == "" {
+		return false, nil
+	}
+
+	enabled, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("failed to parse %s: %w", debugsymbols.Env, err)
+	}
+
+	return enabled, nil
+}
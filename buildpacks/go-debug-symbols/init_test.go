@@ -0,0 +1,17 @@
+package godebugsymbols_test
+
+import (
+	"testing"
+
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+)
+
+func TestUnitGoDebugSymbols(t *testing.T) {
+	// the mode is configured through the environment, which the specs
+	// set with t.Setenv
+	suite := spec.New("go-debug-symbols", spec.Report(report.Terminal{}), spec.Sequential())
+	suite("Build", testBuild)
+	suite("Detect", testDetect)
+	suite.Run(t)
+}
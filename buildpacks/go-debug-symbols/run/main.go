@@ -0,0 +1,16 @@
+package main
+
+import (
+	"os"
+
+	godebugsymbols "github.com/paketo-buildpacks/go/buildpacks/go-debug-symbols"
+	"github.com/paketo-buildpacks/packit/v2"
+	"github.com/paketo-buildpacks/packit/v2/scribe"
+)
+
+func main() {
+	packit.Run(
+		godebugsymbols.Detect(),
+		godebugsymbols.Build(scribe.NewEmitter(os.Stdout).WithLevel(os.Getenv("BP_LOG_LEVEL"))),
+	)
+}
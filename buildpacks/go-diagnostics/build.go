@@ -0,0 +1,80 @@
+package godiagnostics
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/paketo-buildpacks/go/diagnostics"
+	"github.com/paketo-buildpacks/packit/v2"
+	"github.com/paketo-buildpacks/packit/v2/scribe"
+)
+
+// LayerName is the name of the build-only layer holding the listener and the
+// overlay.
+const LayerName = "diagnostics"
+
+// Build runs before go-build. It writes the diagnostics listener and an
+// overlay that adds it to every target of BP_GO_TARGETS into a layer that is
+// neither cached nor exported, and adds the overlay to BP_GO_BUILD_FLAGS for
+// go-build. The application directory is left alone, so the listener only
+// ends up in the image as part of the binaries.
+func Build(logger scribe.Emitter) packit.BuildFunc {
+	return func(context packit.BuildContext) (packit.BuildResult, error) {
+		logger.Title("%s %s", context.BuildpackInfo.Name, context.BuildpackInfo.Version)
+
+		var targets []string
+		for _, target := range strings.Split(os.Getenv("BP_GO_TARGETS"), ":") {
+			if target = strings.TrimSpace(target); target != "" {
+				targets = append(targets, target)
+			}
+		}
+
+		layer, err := context.Layers.Get(LayerName)
+		if err != nil {
+			return packit.BuildResult{}, err
+		}
+
+		layer, err = layer.Reset()
+		if err != nil {
+			return packit.BuildResult{}, err
+		}
+		layer.Build = true
+
+		overlay, err := diagnostics.Inject(context.WorkingDir, layer.Path, targets)
+		if err != nil {
+			return packit.BuildResult{}, err
+		}
+
+		flags, err := diagnostics.BuildFlags(os.Getenv("BP_GO_BUILD_FLAGS"), overlay)
+		if err != nil {
+			return packit.BuildResult{}, err
+		}
+
+		// variables set for the build take precedence over the build
+		// environment of layers, so go-build would never see the overlay
+		_, err = os.Stat(filepath.Join(context.Platform.Path, "env", "BP_GO_BUILD_FLAGS"))
+		if err == nil {
+			return packit.BuildResult{}, fmt.Errorf("BP_GO_BUILD_FLAGS is set for the build, which takes precedence over the flags of buildpacks: set it to %q to link the diagnostics listener", flags)
+		}
+		if !errors.Is(err, os.ErrNotExist) {
+			return packit.BuildResult{}, err
+		}
+
+		if len(targets) == 0 {
+			targets = []string{"."}
+		}
+
+		logger.Process("Linking the diagnostics listener into %s", strings.Join(targets, ", "))
+		layer.BuildEnv.Override("BP_GO_BUILD_FLAGS", flags)
+		logger.Subprocess("BP_GO_BUILD_FLAGS=%s", flags)
+		logger.Subprocess("Enable it at launch with %s=true or a %s binding", diagnostics.EnabledEnv, diagnostics.BindingType)
+		logger.Break()
+
+		return packit.BuildResult{
+			Layers: []packit.Layer{layer},
+		}, nil
+	}
+}
@@ -0,0 +1,139 @@
+package godiagnostics_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	godiagnostics "github.com/paketo-buildpacks/go/buildpacks/go-diagnostics"
+	"github.com/paketo-buildpacks/go/diagnostics"
+	"github.com/paketo-buildpacks/packit/v2"
+	"github.com/paketo-buildpacks/packit/v2/scribe"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testBuild(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		workingDir  string
+		platformDir string
+		buffer      *bytes.Buffer
+		build       packit.BuildFunc
+		buildCtx    packit.BuildContext
+	)
+
+	it.Before(func() {
+		workingDir = t.TempDir()
+		Expect(os.WriteFile(filepath.Join(workingDir, "main.go"), []byte("package main\n\nfunc main() {}\n"), 0644)).To(Succeed())
+		Expect(os.MkdirAll(filepath.Join(workingDir, "cmd", "worker"), os.ModePerm)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(workingDir, "cmd", "worker", "main.go"), []byte("package main\n\nfunc main() {}\n"), 0644)).To(Succeed())
+
+		platformDir = t.TempDir()
+		t.Setenv("BP_GO_TARGETS", "")
+		t.Setenv("BP_GO_BUILD_FLAGS", "")
+
+		buffer = bytes.NewBuffer(nil)
+		build = godiagnostics.Build(scribe.NewEmitter(buffer))
+		buildCtx = packit.BuildContext{
+			BuildpackInfo: packit.BuildpackInfo{
+				Name:    "Some Buildpack",
+				Version: "some-version",
+			},
+			WorkingDir: workingDir,
+			Platform:   packit.Platform{Path: platformDir},
+			Layers:     packit.Layers{Path: t.TempDir()},
+		}
+	})
+
+	it("links the listener into the targets through an overlay in a build-only layer", func() {
+		t.Setenv("BP_GO_TARGETS", "./cmd/worker")
+		t.Setenv("BP_GO_BUILD_FLAGS", "-trimpath")
+
+		result, err := build(buildCtx)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(result.Layers).To(HaveLen(1))
+		layer := result.Layers[0]
+		Expect(layer.Name).To(Equal("diagnostics"))
+		Expect(layer.Build).To(BeTrue())
+		Expect(layer.Launch).To(BeFalse())
+		Expect(layer.Cache).To(BeFalse())
+
+		overlay := filepath.Join(layer.Path, "overlay.json")
+		Expect(layer.BuildEnv).To(Equal(packit.Environment{
+			"BP_GO_BUILD_FLAGS.override": "-trimpath -overlay=" + overlay,
+		}))
+
+		content, err := os.ReadFile(overlay)
+		Expect(err).NotTo(HaveOccurred())
+
+		var written diagnostics.Overlay
+		Expect(json.Unmarshal(content, &written)).To(Succeed())
+		Expect(written.Replace).To(Equal(map[string]string{
+			filepath.Join(workingDir, "cmd", "worker", "zz_paketo_diagnostics.go"): filepath.Join(layer.Path, "diagnostics.go"),
+		}))
+
+		// the application is left alone
+		entries, err := os.ReadDir(workingDir)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(entries).To(HaveLen(2))
+
+		Expect(buffer.String()).To(ContainSubstring("Some Buildpack some-version"))
+		Expect(buffer.String()).To(ContainSubstring("Linking the diagnostics listener into ./cmd/worker"))
+		Expect(buffer.String()).To(ContainSubstring("Enable it at launch with BPL_GO_DIAGNOSTICS_ENABLED=true or a go-diagnostics binding"))
+	})
+
+	it("links the listener into the application directory without BP_GO_TARGETS", func() {
+		result, err := build(buildCtx)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(result.Layers[0].BuildEnv).To(Equal(packit.Environment{
+			"BP_GO_BUILD_FLAGS.override": "-overlay=" + filepath.Join(result.Layers[0].Path, "overlay.json"),
+		}))
+		Expect(buffer.String()).To(ContainSubstring("Linking the diagnostics listener into ."))
+	})
+
+	context("failure cases", func() {
+		context("when BP_GO_BUILD_FLAGS is set for the build", func() {
+			it.Before(func() {
+				Expect(os.MkdirAll(filepath.Join(platformDir, "env"), os.ModePerm)).To(Succeed())
+				Expect(os.WriteFile(filepath.Join(platformDir, "env", "BP_GO_BUILD_FLAGS"), []byte("-trimpath"), 0644)).To(Succeed())
+				t.Setenv("BP_GO_BUILD_FLAGS", "-trimpath")
+			})
+
+			it("returns an error with the flags to set", func() {
+				_, err := build(buildCtx)
+				Expect(err).To(MatchError(ContainSubstring(`BP_GO_BUILD_FLAGS is set for the build, which takes precedence over the flags of buildpacks: set it to "-trimpath -overlay=`)))
+			})
+		})
+
+		context("when a target is not a main package", func() {
+			it.Before(func() {
+				t.Setenv("BP_GO_TARGETS", "./internal")
+				Expect(os.MkdirAll(filepath.Join(workingDir, "internal"), os.ModePerm)).To(Succeed())
+				Expect(os.WriteFile(filepath.Join(workingDir, "internal", "internal.go"), []byte("package internal\n"), 0644)).To(Succeed())
+			})
+
+			it("returns an error", func() {
+				_, err := build(buildCtx)
+				Expect(err).To(MatchError("target internal is not a main package"))
+			})
+		})
+
+		context("when BP_GO_BUILD_FLAGS already sets an overlay", func() {
+			it.Before(func() {
+				t.Setenv("BP_GO_BUILD_FLAGS", "-overlay=mine.json")
+			})
+
+			it("returns an error", func() {
+				_, err := build(buildCtx)
+				Expect(err).To(MatchError("BP_GO_BUILD_FLAGS already sets an overlay, which cannot be combined with the diagnostics overlay"))
+			})
+		})
+	})
+}
@@ -0,0 +1,40 @@
+package godiagnostics
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/paketo-buildpacks/go/diagnostics"
+	"github.com/paketo-buildpacks/packit/v2"
+)
+
+// Detect passes when BP_GO_DIAGNOSTICS is set.
+func Detect() packit.DetectFunc {
+	return func(context packit.DetectContext) (packit.DetectResult, error) {
+		enabled, err := enabled()
+		if err != nil {
+			return packit.DetectResult{}, err
+		}
+
+		if !enabled {
+			return packit.DetectResult{}, packit.Fail.WithMessage("%s is not set", diagnostics.BuildEnv)
+		}
+
+		return packit.DetectResult{}, nil
+	}
+}
+
+func enabled() (bool, error) {
+	value, ok := os.LookupEnv(diagnostics.BuildEnv)
+	if !ok || value == "" {
+		return false, nil
+	}
+
+	enabled, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("failed to parse %s: %w", diagnostics.BuildEnv, err)
+	}
+
+	return enabled, nil
+}
@@ -0,0 +1,51 @@
+package godiagnostics_test
+
+import (
+	"testing"
+
+	godiagnostics "github.com/paketo-buildpacks/go/buildpacks/go-diagnostics"
+	"github.com/paketo-buildpacks/packit/v2"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testDetect(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		detect packit.DetectFunc
+	)
+
+	it.Before(func() {
+		detect = godiagnostics.Detect()
+	})
+
+	it("passes when BP_GO_DIAGNOSTICS is true", func() {
+		t.Setenv("BP_GO_DIAGNOSTICS", "true")
+
+		result, err := detect(packit.DetectContext{WorkingDir: t.TempDir()})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result).To(Equal(packit.DetectResult{}))
+	})
+
+	it("fails when BP_GO_DIAGNOSTICS is not set or false", func() {
+		for _, value := range []string{"", "false"} {
+			t.Setenv("BP_GO_DIAGNOSTICS", value)
+
+			_, err := detect(packit.DetectContext{WorkingDir: t.TempDir()})
+			Expect(err).To(MatchError(packit.Fail.WithMessage("BP_GO_DIAGNOSTICS is not set")))
+		}
+	})
+
+	context("failure cases", func() {
+		context("when BP_GO_DIAGNOSTICS cannot be parsed", func() {
+			it("returns an error", func() {
+				t.Setenv("BP_GO_DIAGNOSTICS", "sometimes")
+
+				_, err := detect(packit.DetectContext{WorkingDir: t.TempDir()})
+				Expect(err).To(MatchError(ContainSubstring("failed to parse BP_GO_DIAGNOSTICS")))
+			})
+		})
+	})
+}
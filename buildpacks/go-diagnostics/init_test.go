@@ -0,0 +1,17 @@
+package godiagnostics_test
+
+import (
+	"testing"
+
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+)
+
+func TestUnitGoDiagnostics(t *testing.T) {
+	// the listener is configured through the environment, which the specs
+	// set with t.Setenv
+	suite := spec.New("go-diagnostics", spec.Report(report.Terminal{}), spec.Sequential())
+	suite("Build", testBuild)
+	suite("Detect", testDetect)
+	suite.Run(t)
+}
@@ -0,0 +1,16 @@
+package main
+
+import (
+	"os"
+
+	godiagnostics "github.com/paketo-buildpacks/go/buildpacks/go-diagnostics"
+	"github.com/paketo-buildpacks/packit/v2"
+	"github.com/paketo-buildpacks/packit/v2/scribe"
+)
+
+func main() {
+	packit.Run(
+		godiagnostics.Detect(),
+		godiagnostics.Build(scribe.NewEmitter(os.Stdout).WithLevel(os.Getenv("BP_LOG_LEVEL"))),
+	)
+}
@@ -0,0 +1,118 @@
+package gogoreleaser
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/paketo-buildpacks/go/goreleaser"
+	"github.com/paketo-buildpacks/packit/v2"
+	"github.com/paketo-buildpacks/packit/v2/scribe"
+)
+
+// LayerName is the name of the build layer holding the translated variables.
+const LayerName = "goreleaser"
+
+// Build translates the builds of the GoReleaser configuration that target
+// linux on the architecture of the image, and sets the result as build
+// environment variables for go-build. Variables the user set keep
+// precedence, and the layer is neither cached nor exported.
+func Build(logger scribe.Emitter) packit.BuildFunc {
+	return func(context packit.BuildContext) (packit.BuildResult, error) {
+		logger.Title("%s %s", context.BuildpackInfo.Name, context.BuildpackInfo.Version)
+
+		path, ok, err := goreleaser.FindConfig(context.WorkingDir)
+		if err != nil {
+			return packit.BuildResult{}, err
+		}
+
+		if !ok {
+			return packit.BuildResult{}, fmt.Errorf("no GoReleaser configuration found")
+		}
+
+		config, err := goreleaser.LoadConfig(path)
+		if err != nil {
+			return packit.BuildResult{}, err
+		}
+
+		release := goreleaser.Release{
+			Tag:    "v0.0.0",
+			Commit: os.Getenv(goreleaser.CommitEnv),
+			Env:    map[string]string{},
+		}
+		if tag, ok := os.LookupEnv(goreleaser.TagEnv); ok && tag != "" {
+			release.Tag = tag
+		}
+
+		release.Date, err = releaseDate()
+		if err != nil {
+			return packit.BuildResult{}, err
+		}
+
+		for _, variable := range os.Environ() {
+			name, value, _ := strings.Cut(variable, "=")
+			release.Env[name] = value
+		}
+
+		translation, err := goreleaser.Translate(config, os.Getenv(goreleaser.IDEnv), runtime.GOARCH, release)
+		if err != nil {
+			return packit.BuildResult{}, err
+		}
+
+		layer, err := context.Layers.Get(LayerName)
+		if err != nil {
+			return packit.BuildResult{}, err
+		}
+
+		layer, err = layer.Reset()
+		if err != nil {
+			return packit.BuildResult{}, err
+		}
+		layer.Build = true
+
+		relative, err := filepath.Rel(context.WorkingDir, path)
+		if err != nil {
+			return packit.BuildResult{}, err
+		}
+
+		logger.Process("Translating build %s of %s for %s", strings.Join(translation.Builds, ", "), relative, release.Tag)
+		for _, variable := range translation.Environ() {
+			name, value, _ := strings.Cut(variable, "=")
+			if current, ok := os.LookupEnv(name); ok {
+				logger.Subprocess("%s is set, keeping %s", name, current)
+				continue
+			}
+
+			layer.BuildEnv.Override(name, value)
+			logger.Subprocess("%s", variable)
+		}
+
+		for _, warning := range translation.Warnings {
+			logger.Subprocess("Warning: %s", warning)
+		}
+		logger.Break()
+
+		return packit.BuildResult{
+			Layers: []packit.Layer{layer},
+		}, nil
+	}
+}
+
+// releaseDate is the date of the release, {{.Date}} in templates, which is
+// SOURCE_DATE_EPOCH for reproducible builds and the time of the build
+// otherwise.
+func releaseDate() (time.Time, error) {
+	if epoch, ok := os.LookupEnv("SOURCE_DATE_EPOCH"); ok {
+		seconds, err := strconv.ParseInt(epoch, 10, 64)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid SOURCE_DATE_EPOCH %q: %w", epoch, err)
+		}
+		return time.Unix(seconds, 0).UTC(), nil
+	}
+
+	return time.Now().UTC(), nil
+}
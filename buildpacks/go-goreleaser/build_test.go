@@ -0,0 +1,146 @@
+package gogoreleaser_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	gogoreleaser "github.com/paketo-buildpacks/go/buildpacks/go-goreleaser"
+	"github.com/paketo-buildpacks/packit/v2"
+	"github.com/paketo-buildpacks/packit/v2/scribe"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testBuild(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		layersDir  string
+		workingDir string
+		buffer     *bytes.Buffer
+		build      packit.BuildFunc
+		buildCtx   packit.BuildContext
+	)
+
+	it.Before(func() {
+		layersDir = t.TempDir()
+		workingDir = t.TempDir()
+
+		Expect(os.WriteFile(filepath.Join(workingDir, ".goreleaser.yaml"), []byte(`version: 2
+project_name: some-app
+builds:
+  - id: server
+    main: ./cmd/server
+    binary: app
+    env:
+      - CGO_ENABLED=0
+    flags:
+      - -trimpath
+    ldflags:
+      - -s -w -X main.version={{.Version}} -X main.commit={{.ShortCommit}} -X main.date={{.Date}}
+    goos:
+      - linux
+    goarch:
+      - `+runtime.GOARCH+`
+`), 0644)).To(Succeed())
+
+		t.Setenv("BP_GO_BUILD_GORELEASER_TAG", "v1.2.3")
+		t.Setenv("BP_GO_BUILD_GORELEASER_COMMIT", "0123456789abcdef0123456789abcdef01234567")
+		t.Setenv("SOURCE_DATE_EPOCH", "1704067200")
+		unsetenv(t, "BP_GO_TARGETS", "BP_GO_BUILD_FLAGS", "BP_GO_BUILD_LDFLAGS", "CGO_ENABLED")
+
+		buffer = bytes.NewBuffer(nil)
+		build = gogoreleaser.Build(scribe.NewEmitter(buffer))
+		buildCtx = packit.BuildContext{
+			BuildpackInfo: packit.BuildpackInfo{
+				Name:    "Some Buildpack",
+				Version: "some-version",
+			},
+			WorkingDir: workingDir,
+			Layers:     packit.Layers{Path: layersDir},
+		}
+	})
+
+	it("sets the variables of the build for go-build in a build layer", func() {
+		result, err := build(buildCtx)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(result.Layers).To(HaveLen(1))
+		layer := result.Layers[0]
+		Expect(layer.Name).To(Equal("goreleaser"))
+		Expect(layer.Build).To(BeTrue())
+		Expect(layer.Launch).To(BeFalse())
+		Expect(layer.Cache).To(BeFalse())
+		Expect(layer.BuildEnv).To(Equal(packit.Environment{
+			"BP_GO_TARGETS.override":       "./cmd/server",
+			"BP_GO_BUILD_FLAGS.override":   "-trimpath",
+			"BP_GO_BUILD_LDFLAGS.override": "-s -w -X main.version=1.2.3 -X main.commit=0123456 -X main.date=2024-01-01T00:00:00Z",
+			"CGO_ENABLED.override":         "0",
+		}))
+
+		Expect(buffer.String()).To(ContainSubstring("Some Buildpack some-version"))
+		Expect(buffer.String()).To(ContainSubstring("Translating build server of .goreleaser.yaml for v1.2.3"))
+		Expect(buffer.String()).To(ContainSubstring("BP_GO_TARGETS=./cmd/server"))
+		Expect(buffer.String()).To(ContainSubstring("Warning: build server names its binary app, go-build names it server after its package"))
+	})
+
+	it("defaults to the v0.0.0 tag", func() {
+		t.Setenv("BP_GO_BUILD_GORELEASER_TAG", "")
+
+		result, err := build(buildCtx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Layers[0].BuildEnv).To(HaveKeyWithValue("BP_GO_BUILD_LDFLAGS.override", ContainSubstring("-X main.version=0.0.0")))
+	})
+
+	context("when the user sets a variable", func() {
+		it.Before(func() {
+			t.Setenv("BP_GO_BUILD_LDFLAGS", "-s -w")
+		})
+
+		it("keeps the value of the user", func() {
+			result, err := build(buildCtx)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(result.Layers[0].BuildEnv).NotTo(HaveKey("BP_GO_BUILD_LDFLAGS.override"))
+			Expect(buffer.String()).To(ContainSubstring("BP_GO_BUILD_LDFLAGS is set, keeping -s -w"))
+		})
+	})
+
+	context("failure cases", func() {
+		context("when no build targets the architecture of the image", func() {
+			it.Before(func() {
+				Expect(os.WriteFile(filepath.Join(workingDir, ".goreleaser.yaml"), []byte("version: 2\nbuilds:\n  - id: server\n    goos: [darwin]\n"), 0644)).To(Succeed())
+			})
+
+			it("returns an error", func() {
+				_, err := build(buildCtx)
+				Expect(err).To(HaveOccurred())
+			})
+		})
+
+		context("when SOURCE_DATE_EPOCH is invalid", func() {
+			it.Before(func() {
+				t.Setenv("SOURCE_DATE_EPOCH", "yesterday")
+			})
+
+			it("returns an error", func() {
+				_, err := build(buildCtx)
+				Expect(err).To(MatchError(ContainSubstring(`invalid SOURCE_DATE_EPOCH "yesterday"`)))
+			})
+		})
+	})
+}
+
+// unsetenv unsets variables for the duration of a spec, since t.Setenv can
+// only set them.
+func unsetenv(t *testing.T, names ...string) {
+	for _, name := range names {
+		t.Setenv(name, "")
+		Expect := NewWithT(t).Expect
+		Expect(os.Unsetenv(name)).To(Succeed())
+	}
+}
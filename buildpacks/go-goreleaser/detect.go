@@ -0,0 +1,50 @@
+package gogoreleaser
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/paketo-buildpacks/go/goreleaser"
+	"github.com/paketo-buildpacks/packit/v2"
+)
+
+// Detect passes when BP_GO_BUILD_GORELEASER is set and the application has a
+// GoReleaser configuration.
+func Detect() packit.DetectFunc {
+	return func(context packit.DetectContext) (packit.DetectResult, error) {
+		enabled, err := enabled()
+		if err != nil {
+			return packit.DetectResult{}, err
+		}
+
+		if !enabled {
+			return packit.DetectResult{}, packit.Fail.WithMessage("%s is not set", goreleaser.EnabledEnv)
+		}
+
+		_, ok, err := goreleaser.FindConfig(context.WorkingDir)
+		if err != nil {
+			return packit.DetectResult{}, err
+		}
+
+		if !ok {
+			return packit.DetectResult{}, packit.Fail.WithMessage("no GoReleaser configuration found")
+		}
+
+		return packit.DetectResult{}, nil
+	}
+}
+
+func enabled() (bool, error) {
+	value, ok := os.LookupEnv(goreleaser.EnabledEnv)
+	if !ok || value == "" {
+		return false, nil
+	}
+
+	enabled, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("failed to parse %s: %w", goreleaser.EnabledEnv, err)
+	}
+
+	return enabled, nil
+}
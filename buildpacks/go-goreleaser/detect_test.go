@@ -0,0 +1,70 @@
+package gogoreleaser_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	gogoreleaser "github.com/paketo-buildpacks/go/buildpacks/go-goreleaser"
+	"github.com/paketo-buildpacks/packit/v2"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testDetect(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		workingDir string
+		detect     packit.DetectFunc
+	)
+
+	it.Before(func() {
+		workingDir = t.TempDir()
+		Expect(os.WriteFile(filepath.Join(workingDir, ".goreleaser.yaml"), []byte("version: 2\n"), 0644)).To(Succeed())
+
+		detect = gogoreleaser.Detect()
+	})
+
+	it("passes when enabled and there is a GoReleaser configuration", func() {
+		t.Setenv("BP_GO_BUILD_GORELEASER", "true")
+
+		result, err := detect(packit.DetectContext{WorkingDir: workingDir})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result).To(Equal(packit.DetectResult{}))
+	})
+
+	it("fails when BP_GO_BUILD_GORELEASER is not set", func() {
+		t.Setenv("BP_GO_BUILD_GORELEASER", "")
+
+		_, err := detect(packit.DetectContext{WorkingDir: workingDir})
+		Expect(err).To(MatchError(packit.Fail.WithMessage("BP_GO_BUILD_GORELEASER is not set")))
+	})
+
+	it("fails when BP_GO_BUILD_GORELEASER is false", func() {
+		t.Setenv("BP_GO_BUILD_GORELEASER", "false")
+
+		_, err := detect(packit.DetectContext{WorkingDir: workingDir})
+		Expect(err).To(MatchError(packit.Fail.WithMessage("BP_GO_BUILD_GORELEASER is not set")))
+	})
+
+	it("fails when there is no GoReleaser configuration", func() {
+		t.Setenv("BP_GO_BUILD_GORELEASER", "true")
+		Expect(os.Remove(filepath.Join(workingDir, ".goreleaser.yaml"))).To(Succeed())
+
+		_, err := detect(packit.DetectContext{WorkingDir: workingDir})
+		Expect(err).To(MatchError(packit.Fail.WithMessage("no GoReleaser configuration found")))
+	})
+
+	context("failure cases", func() {
+		context("when BP_GO_BUILD_GORELEASER cannot be parsed", func() {
+			it("returns an error", func() {
+				t.Setenv("BP_GO_BUILD_GORELEASER", "sometimes")
+
+				_, err := detect(packit.DetectContext{WorkingDir: workingDir})
+				Expect(err).To(MatchError(ContainSubstring("failed to parse BP_GO_BUILD_GORELEASER")))
+			})
+		})
+	})
+}
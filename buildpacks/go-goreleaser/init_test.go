@@ -0,0 +1,17 @@
+package gogoreleaser_test
+
+import (
+	"testing"
+
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+)
+
+func TestUnitGoGoReleaser(t *testing.T) {
+	// the release is configured through the environment, which the specs set
+	// with t.Setenv
+	suite := spec.New("go-goreleaser", spec.Report(report.Terminal{}), spec.Sequential())
+	suite("Build", testBuild)
+	suite("Detect", testDetect)
+	suite.Run(t)
+}
@@ -0,0 +1,16 @@
+package main
+
+import (
+	"os"
+
+	gogoreleaser "github.com/paketo-buildpacks/go/buildpacks/go-goreleaser"
+	"github.com/paketo-buildpacks/packit/v2"
+	"github.com/paketo-buildpacks/packit/v2/scribe"
+)
+
+func main() {
+	packit.Run(
+		gogoreleaser.Detect(),
+		gogoreleaser.Build(scribe.NewEmitter(os.Stdout).WithLevel(os.Getenv("BP_LOG_LEVEL"))),
+	)
+}
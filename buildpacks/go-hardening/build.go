@@ -0,0 +1,91 @@
+package gohardening
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/paketo-buildpacks/go/hardening"
+	"github.com/paketo-buildpacks/packit/v2"
+	"github.com/paketo-buildpacks/packit/v2/scribe"
+)
+
+// LayerName is the name of the launch layer holding the relocated writable
+// variables.
+const LayerName = "hardening"
+
+// Build fails when the build runs as root or when BP_KEEP_FILES keeps files
+// that would need write access on a read-only root filesystem, and points the
+// variables Go programs write to at BP_GO_WRITABLE_DIR when the image runs.
+// It runs before go-build, so that the build fails before anything is
+// compiled.
+//
+// CNB_USER_ID is the user of the build image that the lifecycle runs the
+// buildpacks as, so the root check only covers the build user. The image runs
+// as the user of the run image, which buildpacks cannot see, and which
+// `cmd/hardening verify` checks once the image is exported.
+func Build(logger scribe.Emitter) packit.BuildFunc {
+	return func(context packit.BuildContext) (packit.BuildResult, error) {
+		logger.Title("%s %s", context.BuildpackInfo.Name, context.BuildpackInfo.Version)
+
+		if os.Getenv("CNB_USER_ID") == "0" {
+			return packit.BuildResult{}, fmt.Errorf("the build runs as root (CNB_USER_ID=0), the builder must use a non-root user")
+		}
+
+		logger.Process("Checking the files BP_KEEP_FILES keeps")
+		violations, err := hardening.CheckKeptFiles(context.WorkingDir, os.Getenv("BP_KEEP_FILES"), hardening.SplitGlobs(os.Getenv(hardening.WritablePathsEnv)))
+		if err != nil {
+			return packit.BuildResult{}, err
+		}
+
+		if len(violations) > 0 {
+			var lines []string
+			for _, violation := range violations {
+				lines = append(lines, "  "+violation.String())
+			}
+
+			return packit.BuildResult{}, fmt.Errorf("BP_KEEP_FILES keeps files that would need write access on a read-only root filesystem:\n%s\nwrite them to %s instead", strings.Join(lines, "\n"), hardening.WritableDirEnv)
+		}
+		logger.Subprocess("No kept file needs write access")
+		logger.Break()
+
+		dir := os.Getenv(hardening.WritableDirEnv)
+		if dir == "" {
+			dir = hardening.DefaultWritableDir
+		}
+
+		environment, err := hardening.LaunchEnvironment(dir)
+		if err != nil {
+			return packit.BuildResult{}, err
+		}
+
+		layer, err := context.Layers.Get(LayerName)
+		if err != nil {
+			return packit.BuildResult{}, err
+		}
+
+		layer, err = layer.Reset()
+		if err != nil {
+			return packit.BuildResult{}, err
+		}
+		layer.Launch = true
+
+		var names []string
+		for name := range environment {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		logger.Process("Relocating the writable paths to %s", dir)
+		for _, name := range names {
+			layer.LaunchEnv.Default(name, environment[name])
+			logger.Subprocess("%s -> %s", name, environment[name])
+		}
+		logger.Break()
+
+		return packit.BuildResult{
+			Layers: []packit.Layer{layer},
+		}, nil
+	}
+}
@@ -0,0 +1,126 @@
+package gohardening_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	gohardening "github.com/paketo-buildpacks/go/buildpacks/go-hardening"
+	"github.com/paketo-buildpacks/packit/v2"
+	"github.com/paketo-buildpacks/packit/v2/scribe"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testBuild(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		layersDir  string
+		workingDir string
+		buffer     *bytes.Buffer
+		build      packit.BuildFunc
+		buildCtx   packit.BuildContext
+	)
+
+	it.Before(func() {
+		layersDir = t.TempDir()
+		workingDir = t.TempDir()
+
+		Expect(os.WriteFile(filepath.Join(workingDir, "config.yml"), []byte("greeting: hello\n"), 0644)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(workingDir, "app.log"), nil, 0644)).To(Succeed())
+
+		t.Setenv("CNB_USER_ID", "1002")
+		t.Setenv("BP_KEEP_FILES", "config.yml")
+		t.Setenv("BP_GO_WRITABLE_DIR", "/var/run/app")
+		t.Setenv("BP_GO_WRITABLE_PATHS", "")
+
+		buffer = bytes.NewBuffer(nil)
+		build = gohardening.Build(scribe.NewEmitter(buffer))
+		buildCtx = packit.BuildContext{
+			BuildpackInfo: packit.BuildpackInfo{
+				Name:    "Some Buildpack",
+				Version: "some-version",
+			},
+			WorkingDir: workingDir,
+			Layers:     packit.Layers{Path: layersDir},
+		}
+	})
+
+	it("points the writable variables at the writable directory in a launch layer", func() {
+		result, err := build(buildCtx)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(result.Layers).To(HaveLen(1))
+		layer := result.Layers[0]
+		Expect(layer.Name).To(Equal("hardening"))
+		Expect(layer.Launch).To(BeTrue())
+		Expect(layer.Build).To(BeFalse())
+		Expect(layer.LaunchEnv).To(Equal(packit.Environment{
+			"TMPDIR.default":         "/var/run/app",
+			"GOCOVERDIR.default":     "/var/run/app",
+			"XDG_CACHE_HOME.default": "/var/run/app",
+		}))
+
+		Expect(buffer.String()).To(ContainSubstring("Some Buildpack some-version"))
+		Expect(buffer.String()).To(ContainSubstring("No kept file needs write access"))
+		Expect(buffer.String()).To(ContainSubstring("Relocating the writable paths to /var/run/app"))
+		Expect(buffer.String()).To(ContainSubstring("TMPDIR -> /var/run/app"))
+	})
+
+	it("defaults to /tmp", func() {
+		t.Setenv("BP_GO_WRITABLE_DIR", "")
+
+		result, err := build(buildCtx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Layers[0].LaunchEnv).To(HaveKeyWithValue("TMPDIR.default", "/tmp"))
+	})
+
+	context("failure cases", func() {
+		context("when BP_KEEP_FILES keeps a file the app writes to", func() {
+			it.Before(func() {
+				t.Setenv("BP_KEEP_FILES", "config.yml:*.log")
+			})
+
+			it("returns an error", func() {
+				_, err := build(buildCtx)
+				Expect(err).To(MatchError("BP_KEEP_FILES keeps files that would need write access on a read-only root filesystem:\n  app.log looks like a file the app writes to (*.log)\nwrite them to BP_GO_WRITABLE_DIR instead"))
+			})
+		})
+
+		context("when BP_KEEP_FILES keeps a writable path", func() {
+			it.Before(func() {
+				t.Setenv("BP_GO_WRITABLE_PATHS", "config.yml")
+			})
+
+			it("returns an error", func() {
+				_, err := build(buildCtx)
+				Expect(err).To(MatchError(ContainSubstring("config.yml")))
+			})
+		})
+
+		context("when the build runs as root", func() {
+			it.Before(func() {
+				t.Setenv("CNB_USER_ID", "0")
+			})
+
+			it("returns an error", func() {
+				_, err := build(buildCtx)
+				Expect(err).To(MatchError("the build runs as root (CNB_USER_ID=0), the builder must use a non-root user"))
+			})
+		})
+
+		context("when the writable directory is part of the image", func() {
+			it.Before(func() {
+				t.Setenv("BP_GO_WRITABLE_DIR", "/workspace/tmp")
+			})
+
+			it("returns an error", func() {
+				_, err := build(buildCtx)
+				Expect(err).To(MatchError("writable directory /workspace/tmp is inside /workspace, which is part of the image"))
+			})
+		})
+	})
+}
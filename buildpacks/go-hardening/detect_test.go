@@ -0,0 +1,51 @@
+package gohardening_test
+
+import (
+	"testing"
+
+	gohardening "github.com/paketo-buildpacks/go/buildpacks/go-hardening"
+	"github.com/paketo-buildpacks/packit/v2"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testDetect(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		detect packit.DetectFunc
+	)
+
+	it.Before(func() {
+		detect = gohardening.Detect()
+	})
+
+	it("passes when BP_GO_HARDENING is true", func() {
+		t.Setenv("BP_GO_HARDENING", "true")
+
+		result, err := detect(packit.DetectContext{WorkingDir: t.TempDir()})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result).To(Equal(packit.DetectResult{}))
+	})
+
+	it("fails when BP_GO_HARDENING is not set or false", func() {
+		for _, value := range []string{"", "false"} {
+			t.Setenv("BP_GO_HARDENING", value)
+
+			_, err := detect(packit.DetectContext{WorkingDir: t.TempDir()})
+			Expect(err).To(MatchError(packit.Fail.WithMessage("BP_GO_HARDENING is not set")))
+		}
+	})
+
+	context("failure cases", func() {
+		context("when BP_GO_HARDENING cannot be parsed", func() {
+			it("returns an error", func() {
+				t.Setenv("BP_GO_HARDENING", "sometimes")
+
+				_, err := detect(packit.DetectContext{WorkingDir: t.TempDir()})
+				Expect(err).To(MatchError(ContainSubstring("failed to parse BP_GO_HARDENING")))
+			})
+		})
+	})
+}
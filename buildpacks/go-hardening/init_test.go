@@ -0,0 +1,17 @@
+package gohardening_test
+
+import (
+	"testing"
+
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+)
+
+func TestUnitGoHardening(t *testing.T) {
+	// the checks are configured through the environment, which the specs
+	// set with t.Setenv
+	suite := spec.New("go-hardening", spec.Report(report.Terminal{}), spec.Sequential())
+	suite("Build", testBuild)
+	suite("Detect", testDetect)
+	suite.Run(t)
+}
@@ -0,0 +1,16 @@
+package main
+
+import (
+	"os"
+
+	gohardening "github.com/paketo-buildpacks/go/buildpacks/go-hardening"
+	"github.com/paketo-buildpacks/packit/v2"
+	"github.com/paketo-buildpacks/packit/v2/scribe"
+)
+
+func main() {
+	packit.Run(
+		gohardening.Detect(),
+		gohardening.Build(scribe.NewEmitter(os.Stdout).WithLevel(os.Getenv("BP_LOG_LEVEL"))),
+	)
+}
@@ -0,0 +1,102 @@
+package gohealth
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+
+	"github.com/paketo-buildpacks/go/health"
+	"github.com/paketo-buildpacks/packit/v2"
+	"github.com/paketo-buildpacks/packit/v2/fs"
+	"github.com/paketo-buildpacks/packit/v2/scribe"
+)
+
+// LayerName is the name of the launch layer holding the probe.
+const LayerName = "probe"
+
+// Build runs after go-build. It installs the probe of this buildpack in a
+// launch layer, assigns the health process type that runs it against
+// BP_GO_HEALTH_CHECK_TARGET, and labels the image with its health check.
+// Buildpacks cannot set the health check of an image, so the label is
+// applied by the platform, such as with cmd/health apply.
+func Build(logger scribe.Emitter) packit.BuildFunc {
+	return func(context packit.BuildContext) (packit.BuildResult, error) {
+		logger.Title("%s %s", context.BuildpackInfo.Name, context.BuildpackInfo.Version)
+
+		metadata := health.Metadata{
+			Target:      os.Getenv(health.BuildTargetEnv),
+			Interval:    os.Getenv(health.BuildIntervalEnv),
+			Timeout:     os.Getenv(health.BuildTimeoutEnv),
+			StartPeriod: os.Getenv(health.BuildStartPeriodEnv),
+		}
+
+		if value := os.Getenv(health.BuildRetriesEnv); value != "" {
+			retries, err := strconv.Atoi(value)
+			if err != nil {
+				return packit.BuildResult{}, fmt.Errorf("failed to parse %s: %w", health.BuildRetriesEnv, err)
+			}
+			metadata.Retries = retries
+		}
+
+		layer, err := context.Layers.Get(LayerName)
+		if err != nil {
+			return packit.BuildResult{}, err
+		}
+
+		layer, err = layer.Reset()
+		if err != nil {
+			return packit.BuildResult{}, err
+		}
+		layer.Launch = true
+
+		metadata.Probe = filepath.Join(layer.Path, "bin", "health-probe")
+		_, err = metadata.HealthConfig()
+		if err != nil {
+			return packit.BuildResult{}, err
+		}
+
+		logger.Process("Installing the health probe")
+		err = os.MkdirAll(filepath.Dir(metadata.Probe), os.ModePerm)
+		if err != nil {
+			return packit.BuildResult{}, err
+		}
+
+		err = fs.Copy(filepath.Join(context.CNBPath, "bin", "linux-"+runtime.GOARCH, "tools", "health-probe"), metadata.Probe)
+		if err != nil {
+			return packit.BuildResult{}, fmt.Errorf("failed to install the health probe: %w", err)
+		}
+		logger.Subprocess("Installed to %s", metadata.Probe)
+		logger.Break()
+
+		// the process type runs through the launcher, so the target is a
+		// default that BPL_GO_HEALTH_CHECK_TARGET overrides when the image runs
+		layer.ProcessLaunchEnv[health.ProcessType] = packit.Environment{}
+		layer.ProcessLaunchEnv[health.ProcessType].Default(health.TargetEnv, metadata.Target)
+
+		logger.Process("Assigning process type %s to probe %s", health.ProcessType, metadata.Target)
+		logger.Subprocess("%s=%s", health.TargetEnv, metadata.Target)
+		logger.Break()
+
+		logger.Process("Labelling the health check")
+		logger.Subprocess("%s=%s", health.Label, metadata)
+		logger.Break()
+
+		return packit.BuildResult{
+			Layers: []packit.Layer{layer},
+			Launch: packit.LaunchMetadata{
+				Processes: []packit.Process{
+					{
+						Type:    health.ProcessType,
+						Command: metadata.Probe,
+						Direct:  true,
+					},
+				},
+				Labels: map[string]string{
+					health.Label: metadata.String(),
+				},
+			},
+		}, nil
+	}
+}
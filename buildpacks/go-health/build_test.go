@@ -0,0 +1,140 @@
+package gohealth_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	gohealth "github.com/paketo-buildpacks/go/buildpacks/go-health"
+	"github.com/paketo-buildpacks/go/health"
+	"github.com/paketo-buildpacks/packit/v2"
+	"github.com/paketo-buildpacks/packit/v2/scribe"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testBuild(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		buffer   *bytes.Buffer
+		build    packit.BuildFunc
+		buildCtx packit.BuildContext
+	)
+
+	it.Before(func() {
+		cnbDir := t.TempDir()
+		tools := filepath.Join(cnbDir, "bin", "linux-"+runtime.GOARCH, "tools")
+		Expect(os.MkdirAll(tools, os.ModePerm)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(tools, "health-probe"), []byte("probe"), 0755)).To(Succeed())
+
+		t.Setenv("BP_GO_HEALTH_CHECK_TARGET", "http://localhost:8080/healthz")
+		t.Setenv("BP_GO_HEALTH_CHECK_INTERVAL", "")
+		t.Setenv("BP_GO_HEALTH_CHECK_TIMEOUT", "")
+		t.Setenv("BP_GO_HEALTH_CHECK_START_PERIOD", "")
+		t.Setenv("BP_GO_HEALTH_CHECK_RETRIES", "")
+
+		buffer = bytes.NewBuffer(nil)
+		build = gohealth.Build(scribe.NewEmitter(buffer))
+		buildCtx = packit.BuildContext{
+			BuildpackInfo: packit.BuildpackInfo{
+				Name:    "Some Buildpack",
+				Version: "some-version",
+			},
+			CNBPath:    cnbDir,
+			WorkingDir: t.TempDir(),
+			Layers:     packit.Layers{Path: t.TempDir()},
+		}
+	})
+
+	it("installs the probe, assigns the health process type and labels the health check", func() {
+		t.Setenv("BP_GO_HEALTH_CHECK_INTERVAL", "10s")
+		t.Setenv("BP_GO_HEALTH_CHECK_TIMEOUT", "2s")
+		t.Setenv("BP_GO_HEALTH_CHECK_START_PERIOD", "30s")
+		t.Setenv("BP_GO_HEALTH_CHECK_RETRIES", "3")
+
+		result, err := build(buildCtx)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(result.Layers).To(HaveLen(1))
+		layer := result.Layers[0]
+		Expect(layer.Name).To(Equal("probe"))
+		Expect(layer.Launch).To(BeTrue())
+		Expect(layer.Build).To(BeFalse())
+		Expect(layer.Cache).To(BeFalse())
+		Expect(layer.ProcessLaunchEnv).To(Equal(map[string]packit.Environment{
+			"health": {"BPL_GO_HEALTH_CHECK_TARGET.default": "http://localhost:8080/healthz"},
+		}))
+
+		probe := filepath.Join(layer.Path, "bin", "health-probe")
+		Expect(os.ReadFile(probe)).To(Equal([]byte("probe")))
+
+		Expect(result.Launch.Processes).To(Equal([]packit.Process{
+			{Type: "health", Command: probe, Direct: true},
+		}))
+
+		metadata, err := health.ParseMetadata(result.Launch.Labels["io.paketo.go.health-check"])
+		Expect(err).NotTo(HaveOccurred())
+		Expect(metadata).To(Equal(health.Metadata{
+			Probe:       probe,
+			Target:      "http://localhost:8080/healthz",
+			Interval:    "10s",
+			Timeout:     "2s",
+			StartPeriod: "30s",
+			Retries:     3,
+		}))
+
+		Expect(buffer.String()).To(ContainSubstring("Some Buildpack some-version"))
+		Expect(buffer.String()).To(ContainSubstring("Assigning process type health to probe http://localhost:8080/healthz"))
+		Expect(buffer.String()).To(ContainSubstring("Labelling the health check"))
+	})
+
+	context("failure cases", func() {
+		context("when the target is invalid", func() {
+			it.Before(func() {
+				t.Setenv("BP_GO_HEALTH_CHECK_TARGET", "ftp://localhost:21")
+			})
+
+			it("returns an error", func() {
+				_, err := build(buildCtx)
+				Expect(err).To(MatchError(ContainSubstring(`ftp://localhost:21`)))
+			})
+		})
+
+		context("when a duration is invalid", func() {
+			it.Before(func() {
+				t.Setenv("BP_GO_HEALTH_CHECK_INTERVAL", "often")
+			})
+
+			it("returns an error", func() {
+				_, err := build(buildCtx)
+				Expect(err).To(MatchError(ContainSubstring(`invalid health check interval "often"`)))
+			})
+		})
+
+		context("when BP_GO_HEALTH_CHECK_RETRIES cannot be parsed", func() {
+			it.Before(func() {
+				t.Setenv("BP_GO_HEALTH_CHECK_RETRIES", "a few")
+			})
+
+			it("returns an error", func() {
+				_, err := build(buildCtx)
+				Expect(err).To(MatchError(ContainSubstring("failed to parse BP_GO_HEALTH_CHECK_RETRIES")))
+			})
+		})
+
+		context("when the probe is missing from the buildpack", func() {
+			it.Before(func() {
+				buildCtx.CNBPath = t.TempDir()
+			})
+
+			it("returns an error", func() {
+				_, err := build(buildCtx)
+				Expect(err).To(MatchError(ContainSubstring("failed to install the health probe")))
+			})
+		})
+	})
+}
@@ -0,0 +1,19 @@
+package gohealth
+
+import (
+	"os"
+
+	"github.com/paketo-buildpacks/go/health"
+	"github.com/paketo-buildpacks/packit/v2"
+)
+
+// Detect passes when BP_GO_HEALTH_CHECK_TARGET is set.
+func Detect() packit.DetectFunc {
+	return func(context packit.DetectContext) (packit.DetectResult, error) {
+		if os.Getenv(health.BuildTargetEnv) == "" {
+			return packit.DetectResult{}, packit.Fail.WithMessage("%s is not set", health.BuildTargetEnv)
+		}
+
+		return packit.DetectResult{}, nil
+	}
+}
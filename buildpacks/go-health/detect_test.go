@@ -0,0 +1,38 @@
+package gohealth_test
+
+import (
+	"testing"
+
+	gohealth "github.com/paketo-buildpacks/go/buildpacks/go-health"
+	"github.com/paketo-buildpacks/packit/v2"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testDetect(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		detect packit.DetectFunc
+	)
+
+	it.Before(func() {
+		detect = gohealth.Detect()
+	})
+
+	it("passes when BP_GO_HEALTH_CHECK_TARGET is set", func() {
+		t.Setenv("BP_GO_HEALTH_CHECK_TARGET", "http://localhost:8080/healthz")
+
+		result, err := detect(packit.DetectContext{WorkingDir: t.TempDir()})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result).To(Equal(packit.DetectResult{}))
+	})
+
+	it("fails when BP_GO_HEALTH_CHECK_TARGET is not set", func() {
+		t.Setenv("BP_GO_HEALTH_CHECK_TARGET", "")
+
+		_, err := detect(packit.DetectContext{WorkingDir: t.TempDir()})
+		Expect(err).To(MatchError(packit.Fail.WithMessage("BP_GO_HEALTH_CHECK_TARGET is not set")))
+	})
+}
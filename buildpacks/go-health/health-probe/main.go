@@ -0,0 +1,64 @@
+// health-probe checks that an application is healthy and exits with 0 when it
+// is and 1 when it is not. It is built as a static binary that runs in run
+// images without a shell or curl:
+//
+//	CGO_ENABLED=0 go build -trimpath -ldflags="-s -w" ./buildpacks/go-health/health-probe
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/paketo-buildpacks/go/health"
+)
+
+func main() {
+	err := run(os.Args[1:])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+// run probes the target given as the only argument, or the target in
+// BPL_GO_HEALTH_CHECK_TARGET.
+func run(args []string) error {
+	value := os.Getenv(health.TargetEnv)
+	if len(args) > 0 {
+		value = args[0]
+	}
+
+	if value == "" {
+		return fmt.Errorf("missing target: pass it as an argument or set %s", health.TargetEnv)
+	}
+
+	target, err := health.ParseTarget(value)
+	if err != nil {
+		return err
+	}
+
+	timeout := health.DefaultTimeout
+	if value := os.Getenv(health.TimeoutEnv); value != "" {
+		timeout, err = time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("invalid %s %q: %w", health.TimeoutEnv, value, err)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	err = health.Probe(ctx, target)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return fmt.Errorf("%s did not respond within %s", target, timeout)
+		}
+		return err
+	}
+
+	fmt.Printf("%s is healthy\n", target)
+	return nil
+}
@@ -0,0 +1,17 @@
+package gohealth_test
+
+import (
+	"testing"
+
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+)
+
+func TestUnitGoHealth(t *testing.T) {
+	// the health check is configured through the environment, which the
+	// specs set with t.Setenv
+	suite := spec.New("go-health", spec.Report(report.Terminal{}), spec.Sequential())
+	suite("Build", testBuild)
+	suite("Detect", testDetect)
+	suite.Run(t)
+}
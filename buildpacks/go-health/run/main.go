@@ -0,0 +1,16 @@
+package main
+
+import (
+	"os"
+
+	gohealth "github.com/paketo-buildpacks/go/buildpacks/go-health"
+	"github.com/paketo-buildpacks/packit/v2"
+	"github.com/paketo-buildpacks/packit/v2/scribe"
+)
+
+func main() {
+	packit.Run(
+		gohealth.Detect(),
+		gohealth.Build(scribe.NewEmitter(os.Stdout).WithLevel(os.Getenv("BP_LOG_LEVEL"))),
+	)
+}
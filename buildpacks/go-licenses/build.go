@@ -0,0 +1,124 @@
+package golicenses
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/paketo-buildpacks/go/licenses"
+	"github.com/paketo-buildpacks/packit/v2"
+	"github.com/paketo-buildpacks/packit/v2/fs"
+	"github.com/paketo-buildpacks/packit/v2/scribe"
+)
+
+// DenyListEnv configures the deny-list. It defaults to
+// licenses.DefaultDenyList.
+const DenyListEnv = "BP_GO_LICENSE_DENY_LIST"
+
+// LayerName is the name of the launch layer holding the license report.
+const LayerName = "licenses"
+
+// ReportFile is the name of the license report in the layer.
+const ReportFile = "licenses.json"
+
+// Build takes the license inventory of the modules of the application, writes
+// it to a launch layer and fails the build when a module is published under a
+// license on the deny-list. Modules are read from the vendor directory, which
+// go-mod-vendor fills, or else from the module cache in GOMODCACHE or the
+// mod-cache layer of go-mod-vendor.
+func Build(logger scribe.Emitter) packit.BuildFunc {
+	return func(context packit.BuildContext) (packit.BuildResult, error) {
+		logger.Title("%s %s", context.BuildpackInfo.Name, context.BuildpackInfo.Version)
+
+		deny := licenses.DefaultDenyList
+		if value, ok := os.LookupEnv(DenyListEnv); ok {
+			deny = licenses.ParseDenyList(value)
+		}
+
+		modules, err := findModules(context.WorkingDir, filepath.Dir(context.Layers.Path))
+		if err != nil {
+			return packit.BuildResult{}, err
+		}
+
+		report, err := licenses.Inventory(modules)
+		if err != nil {
+			return packit.BuildResult{}, err
+		}
+
+		logger.Process("Taking the license inventory of %d modules", len(report.Modules))
+		for _, module := range report.Modules {
+			logger.Subprocess("%s@%s: %s", module.Path, module.Version, strings.Join(module.Licenses, ", "))
+		}
+		logger.Break()
+
+		violations := licenses.Policy{Deny: deny}.Check(report)
+		if len(violations) > 0 {
+			var lines []string
+			for _, violation := range violations {
+				lines = append(lines, "  "+violation.String())
+			}
+
+			return packit.BuildResult{}, fmt.Errorf("modules are published under denied licenses (%s=%s):\n%s", DenyListEnv, strings.Join(deny, ","), strings.Join(lines, "\n"))
+		}
+
+		layer, err := context.Layers.Get(LayerName)
+		if err != nil {
+			return packit.BuildResult{}, err
+		}
+
+		layer, err = layer.Reset()
+		if err != nil {
+			return packit.BuildResult{}, err
+		}
+		layer.Launch = true
+
+		content, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return packit.BuildResult{}, err
+		}
+
+		err = os.WriteFile(filepath.Join(layer.Path, ReportFile), content, 0644)
+		if err != nil {
+			return packit.BuildResult{}, err
+		}
+
+		logger.Process("Writing the license report to %s", filepath.Join(layer.Path, ReportFile))
+		logger.Break()
+
+		return packit.BuildResult{
+			Layers: []packit.Layer{layer},
+		}, nil
+	}
+}
+
+func findModules(workingDir, layersDir string) ([]licenses.Module, error) {
+	vendorDir := filepath.Join(workingDir, "vendor")
+	exists, err := fs.Exists(filepath.Join(vendorDir, "modules.txt"))
+	if err != nil {
+		return nil, err
+	}
+
+	if exists {
+		return licenses.VendoredModules(vendorDir)
+	}
+
+	goSum := filepath.Join(workingDir, "go.sum")
+	exists, err = fs.Exists(goSum)
+	if err != nil {
+		return nil, err
+	}
+
+	// a module without dependencies has no go.sum
+	if !exists {
+		return nil, nil
+	}
+
+	modCache := os.Getenv("GOMODCACHE")
+	if modCache == "" {
+		modCache = filepath.Join(layersDir, "paketo-buildpacks_go-mod-vendor", "mod-cache")
+	}
+
+	return licenses.CachedModules(modCache, goSum)
+}
@@ -0,0 +1,169 @@
+package golicenses_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	golicenses "github.com/paketo-buildpacks/go/buildpacks/go-licenses"
+	"github.com/paketo-buildpacks/go/licenses"
+	"github.com/paketo-buildpacks/packit/v2"
+	"github.com/paketo-buildpacks/packit/v2/scribe"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testBuild(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		testdata  string
+		layersDir string
+		buffer    *bytes.Buffer
+		build     packit.BuildFunc
+		buildCtx  packit.BuildContext
+	)
+
+	readReport := func(result packit.BuildResult) licenses.Report {
+		Expect(result.Layers).To(HaveLen(1))
+		Expect(result.Layers[0].Name).To(Equal("licenses"))
+		Expect(result.Layers[0].Launch).To(BeTrue())
+
+		content, err := os.ReadFile(filepath.Join(result.Layers[0].Path, "licenses.json"))
+		Expect(err).NotTo(HaveOccurred())
+
+		var report licenses.Report
+		Expect(json.Unmarshal(content, &report)).To(Succeed())
+		return report
+	}
+
+	it.Before(func() {
+		var err error
+		testdata, err = filepath.Abs(filepath.Join("..", "..", "licenses", "testdata"))
+		Expect(err).NotTo(HaveOccurred())
+
+		layersDir = t.TempDir()
+
+		buffer = bytes.NewBuffer(nil)
+		build = golicenses.Build(scribe.NewEmitter(buffer))
+		buildCtx = packit.BuildContext{
+			BuildpackInfo: packit.BuildpackInfo{
+				Name:    "Some Buildpack",
+				Version: "some-version",
+			},
+			WorkingDir: testdata,
+			Layers:     packit.Layers{Path: filepath.Join(layersDir, "paketo-buildpacks_go-licenses")},
+		}
+	})
+
+	context("when the modules are vendored", func() {
+		it.Before(func() {
+			t.Setenv("BP_GO_LICENSE_DENY_LIST", "AGPL-*")
+		})
+
+		it("writes the license report to a launch layer", func() {
+			result, err := build(buildCtx)
+			Expect(err).NotTo(HaveOccurred())
+
+			var summary []string
+			for _, module := range readReport(result).Modules {
+				summary = append(summary, module.Path+" "+module.Licenses[0])
+			}
+			Expect(summary).To(Equal([]string{
+				"example.com/gpl GPL-3.0-only",
+				"example.com/local MIT",
+				"example.com/mit MIT",
+				"example.com/none UNKNOWN",
+			}))
+
+			Expect(buffer.String()).To(ContainSubstring("Some Buildpack some-version"))
+			Expect(buffer.String()).To(ContainSubstring("Taking the license inventory of 4 modules"))
+			Expect(buffer.String()).To(ContainSubstring("example.com/gpl@v1.2.0: GPL-3.0-only"))
+		})
+	})
+
+	context("when the modules are only in the module cache", func() {
+		var modCache string
+
+		it.Before(func() {
+			buildCtx.WorkingDir = t.TempDir()
+			content, err := os.ReadFile(filepath.Join(testdata, "go.sum"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(os.WriteFile(filepath.Join(buildCtx.WorkingDir, "go.sum"), content, 0600)).To(Succeed())
+
+			modCache = filepath.Join(testdata, "modcache")
+		})
+
+		it("reads the modules from GOMODCACHE", func() {
+			t.Setenv("GOMODCACHE", modCache)
+
+			result, err := build(buildCtx)
+			Expect(err).NotTo(HaveOccurred())
+
+			report := readReport(result)
+			Expect(report.Modules).To(ConsistOf(SatisfyAll(
+				HaveField("Module.Path", "example.com/SomeModule"),
+				HaveField("Licenses", Equal([]string{"MIT"})),
+			)))
+		})
+
+		it("falls back to the mod-cache layer of go-mod-vendor", func() {
+			t.Setenv("GOMODCACHE", "")
+			Expect(os.MkdirAll(filepath.Join(layersDir, "paketo-buildpacks_go-mod-vendor"), os.ModePerm)).To(Succeed())
+			Expect(os.Symlink(modCache, filepath.Join(layersDir, "paketo-buildpacks_go-mod-vendor", "mod-cache"))).To(Succeed())
+
+			result, err := build(buildCtx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(readReport(result).Modules).To(HaveLen(1))
+		})
+	})
+
+	context("when the application has no dependencies", func() {
+		it.Before(func() {
+			buildCtx.WorkingDir = t.TempDir()
+		})
+
+		it("writes an empty report", func() {
+			result, err := build(buildCtx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(readReport(result).Modules).To(BeEmpty())
+		})
+	})
+
+	context("failure cases", func() {
+		context("when a module is published under a license on the default deny-list", func() {
+			it("fails the build and names the module and license", func() {
+				_, err := build(buildCtx)
+				Expect(err).To(MatchError("modules are published under denied licenses (BP_GO_LICENSE_DENY_LIST=GPL-*,AGPL-*):\n  example.com/gpl@v1.2.0: GPL-3.0-only"))
+				Expect(filepath.Join(layersDir, "paketo-buildpacks_go-licenses", "licenses")).NotTo(BeADirectory())
+			})
+		})
+
+		context("when a module is published under a license on the configured deny-list", func() {
+			it.Before(func() {
+				t.Setenv("BP_GO_LICENSE_DENY_LIST", "MIT")
+			})
+
+			it("fails the build", func() {
+				_, err := build(buildCtx)
+				Expect(err).To(MatchError(ContainSubstring("example.com/local@v1.0.0: MIT")))
+				Expect(err).To(MatchError(ContainSubstring("example.com/mit@v1.0.1: MIT")))
+			})
+		})
+
+		context("when the vendored modules cannot be read", func() {
+			it.Before(func() {
+				buildCtx.WorkingDir = t.TempDir()
+				Expect(os.MkdirAll(filepath.Join(buildCtx.WorkingDir, "vendor", "modules.txt"), os.ModePerm)).To(Succeed())
+			})
+
+			it("returns an error", func() {
+				_, err := build(buildCtx)
+				Expect(err).To(MatchError(ContainSubstring("failed to read vendored modules")))
+			})
+		})
+	})
+}
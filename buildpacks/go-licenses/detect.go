@@ -0,0 +1,54 @@
+package golicenses
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/paketo-buildpacks/packit/v2"
+)
+
+// CheckEnv opts into the license check.
+const CheckEnv = "BP_GO_LICENSE_CHECK"
+
+// Detect passes when BP_GO_LICENSE_CHECK is set for applications with a
+// go.mod file, whose modules are either vendored or in the module cache that
+// go-mod-vendor fills.
+func Detect() packit.DetectFunc {
+	return func(context packit.DetectContext) (packit.DetectResult, error) {
+		enabled, err := enabled()
+		if err != nil {
+			return packit.DetectResult{}, err
+		}
+
+		if !enabled {
+			return packit.DetectResult{}, packit.Fail.WithMessage("%s is not set", CheckEnv)
+		}
+
+		_, err = os.Stat(filepath.Join(context.WorkingDir, "go.mod"))
+		if err != nil {
+			if os.IsNotExist(err) {
+				return packit.DetectResult{}, packit.Fail.WithMessage("go.mod file is not present")
+			}
+
+			return packit.DetectResult{}, err
+		}
+
+		return packit.DetectResult{}, nil
+	}
+}
+
+func enabled() (bool, error) {
+	value, ok := os.LookupEnv(CheckEnv)
+	if !ok || value == "" {
+		return false, nil
+	}
+
+	enabled, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("failed to parse %s: %w", CheckEnv, err)
+	}
+
+	return enabled, nil
+}
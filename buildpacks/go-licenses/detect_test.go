@@ -0,0 +1,60 @@
+package golicenses_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	golicenses "github.com/paketo-buildpacks/go/buildpacks/go-licenses"
+	"github.com/paketo-buildpacks/packit/v2"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testDetect(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		workingDir string
+	)
+
+	it.Before(func() {
+		workingDir = t.TempDir()
+		Expect(os.WriteFile(filepath.Join(workingDir, "go.mod"), []byte("module app\n"), 0600)).To(Succeed())
+		t.Setenv("BP_GO_LICENSE_CHECK", "true")
+	})
+
+	it("passes without requirements when BP_GO_LICENSE_CHECK is true and there is a go.mod file", func() {
+		result, err := golicenses.Detect()(packit.DetectContext{WorkingDir: workingDir})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result).To(Equal(packit.DetectResult{}))
+	})
+
+	it("fails when BP_GO_LICENSE_CHECK is not set or false", func() {
+		for _, value := range []string{"", "false"} {
+			t.Setenv("BP_GO_LICENSE_CHECK", value)
+
+			_, err := golicenses.Detect()(packit.DetectContext{WorkingDir: workingDir})
+			Expect(err).To(MatchError(packit.Fail.WithMessage("BP_GO_LICENSE_CHECK is not set")))
+		}
+	})
+
+	it("fails when there is no go.mod file", func() {
+		Expect(os.Remove(filepath.Join(workingDir, "go.mod"))).To(Succeed())
+
+		_, err := golicenses.Detect()(packit.DetectContext{WorkingDir: workingDir})
+		Expect(err).To(MatchError(packit.Fail.WithMessage("go.mod file is not present")))
+	})
+
+	context("failure cases", func() {
+		context("when BP_GO_LICENSE_CHECK cannot be parsed", func() {
+			it("returns an error", func() {
+				t.Setenv("BP_GO_LICENSE_CHECK", "sometimes")
+
+				_, err := golicenses.Detect()(packit.DetectContext{WorkingDir: workingDir})
+				Expect(err).To(MatchError(ContainSubstring("failed to parse BP_GO_LICENSE_CHECK")))
+			})
+		})
+	})
+}
@@ -0,0 +1,17 @@
+package golicenses_test
+
+import (
+	"testing"
+
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+)
+
+func TestUnitGoLicenses(t *testing.T) {
+	// Detect reads BP_GO_LICENSE_CHECK and Build reads BP_GO_LICENSE_DENY_LIST
+	// and GOMODCACHE, which the specs set with t.Setenv.
+	suite := spec.New("go-licenses", spec.Report(report.Terminal{}), spec.Sequential())
+	suite("Build", testBuild)
+	suite("Detect", testDetect)
+	suite.Run(t)
+}
@@ -0,0 +1,16 @@
+package main
+
+import (
+	"os"
+
+	golicenses "github.com/paketo-buildpacks/go/buildpacks/go-licenses"
+	"github.com/paketo-buildpacks/packit/v2"
+	"github.com/paketo-buildpacks/packit/v2/scribe"
+)
+
+func main() {
+	packit.Run(
+		golicenses.Detect(),
+		golicenses.Build(scribe.NewEmitter(os.Stdout).WithLevel(os.Getenv("BP_LOG_LEVEL"))),
+	)
+}
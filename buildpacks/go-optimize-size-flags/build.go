@@ -0,0 +1,78 @@
+package gooptimizesizeflags
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/paketo-buildpacks/go/optimize"
+	"github.com/paketo-buildpacks/packit/v2"
+	"github.com/paketo-buildpacks/packit/v2/scribe"
+)
+
+// LayerName is the name of the build layer holding the flags.
+const LayerName = "optimize-size"
+
+// Build adds -trimpath to BP_GO_BUILD_FLAGS and -s -w to BP_GO_BUILD_LDFLAGS
+// for go-build, through the build environment of a layer that is neither
+// cached nor exported. Values that earlier buildpacks set through their layers,
+// such as the ldflags of go-goreleaser, are kept and the flags are added to
+// them. Variables set for the build take precedence over the build
+// environment of a layer, so a variable the user set without the flags is
+// left alone with a warning.
+func Build(logger scribe.Emitter) packit.BuildFunc {
+	return func(context packit.BuildContext) (packit.BuildResult, error) {
+		logger.Title("%s %s", context.BuildpackInfo.Name, context.BuildpackInfo.Version)
+
+		mode, err := optimize.ParseMode(os.Getenv(optimize.Env))
+		if err != nil {
+			return packit.BuildResult{}, err
+		}
+
+		layer, err := context.Layers.Get(LayerName)
+		if err != nil {
+			return packit.BuildResult{}, err
+		}
+
+		layer, err = layer.Reset()
+		if err != nil {
+			return packit.BuildResult{}, err
+		}
+		layer.Build = true
+
+		environment := optimize.BuildEnvironment(mode, os.Getenv("BP_GO_BUILD_FLAGS"), os.Getenv("BP_GO_BUILD_LDFLAGS"))
+
+		var names []string
+		for name := range environment {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		logger.Process("Adding the flags of %s=%s", optimize.Env, mode)
+		for _, name := range names {
+			current := os.Getenv(name)
+			if current == environment[name] {
+				logger.Subprocess("%s already holds them", name)
+				continue
+			}
+
+			_, err = os.Stat(filepath.Join(context.Platform.Path, "env", name))
+			if err == nil {
+				logger.Subprocess("Warning: %s is set to %q, set it to %q to strip the binaries", name, current, environment[name])
+				continue
+			}
+			if !errors.Is(err, os.ErrNotExist) {
+				return packit.BuildResult{}, err
+			}
+
+			layer.BuildEnv.Override(name, environment[name])
+			logger.Subprocess("%s=%s", name, environment[name])
+		}
+		logger.Break()
+
+		return packit.BuildResult{
+			Layers: []packit.Layer{layer},
+		}, nil
+	}
+}
@@ -0,0 +1,120 @@
+package gooptimizesizeflags_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	gooptimizesizeflags "github.com/paketo-buildpacks/go/buildpacks/go-optimize-size-flags"
+	"github.com/paketo-buildpacks/packit/v2"
+	"github.com/paketo-buildpacks/packit/v2/scribe"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testBuild(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		layersDir   string
+		platformDir string
+		buffer      *bytes.Buffer
+		build       packit.BuildFunc
+		buildCtx    packit.BuildContext
+	)
+
+	it.Before(func() {
+		layersDir = t.TempDir()
+		platformDir = t.TempDir()
+
+		t.Setenv("BP_GO_OPTIMIZE_SIZE", "true")
+		for _, name := range []string{"BP_GO_BUILD_FLAGS", "BP_GO_BUILD_LDFLAGS"} {
+			t.Setenv(name, "")
+			Expect(os.Unsetenv(name)).To(Succeed())
+		}
+
+		buffer = bytes.NewBuffer(nil)
+		build = gooptimizesizeflags.Build(scribe.NewEmitter(buffer))
+		buildCtx = packit.BuildContext{
+			BuildpackInfo: packit.BuildpackInfo{
+				Name:    "Some Buildpack",
+				Version: "some-version",
+			},
+			WorkingDir: t.TempDir(),
+			Platform:   packit.Platform{Path: platformDir},
+			Layers:     packit.Layers{Path: layersDir},
+		}
+	})
+
+	it("sets the flags for go-build in a build layer", func() {
+		result, err := build(buildCtx)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(result.Layers).To(HaveLen(1))
+		layer := result.Layers[0]
+		Expect(layer.Name).To(Equal("optimize-size"))
+		Expect(layer.Build).To(BeTrue())
+		Expect(layer.Launch).To(BeFalse())
+		Expect(layer.Cache).To(BeFalse())
+		Expect(layer.BuildEnv).To(Equal(packit.Environment{
+			"BP_GO_BUILD_FLAGS.override":   "-trimpath",
+			"BP_GO_BUILD_LDFLAGS.override": "-s -w",
+		}))
+
+		Expect(buffer.String()).To(ContainSubstring("Some Buildpack some-version"))
+		Expect(buffer.String()).To(ContainSubstring("Adding the flags of BP_GO_OPTIMIZE_SIZE=strip"))
+		Expect(buffer.String()).To(ContainSubstring("BP_GO_BUILD_LDFLAGS=-s -w"))
+	})
+
+	context("when an earlier buildpack sets the flags through its layer", func() {
+		it.Before(func() {
+			t.Setenv("BP_GO_BUILD_LDFLAGS", "-X main.version=1.2.3")
+		})
+
+		it("adds the flags to its value", func() {
+			result, err := build(buildCtx)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(result.Layers[0].BuildEnv).To(Equal(packit.Environment{
+				"BP_GO_BUILD_FLAGS.override":   "-trimpath",
+				"BP_GO_BUILD_LDFLAGS.override": "-X main.version=1.2.3 -s -w",
+			}))
+			Expect(buffer.String()).To(ContainSubstring("BP_GO_BUILD_LDFLAGS=-X main.version=1.2.3 -s -w"))
+		})
+	})
+
+	context("when the user sets the flags", func() {
+		it.Before(func() {
+			t.Setenv("BP_GO_BUILD_FLAGS", "-trimpath")
+			t.Setenv("BP_GO_BUILD_LDFLAGS", "-X main.version=1.2.3")
+
+			// pack writes the variables set for the build to the platform
+			// directory
+			Expect(os.MkdirAll(filepath.Join(platformDir, "env"), os.ModePerm)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(platformDir, "env", "BP_GO_BUILD_FLAGS"), []byte("-trimpath"), 0644)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(platformDir, "env", "BP_GO_BUILD_LDFLAGS"), []byte("-X main.version=1.2.3"), 0644)).To(Succeed())
+		})
+
+		it("leaves them alone, since they take precedence, and warns when they miss a flag", func() {
+			result, err := build(buildCtx)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(result.Layers[0].BuildEnv).To(BeEmpty())
+			Expect(buffer.String()).To(ContainSubstring("BP_GO_BUILD_FLAGS already holds them"))
+			Expect(buffer.String()).To(ContainSubstring(`Warning: BP_GO_BUILD_LDFLAGS is set to "-X main.version=1.2.3", set it to "-X main.version=1.2.3 -s -w" to strip the binaries`))
+		})
+	})
+
+	context("failure cases", func() {
+		context("when BP_GO_OPTIMIZE_SIZE is invalid", func() {
+			it("returns an error", func() {
+				t.Setenv("BP_GO_OPTIMIZE_SIZE", "gzip")
+
+				_, err := build(buildCtx)
+				Expect(err).To(MatchError(ContainSubstring("invalid BP_GO_OPTIMIZE_SIZE")))
+			})
+		})
+	})
+}
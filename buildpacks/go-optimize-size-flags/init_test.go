@@ -0,0 +1,17 @@
+package gooptimizesizeflags_test
+
+import (
+	"testing"
+
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+)
+
+func TestUnitGoOptimizeSizeFlags(t *testing.T) {
+	// the mode and flags are read from the environment, which the specs
+	// set with t.Setenv
+	suite := spec.New("go-optimize-size-flags", spec.Report(report.Terminal{}), spec.Sequential())
+	suite("Build", testBuild)
+	suite("Detect", testDetect)
+	suite.Run(t)
+}
@@ -0,0 +1,16 @@
+package main
+
+import (
+	"os"
+
+	gooptimizesizeflags "github.com/paketo-buildpacks/go/buildpacks/go-optimize-size-flags"
+	"github.com/paketo-buildpacks/packit/v2"
+	"github.com/paketo-buildpacks/packit/v2/scribe"
+)
+
+func main() {
+	packit.Run(
+		gooptimizesizeflags.Detect(),
+		gooptimizesizeflags.Build(scribe.NewEmitter(os.Stdout).WithLevel(os.Getenv("BP_LOG_LEVEL"))),
+	)
+}
@@ -0,0 +1,83 @@
+package gooptimizesize
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/paketo-buildpacks/go/gobuild"
+	"github.com/paketo-buildpacks/go/optimize"
+	"github.com/paketo-buildpacks/packit/v2"
+	"github.com/paketo-buildpacks/packit/v2/scribe"
+)
+
+// Build runs after go-build. It compresses the binaries of go-build with UPX,
+// in place in the targets layer of go-build, when BP_GO_OPTIMIZE_SIZE is upx
+// and UPX is on PATH, logs the size of every binary, warns about binaries
+// that still hold debug information and labels the image with the sizes.
+func Build(logger scribe.Emitter) packit.BuildFunc {
+	return func(context packit.BuildContext) (packit.BuildResult, error) {
+		logger.Title("%s %s", context.BuildpackInfo.Name, context.BuildpackInfo.Version)
+
+		mode, err := optimize.ParseMode(os.Getenv(optimize.Env))
+		if err != nil {
+			return packit.BuildResult{}, err
+		}
+
+		dir := gobuild.Targets(context.Layers.Path)
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return packit.BuildResult{}, fmt.Errorf("go-build left no binaries in %s", dir)
+			}
+			return packit.BuildResult{}, err
+		}
+
+		var upx string
+		if mode == optimize.Compress {
+			upx, err = exec.LookPath("upx")
+			if err != nil {
+				logger.Subprocess("upx is not installed, binaries are only stripped")
+				upx = ""
+			}
+		}
+
+		labels := map[string]string{}
+		logger.Process("Measuring the binaries of go-build")
+		for _, entry := range entries {
+			if !entry.Type().IsRegular() {
+				continue
+			}
+
+			binary := filepath.Join(dir, entry.Name())
+			result, err := optimize.Shrink(binary, mode, upx, logger.ActionWriter)
+			if err != nil {
+				return packit.BuildResult{}, err
+			}
+
+			debug, err := optimize.HasDebugInfo(binary)
+			if err != nil {
+				return packit.BuildResult{}, fmt.Errorf("failed to read %s: %w", binary, err)
+			}
+
+			if debug {
+				logger.Subprocess("Warning: %s still holds debug information, check that BP_GO_BUILD_LDFLAGS includes -s -w", result.Name)
+			}
+
+			logger.Subprocess("%s", result)
+
+			for key, value := range result.Labels() {
+				labels[key] = value
+			}
+		}
+		logger.Break()
+
+		return packit.BuildResult{
+			Launch: packit.LaunchMetadata{
+				Labels: labels,
+			},
+		}, nil
+	}
+}
@@ -0,0 +1,144 @@
+package gooptimizesize_test
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	gooptimizesize "github.com/paketo-buildpacks/go/buildpacks/go-optimize-size"
+	"github.com/paketo-buildpacks/go/optimize"
+	"github.com/paketo-buildpacks/packit/v2"
+	"github.com/paketo-buildpacks/packit/v2/scribe"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testBuild(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		targets  string
+		binary   string
+		log      string
+		buffer   *bytes.Buffer
+		build    packit.BuildFunc
+		buildCtx packit.BuildContext
+	)
+
+	// compile builds a program into the targets of go-build with the given
+	// ldflags.
+	compile := func(ldflags string) {
+		dir := t.TempDir()
+		Expect(os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n\nfunc main() {}\n"), 0644)).To(Succeed())
+
+		output, err := exec.Command("go", "build", "-C", dir, "-o", binary, "-ldflags", ldflags, "main.go").CombinedOutput()
+		Expect(err).NotTo(HaveOccurred(), string(output))
+	}
+
+	it.Before(func() {
+		layersDir := t.TempDir()
+		targets = filepath.Join(layersDir, "paketo-buildpacks_go-build", "targets", "bin")
+		Expect(os.MkdirAll(targets, os.ModePerm)).To(Succeed())
+		binary = filepath.Join(targets, "server")
+
+		// a stand-in for upx that records that it ran and leaves the binary
+		// readable as ELF
+		bin := t.TempDir()
+		log = filepath.Join(bin, "upx.log")
+		Expect(os.WriteFile(filepath.Join(bin, "upx"), []byte("#!/bin/sh\necho \"$@\" >> "+log+"\n"), 0755)).To(Succeed())
+		t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+		t.Setenv("BP_GO_OPTIMIZE_SIZE", "true")
+
+		buffer = bytes.NewBuffer(nil)
+		build = gooptimizesize.Build(scribe.NewEmitter(buffer))
+		buildCtx = packit.BuildContext{
+			BuildpackInfo: packit.BuildpackInfo{
+				Name:    "Some Buildpack",
+				Version: "some-version",
+			},
+			WorkingDir: t.TempDir(),
+			Layers:     packit.Layers{Path: filepath.Join(layersDir, "paketo-buildpacks_go-optimize-size")},
+		}
+	})
+
+	it("labels the image with the size of every binary of go-build", func() {
+		compile("-s -w")
+		info, err := os.Stat(binary)
+		Expect(err).NotTo(HaveOccurred())
+		size := strconv.FormatInt(info.Size(), 10)
+
+		result, err := build(buildCtx)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(result.Layers).To(BeEmpty())
+		Expect(result.Launch.Labels).To(Equal(map[string]string{
+			"io.paketo.go.optimize-size.server.before": size,
+			"io.paketo.go.optimize-size.server.after":  size,
+		}))
+
+		Expect(buffer.String()).To(ContainSubstring("Some Buildpack some-version"))
+		Expect(buffer.String()).To(ContainSubstring("server: " + optimize.FormatSize(info.Size()) + " -> " + optimize.FormatSize(info.Size())))
+		Expect(buffer.String()).NotTo(ContainSubstring("Warning"))
+		Expect(log).NotTo(BeAnExistingFile())
+	})
+
+	it("warns about binaries that still hold debug information", func() {
+		compile("")
+
+		_, err := build(buildCtx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(buffer.String()).To(ContainSubstring("Warning: server still holds debug information, check that BP_GO_BUILD_LDFLAGS includes -s -w"))
+	})
+
+	context("when BP_GO_OPTIMIZE_SIZE is upx", func() {
+		it.Before(func() {
+			t.Setenv("BP_GO_OPTIMIZE_SIZE", "upx")
+			compile("-s -w")
+		})
+
+		it("compresses the binaries with the upx on PATH", func() {
+			_, err := build(buildCtx)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(os.ReadFile(log)).To(Equal([]byte("--best --lzma --no-progress " + binary + "\n")))
+		})
+
+		it("only strips without upx", func() {
+			t.Setenv("PATH", "")
+
+			_, err := build(buildCtx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(buffer.String()).To(ContainSubstring("upx is not installed, binaries are only stripped"))
+			Expect(log).NotTo(BeAnExistingFile())
+		})
+	})
+
+	context("failure cases", func() {
+		context("when go-build left no binaries", func() {
+			it.Before(func() {
+				Expect(os.RemoveAll(targets)).To(Succeed())
+			})
+
+			it("returns an error", func() {
+				_, err := build(buildCtx)
+				Expect(err).To(MatchError("go-build left no binaries in " + targets))
+			})
+		})
+
+		context("when a binary is not an ELF binary", func() {
+			it.Before(func() {
+				Expect(os.WriteFile(binary, []byte("#!/bin/sh\n"), 0755)).To(Succeed())
+			})
+
+			it("returns an error", func() {
+				_, err := build(buildCtx)
+				Expect(err).To(MatchError(ContainSubstring("failed to read " + binary)))
+			})
+		})
+	})
+}
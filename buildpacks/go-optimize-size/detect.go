@@ -0,0 +1,24 @@
+package gooptimizesize
+
+import (
+	"os"
+
+	"github.com/paketo-buildpacks/go/optimize"
+	"github.com/paketo-buildpacks/packit/v2"
+)
+
+// Detect passes when BP_GO_OPTIMIZE_SIZE turns the mode on.
+func Detect() packit.DetectFunc {
+	return func(context packit.DetectContext) (packit.DetectResult, error) {
+		mode, err := optimize.ParseMode(os.Getenv(optimize.Env))
+		if err != nil {
+			return packit.DetectResult{}, err
+		}
+
+		if mode == optimize.Off {
+			return packit.DetectResult{}, packit.Fail.WithMessage("%s is not set", optimize.Env)
+		}
+
+		return packit.DetectResult{}, nil
+	}
+}
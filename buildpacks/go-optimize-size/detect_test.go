@@ -0,0 +1,53 @@
+package gooptimizesize_test
+
+import (
+	"testing"
+
+	gooptimizesize "github.com/paketo-buildpacks/go/buildpacks/go-optimize-size"
+	"github.com/paketo-buildpacks/packit/v2"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testDetect(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		detect packit.DetectFunc
+	)
+
+	it.Before(func() {
+		detect = gooptimizesize.Detect()
+	})
+
+	it("passes when BP_GO_OPTIMIZE_SIZE turns the mode on", func() {
+		for _, value := range []string{"true", "strip", "upx"} {
+			t.Setenv("BP_GO_OPTIMIZE_SIZE", value)
+
+			result, err := detect(packit.DetectContext{WorkingDir: t.TempDir()})
+			Expect(err).NotTo(HaveOccurred(), value)
+			Expect(result).To(Equal(packit.DetectResult{}))
+		}
+	})
+
+	it("fails when BP_GO_OPTIMIZE_SIZE is not set or false", func() {
+		for _, value := range []string{"", "false"} {
+			t.Setenv("BP_GO_OPTIMIZE_SIZE", value)
+
+			_, err := detect(packit.DetectContext{WorkingDir: t.TempDir()})
+			Expect(err).To(MatchError(packit.Fail.WithMessage("BP_GO_OPTIMIZE_SIZE is not set")))
+		}
+	})
+
+	context("failure cases", func() {
+		context("when BP_GO_OPTIMIZE_SIZE is invalid", func() {
+			it("returns an error", func() {
+				t.Setenv("BP_GO_OPTIMIZE_SIZE", "gzip")
+
+				_, err := detect(packit.DetectContext{WorkingDir: t.TempDir()})
+				Expect(err).To(MatchError(`invalid BP_GO_OPTIMIZE_SIZE "gzip": expected one of true, false, strip or upx`))
+			})
+		})
+	})
+}
@@ -0,0 +1,17 @@
+package gooptimizesize_test
+
+import (
+	"testing"
+
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+)
+
+func TestUnitGoOptimizeSize(t *testing.T) {
+	// the mode and PATH are read from the environment, which the specs
+	// set with t.Setenv
+	suite := spec.New("go-optimize-size", spec.Report(report.Terminal{}), spec.Sequential())
+	suite("Build", testBuild)
+	suite("Detect", testDetect)
+	suite.Run(t)
+}
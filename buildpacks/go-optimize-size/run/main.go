@@ -0,0 +1,16 @@
+package main
+
+import (
+	"os"
+
+	gooptimizesize "github.com/paketo-buildpacks/go/buildpacks/go-optimize-size"
+	"github.com/paketo-buildpacks/packit/v2"
+	"github.com/paketo-buildpacks/packit/v2/scribe"
+)
+
+func main() {
+	packit.Run(
+		gooptimizesize.Detect(),
+		gooptimizesize.Build(scribe.NewEmitter(os.Stdout).WithLevel(os.Getenv("BP_LOG_LEVEL"))),
+	)
+}
@@ -0,0 +1,176 @@
+package gootel
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/paketo-buildpacks/go/gobuild"
+	"github.com/paketo-buildpacks/go/otel"
+	"github.com/paketo-buildpacks/packit/v2"
+	"github.com/paketo-buildpacks/packit/v2/scribe"
+)
+
+const (
+	// InstrumentationLayerName is the name of the cached launch layer holding
+	// the instrumentation.
+	InstrumentationLayerName = "instrumentation"
+
+	// LayerName is the name of the launch layer holding the environment of
+	// the process types and the launch helper.
+	LayerName = "otel"
+)
+
+// Build runs after go-build. It installs the instrumentation from its release
+// image, pinned by digest, in a cached launch layer and assigns a process type that runs it
+// against each binary of go-build, configured from the otel binding. The
+// instrumentation attaches with eBPF, which needs privileges the application
+// should not have, so it runs as a process of its own, such as a sidecar that
+// shares the process namespace of the application. The headers of the binding
+// are added by a launch helper when the image runs.
+func Build(logger scribe.Emitter) packit.BuildFunc {
+	return func(context packit.BuildContext) (packit.BuildResult, error) {
+		logger.Title("%s %s", context.BuildpackInfo.Name, context.BuildpackInfo.Version)
+
+		config, ok, err := otel.Detect(context.Platform.Path, os.Getenv(otel.EnabledEnv))
+		if err != nil {
+			return packit.BuildResult{}, err
+		}
+
+		if !ok {
+			return packit.BuildResult{}, fmt.Errorf("no %s binding found and %s is not set", otel.BindingType, otel.EnabledEnv)
+		}
+
+		dir := gobuild.Targets(context.Layers.Path)
+		entries, err := os.ReadDir(dir)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return packit.BuildResult{}, err
+		}
+
+		var binaries []string
+		for _, entry := range entries {
+			if entry.Type().IsRegular() {
+				binaries = append(binaries, entry.Name())
+			}
+		}
+
+		if len(binaries) == 0 {
+			return packit.BuildResult{}, fmt.Errorf("go-build left no binaries in %s", dir)
+		}
+
+		ref, err := otel.InstrumentationImage(context.CNBPath, os.Getenv(otel.InstrumentationImageEnv))
+		if err != nil {
+			return packit.BuildResult{}, err
+		}
+
+		instrumentationLayer, err := context.Layers.Get(InstrumentationLayerName)
+		if err != nil {
+			return packit.BuildResult{}, err
+		}
+
+		executable := filepath.Join(instrumentationLayer.Path, "bin", filepath.Base(otel.InstrumentationPath))
+		_, err = os.Stat(executable)
+		if err == nil && instrumentationLayer.Metadata["image"] == ref.String() {
+			logger.Process("Reusing the instrumentation of %s", ref)
+		} else {
+			instrumentationLayer, err = instrumentationLayer.Reset()
+			if err != nil {
+				return packit.BuildResult{}, err
+			}
+
+			logger.Process("Installing the instrumentation of %s", ref)
+			err = pull(ref, executable)
+			if err != nil {
+				return packit.BuildResult{}, err
+			}
+
+			instrumentationLayer.Metadata = map[string]interface{}{"image": ref.String()}
+		}
+		instrumentationLayer.Launch = true
+		instrumentationLayer.Cache = true
+		logger.Break()
+
+		layer, err := context.Layers.Get(LayerName)
+		if err != nil {
+			return packit.BuildResult{}, err
+		}
+
+		layer, err = layer.Reset()
+		if err != nil {
+			return packit.BuildResult{}, err
+		}
+		layer.Launch = true
+		layer.ExecD = []string{filepath.Join(context.CNBPath, "bin", "linux-"+runtime.GOARCH, "tools", "otel-env")}
+
+		var processes []packit.Process
+		for _, binary := range binaries {
+			processType := otel.ProcessType
+			if len(binaries) > 1 {
+				processType = otel.ProcessType + "-" + binary
+			}
+
+			logger.Process("Assigning process type %s to instrument %s", processType, binary)
+
+			environment := config.Environment(filepath.Join(dir, binary), binary)
+
+			var names []string
+			for name := range environment {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+
+			launchEnv := packit.Environment{}
+			for _, name := range names {
+				if name == SecretEnv {
+					logger.Subprocess("Reading %s from the binding when the image runs", name)
+					continue
+				}
+
+				launchEnv.Default(name, environment[name])
+				logger.Subprocess("%s=%s", name, environment[name])
+			}
+			layer.ProcessLaunchEnv[processType] = launchEnv
+
+			processes = append(processes, packit.Process{
+				Type:    processType,
+				Command: executable,
+				Direct:  true,
+			})
+			logger.Break()
+		}
+
+		return packit.BuildResult{
+			Layers: []packit.Layer{instrumentationLayer, layer},
+			Launch: packit.LaunchMetadata{
+				Processes: processes,
+			},
+		}, nil
+	}
+}
+
+// pull copies the instrumentation out of the image for the architecture of
+// the build. The image is referenced by digest, so remote verifies that the
+// manifests it reads match it.
+func pull(ref name.Digest, executable string) error {
+	image, err := remote.Image(ref,
+		remote.WithAuthFromKeychain(authn.DefaultKeychain),
+		remote.WithPlatform(v1.Platform{OS: "linux", Architecture: runtime.GOARCH}),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to pull %s: %w", ref, err)
+	}
+
+	err = otel.ExtractInstrumentation(image, executable)
+	if err != nil {
+		return fmt.Errorf("failed to install the instrumentation of %s: %w", ref, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,243 @@
+package gootel_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"fmt"
+	"io"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
+	gootel "github.com/paketo-buildpacks/go/buildpacks/go-otel"
+	"github.com/paketo-buildpacks/packit/v2"
+	"github.com/paketo-buildpacks/packit/v2/scribe"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testBuild(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		server      *httptest.Server
+		image       string
+		platformDir string
+		targets     string
+		buffer      *bytes.Buffer
+		build       packit.BuildFunc
+		buildCtx    packit.BuildContext
+	)
+
+	it.Before(func() {
+		// a registry serving an instrumentation image
+		server = httptest.NewServer(registry.New())
+
+		var content bytes.Buffer
+		tw := tar.NewWriter(&content)
+		Expect(tw.WriteHeader(&tar.Header{Typeflag: tar.TypeReg, Name: "otel-go-instrumentation", Mode: 0755, Size: int64(len("instrumentation"))})).To(Succeed())
+		_, err := tw.Write([]byte("instrumentation"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tw.Close()).To(Succeed())
+
+		layer, err := tarball.LayerFromOpener(func() (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewReader(content.Bytes())), nil
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		instrumentation, err := mutate.AppendLayers(empty.Image, layer)
+		Expect(err).NotTo(HaveOccurred())
+
+		tag := fmt.Sprintf("%s/otel/autoinstrumentation-go:test", strings.TrimPrefix(server.URL, "http://"))
+		ref, err := name.ParseReference(tag)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(remote.Write(ref, instrumentation)).To(Succeed())
+
+		digest, err := instrumentation.Digest()
+		Expect(err).NotTo(HaveOccurred())
+		image = ref.Context().Digest(digest.String()).String()
+
+		t.Setenv("BP_OTEL_INSTRUMENTATION_IMAGE", "")
+		t.Setenv("BP_OTEL_ENABLED", "")
+
+		platformDir = t.TempDir()
+		writeBinding(t, filepath.Join(platformDir, "bindings"), map[string]string{
+			"endpoint":     "http://collector:4318",
+			"headers":      "x-api-key=secret\n",
+			"service-name": "checkout",
+		})
+
+		layersDir := t.TempDir()
+		targets = filepath.Join(layersDir, "paketo-buildpacks_go-build", "targets", "bin")
+		Expect(os.MkdirAll(targets, os.ModePerm)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(targets, "server"), []byte("server"), 0755)).To(Succeed())
+
+		// the buildpack lists the image by tag and is packaged with the
+		// lockfile that records its digest
+		cnbDir := t.TempDir()
+		Expect(os.WriteFile(filepath.Join(cnbDir, "buildpack.toml"), fmt.Appendf(nil, "[[metadata.images]]\n  uri = \"docker://%s\"\n", tag), 0600)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(cnbDir, "package.lock.toml"), fmt.Appendf(nil, "[[images]]\n  uri = \"docker://%s\"\n  digest = %q\n", tag, digest), 0600)).To(Succeed())
+
+		buffer = bytes.NewBuffer(nil)
+		build = gootel.Build(scribe.NewEmitter(buffer))
+		buildCtx = packit.BuildContext{
+			BuildpackInfo: packit.BuildpackInfo{
+				Name:    "Some Buildpack",
+				Version: "some-version",
+			},
+			CNBPath:    cnbDir,
+			WorkingDir: t.TempDir(),
+			Platform:   packit.Platform{Path: platformDir},
+			Layers:     packit.Layers{Path: filepath.Join(layersDir, "paketo-buildpacks_go-otel")},
+		}
+	})
+
+	it.After(func() {
+		server.Close()
+	})
+
+	it("installs the instrumentation and assigns a process type that runs it against the binary", func() {
+		result, err := build(buildCtx)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(result.Layers).To(HaveLen(2))
+
+		instrumentationLayer := result.Layers[0]
+		Expect(instrumentationLayer.Name).To(Equal("instrumentation"))
+		Expect(instrumentationLayer.Launch).To(BeTrue())
+		Expect(instrumentationLayer.Cache).To(BeTrue())
+		Expect(instrumentationLayer.Build).To(BeFalse())
+		Expect(instrumentationLayer.Metadata).To(Equal(map[string]interface{}{"image": image}))
+
+		executable := filepath.Join(instrumentationLayer.Path, "bin", "otel-go-instrumentation")
+		Expect(os.ReadFile(executable)).To(Equal([]byte("instrumentation")))
+
+		layer := result.Layers[1]
+		Expect(layer.Name).To(Equal("otel"))
+		Expect(layer.Launch).To(BeTrue())
+		Expect(layer.Cache).To(BeFalse())
+		Expect(layer.ExecD).To(Equal([]string{filepath.Join(buildCtx.CNBPath, "bin", "linux-"+runtime.GOARCH, "tools", "otel-env")}))
+		Expect(layer.LaunchEnv).To(BeEmpty())
+		Expect(layer.ProcessLaunchEnv).To(Equal(map[string]packit.Environment{
+			"otel-instrumentation": {
+				"OTEL_EXPORTER_OTLP_ENDPOINT.default": "http://collector:4318",
+				"OTEL_EXPORTER_OTLP_PROTOCOL.default": "http/protobuf",
+				"OTEL_GO_AUTO_TARGET_EXE.default":     filepath.Join(targets, "server"),
+				"OTEL_SERVICE_NAME.default":           "checkout",
+				"OTEL_TRACES_EXPORTER.default":        "otlp",
+			},
+		}))
+
+		Expect(result.Launch.Processes).To(Equal([]packit.Process{
+			{Type: "otel-instrumentation", Command: executable, Direct: true},
+		}))
+
+		Expect(buffer.String()).To(ContainSubstring("Some Buildpack some-version"))
+		Expect(buffer.String()).To(ContainSubstring("Installing the instrumentation of " + image))
+		Expect(buffer.String()).To(ContainSubstring("Assigning process type otel-instrumentation to instrument server"))
+		Expect(buffer.String()).To(ContainSubstring("Reading OTEL_EXPORTER_OTLP_HEADERS from the binding when the image runs"))
+		Expect(buffer.String()).NotTo(ContainSubstring("secret"))
+	})
+
+	it("reuses the cached instrumentation of the same image", func() {
+		_, err := build(buildCtx)
+		Expect(err).NotTo(HaveOccurred())
+
+		// the layer metadata is written by packit after the build, so the
+		// second build reads it from the TOML file packit would write
+		Expect(os.WriteFile(filepath.Join(buildCtx.Layers.Path, "instrumentation.toml"), fmt.Appendf(nil, "[metadata]\n  image = %q\n", image), 0644)).To(Succeed())
+		server.Close()
+
+		buffer.Reset()
+		result, err := build(buildCtx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Layers[0].Metadata).To(Equal(map[string]interface{}{"image": image}))
+		Expect(buffer.String()).To(ContainSubstring("Reusing the instrumentation of " + image))
+	})
+
+	it("assigns a process type per binary when go-build built several", func() {
+		Expect(os.WriteFile(filepath.Join(targets, "worker"), []byte("worker"), 0755)).To(Succeed())
+
+		result, err := build(buildCtx)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(result.Launch.Processes).To(HaveLen(2))
+		Expect(result.Launch.Processes[0].Type).To(Equal("otel-instrumentation-server"))
+		Expect(result.Launch.Processes[1].Type).To(Equal("otel-instrumentation-worker"))
+		Expect(result.Layers[1].ProcessLaunchEnv["otel-instrumentation-worker"]).To(HaveKeyWithValue("OTEL_GO_AUTO_TARGET_EXE.default", filepath.Join(targets, "worker")))
+	})
+
+	context("failure cases", func() {
+		context("when go-build left no binaries", func() {
+			it.Before(func() {
+				Expect(os.RemoveAll(targets)).To(Succeed())
+			})
+
+			it("returns an error", func() {
+				_, err := build(buildCtx)
+				Expect(err).To(MatchError("go-build left no binaries in " + targets))
+			})
+		})
+
+		context("when the buildpack was packaged without a lockfile", func() {
+			it.Before(func() {
+				Expect(os.Remove(filepath.Join(buildCtx.CNBPath, "package.lock.toml"))).To(Succeed())
+			})
+
+			it("returns an error", func() {
+				_, err := build(buildCtx)
+				Expect(err).To(MatchError(ContainSubstring("the buildpack was packaged without package.lock.toml")))
+			})
+		})
+
+		context("when BP_OTEL_INSTRUMENTATION_IMAGE references the image by tag", func() {
+			it.Before(func() {
+				t.Setenv("BP_OTEL_INSTRUMENTATION_IMAGE", strings.TrimPrefix(server.URL, "http://")+"/otel/autoinstrumentation-go:test")
+			})
+
+			it("returns an error", func() {
+				_, err := build(buildCtx)
+				Expect(err).To(MatchError(ContainSubstring("BP_OTEL_INSTRUMENTATION_IMAGE must reference the image by digest")))
+			})
+		})
+
+		context("when the instrumentation image cannot be pulled", func() {
+			it.Before(func() {
+				t.Setenv("BP_OTEL_INSTRUMENTATION_IMAGE", strings.TrimPrefix(server.URL, "http://")+"/otel/missing@sha256:0000000000000000000000000000000000000000000000000000000000000000")
+			})
+
+			it("returns an error", func() {
+				_, err := build(buildCtx)
+				Expect(err).To(MatchError(ContainSubstring("failed to pull")))
+			})
+		})
+
+		context("when the image has no instrumentation", func() {
+			it.Before(func() {
+				ref, err := name.ParseReference(strings.TrimPrefix(server.URL, "http://") + "/otel/empty:test")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(remote.Write(ref, empty.Image)).To(Succeed())
+
+				digest, err := empty.Image.Digest()
+				Expect(err).NotTo(HaveOccurred())
+
+				t.Setenv("BP_OTEL_INSTRUMENTATION_IMAGE", ref.Context().Digest(digest.String()).String())
+			})
+
+			it("returns an error", func() {
+				_, err := build(buildCtx)
+				Expect(err).To(MatchError(ContainSubstring("the image has no /otel-go-instrumentation")))
+			})
+		})
+	})
+}
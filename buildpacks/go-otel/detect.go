@@ -0,0 +1,25 @@
+package gootel
+
+import (
+	"os"
+
+	"github.com/paketo-buildpacks/go/otel"
+	"github.com/paketo-buildpacks/packit/v2"
+)
+
+// Detect passes when there is an otel service binding or when BP_OTEL_ENABLED
+// is set.
+func Detect() packit.DetectFunc {
+	return func(context packit.DetectContext) (packit.DetectResult, error) {
+		_, ok, err := otel.Detect(context.Platform.Path, os.Getenv(otel.EnabledEnv))
+		if err != nil {
+			return packit.DetectResult{}, err
+		}
+
+		if !ok {
+			return packit.DetectResult{}, packit.Fail.WithMessage("no %s binding found and %s is not set", otel.BindingType, otel.EnabledEnv)
+		}
+
+		return packit.DetectResult{}, nil
+	}
+}
@@ -0,0 +1,83 @@
+package gootel_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	gootel "github.com/paketo-buildpacks/go/buildpacks/go-otel"
+	"github.com/paketo-buildpacks/packit/v2"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testDetect(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		platformDir string
+		detect      packit.DetectFunc
+	)
+
+	it.Before(func() {
+		platformDir = t.TempDir()
+		t.Setenv("BP_OTEL_ENABLED", "")
+
+		detect = gootel.Detect()
+	})
+
+	it("passes with an otel binding", func() {
+		writeBinding(t, filepath.Join(platformDir, "bindings"), map[string]string{"endpoint": "http://collector:4318"})
+
+		result, err := detect(packit.DetectContext{WorkingDir: t.TempDir(), Platform: packit.Platform{Path: platformDir}})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result).To(Equal(packit.DetectResult{}))
+	})
+
+	it("passes when BP_OTEL_ENABLED is true", func() {
+		t.Setenv("BP_OTEL_ENABLED", "true")
+
+		result, err := detect(packit.DetectContext{WorkingDir: t.TempDir(), Platform: packit.Platform{Path: platformDir}})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result).To(Equal(packit.DetectResult{}))
+	})
+
+	it("fails without a binding or BP_OTEL_ENABLED", func() {
+		_, err := detect(packit.DetectContext{WorkingDir: t.TempDir(), Platform: packit.Platform{Path: platformDir}})
+		Expect(err).To(MatchError(packit.Fail.WithMessage("no otel binding found and BP_OTEL_ENABLED is not set")))
+	})
+
+	context("failure cases", func() {
+		context("when the binding is invalid", func() {
+			it.Before(func() {
+				writeBinding(t, filepath.Join(platformDir, "bindings"), map[string]string{"endpoint": "collector:4318"})
+			})
+
+			it("returns an error", func() {
+				_, err := detect(packit.DetectContext{WorkingDir: t.TempDir(), Platform: packit.Platform{Path: platformDir}})
+				Expect(err).To(MatchError(ContainSubstring("binding tracing has an invalid endpoint")))
+			})
+		})
+	})
+}
+
+// writeBinding writes an otel binding named tracing with the given entries
+// under root.
+func writeBinding(t *testing.T, root string, entries map[string]string) {
+	t.Helper()
+
+	dir := filepath.Join(root, "tracing")
+	err := os.MkdirAll(dir, os.ModePerm)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	entries["type"] = "otel"
+	for entry, content := range entries {
+		err = os.WriteFile(filepath.Join(dir, entry), []byte(content), 0600)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package gootel
+
+import (
+	"io"
+
+	"github.com/BurntSushi/toml"
+	"github.com/paketo-buildpacks/go/otel"
+)
+
+// SecretEnv is the variable that is not written into the image. The headers
+// usually hold credentials, so the launch helper reads them from the binding,
+// which is only mounted when the image runs.
+const SecretEnv = "OTEL_EXPORTER_OTLP_HEADERS"
+
+// ExecD writes the headers of the otel binding found by otel.Find to output,
+// in the format of an exec.d launch helper. Only the instrumentation process has
+// OTEL_GO_AUTO_TARGET_EXE set, given as target, so the application never sees
+// them.
+func ExecD(platformDir, target string, output io.Writer) error {
+	if target == "" {
+		return nil
+	}
+
+	config, ok, err := otel.Detect(platformDir, "")
+	if err != nil || !ok {
+		return err
+	}
+
+	headers, ok := config.Environment(target, "")[SecretEnv]
+	if !ok {
+		return nil
+	}
+
+	return toml.NewEncoder(output).Encode(map[string]string{SecretEnv: headers})
+}
@@ -0,0 +1,66 @@
+package gootel_test
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	gootel "github.com/paketo-buildpacks/go/buildpacks/go-otel"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testExecD(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		platformDir string
+		output      *bytes.Buffer
+	)
+
+	it.Before(func() {
+		platformDir = t.TempDir()
+		output = bytes.NewBuffer(nil)
+	})
+
+	it("adds the headers of the binding to the instrumentation process", func() {
+		writeBinding(t, filepath.Join(platformDir, "bindings"), map[string]string{
+			"endpoint": "http://collector:4318",
+			"headers":  "x-api-key=secret\nx-tenant=team a\n",
+		})
+
+		Expect(gootel.ExecD(platformDir, "/layers/paketo-buildpacks_go-build/targets/bin/server", output)).To(Succeed())
+		Expect(output.String()).To(Equal("OTEL_EXPORTER_OTLP_HEADERS = \"x-api-key=secret,x-tenant=team%20a\"\n"))
+	})
+
+	it("leaves other processes alone", func() {
+		writeBinding(t, filepath.Join(platformDir, "bindings"), map[string]string{
+			"endpoint": "http://collector:4318",
+			"headers":  "x-api-key=secret\n",
+		})
+
+		Expect(gootel.ExecD(platformDir, "", output)).To(Succeed())
+		Expect(output.String()).To(BeEmpty())
+	})
+
+	it("adds nothing without headers or without a binding", func() {
+		Expect(gootel.ExecD(platformDir, "/workspace/server", output)).To(Succeed())
+
+		writeBinding(t, filepath.Join(platformDir, "bindings"), map[string]string{"endpoint": "http://collector:4318"})
+		Expect(gootel.ExecD(platformDir, "/workspace/server", output)).To(Succeed())
+
+		Expect(output.String()).To(BeEmpty())
+	})
+
+	context("failure cases", func() {
+		context("when the binding is invalid", func() {
+			it("returns an error", func() {
+				writeBinding(t, filepath.Join(platformDir, "bindings"), map[string]string{"endpoint": "http://collector:4318", "headers": "x-api-key\n"})
+
+				err := gootel.ExecD(platformDir, "/workspace/server", output)
+				Expect(err).To(MatchError(ContainSubstring("binding tracing has an invalid headers entry")))
+			})
+		})
+	})
+}
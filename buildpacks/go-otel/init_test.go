@@ -0,0 +1,18 @@
+package gootel_test
+
+import (
+	"testing"
+
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+)
+
+func TestUnitGoOtel(t *testing.T) {
+	// the instrumentation is configured through the environment, which the
+	// specs set with t.Setenv
+	suite := spec.New("go-otel", spec.Report(report.Terminal{}), spec.Sequential())
+	suite("Build", testBuild)
+	suite("Detect", testDetect)
+	suite("ExecD", testExecD)
+	suite.Run(t)
+}
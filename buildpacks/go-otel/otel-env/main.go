@@ -0,0 +1,22 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	gootel "github.com/paketo-buildpacks/go/buildpacks/go-otel"
+)
+
+func main() {
+	output := os.NewFile(3, "/dev/fd/3")
+	defer output.Close()
+
+	// The platform directory only exists during the build, so the bindings of
+	// the running image are found through SERVICE_BINDING_ROOT, CNB_BINDINGS
+	// or VCAP_SERVICES.
+	err := gootel.ExecD("/platform", os.Getenv("OTEL_GO_AUTO_TARGET_EXE"), output)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,16 @@
+package main
+
+import (
+	"os"
+
+	gootel "github.com/paketo-buildpacks/go/buildpacks/go-otel"
+	"github.com/paketo-buildpacks/packit/v2"
+	"github.com/paketo-buildpacks/packit/v2/scribe"
+)
+
+func main() {
+	packit.Run(
+		gootel.Detect(),
+		gootel.Build(scribe.NewEmitter(os.Stdout).WithLevel(os.Getenv("BP_LOG_LEVEL"))),
+	)
+}
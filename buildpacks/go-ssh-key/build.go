@@ -0,0 +1,73 @@
+package gosshkey
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/paketo-buildpacks/go/sshkey"
+	"github.com/paketo-buildpacks/packit/v2"
+	"github.com/paketo-buildpacks/packit/v2/scribe"
+)
+
+// LayerName is the name of the build layer holding the key.
+const LayerName = "ssh-key"
+
+// Build installs the key and known_hosts of the ssh-key binding in a layer
+// that is only available during the build, and sets the environment that
+// makes git use them for go-mod-vendor and go-build. The layer is neither
+// cached nor exported, so the key never reaches the build cache or the image.
+func Build(logger scribe.Emitter) packit.BuildFunc {
+	return func(context packit.BuildContext) (packit.BuildResult, error) {
+		logger.Title("%s %s", context.BuildpackInfo.Name, context.BuildpackInfo.Version)
+
+		binding, ok, err := sshkey.Find(context.Platform.Path)
+		if err != nil {
+			return packit.BuildResult{}, err
+		}
+
+		if !ok {
+			return packit.BuildResult{}, fmt.Errorf("no %s binding found", sshkey.BindingType)
+		}
+
+		layer, err := context.Layers.Get(LayerName)
+		if err != nil {
+			return packit.BuildResult{}, err
+		}
+
+		layer, err = layer.Reset()
+		if err != nil {
+			return packit.BuildResult{}, err
+		}
+		layer.Build = true
+
+		installation, err := sshkey.Install(binding, layer.Path)
+		if err != nil {
+			return packit.BuildResult{}, err
+		}
+
+		environ, err := installation.Environ(os.Environ())
+		if err != nil {
+			return packit.BuildResult{}, err
+		}
+
+		// only the variables the key adds or changes are set for the
+		// buildpacks that follow, since they already see everything else
+		for _, variable := range environ {
+			name, value, _ := strings.Cut(variable, "=")
+			if current, ok := os.LookupEnv(name); !ok || current != value {
+				layer.BuildEnv.Override(name, value)
+			}
+		}
+
+		logger.Process("Installing the SSH key of binding %s", binding.Name)
+		for _, rewrite := range installation.Rewrites {
+			logger.Subprocess("%s -> %s", rewrite.From, rewrite.To)
+		}
+		logger.Break()
+
+		return packit.BuildResult{
+			Layers: []packit.Layer{layer},
+		}, nil
+	}
+}
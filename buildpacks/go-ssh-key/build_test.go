@@ -0,0 +1,121 @@
+package gosshkey_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	gosshkey "github.com/paketo-buildpacks/go/buildpacks/go-ssh-key"
+	"github.com/paketo-buildpacks/packit/v2"
+	"github.com/paketo-buildpacks/packit/v2/scribe"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testBuild(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		platformDir string
+		key         []byte
+		buffer      *bytes.Buffer
+		build       packit.BuildFunc
+		buildCtx    packit.BuildContext
+	)
+
+	it.Before(func() {
+		unsetBindingRoots(t)
+		t.Setenv("GIT_CONFIG_COUNT", "")
+		Expect(os.Unsetenv("GIT_CONFIG_COUNT")).To(Succeed())
+
+		platformDir = t.TempDir()
+		key = writeBinding(t, filepath.Join(platformDir, "bindings"))
+
+		buffer = bytes.NewBuffer(nil)
+		build = gosshkey.Build(scribe.NewEmitter(buffer))
+		buildCtx = packit.BuildContext{
+			BuildpackInfo: packit.BuildpackInfo{
+				Name:    "Some Buildpack",
+				Version: "some-version",
+			},
+			Platform: packit.Platform{Path: platformDir},
+			Layers:   packit.Layers{Path: t.TempDir()},
+		}
+	})
+
+	it("installs the key in a build-only layer and points git at it", func() {
+		result, err := build(buildCtx)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(result.Layers).To(HaveLen(1))
+		layer := result.Layers[0]
+		Expect(layer.Name).To(Equal("ssh-key"))
+		Expect(layer.Build).To(BeTrue())
+		Expect(layer.Launch).To(BeFalse())
+		Expect(layer.Cache).To(BeFalse())
+
+		keys, err := filepath.Glob(filepath.Join(layer.Path, "*", "id"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(keys).To(HaveLen(1))
+		Expect(os.ReadFile(keys[0])).To(Equal(key))
+
+		info, err := os.Stat(keys[0])
+		Expect(err).NotTo(HaveOccurred())
+		Expect(info.Mode().Perm()).To(Equal(os.FileMode(0600)))
+
+		Expect(layer.BuildEnv).To(HaveKeyWithValue("GIT_SSH_COMMAND.override", ContainSubstring("-i '"+keys[0]+"'")))
+		Expect(layer.BuildEnv).To(HaveKeyWithValue("GIT_SSH_COMMAND.override", ContainSubstring("StrictHostKeyChecking=yes")))
+		Expect(layer.BuildEnv).To(HaveKeyWithValue("GIT_CONFIG_KEY_0.override", "url.ssh://git@git.example.com/.insteadOf"))
+		Expect(layer.BuildEnv).To(HaveKeyWithValue("GIT_CONFIG_VALUE_0.override", "https://git.example.com/"))
+		Expect(layer.BuildEnv).To(HaveKeyWithValue("GIT_CONFIG_COUNT.override", "1"))
+		Expect(layer.LaunchEnv).To(BeEmpty())
+
+		Expect(buffer.String()).To(ContainSubstring("Some Buildpack some-version"))
+		Expect(buffer.String()).To(ContainSubstring("Installing the SSH key of binding ssh"))
+		Expect(buffer.String()).To(ContainSubstring("https://git.example.com/ -> ssh://git@git.example.com/"))
+	})
+
+	context("when earlier buildpacks or the user set git config entries", func() {
+		it.Before(func() {
+			t.Setenv("GIT_CONFIG_COUNT", "1")
+			t.Setenv("GIT_CONFIG_KEY_0", "url.https://mirror.example.com/.insteadOf")
+			t.Setenv("GIT_CONFIG_VALUE_0", "https://example.com/")
+		})
+
+		it("adds its entries after theirs", func() {
+			result, err := build(buildCtx)
+			Expect(err).NotTo(HaveOccurred())
+
+			env := result.Layers[0].BuildEnv
+			Expect(env).NotTo(HaveKey("GIT_CONFIG_KEY_0.override"))
+			Expect(env).To(HaveKeyWithValue("GIT_CONFIG_KEY_1.override", "url.ssh://git@git.example.com/.insteadOf"))
+			Expect(env).To(HaveKeyWithValue("GIT_CONFIG_COUNT.override", "2"))
+		})
+	})
+
+	context("failure cases", func() {
+		context("when the key is not valid", func() {
+			it.Before(func() {
+				Expect(os.WriteFile(filepath.Join(platformDir, "bindings", "ssh", "ssh-privatekey"), []byte("not a key"), 0644)).To(Succeed())
+			})
+
+			it("returns an error", func() {
+				_, err := build(buildCtx)
+				Expect(err).To(MatchError(ContainSubstring("binding ssh does not hold a valid SSH private key")))
+			})
+		})
+
+		context("when there is no binding", func() {
+			it.Before(func() {
+				Expect(os.RemoveAll(filepath.Join(platformDir, "bindings"))).To(Succeed())
+			})
+
+			it("returns an error", func() {
+				_, err := build(buildCtx)
+				Expect(err).To(MatchError("no ssh-key binding found"))
+			})
+		})
+	})
+}
@@ -0,0 +1,22 @@
+package gosshkey
+
+import (
+	"github.com/paketo-buildpacks/go/sshkey"
+	"github.com/paketo-buildpacks/packit/v2"
+)
+
+// Detect passes when there is an ssh-key service binding.
+func Detect() packit.DetectFunc {
+	return func(context packit.DetectContext) (packit.DetectResult, error) {
+		_, ok, err := sshkey.Find(context.Platform.Path)
+		if err != nil {
+			return packit.DetectResult{}, err
+		}
+
+		if !ok {
+			return packit.DetectResult{}, packit.Fail.WithMessage("no %s binding found", sshkey.BindingType)
+		}
+
+		return packit.DetectResult{}, nil
+	}
+}
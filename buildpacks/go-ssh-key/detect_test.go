@@ -0,0 +1,64 @@
+package gosshkey_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	gosshkey "github.com/paketo-buildpacks/go/buildpacks/go-ssh-key"
+	"github.com/paketo-buildpacks/packit/v2"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testDetect(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		platformDir string
+		detect      packit.DetectFunc
+	)
+
+	it.Before(func() {
+		unsetBindingRoots(t)
+		platformDir = t.TempDir()
+		detect = gosshkey.Detect()
+	})
+
+	it("passes without requirements when the platform has an ssh-key binding", func() {
+		writeBinding(t, filepath.Join(platformDir, "bindings"))
+
+		result, err := detect(packit.DetectContext{Platform: packit.Platform{Path: platformDir}})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result).To(Equal(packit.DetectResult{}))
+	})
+
+	it("reads the bindings from SERVICE_BINDING_ROOT", func() {
+		root := t.TempDir()
+		writeBinding(t, root)
+		t.Setenv("SERVICE_BINDING_ROOT", root)
+
+		_, err := detect(packit.DetectContext{Platform: packit.Platform{Path: platformDir}})
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	it("fails without an ssh-key binding", func() {
+		_, err := detect(packit.DetectContext{Platform: packit.Platform{Path: platformDir}})
+		Expect(err).To(MatchError(packit.Fail.WithMessage("no ssh-key binding found")))
+	})
+
+	context("failure cases", func() {
+		context("when the binding has no private key", func() {
+			it.Before(func() {
+				writeBinding(t, filepath.Join(platformDir, "bindings"))
+				Expect(os.Remove(filepath.Join(platformDir, "bindings", "ssh", "ssh-privatekey"))).To(Succeed())
+			})
+
+			it("returns an error", func() {
+				_, err := detect(packit.DetectContext{Platform: packit.Platform{Path: platformDir}})
+				Expect(err).To(MatchError("binding ssh is missing its ssh-privatekey entry"))
+			})
+		})
+	})
+}
@@ -0,0 +1,49 @@
+package gosshkey_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/paketo-buildpacks/go/sshkey"
+	"golang.org/x/crypto/ssh"
+
+	. "github.com/onsi/gomega"
+)
+
+// writeBinding writes an ssh-key binding with a new key to root and returns
+// the key.
+func writeBinding(t *testing.T, root string) []byte {
+	Expect := NewWithT(t).Expect
+
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	Expect(err).NotTo(HaveOccurred())
+
+	block, err := ssh.MarshalPrivateKey(private, "")
+	Expect(err).NotTo(HaveOccurred())
+	key := pem.EncodeToMemory(block)
+
+	hostKey, err := ssh.NewPublicKey(public)
+	Expect(err).NotTo(HaveOccurred())
+
+	binding := filepath.Join(root, "ssh")
+	Expect(os.MkdirAll(binding, os.ModePerm)).To(Succeed())
+	Expect(os.WriteFile(filepath.Join(binding, "type"), []byte(sshkey.BindingType), 0644)).To(Succeed())
+	Expect(os.WriteFile(filepath.Join(binding, sshkey.PrivateKeyEntry), key, 0644)).To(Succeed())
+	Expect(os.WriteFile(filepath.Join(binding, sshkey.KnownHostsEntry), []byte("git.example.com "+string(ssh.MarshalAuthorizedKey(hostKey))), 0644)).To(Succeed())
+
+	return key
+}
+
+// unsetBindingRoots clears the variables that override the binding root for
+// the duration of a spec.
+func unsetBindingRoots(t *testing.T) {
+	for _, name := range []string{"SERVICE_BINDING_ROOT", "CNB_BINDINGS", "VCAP_SERVICES"} {
+		t.Setenv(name, "")
+		Expect := NewWithT(t).Expect
+		Expect(os.Unsetenv(name)).To(Succeed())
+	}
+}
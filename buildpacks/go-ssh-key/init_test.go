@@ -0,0 +1,17 @@
+package gosshkey_test
+
+import (
+	"testing"
+
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+)
+
+func TestUnitGoSSHKey(t *testing.T) {
+	// the binding root and the git config are read from the environment, which
+	// the specs set with t.Setenv
+	suite := spec.New("go-ssh-key", spec.Report(report.Terminal{}), spec.Sequential())
+	suite("Build", testBuild)
+	suite("Detect", testDetect)
+	suite.Run(t)
+}
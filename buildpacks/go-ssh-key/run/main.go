@@ -0,0 +1,16 @@
+package main
+
+import (
+	"os"
+
+	gosshkey "github.com/paketo-buildpacks/go/buildpacks/go-ssh-key"
+	"github.com/paketo-buildpacks/packit/v2"
+	"github.com/paketo-buildpacks/packit/v2/scribe"
+)
+
+func main() {
+	packit.Run(
+		gosshkey.Detect(),
+		gosshkey.Build(scribe.NewEmitter(os.Stdout).WithLevel(os.Getenv("BP_LOG_LEVEL"))),
+	)
+}
@@ -0,0 +1,94 @@
+package sbomaggregator
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/paketo-buildpacks/go/sbom"
+	"github.com/paketo-buildpacks/packit/v2"
+	"github.com/paketo-buildpacks/packit/v2/scribe"
+)
+
+// normalizedTime is the creation time the lifecycle gives reproducible
+// images, used when SOURCE_DATE_EPOCH is not set so that the merged SPDX
+// document does not change between builds.
+var normalizedTime = time.Date(1980, time.January, 1, 0, 0, 1, 0, time.UTC)
+
+// Build merges the CycloneDX and SPDX launch SBOMs written by the buildpacks
+// that ran before it into one document per format, and writes them as its own
+// launch SBOM.
+func Build(logger scribe.Emitter) packit.BuildFunc {
+	return func(context packit.BuildContext) (packit.BuildResult, error) {
+		logger.Title("%s %s", context.BuildpackInfo.Name, context.BuildpackInfo.Version)
+
+		created := normalizedTime
+		if epoch, ok := os.LookupEnv("SOURCE_DATE_EPOCH"); ok {
+			seconds, err := strconv.ParseInt(epoch, 10, 64)
+			if err != nil {
+				return packit.BuildResult{}, fmt.Errorf("failed to parse SOURCE_DATE_EPOCH: %w", err)
+			}
+			created = time.Unix(seconds, 0)
+		}
+
+		layersDir := filepath.Dir(context.Layers.Path)
+		self := filepath.Base(context.Layers.Path)
+		name := filepath.Base(context.WorkingDir)
+
+		cyclonedx, err := read(layersDir, self, sbom.CycloneDXExtension)
+		if err != nil {
+			return packit.BuildResult{}, err
+		}
+
+		spdx, err := read(layersDir, self, sbom.SPDXExtension)
+		if err != nil {
+			return packit.BuildResult{}, err
+		}
+
+		logger.Process("Merging launch SBOMs")
+		logger.Subprocess("%d CycloneDX documents", len(cyclonedx))
+		logger.Subprocess("%d SPDX documents", len(spdx))
+		logger.Break()
+
+		mergedCycloneDX, err := sbom.MergeCycloneDX(name, cyclonedx...)
+		if err != nil {
+			return packit.BuildResult{}, err
+		}
+
+		mergedSPDX, err := sbom.MergeSPDX(name, created, spdx...)
+		if err != nil {
+			return packit.BuildResult{}, err
+		}
+
+		return packit.BuildResult{
+			Launch: packit.LaunchMetadata{
+				SBOM: packit.SBOMFormats{
+					{Extension: sbom.CycloneDXExtension, Content: bytes.NewReader(mergedCycloneDX)},
+					{Extension: sbom.SPDXExtension, Content: bytes.NewReader(mergedSPDX)},
+				},
+			},
+		}, nil
+	}
+}
+
+func read(layersDir, self, extension string) ([][]byte, error) {
+	paths, err := sbom.FindLaunch(layersDir, self, extension)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find launch SBOMs: %w", err)
+	}
+
+	var documents [][]byte
+	for _, path := range paths {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		documents = append(documents, content)
+	}
+
+	return documents, nil
+}
@@ -0,0 +1,135 @@
+package sbomaggregator_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	sbomaggregator "github.com/paketo-buildpacks/go/buildpacks/sbom-aggregator"
+	"github.com/paketo-buildpacks/go/sbom"
+	"github.com/paketo-buildpacks/packit/v2"
+	"github.com/paketo-buildpacks/packit/v2/scribe"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testBuild(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		layersDir string
+		buffer    *bytes.Buffer
+		build     packit.BuildFunc
+		buildCtx  packit.BuildContext
+	)
+
+	copyFile := func(from, to string) {
+		content, err := os.ReadFile(from)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(os.MkdirAll(filepath.Dir(to), os.ModePerm)).To(Succeed())
+		Expect(os.WriteFile(to, content, 0600)).To(Succeed())
+	}
+
+	formats := func(result packit.BuildResult) map[string][]byte {
+		documents := map[string][]byte{}
+		for _, format := range result.Launch.SBOM.Formats() {
+			content, err := io.ReadAll(format.Content)
+			Expect(err).NotTo(HaveOccurred())
+			documents[format.Extension] = content
+		}
+		return documents
+	}
+
+	it.Before(func() {
+		layersDir = t.TempDir()
+
+		goBuild := filepath.Join(layersDir, "paketo-buildpacks_go-build")
+		Expect(os.MkdirAll(goBuild, os.ModePerm)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(goBuild, "targets.toml"), []byte("[types]\n  launch = true\n"), 0600)).To(Succeed())
+		copyFile(filepath.Join("..", "..", "sbom", "testdata", "go-build.cdx.json"), filepath.Join(goBuild, "targets.sbom.cdx.json"))
+		copyFile(filepath.Join("..", "..", "sbom", "testdata", "go-build.spdx.json"), filepath.Join(goBuild, "targets.sbom.spdx.json"))
+
+		// a build-only document that must not end up in the launch SBOM
+		copyFile(filepath.Join("..", "..", "sbom", "testdata", "go-mod-vendor.cdx.json"), filepath.Join(layersDir, "paketo-buildpacks_go-mod-vendor", "build.sbom.cdx.json"))
+
+		self := filepath.Join(layersDir, "paketo-buildpacks_sbom-aggregator")
+		Expect(os.MkdirAll(self, os.ModePerm)).To(Succeed())
+
+		buffer = bytes.NewBuffer(nil)
+		build = sbomaggregator.Build(scribe.NewEmitter(buffer))
+		buildCtx = packit.BuildContext{
+			BuildpackInfo: packit.BuildpackInfo{
+				Name:    "Some Buildpack",
+				Version: "some-version",
+			},
+			WorkingDir: filepath.Join(t.TempDir(), "workspace"),
+			Layers:     packit.Layers{Path: self},
+		}
+	})
+
+	it("writes the merged launch SBOMs as its own launch SBOM", func() {
+		result, err := build(buildCtx)
+		Expect(err).NotTo(HaveOccurred())
+
+		documents := formats(result)
+		Expect(documents).To(HaveLen(2))
+		Expect(sbom.Validate(documents[sbom.CycloneDXExtension])).To(Succeed())
+		Expect(sbom.Validate(documents[sbom.SPDXExtension])).To(Succeed())
+
+		var cyclonedx sbom.CycloneDXDocument
+		Expect(json.Unmarshal(documents[sbom.CycloneDXExtension], &cyclonedx)).To(Succeed())
+		Expect(cyclonedx.Metadata.Component).To(HaveKeyWithValue("name", "workspace"))
+		Expect(cyclonedx.Components).NotTo(BeEmpty())
+
+		var spdx sbom.SPDXDocument
+		Expect(json.Unmarshal(documents[sbom.SPDXExtension], &spdx)).To(Succeed())
+		Expect(spdx.CreationInfo.Created).To(Equal("1980-01-01T00:00:01Z"))
+
+		Expect(buffer.String()).To(ContainSubstring("Some Buildpack some-version"))
+		Expect(buffer.String()).To(ContainSubstring("1 CycloneDX documents"))
+		Expect(buffer.String()).To(ContainSubstring("1 SPDX documents"))
+	})
+
+	context("when SOURCE_DATE_EPOCH is set", func() {
+		it.Before(func() {
+			t.Setenv("SOURCE_DATE_EPOCH", "1700000000")
+		})
+
+		it("uses it as the creation time of the SPDX document", func() {
+			result, err := build(buildCtx)
+			Expect(err).NotTo(HaveOccurred())
+
+			var spdx sbom.SPDXDocument
+			Expect(json.Unmarshal(formats(result)[sbom.SPDXExtension], &spdx)).To(Succeed())
+			Expect(spdx.CreationInfo.Created).To(Equal("2023-11-14T22:13:20Z"))
+		})
+	})
+
+	context("failure cases", func() {
+		context("when SOURCE_DATE_EPOCH is not a number", func() {
+			it.Before(func() {
+				t.Setenv("SOURCE_DATE_EPOCH", "yesterday")
+			})
+
+			it("returns an error", func() {
+				_, err := build(buildCtx)
+				Expect(err).To(MatchError(ContainSubstring("failed to parse SOURCE_DATE_EPOCH")))
+			})
+		})
+
+		context("when the layer metadata cannot be parsed", func() {
+			it.Before(func() {
+				Expect(os.WriteFile(filepath.Join(layersDir, "paketo-buildpacks_go-build", "targets.toml"), []byte("%%%"), 0600)).To(Succeed())
+			})
+
+			it("returns an error", func() {
+				_, err := build(buildCtx)
+				Expect(err).To(MatchError(ContainSubstring("failed to find launch SBOMs")))
+			})
+		})
+	})
+}
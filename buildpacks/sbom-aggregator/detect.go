@@ -0,0 +1,11 @@
+package sbomaggregator
+
+import "github.com/paketo-buildpacks/packit/v2"
+
+// Detect always passes. The buildpack is optional and comes last in every
+// order group, so that it sees the launch SBOMs of every other buildpack.
+func Detect() packit.DetectFunc {
+	return func(context packit.DetectContext) (packit.DetectResult, error) {
+		return packit.DetectResult{}, nil
+	}
+}
@@ -0,0 +1,21 @@
+package sbomaggregator_test
+
+import (
+	"testing"
+
+	sbomaggregator "github.com/paketo-buildpacks/go/buildpacks/sbom-aggregator"
+	"github.com/paketo-buildpacks/packit/v2"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testDetect(t *testing.T, context spec.G, it spec.S) {
+	var Expect = NewWithT(t).Expect
+
+	it("always passes without requirements", func() {
+		result, err := sbomaggregator.Detect()(packit.DetectContext{WorkingDir: t.TempDir()})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result).To(Equal(packit.DetectResult{}))
+	})
+}
@@ -0,0 +1,16 @@
+package sbomaggregator_test
+
+import (
+	"testing"
+
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+)
+
+func TestUnitSBOMAggregator(t *testing.T) {
+	// Build reads SOURCE_DATE_EPOCH, which the specs set with t.Setenv.
+	suite := spec.New("sbom-aggregator", spec.Report(report.Terminal{}), spec.Sequential())
+	suite("Build", testBuild)
+	suite("Detect", testDetect)
+	suite.Run(t)
+}
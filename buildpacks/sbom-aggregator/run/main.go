@@ -0,0 +1,16 @@
+package main
+
+import (
+	"os"
+
+	sbomaggregator "github.com/paketo-buildpacks/go/buildpacks/sbom-aggregator"
+	"github.com/paketo-buildpacks/packit/v2"
+	"github.com/paketo-buildpacks/packit/v2/scribe"
+)
+
+func main() {
+	packit.Run(
+		sbomaggregator.Detect(),
+		sbomaggregator.Build(scribe.NewEmitter(os.Stdout).WithLevel(os.Getenv("BP_LOG_LEVEL"))),
+	)
+}
@@ -0,0 +1,204 @@
+// Package buildtool builds Go applications whose build is driven by a make or
+// Task target instead of go build, and collects the binaries the target
+// declares as its outputs.
+package buildtool
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// TargetEnv names the make or Task target that builds the application.
+	// Nothing is detected without it, so that Makefiles that are only used
+	// during development do not change how an application is built.
+	TargetEnv = "BP_GO_BUILD_TOOL_TARGET"
+
+	// OutputsEnv lists the binaries the target produces, relative to the
+	// application directory.
+	OutputsEnv = "BP_GO_BUILD_TOOL_OUTPUTS"
+)
+
+// Tool is a build tool that runs targets.
+type Tool string
+
+const (
+	Make Tool = "make"
+	Task Tool = "task"
+)
+
+// makefiles are the names make looks for, in the order it looks for them.
+var makefiles = []string{"GNUmakefile", "makefile", "Makefile"}
+
+// taskfiles are the names task looks for, in the order it looks for them.
+var taskfiles = []string{"Taskfile.yml", "taskfile.yml", "Taskfile.yaml", "taskfile.yaml"}
+
+// makeRule matches the targets of a rule, but not variable assignments such as
+// FOO := bar or FOO ::= bar.
+var makeRule = regexp.MustCompile(`^([^\s:#=][^:#=]*)::?(?:[^:=]|$)`)
+
+// Plan is a target to build an application with.
+type Plan struct {
+	Tool Tool
+
+	// File is the name of the Makefile or Taskfile in the application
+	// directory.
+	File string
+
+	Target string
+}
+
+// Detect looks for a Makefile or a Taskfile in dir that defines target. A
+// Makefile wins when both define it, as in the make and task documentation.
+// Each tool only reads the first of its files that exists, so a Taskfile is
+// still used when that Makefile does not define the target.
+func Detect(dir, target string) (Plan, bool, error) {
+	if target == "" {
+		return Plan{}, false, nil
+	}
+
+	for _, candidate := range []struct {
+		tool  Tool
+		files []string
+		has   func(path, target string) (bool, error)
+	}{
+		{Make, makefiles, makefileHasTarget},
+		{Task, taskfiles, taskfileHasTarget},
+	} {
+		for _, file := range candidate.files {
+			path := filepath.Join(dir, file)
+			_, err := os.Stat(path)
+			if err != nil {
+				if errors.Is(err, os.ErrNotExist) {
+					continue
+				}
+				return Plan{}, false, err
+			}
+
+			ok, err := candidate.has(path, target)
+			if err != nil {
+				return Plan{}, false, fmt.Errorf("failed to parse %s: %w", file, err)
+			}
+
+			if !ok {
+				break
+			}
+
+			return Plan{Tool: candidate.tool, File: file, Target: target}, true, nil
+		}
+	}
+
+	return Plan{}, false, nil
+}
+
+// Run runs the target of a plan in dir. The directories in path, such as the
+// bin directory of the Go distribution, are put in front of PATH, so that the
+// target and the tool itself are found there first.
+func (p Plan) Run(dir string, path []string, stdout, stderr io.Writer) error {
+	environ := os.Environ()
+	searchPath := strings.Join(append(path, os.Getenv("PATH")), string(os.PathListSeparator))
+	for i, variable := range environ {
+		if strings.HasPrefix(variable, "PATH=") {
+			environ = append(environ[:i:i], environ[i+1:]...)
+			break
+		}
+	}
+	environ = append(environ, "PATH="+searchPath)
+
+	var args []string
+	switch p.Tool {
+	case Make:
+		args = []string{"--file", p.File, p.Target}
+	case Task:
+		args = []string{"--taskfile", p.File, p.Target}
+	default:
+		return fmt.Errorf("unsupported build tool %q", p.Tool)
+	}
+
+	executable, err := lookPath(string(p.Tool), searchPath)
+	if err != nil {
+		return err
+	}
+
+	command := exec.Command(executable, args...)
+	command.Dir = dir
+	command.Env = environ
+	command.Stdout = stdout
+	command.Stderr = stderr
+
+	err = command.Run()
+	if err != nil {
+		return fmt.Errorf("failed to run %s %s: %w", p.Tool, p.Target, err)
+	}
+
+	return nil
+}
+
+func makefileHasTarget(path, target string) (bool, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return false, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		match := makeRule.FindStringSubmatch(scanner.Text())
+		if match == nil {
+			continue
+		}
+
+		for _, name := range strings.Fields(match[1]) {
+			if name == target {
+				return true, nil
+			}
+		}
+	}
+
+	return false, scanner.Err()
+}
+
+func taskfileHasTarget(path, target string) (bool, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return false, err
+	}
+
+	var taskfile struct {
+		Tasks map[string]yaml.Node `yaml:"tasks"`
+	}
+	err = yaml.Unmarshal(content, &taskfile)
+	if err != nil {
+		return false, err
+	}
+
+	_, ok := taskfile.Tasks[target]
+	return ok, nil
+}
+
+// lookPath finds an executable in a search path other than the one of the
+// current process.
+func lookPath(name, searchPath string) (string, error) {
+	for _, dir := range filepath.SplitList(searchPath) {
+		if dir == "" {
+			continue
+		}
+
+		path := filepath.Join(dir, name)
+		info, err := os.Stat(path)
+		if err == nil && info.Mode().IsRegular() && info.Mode()&0111 != 0 {
+			return path, nil
+		}
+	}
+
+	return "", fmt.Errorf("%s is not installed: no %s executable on PATH", name, name)
+}
@@ -0,0 +1,126 @@
+package buildtool_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/paketo-buildpacks/go/buildtool"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testBuildTool(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		dir string
+	)
+
+	it.Before(func() {
+		dir = t.TempDir()
+	})
+
+	context("Detect", func() {
+		it("finds a Makefile that defines the target", func() {
+			Expect(os.WriteFile(filepath.Join(dir, "Makefile"), []byte("VERSION := 1.0.0\n.PHONY: build test\n\ntest build: generate\n\tgo build -o bin/app .\n"), 0644)).To(Succeed())
+
+			plan, ok, err := buildtool.Detect(dir, "build")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ok).To(BeTrue())
+			Expect(plan).To(Equal(buildtool.Plan{Tool: buildtool.Make, File: "Makefile", Target: "build"}))
+		})
+
+		it("does not mistake variable assignments for targets", func() {
+			Expect(os.WriteFile(filepath.Join(dir, "Makefile"), []byte("build := yes\nbuild::= yes\n"), 0644)).To(Succeed())
+
+			_, ok, err := buildtool.Detect(dir, "build")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ok).To(BeFalse())
+		})
+
+		it("finds a Taskfile that defines the target", func() {
+			Expect(os.WriteFile(filepath.Join(dir, "Taskfile.yml"), []byte("version: '3'\ntasks:\n  build:\n    cmds:\n      - go build -o bin/app .\n"), 0644)).To(Succeed())
+
+			plan, ok, err := buildtool.Detect(dir, "build")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ok).To(BeTrue())
+			Expect(plan).To(Equal(buildtool.Plan{Tool: buildtool.Task, File: "Taskfile.yml", Target: "build"}))
+		})
+
+		it("prefers the Makefile when both exist", func() {
+			Expect(os.WriteFile(filepath.Join(dir, "Makefile"), []byte("build:\n"), 0644)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(dir, "Taskfile.yml"), []byte("tasks:\n  build: {}\n"), 0644)).To(Succeed())
+
+			plan, ok, err := buildtool.Detect(dir, "build")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ok).To(BeTrue())
+			Expect(plan.Tool).To(Equal(buildtool.Make))
+		})
+
+		it("falls back to the Taskfile when the Makefile does not define the target", func() {
+			Expect(os.WriteFile(filepath.Join(dir, "Makefile"), []byte("test:\n"), 0644)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(dir, "Taskfile.yml"), []byte("tasks:\n  build: {}\n"), 0644)).To(Succeed())
+
+			plan, ok, err := buildtool.Detect(dir, "build")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ok).To(BeTrue())
+			Expect(plan).To(Equal(buildtool.Plan{Tool: buildtool.Task, File: "Taskfile.yml", Target: "build"}))
+		})
+
+		it("does not detect without a target or when the target is missing", func() {
+			Expect(os.WriteFile(filepath.Join(dir, "Taskfile.yml"), []byte("tasks:\n  test: {}\n"), 0644)).To(Succeed())
+
+			_, ok, err := buildtool.Detect(dir, "")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ok).To(BeFalse())
+
+			_, ok, err = buildtool.Detect(dir, "build")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ok).To(BeFalse())
+		})
+
+		it("returns an error for a Taskfile that is not valid YAML", func() {
+			Expect(os.WriteFile(filepath.Join(dir, "Taskfile.yml"), []byte("tasks: [unterminated\n"), 0644)).To(Succeed())
+
+			_, _, err := buildtool.Detect(dir, "build")
+			Expect(err).To(MatchError(ContainSubstring("failed to parse Taskfile.yml")))
+		})
+	})
+
+	context("Run", func() {
+		var toolDir string
+
+		it.Before(func() {
+			// a stand-in for task that records its arguments and the PATH it was
+			// run with
+			toolDir = t.TempDir()
+			Expect(os.WriteFile(filepath.Join(toolDir, "task"), []byte("#!/bin/sh\necho \"$@\"\necho \"$PATH\"\n"), 0755)).To(Succeed())
+		})
+
+		it("runs the target with the given directories in front of PATH", func() {
+			var stdout bytes.Buffer
+			plan := buildtool.Plan{Tool: buildtool.Task, File: "Taskfile.yml", Target: "build"}
+			Expect(plan.Run(dir, []string{toolDir, "/go/bin"}, &stdout, &stdout)).To(Succeed())
+
+			Expect(stdout.String()).To(HavePrefix("--taskfile Taskfile.yml build\n" + toolDir + ":/go/bin:"))
+		})
+
+		it("returns an error for an unsupported tool", func() {
+			plan := buildtool.Plan{Tool: "bazel", File: "BUILD", Target: "build"}
+
+			err := plan.Run(dir, []string{toolDir}, &bytes.Buffer{}, &bytes.Buffer{})
+			Expect(err).To(MatchError(`unsupported build tool "bazel"`))
+		})
+
+		it("returns an error when the target fails", func() {
+			Expect(os.WriteFile(filepath.Join(toolDir, "task"), []byte("#!/bin/sh\nexit 2\n"), 0755)).To(Succeed())
+
+			plan := buildtool.Plan{Tool: buildtool.Task, File: "Taskfile.yml", Target: "build"}
+			err := plan.Run(dir, []string{toolDir}, &bytes.Buffer{}, &bytes.Buffer{})
+			Expect(err).To(MatchError("failed to run task build: exit status 2"))
+		})
+	})
+}
@@ -0,0 +1,101 @@
+package buildtool
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Process is a process type that runs one of the collected binaries.
+type Process struct {
+	Type    string `toml:"type"`
+	Command string `toml:"command"`
+	Direct  bool   `toml:"direct"`
+	Default bool   `toml:"default"`
+}
+
+// ParseOutputs splits a comma or whitespace separated list of outputs.
+func ParseOutputs(value string) []string {
+	return strings.FieldsFunc(value, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t' || r == '\n'
+	})
+}
+
+// Collect copies the binaries a target produced in dir to the bin directory
+// of a launch layer and returns a process type for each of them, named after
+// the binary. The first output is the default process.
+func Collect(dir string, outputs []string, layerDir string) ([]Process, error) {
+	if len(outputs) == 0 {
+		return nil, fmt.Errorf("no outputs declared")
+	}
+
+	binDir := filepath.Join(layerDir, "bin")
+	err := os.MkdirAll(binDir, os.ModePerm)
+	if err != nil {
+		return nil, err
+	}
+
+	var processes []Process
+	types := map[string]string{}
+	for i, output := range outputs {
+		if !filepath.IsLocal(output) {
+			return nil, fmt.Errorf("output %s is not inside the application directory", output)
+		}
+
+		name := filepath.Base(output)
+		if other, ok := types[name]; ok {
+			return nil, fmt.Errorf("outputs %s and %s would both be named %s", other, output, name)
+		}
+		types[name] = output
+
+		info, err := os.Stat(filepath.Join(dir, output))
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil, fmt.Errorf("output %s was not produced", output)
+			}
+			return nil, err
+		}
+
+		if !info.Mode().IsRegular() || info.Mode()&0111 == 0 {
+			return nil, fmt.Errorf("output %s is not an executable file", output)
+		}
+
+		destination := filepath.Join(binDir, name)
+		err = copyFile(filepath.Join(dir, output), destination)
+		if err != nil {
+			return nil, err
+		}
+
+		processes = append(processes, Process{
+			Type:    name,
+			Command: destination,
+			Direct:  true,
+			Default: i == 0,
+		})
+	}
+
+	return processes, nil
+}
+
+func copyFile(source, destination string) error {
+	input, err := os.Open(source)
+	if err != nil {
+		return err
+	}
+	defer input.Close()
+
+	output, err := os.OpenFile(destination, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0755)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(output, input)
+	if err != nil {
+		_ = output.Close()
+		return err
+	}
+
+	return output.Close()
+}
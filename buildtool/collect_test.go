@@ -0,0 +1,79 @@
+package buildtool_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/paketo-buildpacks/go/buildtool"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testCollect(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		dir      string
+		layerDir string
+	)
+
+	it.Before(func() {
+		dir = t.TempDir()
+		layerDir = t.TempDir()
+
+		Expect(os.MkdirAll(filepath.Join(dir, "bin"), os.ModePerm)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(dir, "bin", "server"), []byte("server"), 0755)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(dir, "bin", "worker"), []byte("worker"), 0755)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(dir, "README.md"), []byte("readme"), 0644)).To(Succeed())
+	})
+
+	context("Collect", func() {
+		it("copies the outputs to the layer and returns a process for each", func() {
+			processes, err := buildtool.Collect(dir, []string{"bin/server", "bin/worker"}, layerDir)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(processes).To(Equal([]buildtool.Process{
+				{Type: "server", Command: filepath.Join(layerDir, "bin", "server"), Direct: true, Default: true},
+				{Type: "worker", Command: filepath.Join(layerDir, "bin", "worker"), Direct: true},
+			}))
+
+			Expect(os.ReadFile(filepath.Join(layerDir, "bin", "worker"))).To(Equal([]byte("worker")))
+			info, err := os.Stat(filepath.Join(layerDir, "bin", "server"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(info.Mode().Perm()).To(Equal(os.FileMode(0755)))
+		})
+
+		it("returns an error for outputs that were not produced or are not executable", func() {
+			_, err := buildtool.Collect(dir, []string{"bin/missing"}, layerDir)
+			Expect(err).To(MatchError("output bin/missing was not produced"))
+
+			_, err = buildtool.Collect(dir, []string{"README.md"}, layerDir)
+			Expect(err).To(MatchError("output README.md is not an executable file"))
+		})
+
+		it("returns an error for outputs outside of the application", func() {
+			_, err := buildtool.Collect(dir, []string{"../server"}, layerDir)
+			Expect(err).To(MatchError("output ../server is not inside the application directory"))
+		})
+
+		it("returns an error for outputs with the same name", func() {
+			Expect(os.WriteFile(filepath.Join(dir, "server"), []byte("server"), 0755)).To(Succeed())
+
+			_, err := buildtool.Collect(dir, []string{"bin/server", "server"}, layerDir)
+			Expect(err).To(MatchError("outputs bin/server and server would both be named server"))
+		})
+
+		it("returns an error without outputs", func() {
+			_, err := buildtool.Collect(dir, nil, layerDir)
+			Expect(err).To(MatchError("no outputs declared"))
+		})
+	})
+
+	context("ParseOutputs", func() {
+		it("splits a comma or space separated list", func() {
+			Expect(buildtool.ParseOutputs("bin/server, bin/worker  bin/cli,")).To(Equal([]string{"bin/server", "bin/worker", "bin/cli"}))
+			Expect(buildtool.ParseOutputs("")).To(BeEmpty())
+		})
+	})
+}
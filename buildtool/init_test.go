@@ -0,0 +1,15 @@
+package buildtool_test
+
+import (
+	"testing"
+
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+)
+
+func TestUnitBuildTool(t *testing.T) {
+	suite := spec.New("buildtool", spec.Report(report.Terminal{}), spec.Parallel())
+	suite("BuildTool", testBuildTool)
+	suite("Collect", testCollect)
+	suite.Run(t)
+}
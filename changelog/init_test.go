@@ -0,0 +1,15 @@
+package changelog_test
+
+import (
+	"testing"
+
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+)
+
+func TestUnitChangelog(t *testing.T) {
+	suite := spec.New("changelog", spec.Report(report.Terminal{}), spec.Parallel())
+	suite("ReleaseCache", testReleaseCache)
+	suite("Render", testRender)
+	suite.Run(t)
+}
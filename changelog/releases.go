@@ -0,0 +1,94 @@
+package changelog
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+// Release is the metadata of a single component release, in the shape
+// returned by the GitHub releases API.
+type Release struct {
+	TagName string `json:"tag_name"`
+	Name    string `json:"name"`
+	Body    string `json:"body"`
+	URL     string `json:"html_url"`
+}
+
+// ReleaseCache reads component release metadata from a local directory, so
+// that release notes can be rendered without network access. The directory
+// holds one <component>.json file per component (e.g. go-build.json), each
+// containing a JSON array of releases.
+type ReleaseCache struct {
+	Dir string
+}
+
+// NewReleaseCache returns a ReleaseCache reading from the given directory.
+func NewReleaseCache(dir string) ReleaseCache {
+	return ReleaseCache{Dir: dir}
+}
+
+// Between returns the cached releases of the component with the given id
+// whose versions are greater than from and at most to, oldest first. A
+// component without cached metadata has no releases.
+func (c ReleaseCache) Between(id, from, to string) ([]Release, error) {
+	content, err := os.ReadFile(filepath.Join(c.Dir, fmt.Sprintf("%s.json", path.Base(id))))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("failed to read release metadata for %s: %w", id, err)
+	}
+
+	var releases []Release
+	err = json.Unmarshal(content, &releases)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse release metadata for %s: %w", id, err)
+	}
+
+	lower, err := semver.NewVersion(from)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse version %q of %s: %w", from, id, err)
+	}
+
+	upper, err := semver.NewVersion(to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse version %q of %s: %w", to, id, err)
+	}
+
+	type versioned struct {
+		version *semver.Version
+		release Release
+	}
+
+	var matches []versioned
+	for _, release := range releases {
+		version, err := semver.NewVersion(strings.TrimPrefix(release.TagName, "v"))
+		if err != nil {
+			continue
+		}
+
+		if version.GreaterThan(lower) && !version.GreaterThan(upper) {
+			matches = append(matches, versioned{version: version, release: release})
+		}
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		return matches[i].version.LessThan(matches[j].version)
+	})
+
+	var between []Release
+	for _, match := range matches {
+		between = append(between, match.release)
+	}
+
+	return between, nil
+}
@@ -0,0 +1,70 @@
+package changelog_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/paketo-buildpacks/go/changelog"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testReleaseCache(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		cache changelog.ReleaseCache
+	)
+
+	it.Before(func() {
+		cache = changelog.NewReleaseCache(filepath.Join("testdata", "releases"))
+	})
+
+	it("returns the releases after the old version up to the new version, oldest first", func() {
+		releases, err := cache.Between("paketo-buildpacks/go-build", "2.4.20", "2.5.1")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(releases).To(Equal([]changelog.Release{
+			{
+				TagName: "v2.5.0",
+				Name:    "v2.5.0",
+				Body:    "* Adds support for BP_GO_BUILD_FULL_PATHS",
+				URL:     "https://github.com/paketo-buildpacks/go-build/releases/tag/v2.5.0",
+			},
+			{
+				TagName: "v2.5.1",
+				Name:    "v2.5.1",
+				Body:    "* Fixes flag parsing when BP_GO_BUILD_FLAGS contains quotes",
+				URL:     "https://github.com/paketo-buildpacks/go-build/releases/tag/v2.5.1",
+			},
+		}))
+	})
+
+	context("when the component has no cached metadata", func() {
+		it("returns no releases", func() {
+			releases, err := cache.Between("paketo-buildpacks/go-dist", "2.10.9", "2.10.10")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(releases).To(BeEmpty())
+		})
+	})
+
+	context("failure cases", func() {
+		context("when the metadata is malformed", func() {
+			it("returns an error", func() {
+				dir := t.TempDir()
+				Expect(os.WriteFile(filepath.Join(dir, "go-build.json"), []byte("%%%"), 0600)).To(Succeed())
+
+				_, err := changelog.NewReleaseCache(dir).Between("paketo-buildpacks/go-build", "2.4.20", "2.5.1")
+				Expect(err).To(MatchError(ContainSubstring("failed to parse release metadata for paketo-buildpacks/go-build")))
+			})
+		})
+
+		context("when a version is not a semantic version", func() {
+			it("returns an error", func() {
+				_, err := cache.Between("paketo-buildpacks/go-build", "latest", "2.5.1")
+				Expect(err).To(MatchError(ContainSubstring(`failed to parse version "latest" of paketo-buildpacks/go-build`)))
+			})
+		})
+	})
+}
@@ -0,0 +1,134 @@
+package changelog
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/paketo-buildpacks/go/components"
+)
+
+// Releases looks up the releases of a component between two versions.
+type Releases interface {
+	Between(id, from, to string) ([]Release, error)
+}
+
+// Render formats the given component changes as Markdown release notes. When
+// releases is not nil, the notes of every component release included in an
+// update are appended.
+func Render(changes []components.Change, releases Releases) (string, error) {
+	var added, removed, updated []components.Change
+	for _, change := range changes {
+		switch change.Type {
+		case components.Added:
+			added = append(added, change)
+		case components.Removed:
+			removed = append(removed, change)
+		case components.Updated:
+			updated = append(updated, change)
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString("## Component Changes\n")
+
+	if len(changes) == 0 {
+		b.WriteString("\nNo component buildpacks changed.\n")
+		return b.String(), nil
+	}
+
+	if len(added) > 0 {
+		b.WriteString("\n### Added\n\n")
+		for _, change := range added {
+			fmt.Fprintf(&b, "* `%s` %s%s\n", change.ID, change.New.Version, optional(change.New))
+		}
+	}
+
+	if len(removed) > 0 {
+		b.WriteString("\n### Removed\n\n")
+		for _, change := range removed {
+			fmt.Fprintf(&b, "* `%s` %s%s\n", change.ID, change.Old.Version, optional(change.Old))
+		}
+	}
+
+	if len(updated) > 0 {
+		b.WriteString("\n### Updated\n\n")
+		b.WriteString("| Buildpack | Old Version | New Version | Change |\n")
+		b.WriteString("|---|---|---|---|\n")
+		for _, change := range updated {
+			fmt.Fprintf(&b, "| `%s` | %s | %s | %s |\n", change.ID, change.Old.Version, change.New.Version, describe(change))
+		}
+	}
+
+	if releases == nil {
+		return b.String(), nil
+	}
+
+	var notes strings.Builder
+	for _, change := range updated {
+		if change.Downgrade || change.Old.Version == change.New.Version {
+			continue
+		}
+
+		included, err := releases.Between(change.ID, change.Old.Version, change.New.Version)
+		if err != nil {
+			return "", err
+		}
+
+		for _, release := range included {
+			title := release.Name
+			if title == "" {
+				title = release.TagName
+			}
+
+			fmt.Fprintf(&notes, "\n<details>\n<summary><code>%s</code> %s</summary>\n\n", change.ID, title)
+			if release.URL != "" {
+				fmt.Fprintf(&notes, "[Release](%s)\n\n", release.URL)
+			}
+			fmt.Fprintf(&notes, "%s\n\n</details>\n", strings.TrimSpace(release.Body))
+		}
+	}
+
+	if notes.Len() > 0 {
+		b.WriteString("\n### Release Notes\n")
+		b.WriteString(notes.String())
+	}
+
+	return b.String(), nil
+}
+
+func optional(component components.Component) string {
+	if component.Optional {
+		return " (optional)"
+	}
+
+	return ""
+}
+
+func describe(change components.Change) string {
+	var parts []string
+	if change.Bump != components.NoBump {
+		bump := string(change.Bump)
+		if change.Downgrade {
+			bump += " downgrade"
+		}
+		parts = append(parts, bump)
+	}
+
+	if change.Old.Optional != change.New.Optional {
+		if change.New.Optional {
+			parts = append(parts, "now optional")
+		} else {
+			parts = append(parts, "now required")
+		}
+	}
+
+	if change.Old.URI != change.New.URI && change.Old.Version == change.New.Version {
+		parts = append(parts, "packaged from "+change.New.URI)
+	}
+
+	if change.SourceChanged {
+		parts = append(parts, "source changed")
+	}
+
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,102 @@
+package changelog_test
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/paketo-buildpacks/go/changelog"
+	"github.com/paketo-buildpacks/go/components"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+type failingReleases struct{}
+
+func (failingReleases) Between(id, from, to string) ([]changelog.Release, error) {
+	return nil, errors.New("failed to look up releases")
+}
+
+func testRender(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		changes []components.Change
+	)
+
+	it.Before(func() {
+		changes = components.Diff(
+			[]components.Component{
+				{ID: "paketo-buildpacks/go-dist", Version: "2.10.9"},
+				{ID: "paketo-buildpacks/go-build", Version: "2.4.20"},
+				{ID: "paketo-buildpacks/procfile", Version: "5.13.2", Optional: true},
+				{ID: "paketo-buildpacks/dep", Version: "0.1.0"},
+			},
+			[]components.Component{
+				{ID: "paketo-buildpacks/go-dist", Version: "3.0.0"},
+				{ID: "paketo-buildpacks/go-build", Version: "2.5.1"},
+				{ID: "paketo-buildpacks/procfile", Version: "5.13.2"},
+				{ID: "paketo-buildpacks/watchexec", Version: "3.9.3", Optional: true},
+			},
+		)
+	})
+
+	it("renders the component changes as Markdown", func() {
+		notes, err := changelog.Render(changes, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(notes).To(Equal("## Component Changes\n" +
+			"\n### Added\n\n" +
+			"* `paketo-buildpacks/watchexec` 3.9.3 (optional)\n" +
+			"\n### Removed\n\n" +
+			"* `paketo-buildpacks/dep` 0.1.0\n" +
+			"\n### Updated\n\n" +
+			"| Buildpack | Old Version | New Version | Change |\n" +
+			"|---|---|---|---|\n" +
+			"| `paketo-buildpacks/go-dist` | 2.10.9 | 3.0.0 | major |\n" +
+			"| `paketo-buildpacks/go-build` | 2.4.20 | 2.5.1 | minor |\n" +
+			"| `paketo-buildpacks/procfile` | 5.13.2 | 5.13.2 | now required |\n"))
+	})
+
+	context("when the source of a component kept in this repository changed", func() {
+		it("lists it as updated", func() {
+			component := components.Component{ID: "paketo-buildpacks/go-otel", Version: "1.0.0", URI: "build/components/go-otel.tgz"}
+			notes, err := changelog.Render([]components.Change{
+				{ID: component.ID, Type: components.Updated, Old: component, New: component, Bump: components.NoBump, SourceChanged: true},
+			}, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(notes).To(HaveSuffix("| `paketo-buildpacks/go-otel` | 1.0.0 | 1.0.0 | source changed |\n"))
+		})
+	})
+
+	context("when release metadata is available", func() {
+		it("appends the notes of every included release", func() {
+			notes, err := changelog.Render(changes, changelog.NewReleaseCache(filepath.Join("testdata", "releases")))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(notes).To(HaveSuffix("\n### Release Notes\n" +
+				"\n<details>\n<summary><code>paketo-buildpacks/go-build</code> v2.5.0</summary>\n\n" +
+				"[Release](https://github.com/paketo-buildpacks/go-build/releases/tag/v2.5.0)\n\n" +
+				"* Adds support for BP_GO_BUILD_FULL_PATHS\n\n</details>\n" +
+				"\n<details>\n<summary><code>paketo-buildpacks/go-build</code> v2.5.1</summary>\n\n" +
+				"[Release](https://github.com/paketo-buildpacks/go-build/releases/tag/v2.5.1)\n\n" +
+				"* Fixes flag parsing when BP_GO_BUILD_FLAGS contains quotes\n\n</details>\n"))
+		})
+	})
+
+	context("when nothing changed", func() {
+		it("says so", func() {
+			notes, err := changelog.Render(nil, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(notes).To(Equal("## Component Changes\n\nNo component buildpacks changed.\n"))
+		})
+	})
+
+	context("failure cases", func() {
+		context("when the releases cannot be looked up", func() {
+			it("returns an error", func() {
+				_, err := changelog.Render(changes, failingReleases{})
+				Expect(err).To(MatchError("failed to look up releases"))
+			})
+		})
+	})
+}
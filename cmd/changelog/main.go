@@ -0,0 +1,76 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/paketo-buildpacks/go/changelog"
+	"github.com/paketo-buildpacks/go/components"
+)
+
+func main() {
+	err := run(os.Args[1:])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string) error {
+	var oldBuildpack, oldPackage, oldRef, newBuildpack, newPackage, releasesDir, output string
+
+	set := flag.NewFlagSet("changelog", flag.ContinueOnError)
+	set.StringVar(&oldBuildpack, "old-buildpack", "", "path to the previous buildpack.toml (required)")
+	set.StringVar(&oldPackage, "old-package", "", "path to the previous package.toml (required)")
+	set.StringVar(&oldRef, "old-ref", "", "git ref of the previous version, to find the component buildpacks kept in this repository whose source changed (optional)")
+	set.StringVar(&newBuildpack, "new-buildpack", "buildpack.toml", "path to the current buildpack.toml")
+	set.StringVar(&newPackage, "new-package", "package.toml", "path to the current package.toml")
+	set.StringVar(&releasesDir, "releases", "", "directory of cached component release metadata used to add release notes (optional)")
+	set.StringVar(&output, "output", "", "file to write the release notes to (default: stdout)")
+	err := set.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	if oldBuildpack == "" || oldPackage == "" {
+		return errors.New("--old-buildpack and --old-package are required")
+	}
+
+	old, err := components.LoadComposite(oldBuildpack, oldPackage)
+	if err != nil {
+		return err
+	}
+
+	current, err := components.LoadComposite(newBuildpack, newPackage)
+	if err != nil {
+		return err
+	}
+
+	changes := components.Diff(old, current)
+	if oldRef != "" {
+		changes, err = components.MarkSourceChanges(changes, current, components.NewGitSourceChanges(filepath.Dir(newBuildpack), oldRef))
+		if err != nil {
+			return err
+		}
+	}
+
+	var releases changelog.Releases
+	if releasesDir != "" {
+		releases = changelog.NewReleaseCache(releasesDir)
+	}
+
+	notes, err := changelog.Render(changes, releases)
+	if err != nil {
+		return err
+	}
+
+	if output == "" {
+		_, err = fmt.Print(notes)
+		return err
+	}
+
+	return os.WriteFile(output, []byte(notes), 0644)
+}
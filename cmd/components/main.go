@@ -0,0 +1,251 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/paketo-buildpacks/go/components"
+)
+
+func main() {
+	err := run(os.Args[1:])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string) error {
+	if len(args) == 0 {
+		return errors.New("missing command: expected one of [check, lock, pin, vendor]")
+	}
+
+	switch args[0] {
+	case "check":
+		return check(args[1:])
+	case "lock":
+		return lock(args[1:])
+	case "pin":
+		return pin(args[1:])
+	case "vendor":
+		return vendor(args[1:])
+	default:
+		return fmt.Errorf("unknown command %q: expected one of [check, lock, pin, vendor]", args[0])
+	}
+}
+
+func check(args []string) error {
+	var packagePath, lockfilePath, buildpacksDir string
+
+	set := flag.NewFlagSet("check", flag.ContinueOnError)
+	set.StringVar(&packagePath, "package", "package.toml", "path to the package.toml to check")
+	set.StringVar(&lockfilePath, "lockfile", "", "path to the lockfile (default: package.lock.toml next to package.toml)")
+	set.StringVar(&buildpacksDir, "buildpacks", "", "directory of the component buildpacks whose images to check, skipped when empty")
+	err := set.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	if lockfilePath == "" {
+		lockfilePath = filepath.Join(filepath.Dir(packagePath), components.LockfileName)
+	}
+
+	config, err := components.ParsePackageConfig(packagePath)
+	if err != nil {
+		return err
+	}
+
+	lockfile, err := components.ReadLockfile(lockfilePath)
+	if err != nil {
+		return err
+	}
+
+	err = components.Check(config, lockfile)
+	if err != nil {
+		return err
+	}
+
+	if buildpacksDir == "" {
+		return nil
+	}
+
+	images, err := components.ComponentImages(buildpacksDir)
+	if err != nil {
+		return err
+	}
+
+	return components.CheckImages(images, lockfile)
+}
+
+func lock(args []string) error {
+	var packagePath, lockfilePath, buildpacksDir, registry string
+
+	set := flag.NewFlagSet("lock", flag.ContinueOnError)
+	set.StringVar(&packagePath, "package", "package.toml", "path to the package.toml to lock")
+	set.StringVar(&lockfilePath, "lockfile", "", "path to the lockfile (default: package.lock.toml next to package.toml)")
+	set.StringVar(&buildpacksDir, "buildpacks", "", "directory of the component buildpacks whose images to lock (default: buildpacks next to package.toml)")
+	set.StringVar(&registry, "registry", "", "registry host to resolve the component buildpackages from instead of the one they reference (optional)")
+	err := set.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	if lockfilePath == "" {
+		lockfilePath = filepath.Join(filepath.Dir(packagePath), components.LockfileName)
+	}
+
+	config, err := components.ParsePackageConfig(packagePath)
+	if err != nil {
+		return err
+	}
+
+	if buildpacksDir == "" {
+		buildpacksDir = filepath.Join(filepath.Dir(packagePath), "buildpacks")
+	}
+
+	lockfile, err := components.Lock(config, components.NewRegistrySource(registry, remote.WithAuthFromKeychain(authn.DefaultKeychain)))
+	if err != nil {
+		return err
+	}
+
+	images, err := components.ComponentImages(buildpacksDir)
+	if err != nil {
+		return err
+	}
+
+	// the images are pulled by the builds, never through a mirror registry
+	lockfile.Images, err = components.LockImages(images, components.NewRegistrySource("", remote.WithAuthFromKeychain(authn.DefaultKeychain)))
+	if err != nil {
+		return err
+	}
+
+	for _, dependency := range append(lockfile.Dependencies, lockfile.Images...) {
+		fmt.Printf("Locked %s to %s\n", dependency.URI, dependency.Digest)
+	}
+
+	return components.WriteLockfile(lockfilePath, lockfile)
+}
+
+func pin(args []string) error {
+	var packagePath, lockfilePath string
+
+	set := flag.NewFlagSet("pin", flag.ContinueOnError)
+	set.StringVar(&packagePath, "package", "package.toml", "path to the package.toml to pin, which is rewritten in place")
+	set.StringVar(&lockfilePath, "lockfile", "", "path to the lockfile (default: package.lock.toml next to package.toml)")
+	err := set.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	if lockfilePath == "" {
+		lockfilePath = filepath.Join(filepath.Dir(packagePath), components.LockfileName)
+	}
+
+	config, err := components.ParsePackageConfig(packagePath)
+	if err != nil {
+		return err
+	}
+
+	lockfile, err := components.ReadLockfile(lockfilePath)
+	if err != nil {
+		return err
+	}
+
+	pinned, err := components.Pin(config, lockfile, components.NewRegistrySource("", remote.WithAuthFromKeychain(authn.DefaultKeychain)))
+	if err != nil {
+		return err
+	}
+
+	for _, dependency := range pinned.Dependencies {
+		fmt.Printf("Pinned %s\n", dependency.URI)
+	}
+
+	return components.WritePackageConfig(packagePath, pinned)
+}
+
+func vendor(args []string) error {
+	var packagePath, lockfilePath, layoutPath, registry, target, output string
+
+	set := flag.NewFlagSet("vendor", flag.ContinueOnError)
+	set.StringVar(&packagePath, "package", "package.toml", "path to the package.toml to vendor")
+	set.StringVar(&lockfilePath, "lockfile", "", "path to the lockfile (default: package.lock.toml next to package.toml)")
+	set.StringVar(&layoutPath, "layout", "", "OCI layout directory holding the component buildpackages")
+	set.StringVar(&registry, "registry", "", "registry host (e.g. localhost:5000) holding the component buildpackages")
+	set.StringVar(&target, "target", "linux/amd64", "target platform to vendor")
+	set.StringVar(&output, "output", "", "directory to write the vendored buildpackages and package.toml to (default: directory of package.toml)")
+	err := set.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	var source components.Source
+	switch {
+	case layoutPath != "" && registry != "":
+		return errors.New("--layout and --registry are mutually exclusive")
+	case layoutPath != "":
+		source = components.NewLayoutSource(layoutPath)
+	case registry != "":
+		source = components.NewRegistrySource(registry)
+	default:
+		return errors.New("one of --layout or --registry is required")
+	}
+
+	if lockfilePath == "" {
+		lockfilePath = filepath.Join(filepath.Dir(packagePath), components.LockfileName)
+	}
+
+	if output == "" {
+		output = filepath.Dir(packagePath)
+	}
+
+	platform, err := v1.ParsePlatform(target)
+	if err != nil {
+		return err
+	}
+
+	config, err := components.ParsePackageConfig(packagePath)
+	if err != nil {
+		return err
+	}
+
+	// The vendored package.toml may be written elsewhere, so keep the
+	// composite's own buildpack URI pointing at the same file.
+	if config.Buildpack.URI != "" && !filepath.IsAbs(config.Buildpack.URI) {
+		config.Buildpack.URI, err = filepath.Abs(filepath.Join(filepath.Dir(packagePath), config.Buildpack.URI))
+		if err != nil {
+			return err
+		}
+	}
+
+	lockfile, err := components.ReadLockfile(lockfilePath)
+	if err != nil {
+		return err
+	}
+
+	output, err = filepath.Abs(output)
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(output, os.ModePerm)
+	if err != nil {
+		return err
+	}
+
+	vendored, err := components.Vendor(config, lockfile, source, *platform, output)
+	if err != nil {
+		return err
+	}
+
+	for _, dependency := range vendored.Dependencies {
+		fmt.Printf("Vendored %s\n", dependency.URI)
+	}
+
+	return components.WritePackageConfig(filepath.Join(output, "package.toml"), vendored)
+}
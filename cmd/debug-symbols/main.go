@@ -0,0 +1,140 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/daemon"
+	"github.com/paketo-buildpacks/go/debugsymbols"
+)
+
+func main() {
+	err := run(os.Args[1:])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string) error {
+	if len(args) == 0 {
+		return errors.New("missing command: expected one of [image, verify]")
+	}
+
+	switch args[0] {
+	case "image":
+		return image(args[1:])
+	case "verify":
+		return verify(args[1:])
+	default:
+		return fmt.Errorf("unknown command %q: expected one of [image, verify]", args[0])
+	}
+}
+
+// image copies the symbols that go-debug-symbols exported to an application
+// image in the Docker daemon into a debug image.
+func image(args []string) error {
+	var from, imageName string
+
+	set := flag.NewFlagSet("image", flag.ContinueOnError)
+	set.StringVar(&from, "from", "", "application image built with BP_GO_DEBUG_SYMBOLS (required)")
+	set.StringVar(&imageName, "image", "", "name of the debug image (required)")
+	err := set.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	if from == "" || imageName == "" {
+		return errors.New("--from and --image are required")
+	}
+
+	reference, err := name.ParseReference(from)
+	if err != nil {
+		return err
+	}
+
+	tag, err := name.NewTag(imageName)
+	if err != nil {
+		return err
+	}
+
+	app, err := daemon.Image(reference)
+	if err != nil {
+		return fmt.Errorf("failed to read %s from the Docker daemon: %w", from, err)
+	}
+
+	dir, err := os.MkdirTemp("", "debug-symbols")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	err = debugsymbols.Unpack(app, dir)
+	if err != nil {
+		return err
+	}
+
+	debugImage, err := debugsymbols.Image(dir)
+	if err != nil {
+		return err
+	}
+
+	_, err = daemon.Write(tag, debugImage)
+	if err != nil {
+		return fmt.Errorf("failed to write %s to the Docker daemon: %w", imageName, err)
+	}
+
+	fmt.Printf("Wrote debug image %s\n", tag)
+	return nil
+}
+
+// verify checks that the symbols directory has a symbol file for the build
+// ID of every given binary.
+func verify(args []string) error {
+	var dir string
+
+	set := flag.NewFlagSet("verify", flag.ContinueOnError)
+	set.StringVar(&dir, "dir", "", "symbols directory to look in (required)")
+	err := set.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	if dir == "" {
+		return errors.New("--dir is required")
+	}
+
+	manifest, err := debugsymbols.ReadManifest(dir)
+	if err != nil {
+		return err
+	}
+
+	for _, binary := range set.Args() {
+		id, err := debugsymbols.ReadBuildID(binary)
+		if err != nil {
+			return err
+		}
+
+		symbols, ok := manifest.Find(id.Go)
+		if !ok {
+			return fmt.Errorf("no symbols for %s (build ID %s)", binary, id.Go)
+		}
+
+		fileID, err := debugsymbols.ReadBuildID(filepath.Join(dir, symbols.File))
+		if err != nil {
+			return err
+		}
+
+		if fileID != id {
+			return fmt.Errorf("symbol file %s has build ID %s, not %s", symbols.File, fileID.Go, id.Go)
+		}
+
+		fmt.Printf("%s matches %s\n", binary, symbols.File)
+	}
+
+	return nil
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/daemon"
+	"github.com/paketo-buildpacks/go/hardening"
+)
+
+func main() {
+	err := run(os.Args[1:])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string) error {
+	if len(args) == 0 {
+		return errors.New("missing command: expected one of [verify]")
+	}
+
+	switch args[0] {
+	case "verify":
+		return verify(args[1:])
+	default:
+		return fmt.Errorf("unknown command %q: expected one of [verify]", args[0])
+	}
+}
+
+// verify checks that an image in the Docker daemon runs as a non-root user
+// that Kubernetes can verify.
+func verify(args []string) error {
+	var imageName string
+
+	set := flag.NewFlagSet("verify", flag.ContinueOnError)
+	set.StringVar(&imageName, "image", "", "name of the image in the Docker daemon (required)")
+	err := set.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	if imageName == "" {
+		return errors.New("--image is required")
+	}
+
+	ref, err := name.ParseReference(imageName)
+	if err != nil {
+		return err
+	}
+
+	image, err := daemon.Image(ref)
+	if err != nil {
+		return fmt.Errorf("failed to read image %s from the Docker daemon: %w", imageName, err)
+	}
+
+	config, err := image.ConfigFile()
+	if err != nil {
+		return err
+	}
+
+	err = hardening.VerifyUser(*config)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s runs as user %s\n", imageName, config.Config.User)
+	return nil
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/daemon"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/paketo-buildpacks/go/health"
+)
+
+func main() {
+	err := run(os.Args[1:])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string) error {
+	if len(args) == 0 {
+		return errors.New("missing command: expected one of [apply]")
+	}
+
+	switch args[0] {
+	case "apply":
+		return apply(args[1:])
+	default:
+		return fmt.Errorf("unknown command %q: expected one of [apply]", args[0])
+	}
+}
+
+// apply sets the health check of an image in the Docker daemon from its
+// health check label, like a Dockerfile HEALTHCHECK instruction.
+func apply(args []string) error {
+	var imageName string
+
+	set := flag.NewFlagSet("apply", flag.ContinueOnError)
+	set.StringVar(&imageName, "image", "", "name of the image in the Docker daemon (required)")
+	err := set.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	if imageName == "" {
+		return errors.New("--image is required")
+	}
+
+	tag, err := name.NewTag(imageName)
+	if err != nil {
+		return err
+	}
+
+	image, err := daemon.Image(tag)
+	if err != nil {
+		return fmt.Errorf("failed to read image %s from the Docker daemon: %w", imageName, err)
+	}
+
+	config, err := image.ConfigFile()
+	if err != nil {
+		return err
+	}
+
+	value, ok := config.Config.Labels[health.Label]
+	if !ok {
+		return fmt.Errorf("image %s has no %s label", imageName, health.Label)
+	}
+
+	metadata, err := health.ParseMetadata(value)
+	if err != nil {
+		return err
+	}
+
+	healthConfig, err := metadata.HealthConfig()
+	if err != nil {
+		return err
+	}
+
+	config.Config.Healthcheck = &healthConfig
+	image, err = mutate.Config(image, config.Config)
+	if err != nil {
+		return err
+	}
+
+	_, err = daemon.Write(tag, image)
+	if err != nil {
+		return fmt.Errorf("failed to write %s to the Docker daemon: %w", imageName, err)
+	}
+
+	fmt.Printf("Set the health check of %s to %v\n", tag, healthConfig.Test)
+	return nil
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/daemon"
+	"github.com/google/go-containerregistry/pkg/v1/layout"
+	"github.com/paketo-buildpacks/go/imagediff"
+)
+
+// layoutPrefix marks an image reference as a path to an OCI image layout.
+const layoutPrefix = "oci:"
+
+// errImagesDiffer is returned when the images are not identical, after the
+// report has been written.
+var errImagesDiffer = errors.New("images differ")
+
+func main() {
+	err := run(os.Args[1:])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+// run compares two images and reports every layer and file in which they
+// differ. An image is either a reference to an image in the local Docker
+// daemon, or oci:<path> for an OCI image layout that holds a single image.
+func run(args []string) error {
+	var output string
+
+	set := flag.NewFlagSet("image-diff", flag.ContinueOnError)
+	set.Usage = func() {
+		fmt.Fprintf(set.Output(), "Usage: image-diff [--output <report.json>] <image> <image>\n")
+		set.PrintDefaults()
+	}
+	set.StringVar(&output, "output", "", "path to write the JSON report to")
+	err := set.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	if set.NArg() != 2 {
+		set.Usage()
+		return fmt.Errorf("expected two images, got %d", set.NArg())
+	}
+
+	a, err := readImage(set.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	b, err := readImage(set.Arg(1))
+	if err != nil {
+		return err
+	}
+
+	report, err := imagediff.Compare(a, b)
+	if err != nil {
+		return err
+	}
+
+	fmt.Print(report.String())
+
+	if output != "" {
+		content, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return err
+		}
+
+		err = os.WriteFile(output, content, 0644)
+		if err != nil {
+			return err
+		}
+	}
+
+	if !report.Empty() {
+		return errImagesDiffer
+	}
+
+	return nil
+}
+
+func readImage(ref string) (v1.Image, error) {
+	if path, ok := strings.CutPrefix(ref, layoutPrefix); ok {
+		index, err := layout.ImageIndexFromPath(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read OCI image layout %s: %w", path, err)
+		}
+
+		manifest, err := index.IndexManifest()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read OCI image layout %s: %w", path, err)
+		}
+
+		if len(manifest.Manifests) != 1 {
+			return nil, fmt.Errorf("OCI image layout %s must hold exactly one image, found %d", path, len(manifest.Manifests))
+		}
+
+		return index.Image(manifest.Manifests[0].Digest)
+	}
+
+	parsed, err := name.ParseReference(ref)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse image reference %s: %w", ref, err)
+	}
+
+	image, err := daemon.Image(parsed)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read image %s from the Docker daemon: %w", ref, err)
+	}
+
+	return image, nil
+}
@@ -0,0 +1,201 @@
+package main
+
+import (
+	"crypto"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/paketo-buildpacks/go/components"
+	"github.com/paketo-buildpacks/go/provenance"
+)
+
+// Names of the files written to the output directory.
+const (
+	statementFile = "provenance.intoto.json"
+	envelopeFile  = "provenance.intoto.dsse.json"
+)
+
+func main() {
+	err := run(os.Args[1:])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+// run generates the SLSA provenance of an image in a registry and attaches
+// it to the image as an OCI referrer. It is a post-push step: it runs where
+// the image was pushed from, such as the CI job that ran `pack build
+// --publish`, once the manifest digest that is the subject exists. Everything
+// it records about the build is read from the pushed image: the buildpacks
+// and their versions from the io.buildpacks.build.metadata label, and the Go
+// build settings from the binaries go-build left in it. For an index, every
+// platform image gets provenance of its own. The optional lockfile only adds
+// the digests of the component buildpackages whose versions it locks. When a
+// service binding of type slsa-signing-key is found, the provenance is a
+// signed DSSE envelope, otherwise a plain in-toto statement. With
+// --output-dir, the documents are also written next to, for example, the
+// directory given to `pack build --sbom-output-dir`.
+func run(args []string) error {
+	var imageName, builder, lockfilePath, sourceURI, sourceRevision, platformDir, outputDir string
+
+	set := flag.NewFlagSet("provenance", flag.ContinueOnError)
+	set.StringVar(&imageName, "image", "", "reference of the pushed image or index in a registry (required)")
+	set.StringVar(&builder, "builder", "", "builder image that built the image (required)")
+	set.StringVar(&lockfilePath, "lockfile", "", "package.lock.toml of the Go buildpack release that built the image, to record the digests of its components (optional)")
+	set.StringVar(&sourceURI, "source-uri", "", "URI of the application source")
+	set.StringVar(&sourceRevision, "source-revision", "", "git commit of the application source")
+	set.StringVar(&platformDir, "platform", "/platform", "directory whose bindings directory holds the service bindings, when SERVICE_BINDING_ROOT, CNB_BINDINGS and VCAP_SERVICES are not set")
+	set.StringVar(&outputDir, "output-dir", "", "directory to also write the provenance to (optional)")
+	err := set.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	if imageName == "" || builder == "" {
+		return errors.New("--image and --builder are required")
+	}
+
+	ref, err := name.ParseReference(imageName)
+	if err != nil {
+		return err
+	}
+
+	var lockfile components.Lockfile
+	if lockfilePath != "" {
+		lockfile, err = components.ReadLockfile(lockfilePath)
+		if err != nil {
+			return err
+		}
+	}
+
+	// without a signing key binding, key is nil and the statement is
+	// attached unsigned
+	key, _, err := provenance.FindSigningKey(platformDir)
+	if err != nil {
+		return err
+	}
+
+	options := []remote.Option{remote.WithAuthFromKeychain(authn.DefaultKeychain)}
+
+	descriptor, err := remote.Get(ref, options...)
+	if err != nil {
+		return fmt.Errorf("failed to read image: %w", err)
+	}
+
+	var subjects []provenance.Subject
+	if descriptor.MediaType.IsIndex() {
+		index, err := descriptor.ImageIndex()
+		if err != nil {
+			return fmt.Errorf("failed to read index: %w", err)
+		}
+
+		subjects, err = provenance.SubjectsFromIndex(index)
+		if err != nil {
+			return err
+		}
+	} else {
+		image, err := descriptor.Image()
+		if err != nil {
+			return fmt.Errorf("failed to read image: %w", err)
+		}
+
+		subjects = []provenance.Subject{{Digest: descriptor.Digest, Image: image}}
+	}
+
+	for _, subject := range subjects {
+		configFile, err := subject.Image.ConfigFile()
+		if err != nil {
+			return err
+		}
+
+		buildpacks, err := provenance.BuildpacksFromLabels(configFile.Config.Labels)
+		if err != nil {
+			return err
+		}
+
+		binaries, err := provenance.BinariesFromImage(subject.Image)
+		if err != nil {
+			return err
+		}
+
+		statement := provenance.Generate(provenance.Config{
+			Image:      ref.Context().Name(),
+			Digest:     subject.Digest,
+			Builder:    builder,
+			Buildpacks: buildpacks,
+			Lockfile:   lockfile,
+			Source:     provenance.Source{URI: sourceURI, Revision: sourceRevision},
+			Binaries:   binaries,
+		})
+
+		err = attach(ref.Context().Digest(subject.Digest.String()), subject, statement, key, outputDir, options)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// attach signs the statement when there is a key, attaches it to the image
+// and writes it to the output directory. The documents of the platform images
+// of an index are written to a directory per platform.
+func attach(digest name.Digest, subject provenance.Subject, statement provenance.Statement, key crypto.Signer, outputDir string, options []remote.Option) error {
+	var document any = statement
+	file := statementFile
+	mediaType := provenance.StatementMediaType
+
+	if key != nil {
+		var err error
+		document, err = provenance.Sign(statement, key)
+		if err != nil {
+			return err
+		}
+
+		file = envelopeFile
+		mediaType = provenance.EnvelopeMediaType
+	}
+
+	content, err := json.MarshalIndent(document, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	artifact, err := provenance.Attach(digest, content, mediaType, options...)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Attached %s to %s as %s\n", file, digest, artifact.DigestStr())
+
+	if outputDir != "" {
+		dir := outputDir
+		if subject.Platform != nil {
+			dir = filepath.Join(outputDir, strings.ReplaceAll(subject.Platform.String(), "/", "-"))
+		}
+
+		err = os.MkdirAll(dir, os.ModePerm)
+		if err != nil {
+			return err
+		}
+
+		output := filepath.Join(dir, file)
+		err = os.WriteFile(output, content, 0644)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Wrote %s\n", output)
+	}
+
+	return nil
+}
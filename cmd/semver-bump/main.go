@@ -0,0 +1,74 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/paketo-buildpacks/go/components"
+)
+
+func main() {
+	err := run(os.Args[1:])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+// run prints the semantic version increment (major, minor, patch or none)
+// the composite needs for the component changes between two versions of its
+// buildpack.toml and package.toml. With --old-ref, the components kept in
+// this repository whose source changed since that git ref count as updated.
+// The rules are documented on components.RequiredBump.
+func run(args []string) error {
+	var oldBuildpack, oldPackage, oldRef, newBuildpack, newPackage string
+	var verbose bool
+
+	set := flag.NewFlagSet("semver-bump", flag.ContinueOnError)
+	set.StringVar(&oldBuildpack, "old-buildpack", "", "path to the previous buildpack.toml (required)")
+	set.StringVar(&oldPackage, "old-package", "", "path to the previous package.toml (required)")
+	set.StringVar(&oldRef, "old-ref", "", "git ref of the previous version, to find the component buildpacks kept in this repository whose source changed (optional)")
+	set.StringVar(&newBuildpack, "new-buildpack", "buildpack.toml", "path to the current buildpack.toml")
+	set.StringVar(&newPackage, "new-package", "package.toml", "path to the current package.toml")
+	set.BoolVar(&verbose, "verbose", false, "print the reason for every component change to stderr")
+	err := set.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	if oldBuildpack == "" || oldPackage == "" {
+		return errors.New("--old-buildpack and --old-package are required")
+	}
+
+	old, err := components.LoadComposite(oldBuildpack, oldPackage)
+	if err != nil {
+		return err
+	}
+
+	current, err := components.LoadComposite(newBuildpack, newPackage)
+	if err != nil {
+		return err
+	}
+
+	changes := components.Diff(old, current)
+	if oldRef != "" {
+		changes, err = components.MarkSourceChanges(changes, current, components.NewGitSourceChanges(filepath.Dir(newBuildpack), oldRef))
+		if err != nil {
+			return err
+		}
+	}
+
+	bump, reasons := components.RequiredBump(changes)
+
+	if verbose {
+		for _, reason := range reasons {
+			fmt.Fprintf(os.Stderr, "%s: %s (%s)\n", reason.ID, reason.Bump, reason.Reason)
+		}
+	}
+
+	fmt.Println(bump)
+	return nil
+}
@@ -0,0 +1,88 @@
+package components
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/BurntSushi/toml"
+)
+
+// BuildpackConfig represents the parts of a composite buildpack.toml file
+// that describe its component buildpacks.
+type BuildpackConfig struct {
+	API       string            `toml:"api"`
+	Buildpack BuildpackInfo     `toml:"buildpack"`
+	Order     []BuildpackOrder  `toml:"order"`
+	Metadata  BuildpackMetadata `toml:"metadata"`
+}
+
+// BuildpackInfo is the [buildpack] table of a buildpack.toml file.
+type BuildpackInfo struct {
+	ID          string `toml:"id"`
+	Name        string `toml:"name"`
+	Version     string `toml:"version"`
+	Description string `toml:"description"`
+	Homepage    string `toml:"homepage"`
+}
+
+// BuildpackMetadata is the [metadata] table of a buildpack.toml file.
+type BuildpackMetadata struct {
+	IncludeFiles []string `toml:"include-files"`
+
+	// Images lists the images a component buildpack kept in this repository
+	// pulls files from during the build, as docker:// URIs. They are locked
+	// in package.lock.toml, which is packaged with the component, so that
+	// the build pulls them by digest.
+	Images []PackageDependency `toml:"images"`
+}
+
+// BuildpackOrder is a single [[order]] entry of a buildpack.toml file.
+type BuildpackOrder struct {
+	Group []BuildpackOrderGroup `toml:"group"`
+}
+
+// BuildpackOrderGroup is a single [[order.group]] entry of a buildpack.toml
+// file.
+type BuildpackOrderGroup struct {
+	ID       string `toml:"id"`
+	Version  string `toml:"version"`
+	Optional bool   `toml:"optional,omitempty"`
+}
+
+// ParseBuildpackConfig reads the buildpack.toml file at the given path.
+func ParseBuildpackConfig(path string) (BuildpackConfig, error) {
+	var config BuildpackConfig
+	_, err := toml.DecodeFile(path, &config)
+	if err != nil {
+		return BuildpackConfig{}, fmt.Errorf("failed to parse buildpack config: %w", err)
+	}
+
+	return config, nil
+}
+
+// ComponentImages collects the images listed in the buildpack.toml files of
+// the component buildpacks in the subdirectories of dir.
+func ComponentImages(dir string) ([]PackageDependency, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*", "buildpack.toml"))
+	if err != nil {
+		return nil, err
+	}
+
+	var images []PackageDependency
+	for _, path := range paths {
+		config, err := ParseBuildpackConfig(path)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+
+		for _, image := range config.Metadata.Images {
+			if !image.IsImage() {
+				return nil, fmt.Errorf("%s: image %q is not a %s URI", path, image.URI, DockerScheme)
+			}
+
+			images = append(images, image)
+		}
+	}
+
+	return images, nil
+}
@@ -0,0 +1,65 @@
+package components_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/paketo-buildpacks/go/components"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testBuildpackConfig(t *testing.T, context spec.G, it spec.S) {
+	var Expect = NewWithT(t).Expect
+
+	context("ParseBuildpackConfig", func() {
+		it("parses the order groups of the repository buildpack.toml", func() {
+			config, err := components.ParseBuildpackConfig(filepath.Join("..", "buildpack.toml"))
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(config.Buildpack.ID).To(Equal("paketo-buildpacks/go"))
+			Expect(config.Order).To(HaveLen(4))
+			Expect(config.Order[0].Group).To(ContainElement(HaveField("ID", "paketo-buildpacks/go-build-tool")))
+			Expect(config.Order[1].Group).To(ContainElement(HaveField("ID", "paketo-buildpacks/go-bazel")))
+			Expect(config.Order[2].Group).To(ContainElement(HaveField("ID", "paketo-buildpacks/go-mod-vendor")))
+			Expect(config.Order[3].Group).NotTo(ContainElement(HaveField("ID", "paketo-buildpacks/go-mod-vendor")))
+		})
+
+		context("failure cases", func() {
+			context("when the file is malformed", func() {
+				it("returns an error", func() {
+					path := filepath.Join(t.TempDir(), "buildpack.toml")
+					Expect(os.WriteFile(path, []byte("%%%"), 0600)).To(Succeed())
+
+					_, err := components.ParseBuildpackConfig(path)
+					Expect(err).To(MatchError(ContainSubstring("failed to parse buildpack config")))
+				})
+			})
+		})
+	})
+	context("ComponentImages", func() {
+		it("collects the images of the component buildpacks of the repository", func() {
+			images, err := components.ComponentImages(filepath.Join("..", "buildpacks"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(images).To(ContainElement(HaveField("URI", HavePrefix("docker://ghcr.io/open-telemetry/opentelemetry-go-instrumentation/autoinstrumentation-go:"))))
+		})
+
+		context("failure cases", func() {
+			context("when an image is not a docker:// URI", func() {
+				it("returns an error", func() {
+					dir := t.TempDir()
+					Expect(os.Mkdir(filepath.Join(dir, "some-buildpack"), os.ModePerm)).To(Succeed())
+					Expect(os.WriteFile(filepath.Join(dir, "some-buildpack", "buildpack.toml"), []byte(`
+[[metadata.images]]
+  uri = "ghcr.io/example/instrumentation:v1.0.0"
+`), 0600)).To(Succeed())
+
+					_, err := components.ComponentImages(dir)
+					Expect(err).To(MatchError(ContainSubstring(`image "ghcr.io/example/instrumentation:v1.0.0" is not a docker:// URI`)))
+				})
+			})
+		})
+	})
+}
@@ -0,0 +1,107 @@
+package components
+
+// BumpReason explains the bump a single component change requires.
+type BumpReason struct {
+	ID     string
+	Bump   Bump
+	Reason string
+}
+
+// RequiredBump computes the semantic version increment the composite needs
+// for the given component changes, along with the reason for every change.
+// The composite bump is the largest bump required by any change:
+//
+//   - A component that is removed requires a major bump, since applications
+//     may rely on it.
+//   - A required component that is added requires a major bump, since
+//     applications that it does not detect stop building.
+//   - An optional component that is added requires a minor bump.
+//   - A component that becomes required requires a major bump, and one that
+//     becomes optional requires a minor bump.
+//   - A component version update requires the same bump as the component,
+//     whether or not it is optional: a major update in go-dist requires a
+//     major bump, a patch update in procfile a patch bump.
+//   - A downgrade across a major or minor version requires a major bump,
+//     since it removes features. A patch downgrade requires a patch bump.
+//   - A component that is only packaged from a different URI requires a
+//     patch bump.
+//   - A component kept in this repository whose source changed requires at
+//     least a patch bump. Its version does not change with its source, so a
+//     maintainer raises the bump by hand when the change adds a feature.
+//
+// No changes require NoBump.
+func RequiredBump(changes []Change) (Bump, []BumpReason) {
+	required := NoBump
+	var reasons []BumpReason
+
+	for _, change := range changes {
+		bump, reason := requiredBump(change)
+		if rank(bump) > rank(required) {
+			required = bump
+		}
+
+		reasons = append(reasons, BumpReason{ID: change.ID, Bump: bump, Reason: reason})
+	}
+
+	return required, reasons
+}
+
+func requiredBump(change Change) (Bump, string) {
+	switch change.Type {
+	case Removed:
+		return MajorBump, "removed"
+	case Added:
+		if change.New.Optional {
+			return MinorBump, "added as an optional buildpack"
+		}
+
+		return MajorBump, "added as a required buildpack"
+	}
+
+	bump, reason := NoBump, "no versioned change"
+	raise := func(b Bump, r string) {
+		if rank(b) > rank(bump) {
+			bump, reason = b, r
+		}
+	}
+
+	if change.Old.Optional && !change.New.Optional {
+		raise(MajorBump, "became required")
+	}
+
+	if !change.Old.Optional && change.New.Optional {
+		raise(MinorBump, "became optional")
+	}
+
+	switch {
+	case change.Downgrade && change.Bump != PatchBump:
+		raise(MajorBump, "downgraded from "+change.Old.Version+" to "+change.New.Version)
+	case change.Downgrade:
+		raise(PatchBump, "downgraded from "+change.Old.Version+" to "+change.New.Version)
+	case change.Bump != NoBump:
+		raise(change.Bump, string(change.Bump)+" update from "+change.Old.Version+" to "+change.New.Version)
+	}
+
+	if change.Old.URI != change.New.URI {
+		raise(PatchBump, "packaged from "+change.New.URI)
+	}
+
+	if change.SourceChanged {
+		raise(PatchBump, "source changed in this repository")
+	}
+
+	return bump, reason
+}
+
+func rank(bump Bump) int {
+	switch bump {
+	case MajorBump:
+		return 3
+	case MinorBump:
+		return 2
+	case PatchBump:
+		return 1
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,202 @@
+package components_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/paketo-buildpacks/go/components"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testBump(t *testing.T, context spec.G, it spec.S) {
+	var Expect = NewWithT(t).Expect
+
+	context("RequiredBump", func() {
+		it("requires the largest bump of any change", func() {
+			old := []components.Component{
+				{ID: "paketo-buildpacks/go-dist", Version: "2.10.9"},
+				{ID: "paketo-buildpacks/go-build", Version: "2.4.20"},
+				{ID: "paketo-buildpacks/procfile", Version: "5.13.2", Optional: true},
+			}
+			new := []components.Component{
+				{ID: "paketo-buildpacks/go-dist", Version: "3.0.0"},
+				{ID: "paketo-buildpacks/go-build", Version: "2.4.20"},
+				{ID: "paketo-buildpacks/procfile", Version: "5.13.3", Optional: true},
+				{ID: "paketo-buildpacks/watchexec", Version: "3.9.3", Optional: true},
+			}
+
+			bump, reasons := components.RequiredBump(components.Diff(old, new))
+			Expect(bump).To(Equal(components.MajorBump))
+			Expect(reasons).To(Equal([]components.BumpReason{
+				{ID: "paketo-buildpacks/go-dist", Bump: components.MajorBump, Reason: "major update from 2.10.9 to 3.0.0"},
+				{ID: "paketo-buildpacks/procfile", Bump: components.PatchBump, Reason: "patch update from 5.13.2 to 5.13.3"},
+				{ID: "paketo-buildpacks/watchexec", Bump: components.MinorBump, Reason: "added as an optional buildpack"},
+			}))
+		})
+
+		context("when nothing changed", func() {
+			it("requires no bump", func() {
+				bump, reasons := components.RequiredBump(nil)
+				Expect(bump).To(Equal(components.NoBump))
+				Expect(reasons).To(BeEmpty())
+			})
+		})
+
+		context("when combining changes", func() {
+			patch := components.Change{ID: "patch", Type: components.Updated, Old: components.Component{Version: "1.0.0"}, New: components.Component{Version: "1.0.1"}, Bump: components.PatchBump}
+			minor := components.Change{ID: "minor", Type: components.Added, New: components.Component{Version: "1.0.0", Optional: true}}
+			major := components.Change{ID: "major", Type: components.Removed, Old: components.Component{Version: "1.0.0"}}
+			none := components.Change{ID: "none", Type: components.Updated, Old: components.Component{Version: "1.0.0"}, New: components.Component{Version: "1.0.0+build"}, Bump: components.NoBump}
+
+			for _, e := range []struct {
+				name     string
+				changes  []components.Change
+				expected components.Bump
+			}{
+				{"none", []components.Change{none}, components.NoBump},
+				{"none and patch", []components.Change{none, patch}, components.PatchBump},
+				{"patch and minor", []components.Change{patch, minor}, components.MinorBump},
+				{"minor and patch", []components.Change{minor, patch}, components.MinorBump},
+				{"patch, minor and major", []components.Change{patch, minor, major}, components.MajorBump},
+				{"major and patch", []components.Change{major, patch}, components.MajorBump},
+			} {
+				e := e
+				it(fmt.Sprintf("requires a %s bump for %s", e.expected, e.name), func() {
+					bump, reasons := components.RequiredBump(e.changes)
+					Expect(bump).To(Equal(e.expected))
+					Expect(reasons).To(HaveLen(len(e.changes)))
+				})
+			}
+		})
+	})
+
+	context("rules", func() {
+		context("when a component is added or removed", func() {
+			for _, e := range []struct {
+				change   components.Change
+				expected components.Bump
+				reason   string
+			}{
+				{components.Change{Type: components.Added, New: components.Component{Version: "1.0.0"}}, components.MajorBump, "added as a required buildpack"},
+				{components.Change{Type: components.Added, New: components.Component{Version: "1.0.0", Optional: true}}, components.MinorBump, "added as an optional buildpack"},
+				{components.Change{Type: components.Removed, Old: components.Component{Version: "1.0.0"}}, components.MajorBump, "removed"},
+				{components.Change{Type: components.Removed, Old: components.Component{Version: "1.0.0", Optional: true}}, components.MajorBump, "removed"},
+			} {
+				e := e
+				it(fmt.Sprintf("requires a %s bump when %s", e.expected, e.reason), func() {
+					e.change.ID = "some-id"
+					bump, reasons := components.RequiredBump([]components.Change{e.change})
+					Expect(bump).To(Equal(e.expected))
+					Expect(reasons).To(Equal([]components.BumpReason{{ID: "some-id", Bump: e.expected, Reason: e.reason}}))
+				})
+			}
+		})
+
+		context("when the source of a component kept in this repository changed", func() {
+			var component components.Component
+
+			it.Before(func() {
+				component = components.Component{ID: "paketo-buildpacks/go-otel", Version: "1.0.0", URI: "build/components/go-otel.tgz"}
+			})
+
+			it("requires a patch bump", func() {
+				bump, reasons := components.RequiredBump([]components.Change{
+					{ID: component.ID, Type: components.Updated, Old: component, New: component, Bump: components.NoBump, SourceChanged: true},
+				})
+				Expect(bump).To(Equal(components.PatchBump))
+				Expect(reasons).To(Equal([]components.BumpReason{{ID: component.ID, Bump: components.PatchBump, Reason: "source changed in this repository"}}))
+			})
+
+			it("requires a larger bump when its entry requires one", func() {
+				optional := component
+				optional.Optional = true
+
+				bump, _ := components.RequiredBump([]components.Change{
+					{ID: component.ID, Type: components.Updated, Old: component, New: optional, Bump: components.NoBump, SourceChanged: true},
+				})
+				Expect(bump).To(Equal(components.MinorBump))
+			})
+		})
+
+		context("when a component is updated", func() {
+			// Each dimension of an update is listed with the bump it requires on
+			// its own. Every combination of the dimensions must require the
+			// largest of those bumps.
+			optionality := []struct {
+				old, new bool
+				bump     components.Bump
+			}{
+				{old: false, new: false, bump: components.NoBump},
+				{old: true, new: true, bump: components.NoBump},
+				{old: true, new: false, bump: components.MajorBump},
+				{old: false, new: true, bump: components.MinorBump},
+			}
+
+			versions := []struct {
+				old, new string
+				bump     components.Bump
+			}{
+				{old: "1.2.3", new: "1.2.3", bump: components.NoBump},
+				{old: "1.2.3", new: "1.2.3+build", bump: components.NoBump},
+				{old: "1.2.3", new: "1.2.4", bump: components.PatchBump},
+				{old: "1.2.3", new: "1.3.0", bump: components.MinorBump},
+				{old: "1.2.3", new: "2.0.0", bump: components.MajorBump},
+				{old: "1.2.3", new: "1.2.2", bump: components.PatchBump},
+				{old: "1.2.3", new: "1.1.9", bump: components.MajorBump},
+				{old: "1.2.3", new: "0.9.0", bump: components.MajorBump},
+				{old: "latest", new: "1.2.3", bump: components.MajorBump},
+			}
+
+			uris := []struct {
+				changed bool
+				bump    components.Bump
+			}{
+				{changed: false, bump: components.NoBump},
+				{changed: true, bump: components.PatchBump},
+			}
+
+			largest := func(bumps ...components.Bump) components.Bump {
+				for _, bump := range []components.Bump{components.MajorBump, components.MinorBump, components.PatchBump} {
+					for _, b := range bumps {
+						if b == bump {
+							return bump
+						}
+					}
+				}
+				return components.NoBump
+			}
+
+			for _, o := range optionality {
+				for _, v := range versions {
+					for _, u := range uris {
+						o, v, u := o, v, u
+
+						oldComponent := components.Component{ID: "some-id", Version: v.old, Optional: o.old, URI: "docker://some-registry/some-id:" + v.old}
+						newComponent := components.Component{ID: "some-id", Version: v.new, Optional: o.new, URI: oldComponent.URI}
+						if u.changed {
+							newComponent.URI = "docker://other-registry/some-id:" + v.new
+						}
+
+						if oldComponent == newComponent {
+							continue
+						}
+
+						expected := largest(o.bump, v.bump, u.bump)
+						it(fmt.Sprintf("requires a %s bump for %s -> %s (optional %t -> %t, uri changed %t)", expected, v.old, v.new, o.old, o.new, u.changed), func() {
+							changes := components.Diff([]components.Component{oldComponent}, []components.Component{newComponent})
+							Expect(changes).To(HaveLen(1))
+
+							bump, reasons := components.RequiredBump(changes)
+							Expect(bump).To(Equal(expected))
+							Expect(reasons).To(HaveLen(1))
+							Expect(reasons[0].Bump).To(Equal(expected))
+							Expect(reasons[0].Reason).NotTo(BeEmpty())
+						})
+					}
+				}
+			}
+		})
+	})
+}
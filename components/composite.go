@@ -0,0 +1,76 @@
+package components
+
+import (
+	"path"
+	"strings"
+)
+
+// Component is a buildpack referenced by the order groups of the composite.
+type Component struct {
+	ID      string
+	Version string
+
+	// Optional reports whether the component is optional in every order group
+	// that references it.
+	Optional bool
+
+	// URI is the package.toml dependency the component is packaged from, if
+	// any.
+	URI string
+}
+
+// InRepository reports whether the component is kept in this repository and
+// packaged from a file rather than a published image.
+func (c Component) InRepository() bool {
+	return c.URI != "" && !strings.HasPrefix(c.URI, DockerScheme)
+}
+
+// NewComposite lists the components of a composite buildpack in the order in
+// which they first appear in its order groups.
+func NewComposite(buildpack BuildpackConfig, pkg PackageConfig) []Component {
+	var components []Component
+	index := map[string]int{}
+
+	for _, order := range buildpack.Order {
+		for _, group := range order.Group {
+			i, ok := index[group.ID]
+			if !ok {
+				index[group.ID] = len(components)
+				components = append(components, Component{
+					ID:       group.ID,
+					Version:  group.Version,
+					Optional: group.Optional,
+				})
+				continue
+			}
+
+			components[i].Optional = components[i].Optional && group.Optional
+		}
+	}
+
+	for i, component := range components {
+		for _, dependency := range pkg.Dependencies {
+			if dependency.Name() == path.Base(component.ID) {
+				components[i].URI = dependency.URI
+			}
+		}
+	}
+
+	return components
+}
+
+// LoadComposite reads the components of the composite buildpack described by
+// the given buildpack.toml and package.toml files.
+func LoadComposite(buildpackPath, packagePath string) ([]Component, error) {
+	buildpack, err := ParseBuildpackConfig(buildpackPath)
+	if err != nil {
+		return nil, err
+	}
+
+	pkg, err := ParsePackageConfig(packagePath)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewComposite(buildpack, pkg), nil
+}
@@ -0,0 +1,92 @@
+package components_test
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"testing"
+
+	"github.com/paketo-buildpacks/go/components"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testComposite(t *testing.T, context spec.G, it spec.S) {
+	var Expect = NewWithT(t).Expect
+
+	context("NewComposite", func() {
+		it("lists the components in order of first appearance", func() {
+			composite := components.NewComposite(components.BuildpackConfig{
+				Order: []components.BuildpackOrder{
+					{Group: []components.BuildpackOrderGroup{
+						{ID: "paketo-buildpacks/go-dist", Version: "2.10.9"},
+						{ID: "paketo-buildpacks/go-mod-vendor", Version: "1.1.21"},
+						{ID: "paketo-buildpacks/procfile", Version: "5.13.2", Optional: true},
+					}},
+					{Group: []components.BuildpackOrderGroup{
+						{ID: "paketo-buildpacks/go-dist", Version: "2.10.9", Optional: true},
+						{ID: "paketo-buildpacks/go-build", Version: "2.4.20"},
+						{ID: "paketo-buildpacks/procfile", Version: "5.13.2", Optional: true},
+					}},
+				},
+			}, components.PackageConfig{
+				Dependencies: []components.PackageDependency{
+					{URI: "docker://docker.io/paketobuildpacks/go-build:2.4.20"},
+					{URI: "docker://docker.io/paketobuildpacks/go-dist:2.10.9"},
+				},
+			})
+
+			Expect(composite).To(Equal([]components.Component{
+				{ID: "paketo-buildpacks/go-dist", Version: "2.10.9", URI: "docker://docker.io/paketobuildpacks/go-dist:2.10.9"},
+				{ID: "paketo-buildpacks/go-mod-vendor", Version: "1.1.21"},
+				{ID: "paketo-buildpacks/procfile", Version: "5.13.2", Optional: true},
+				{ID: "paketo-buildpacks/go-build", Version: "2.4.20", URI: "docker://docker.io/paketobuildpacks/go-build:2.4.20"},
+			}))
+		})
+	})
+
+	context("LoadComposite", func() {
+		it("reads the repository composite", func() {
+			composite, err := components.LoadComposite(filepath.Join("..", "buildpack.toml"), filepath.Join("..", "package.toml"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(composite).NotTo(BeEmpty())
+
+			for _, component := range composite {
+				if (components.PackageDependency{URI: component.URI}).IsImage() {
+					Expect(component.URI).To(HaveSuffix(":"+component.Version), component.ID)
+					continue
+				}
+
+				// the order groups must reference the version that the
+				// component kept in this repository is packaged with
+				config, err := components.ParseBuildpackConfig(filepath.Join("..", "buildpacks", path.Base(component.ID), "buildpack.toml"))
+				Expect(err).NotTo(HaveOccurred(), component.ID)
+				Expect(config.Buildpack.ID).To(Equal(component.ID))
+				Expect(config.Buildpack.Version).To(Equal(component.Version), component.ID)
+			}
+		})
+
+		context("failure cases", func() {
+			context("when the buildpack.toml is malformed", func() {
+				it("returns an error", func() {
+					path := filepath.Join(t.TempDir(), "buildpack.toml")
+					Expect(os.WriteFile(path, []byte("%%%"), 0600)).To(Succeed())
+
+					_, err := components.LoadComposite(path, filepath.Join("..", "package.toml"))
+					Expect(err).To(MatchError(ContainSubstring("failed to parse buildpack config")))
+				})
+			})
+
+			context("when the package.toml is malformed", func() {
+				it("returns an error", func() {
+					path := filepath.Join(t.TempDir(), "package.toml")
+					Expect(os.WriteFile(path, []byte("%%%"), 0600)).To(Succeed())
+
+					_, err := components.LoadComposite(filepath.Join("..", "buildpack.toml"), path)
+					Expect(err).To(MatchError(ContainSubstring("failed to parse package config")))
+				})
+			})
+		})
+	})
+}
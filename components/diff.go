@@ -0,0 +1,125 @@
+package components
+
+import (
+	"github.com/Masterminds/semver/v3"
+)
+
+// ChangeType describes how a component changed between two versions of the
+// composite.
+type ChangeType string
+
+const (
+	Added   ChangeType = "added"
+	Removed ChangeType = "removed"
+	Updated ChangeType = "updated"
+)
+
+// Bump is a semantic version increment.
+type Bump string
+
+const (
+	NoBump    Bump = "none"
+	PatchBump Bump = "patch"
+	MinorBump Bump = "minor"
+	MajorBump Bump = "major"
+)
+
+// Change is the difference in a single component between two versions of the
+// composite. Old is the zero Component when the component was added, and New
+// is the zero Component when it was removed.
+type Change struct {
+	ID   string
+	Type ChangeType
+	Old  Component
+	New  Component
+
+	// Bump classifies the version change of an updated component. It is
+	// NoBump for added and removed components, and for updates that only
+	// change whether the component is optional or where it is packaged from.
+	Bump Bump
+
+	// Downgrade reports whether the component moved to a lower version.
+	Downgrade bool
+
+	// SourceChanged reports whether the source of a component kept in this
+	// repository changed. It is only set by MarkSourceChanges.
+	SourceChanged bool
+}
+
+// Diff compares two versions of the composite's components. Changes are
+// listed in the order the components appear in the new composite, followed by
+// the removed components.
+func Diff(old, new []Component) []Change {
+	var changes []Change
+
+	previous := map[string]Component{}
+	for _, component := range old {
+		previous[component.ID] = component
+	}
+
+	current := map[string]bool{}
+	for _, component := range new {
+		current[component.ID] = true
+
+		before, ok := previous[component.ID]
+		if !ok {
+			changes = append(changes, Change{ID: component.ID, Type: Added, New: component, Bump: NoBump})
+			continue
+		}
+
+		if before == component {
+			continue
+		}
+
+		bump, downgrade := classify(before.Version, component.Version)
+		changes = append(changes, Change{
+			ID:        component.ID,
+			Type:      Updated,
+			Old:       before,
+			New:       component,
+			Bump:      bump,
+			Downgrade: downgrade,
+		})
+	}
+
+	for _, component := range old {
+		if !current[component.ID] {
+			changes = append(changes, Change{ID: component.ID, Type: Removed, Old: component, Bump: NoBump})
+		}
+	}
+
+	return changes
+}
+
+// classify returns the most significant part of the version that differs
+// between old and new. Versions that are not valid semantic versions but
+// differ are classified as a major bump, since nothing can be assumed about
+// their compatibility.
+func classify(old, new string) (Bump, bool) {
+	if old == new {
+		return NoBump, false
+	}
+
+	before, err := semver.NewVersion(old)
+	if err != nil {
+		return MajorBump, false
+	}
+
+	after, err := semver.NewVersion(new)
+	if err != nil {
+		return MajorBump, false
+	}
+
+	downgrade := after.LessThan(before)
+
+	switch {
+	case before.Major() != after.Major():
+		return MajorBump, downgrade
+	case before.Minor() != after.Minor():
+		return MinorBump, downgrade
+	case before.Patch() != after.Patch() || before.Prerelease() != after.Prerelease():
+		return PatchBump, downgrade
+	default:
+		return NoBump, downgrade
+	}
+}
@@ -0,0 +1,86 @@
+package components_test
+
+import (
+	"testing"
+
+	"github.com/paketo-buildpacks/go/components"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testDiff(t *testing.T, context spec.G, it spec.S) {
+	var Expect = NewWithT(t).Expect
+
+	it("lists added, removed and updated components", func() {
+		old := []components.Component{
+			{ID: "paketo-buildpacks/go-dist", Version: "2.10.9"},
+			{ID: "paketo-buildpacks/dep", Version: "0.1.0"},
+			{ID: "paketo-buildpacks/go-build", Version: "2.4.20"},
+			{ID: "paketo-buildpacks/procfile", Version: "5.13.2", Optional: true},
+		}
+		new := []components.Component{
+			{ID: "paketo-buildpacks/go-dist", Version: "3.0.0"},
+			{ID: "paketo-buildpacks/go-build", Version: "2.4.20"},
+			{ID: "paketo-buildpacks/procfile", Version: "5.13.3", Optional: true},
+			{ID: "paketo-buildpacks/watchexec", Version: "3.9.3", Optional: true},
+		}
+
+		Expect(components.Diff(old, new)).To(Equal([]components.Change{
+			{ID: "paketo-buildpacks/go-dist", Type: components.Updated, Old: old[0], New: new[0], Bump: components.MajorBump},
+			{ID: "paketo-buildpacks/procfile", Type: components.Updated, Old: old[3], New: new[2], Bump: components.PatchBump},
+			{ID: "paketo-buildpacks/watchexec", Type: components.Added, New: new[3], Bump: components.NoBump},
+			{ID: "paketo-buildpacks/dep", Type: components.Removed, Old: old[1], Bump: components.NoBump},
+		}))
+	})
+
+	context("when comparing versions", func() {
+		type entry struct {
+			old, new  string
+			bump      components.Bump
+			downgrade bool
+		}
+
+		for _, e := range []entry{
+			{old: "1.2.3", new: "2.0.0", bump: components.MajorBump},
+			{old: "1.2.3", new: "1.3.0", bump: components.MinorBump},
+			{old: "1.2.3", new: "1.2.4", bump: components.PatchBump},
+			{old: "1.2.3", new: "1.2.3-rc.1", bump: components.PatchBump, downgrade: true},
+			{old: "1.2.3", new: "1.2.2", bump: components.PatchBump, downgrade: true},
+			{old: "2.0.0", new: "1.9.9", bump: components.MajorBump, downgrade: true},
+			{old: "1.2.3", new: "1.2.3+build", bump: components.NoBump},
+			{old: "latest", new: "1.2.3", bump: components.MajorBump},
+			{old: "1.2.3", new: "latest", bump: components.MajorBump},
+		} {
+			e := e
+			it("classifies "+e.old+" to "+e.new+" as "+string(e.bump), func() {
+				changes := components.Diff(
+					[]components.Component{{ID: "some-id", Version: e.old}},
+					[]components.Component{{ID: "some-id", Version: e.new}},
+				)
+				Expect(changes).To(HaveLen(1))
+				Expect(changes[0].Bump).To(Equal(e.bump))
+				Expect(changes[0].Downgrade).To(Equal(e.downgrade))
+			})
+		}
+	})
+
+	context("when only the optional flag or URI changes", func() {
+		it("lists the update without a version bump", func() {
+			changes := components.Diff(
+				[]components.Component{{ID: "some-id", Version: "1.2.3", URI: "docker://some-registry/some-id:1.2.3"}},
+				[]components.Component{{ID: "some-id", Version: "1.2.3", Optional: true, URI: "docker://other-registry/some-id:1.2.3"}},
+			)
+			Expect(changes).To(HaveLen(1))
+			Expect(changes[0].Type).To(Equal(components.Updated))
+			Expect(changes[0].Bump).To(Equal(components.NoBump))
+		})
+	})
+
+	context("when nothing changed", func() {
+		it("returns no changes", func() {
+			composite := []components.Component{{ID: "some-id", Version: "1.2.3"}}
+			Expect(components.Diff(composite, composite)).To(BeEmpty())
+		})
+	})
+}
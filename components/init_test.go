@@ -0,0 +1,65 @@
+package components_test
+
+import (
+	"archive/tar"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+)
+
+func TestUnitComponents(t *testing.T) {
+	suite := spec.New("components", spec.Report(report.Terminal{}), spec.Parallel())
+	suite("BuildpackConfig", testBuildpackConfig)
+	suite("Bump", testBump)
+	suite("Composite", testComposite)
+	suite("Diff", testDiff)
+	suite("Lock", testLock)
+	suite("Lockfile", testLockfile)
+	suite("PackageConfig", testPackageConfig)
+	suite("Source", testSource)
+	suite("Sources", testSources)
+	suite("Vendor", testVendor)
+	suite.Run(t)
+}
+
+func untar(path, dir string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	tr := tar.NewReader(file)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		target := filepath.Join(dir, header.Name)
+		if header.Typeflag == tar.TypeDir {
+			err = os.MkdirAll(target, os.ModePerm)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		content, err := io.ReadAll(tr)
+		if err != nil {
+			return err
+		}
+
+		err = os.WriteFile(target, content, 0600)
+		if err != nil {
+			return err
+		}
+	}
+}
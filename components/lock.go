@@ -0,0 +1,181 @@
+package components
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Lock resolves every image dependency in the given package config from the
+// source and records its digest.
+func Lock(config PackageConfig, source Source) (Lockfile, error) {
+	var lockfile Lockfile
+	for _, dependency := range config.Dependencies {
+		if !dependency.IsImage() {
+			continue
+		}
+
+		locked, err := lockImage(dependency, source)
+		if err != nil {
+			return Lockfile{}, err
+		}
+
+		lockfile.Dependencies = append(lockfile.Dependencies, locked)
+	}
+
+	return lockfile, nil
+}
+
+// LockImages resolves the images the component buildpacks pull during the
+// build from the source and records their digests.
+func LockImages(images []PackageDependency, source Source) ([]LockedDependency, error) {
+	var locked []LockedDependency
+	for _, image := range images {
+		dependency, err := lockImage(image, source)
+		if err != nil {
+			return nil, err
+		}
+
+		locked = append(locked, dependency)
+	}
+
+	return locked, nil
+}
+
+func lockImage(dependency PackageDependency, source Source) (LockedDependency, error) {
+	ref, err := dependency.Reference()
+	if err != nil {
+		return LockedDependency{}, err
+	}
+
+	artifact, err := source.Resolve(ref)
+	if err != nil {
+		return LockedDependency{}, fmt.Errorf("failed to lock %s: %w", dependency.URI, err)
+	}
+
+	return LockedDependency{
+		URI:    dependency.URI,
+		Digest: artifact.Digest.String(),
+	}, nil
+}
+
+// Pin verifies that every image dependency in the given package config still
+// resolves to its locked digest and returns a copy of the config whose
+// dependencies reference those digests instead of tags, so that a tag pushed
+// after verification cannot change what is packaged.
+func Pin(config PackageConfig, lockfile Lockfile, source Source) (PackageConfig, error) {
+	pinned := PackageConfig{
+		Buildpack: config.Buildpack,
+		Targets:   config.Targets,
+	}
+
+	for _, dependency := range config.Dependencies {
+		if !dependency.IsImage() {
+			pinned.Dependencies = append(pinned.Dependencies, dependency)
+			continue
+		}
+
+		artifact, err := resolveLocked(dependency, lockfile, source)
+		if err != nil {
+			return PackageConfig{}, fmt.Errorf("failed to pin %s: %w", dependency.URI, err)
+		}
+
+		ref, err := dependency.Reference()
+		if err != nil {
+			return PackageConfig{}, err
+		}
+
+		pinned.Dependencies = append(pinned.Dependencies, PackageDependency{
+			URI: fmt.Sprintf("%s%s@%s", DockerScheme, ref.Context().Name(), artifact.Digest),
+		})
+	}
+
+	return pinned, nil
+}
+
+// resolveLocked resolves the dependency from the source and checks that its
+// digest matches the one recorded in the lockfile.
+func resolveLocked(dependency PackageDependency, lockfile Lockfile, source Source) (Artifact, error) {
+	locked, ok := lockfile.Digest(dependency.URI)
+	if !ok {
+		return Artifact{}, errors.New("dependency is missing from the lockfile")
+	}
+
+	ref, err := dependency.Reference()
+	if err != nil {
+		return Artifact{}, err
+	}
+
+	artifact, err := source.Resolve(ref)
+	if err != nil {
+		return Artifact{}, err
+	}
+
+	if !strings.EqualFold(artifact.Digest.String(), locked) {
+		return Artifact{}, fmt.Errorf("digest mismatch: locked %s, resolved %s", locked, artifact.Digest)
+	}
+
+	return artifact, nil
+}
+
+// Check verifies that the lockfile records exactly the image dependencies of
+// the given package config, so that a package.toml that was changed without
+// locking it again is caught before anything is resolved.
+func Check(config PackageConfig, lockfile Lockfile) error {
+	var problems []string
+
+	locked := map[string]bool{}
+	for _, dependency := range lockfile.Dependencies {
+		locked[dependency.URI] = true
+	}
+
+	referenced := map[string]bool{}
+	for _, dependency := range config.Dependencies {
+		if !dependency.IsImage() {
+			continue
+		}
+
+		referenced[dependency.URI] = true
+		if !locked[dependency.URI] {
+			problems = append(problems, fmt.Sprintf("%s is missing from the lockfile", dependency.URI))
+		}
+	}
+
+	for _, dependency := range lockfile.Dependencies {
+		if !referenced[dependency.URI] {
+			problems = append(problems, fmt.Sprintf("%s is locked but no longer in package.toml", dependency.URI))
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("lockfile is stale, run `go run ./cmd/components lock`:\n  %s", strings.Join(problems, "\n  "))
+	}
+
+	return nil
+}
+
+// CheckImages verifies that the lockfile records exactly the images the
+// component buildpacks pull during the build.
+func CheckImages(images []PackageDependency, lockfile Lockfile) error {
+	var problems []string
+
+	listed := map[string]bool{}
+	for _, image := range images {
+		listed[image.URI] = true
+		if _, ok := lockfile.ImageDigest(image.URI); !ok {
+			problems = append(problems, fmt.Sprintf("%s is missing from the lockfile", image.URI))
+		}
+	}
+
+	for _, image := range lockfile.Images {
+		if !listed[image.URI] {
+			problems = append(problems, fmt.Sprintf("%s is locked but no longer in a component buildpack.toml", image.URI))
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("lockfile is stale, run `go run ./cmd/components lock`:\n  %s", strings.Join(problems, "\n  "))
+	}
+
+	return nil
+}
@@ -0,0 +1,216 @@
+package components_test
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/paketo-buildpacks/go/components"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testLock(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		server *httptest.Server
+		source components.RegistrySource
+		config components.PackageConfig
+		digest v1.Hash
+	)
+
+	it.Before(func() {
+		server = httptest.NewServer(registry.New())
+		host := strings.TrimPrefix(server.URL, "http://")
+		source = components.NewRegistrySource(host)
+
+		image, err := random.Image(64, 1)
+		Expect(err).NotTo(HaveOccurred())
+
+		ref, err := name.ParseReference(host + "/paketobuildpacks/go-build:2.4.20")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(remote.Write(ref, image)).To(Succeed())
+
+		digest, err = image.Digest()
+		Expect(err).NotTo(HaveOccurred())
+
+		config = components.PackageConfig{
+			Buildpack: components.PackageBuildpack{URI: "build/buildpack.tgz"},
+			Dependencies: []components.PackageDependency{
+				{URI: "docker://docker.io/paketobuildpacks/go-build:2.4.20"},
+				{URI: "local/buildpack.cnb"},
+			},
+			Targets: []components.PackageTarget{{OS: "linux", Arch: "amd64"}},
+		}
+	})
+
+	it.After(func() {
+		server.Close()
+	})
+
+	context("Lock", func() {
+		it("records the digest of every image dependency", func() {
+			lockfile, err := components.Lock(config, source)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(lockfile).To(Equal(components.Lockfile{
+				Dependencies: []components.LockedDependency{
+					{URI: "docker://docker.io/paketobuildpacks/go-build:2.4.20", Digest: digest.String()},
+				},
+			}))
+		})
+
+		context("failure cases", func() {
+			context("when a dependency cannot be resolved", func() {
+				it("returns an error", func() {
+					config.Dependencies[0].URI = "docker://docker.io/paketobuildpacks/go-dist:2.10.9"
+
+					_, err := components.Lock(config, source)
+					Expect(err).To(MatchError(ContainSubstring("failed to lock docker://docker.io/paketobuildpacks/go-dist:2.10.9")))
+				})
+			})
+		})
+	})
+
+	context("LockImages", func() {
+		it("records the digest of every image", func() {
+			locked, err := components.LockImages([]components.PackageDependency{
+				{URI: "docker://docker.io/paketobuildpacks/go-build:2.4.20"},
+			}, source)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(locked).To(Equal([]components.LockedDependency{
+				{URI: "docker://docker.io/paketobuildpacks/go-build:2.4.20", Digest: digest.String()},
+			}))
+		})
+
+		context("failure cases", func() {
+			context("when an image cannot be resolved", func() {
+				it("returns an error", func() {
+					_, err := components.LockImages([]components.PackageDependency{
+						{URI: "docker://ghcr.io/example/instrumentation:v1.0.0"},
+					}, source)
+					Expect(err).To(MatchError(ContainSubstring("failed to lock docker://ghcr.io/example/instrumentation:v1.0.0")))
+				})
+			})
+		})
+	})
+
+	context("Pin", func() {
+		var lockfile components.Lockfile
+
+		it.Before(func() {
+			lockfile = components.Lockfile{
+				Dependencies: []components.LockedDependency{
+					{URI: "docker://docker.io/paketobuildpacks/go-build:2.4.20", Digest: digest.String()},
+				},
+			}
+		})
+
+		it("rewrites image dependencies to their locked digests", func() {
+			pinned, err := components.Pin(config, lockfile, source)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(pinned).To(Equal(components.PackageConfig{
+				Buildpack: components.PackageBuildpack{URI: "build/buildpack.tgz"},
+				Dependencies: []components.PackageDependency{
+					{URI: "docker://index.docker.io/paketobuildpacks/go-build@" + digest.String()},
+					{URI: "local/buildpack.cnb"},
+				},
+				Targets: []components.PackageTarget{{OS: "linux", Arch: "amd64"}},
+			}))
+		})
+
+		context("failure cases", func() {
+			context("when a dependency is missing from the lockfile", func() {
+				it("returns an error", func() {
+					_, err := components.Pin(config, components.Lockfile{}, source)
+					Expect(err).To(MatchError("failed to pin docker://docker.io/paketobuildpacks/go-build:2.4.20: dependency is missing from the lockfile"))
+				})
+			})
+
+			context("when the tag was re-pushed since it was locked", func() {
+				it("returns an error", func() {
+					lockfile.Dependencies[0].Digest = "sha256:0000000000000000000000000000000000000000000000000000000000000000"
+
+					_, err := components.Pin(config, lockfile, source)
+					Expect(err).To(MatchError(ContainSubstring("digest mismatch: locked sha256:0000000000000000000000000000000000000000000000000000000000000000, resolved " + digest.String())))
+				})
+			})
+		})
+	})
+
+	context("Check", func() {
+		it("accepts a lockfile that records every image dependency", func() {
+			err := components.Check(config, components.Lockfile{
+				Dependencies: []components.LockedDependency{
+					{URI: "docker://docker.io/paketobuildpacks/go-build:2.4.20", Digest: digest.String()},
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		context("failure cases", func() {
+			context("when a dependency is missing from the lockfile", func() {
+				it("returns an error", func() {
+					err := components.Check(config, components.Lockfile{})
+					Expect(err).To(MatchError(ContainSubstring("docker://docker.io/paketobuildpacks/go-build:2.4.20 is missing from the lockfile")))
+				})
+			})
+
+			context("when the lockfile records a dependency that was removed", func() {
+				it("returns an error", func() {
+					err := components.Check(config, components.Lockfile{
+						Dependencies: []components.LockedDependency{
+							{URI: "docker://docker.io/paketobuildpacks/go-build:2.4.20", Digest: digest.String()},
+							{URI: "docker://docker.io/paketobuildpacks/go-build:2.4.19", Digest: digest.String()},
+						},
+					})
+					Expect(err).To(MatchError(ContainSubstring("docker://docker.io/paketobuildpacks/go-build:2.4.19 is locked but no longer in package.toml")))
+				})
+			})
+		})
+	})
+	context("CheckImages", func() {
+		var images []components.PackageDependency
+
+		it.Before(func() {
+			images = []components.PackageDependency{
+				{URI: "docker://ghcr.io/example/instrumentation:v1.0.0"},
+			}
+		})
+
+		it("accepts a lockfile that records every image", func() {
+			err := components.CheckImages(images, components.Lockfile{
+				Images: []components.LockedDependency{
+					{URI: "docker://ghcr.io/example/instrumentation:v1.0.0", Digest: digest.String()},
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		context("failure cases", func() {
+			context("when an image is missing from the lockfile", func() {
+				it("returns an error", func() {
+					err := components.CheckImages(images, components.Lockfile{})
+					Expect(err).To(MatchError(ContainSubstring("docker://ghcr.io/example/instrumentation:v1.0.0 is missing from the lockfile")))
+				})
+			})
+
+			context("when the lockfile records an image that was removed", func() {
+				it("returns an error", func() {
+					err := components.CheckImages(nil, components.Lockfile{
+						Images: []components.LockedDependency{
+							{URI: "docker://ghcr.io/example/instrumentation:v1.0.0", Digest: digest.String()},
+						},
+					})
+					Expect(err).To(MatchError(ContainSubstring("docker://ghcr.io/example/instrumentation:v1.0.0 is locked but no longer in a component buildpack.toml")))
+				})
+			})
+		})
+	})
+}
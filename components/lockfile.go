@@ -0,0 +1,79 @@
+package components
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/BurntSushi/toml"
+)
+
+// LockfileName is the name of the lockfile that sits next to package.toml.
+const LockfileName = "package.lock.toml"
+
+// Lockfile records the OCI digest of every component buildpackage referenced
+// in package.toml, and of every image the component buildpacks pull during
+// the build.
+type Lockfile struct {
+	Dependencies []LockedDependency `toml:"dependencies"`
+	Images       []LockedDependency `toml:"images,omitempty"`
+}
+
+// LockedDependency pins a package.toml dependency URI to a digest.
+type LockedDependency struct {
+	URI    string `toml:"uri"`
+	Digest string `toml:"digest"`
+}
+
+// ReadLockfile reads the lockfile at the given path.
+func ReadLockfile(path string) (Lockfile, error) {
+	var lockfile Lockfile
+	_, err := toml.DecodeFile(path, &lockfile)
+	if err != nil {
+		return Lockfile{}, fmt.Errorf("failed to parse lockfile: %w", err)
+	}
+
+	return lockfile, nil
+}
+
+// WriteLockfile writes the given lockfile to the given path.
+func WriteLockfile(path string, lockfile Lockfile) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to write lockfile: %w", err)
+	}
+	defer file.Close()
+
+	_, err = fmt.Fprintln(file, "# This file is generated by `go run ./cmd/components lock`. Do not edit it by hand.")
+	if err != nil {
+		return fmt.Errorf("failed to write lockfile: %w", err)
+	}
+
+	err = toml.NewEncoder(file).Encode(lockfile)
+	if err != nil {
+		return fmt.Errorf("failed to write lockfile: %w", err)
+	}
+
+	return nil
+}
+
+// Digest returns the locked digest for the given dependency URI.
+func (l Lockfile) Digest(uri string) (string, bool) {
+	for _, dependency := range l.Dependencies {
+		if dependency.URI == uri {
+			return dependency.Digest, true
+		}
+	}
+
+	return "", false
+}
+
+// ImageDigest returns the locked digest for the given component image URI.
+func (l Lockfile) ImageDigest(uri string) (string, bool) {
+	for _, image := range l.Images {
+		if image.URI == uri {
+			return image.Digest, true
+		}
+	}
+
+	return "", false
+}
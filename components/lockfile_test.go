@@ -0,0 +1,78 @@
+package components_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/paketo-buildpacks/go/components"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testLockfile(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		path string
+	)
+
+	it.Before(func() {
+		path = filepath.Join(t.TempDir(), components.LockfileName)
+	})
+
+	context("ReadLockfile", func() {
+		it("parses the locked dependencies", func() {
+			Expect(os.WriteFile(path, []byte(`
+[[dependencies]]
+  uri = "docker://docker.io/paketobuildpacks/go-build:2.4.20"
+  digest = "sha256:aaaa"
+`), 0600)).To(Succeed())
+
+			lockfile, err := components.ReadLockfile(path)
+			Expect(err).NotTo(HaveOccurred())
+
+			digest, ok := lockfile.Digest("docker://docker.io/paketobuildpacks/go-build:2.4.20")
+			Expect(ok).To(BeTrue())
+			Expect(digest).To(Equal("sha256:aaaa"))
+
+			_, ok = lockfile.Digest("docker://docker.io/paketobuildpacks/go-dist:2.10.9")
+			Expect(ok).To(BeFalse())
+		})
+
+		context("failure cases", func() {
+			context("when the lockfile does not exist", func() {
+				it("returns an error", func() {
+					_, err := components.ReadLockfile(path)
+					Expect(err).To(MatchError(ContainSubstring("failed to parse lockfile")))
+				})
+			})
+		})
+	})
+
+	context("WriteLockfile", func() {
+		it("round-trips the lockfile", func() {
+			lockfile := components.Lockfile{
+				Dependencies: []components.LockedDependency{
+					{URI: "docker://docker.io/paketobuildpacks/go-build:2.4.20", Digest: "sha256:aaaa"},
+					{URI: "docker://docker.io/paketobuildpacks/go-dist:2.10.9", Digest: "sha256:bbbb"},
+				},
+			}
+			Expect(components.WriteLockfile(path, lockfile)).To(Succeed())
+
+			parsed, err := components.ReadLockfile(path)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(parsed).To(Equal(lockfile))
+		})
+
+		context("failure cases", func() {
+			context("when the directory does not exist", func() {
+				it("returns an error", func() {
+					err := components.WriteLockfile(filepath.Join(path, "missing", "lock.toml"), components.Lockfile{})
+					Expect(err).To(MatchError(ContainSubstring("failed to write lockfile")))
+				})
+			})
+		})
+	})
+}
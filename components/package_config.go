@@ -0,0 +1,97 @@
+package components
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/google/go-containerregistry/pkg/name"
+)
+
+// DockerScheme is the URI scheme used by package.toml to reference component
+// buildpackages that are published as images.
+const DockerScheme = "docker://"
+
+// PackageConfig represents the contents of a package.toml file.
+type PackageConfig struct {
+	Buildpack    PackageBuildpack    `toml:"buildpack"`
+	Dependencies []PackageDependency `toml:"dependencies"`
+	Targets      []PackageTarget     `toml:"targets,omitempty"`
+}
+
+// PackageBuildpack is the [buildpack] table of a package.toml file.
+type PackageBuildpack struct {
+	URI string `toml:"uri"`
+}
+
+// PackageDependency is a single [[dependencies]] entry of a package.toml file.
+type PackageDependency struct {
+	URI string `toml:"uri"`
+}
+
+// PackageTarget is a single [[targets]] entry of a package.toml file.
+type PackageTarget struct {
+	OS   string `toml:"os"`
+	Arch string `toml:"arch"`
+}
+
+// ParsePackageConfig reads the package.toml file at the given path.
+func ParsePackageConfig(path string) (PackageConfig, error) {
+	var config PackageConfig
+	_, err := toml.DecodeFile(path, &config)
+	if err != nil {
+		return PackageConfig{}, fmt.Errorf("failed to parse package config: %w", err)
+	}
+
+	return config, nil
+}
+
+// WritePackageConfig writes the given config as a package.toml file at the
+// given path.
+func WritePackageConfig(path string, config PackageConfig) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to write package config: %w", err)
+	}
+	defer file.Close()
+
+	err = toml.NewEncoder(file).Encode(config)
+	if err != nil {
+		return fmt.Errorf("failed to write package config: %w", err)
+	}
+
+	return nil
+}
+
+// IsImage reports whether the dependency references a component buildpackage
+// image rather than a local file.
+func (d PackageDependency) IsImage() bool {
+	return strings.HasPrefix(d.URI, DockerScheme)
+}
+
+// Reference parses the image reference of a dependency with a docker:// URI.
+func (d PackageDependency) Reference() (name.Reference, error) {
+	if !d.IsImage() {
+		return nil, fmt.Errorf("dependency %q is not an image reference", d.URI)
+	}
+
+	ref, err := name.ParseReference(strings.TrimPrefix(d.URI, DockerScheme))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse dependency %q: %w", d.URI, err)
+	}
+
+	return ref, nil
+}
+
+// Name returns the short name of the component, which is the final element of
+// its repository path (e.g. "go-build").
+func (d PackageDependency) Name() string {
+	ref, err := d.Reference()
+	if err != nil {
+		return strings.TrimSuffix(path.Base(d.URI), path.Ext(d.URI))
+	}
+
+	return path.Base(ref.Context().RepositoryStr())
+}
@@ -0,0 +1,118 @@
+package components_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/paketo-buildpacks/go/components"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testPackageConfig(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		dir string
+	)
+
+	it.Before(func() {
+		dir = t.TempDir()
+	})
+
+	context("ParsePackageConfig", func() {
+		it("parses the buildpack, dependencies and targets", func() {
+			path := filepath.Join(dir, "package.toml")
+			Expect(os.WriteFile(path, []byte(`
+[buildpack]
+  uri = "build/buildpack.tgz"
+
+[[dependencies]]
+  uri = "docker://docker.io/paketobuildpacks/go-build:2.4.20"
+
+[[dependencies]]
+  uri = "some/local/buildpack.cnb"
+
+[[targets]]
+  arch = "arm64"
+  os = "linux"
+`), 0600)).To(Succeed())
+
+			config, err := components.ParsePackageConfig(path)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(config).To(Equal(components.PackageConfig{
+				Buildpack: components.PackageBuildpack{URI: "build/buildpack.tgz"},
+				Dependencies: []components.PackageDependency{
+					{URI: "docker://docker.io/paketobuildpacks/go-build:2.4.20"},
+					{URI: "some/local/buildpack.cnb"},
+				},
+				Targets: []components.PackageTarget{{OS: "linux", Arch: "arm64"}},
+			}))
+
+			Expect(config.Dependencies[0].IsImage()).To(BeTrue())
+			Expect(config.Dependencies[0].Name()).To(Equal("go-build"))
+			Expect(config.Dependencies[1].IsImage()).To(BeFalse())
+			Expect(config.Dependencies[1].Name()).To(Equal("buildpack"))
+		})
+
+		it("parses the repository package.toml", func() {
+			config, err := components.ParsePackageConfig(filepath.Join("..", "package.toml"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(config.Dependencies).NotTo(BeEmpty())
+
+			for _, dependency := range config.Dependencies {
+				if !dependency.IsImage() {
+					// components kept in this repository are packaged by
+					// scripts/package.sh from buildpacks/<name>
+					Expect(dependency.URI).To(Equal(fmt.Sprintf("build/components/%s.tgz", dependency.Name())))
+					Expect(filepath.Join("..", "buildpacks", dependency.Name(), "buildpack.toml")).To(BeARegularFile())
+					continue
+				}
+
+				_, err := dependency.Reference()
+				Expect(err).NotTo(HaveOccurred())
+			}
+		})
+
+		context("failure cases", func() {
+			context("when the file is malformed", func() {
+				it("returns an error", func() {
+					path := filepath.Join(dir, "package.toml")
+					Expect(os.WriteFile(path, []byte("%%%"), 0600)).To(Succeed())
+
+					_, err := components.ParsePackageConfig(path)
+					Expect(err).To(MatchError(ContainSubstring("failed to parse package config")))
+				})
+			})
+		})
+	})
+
+	context("WritePackageConfig", func() {
+		it("round-trips the config", func() {
+			config := components.PackageConfig{
+				Buildpack:    components.PackageBuildpack{URI: "build/buildpack.tgz"},
+				Dependencies: []components.PackageDependency{{URI: "/some/go-build.cnb"}},
+				Targets:      []components.PackageTarget{{OS: "linux", Arch: "amd64"}},
+			}
+
+			path := filepath.Join(dir, "package.toml")
+			Expect(components.WritePackageConfig(path, config)).To(Succeed())
+
+			parsed, err := components.ParsePackageConfig(path)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(parsed).To(Equal(config))
+		})
+	})
+
+	context("Reference", func() {
+		context("when the dependency is not an image", func() {
+			it("returns an error", func() {
+				_, err := components.PackageDependency{URI: "some/file.cnb"}.Reference()
+				Expect(err).To(MatchError(`dependency "some/file.cnb" is not an image reference`))
+			})
+		})
+	})
+}
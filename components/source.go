@@ -0,0 +1,145 @@
+package components
+
+import (
+	"fmt"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/layout"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+)
+
+// RefNameAnnotation is the OCI annotation used to name images in an OCI image
+// layout.
+const RefNameAnnotation = "org.opencontainers.image.ref.name"
+
+// Artifact is a component buildpackage resolved from a Source. A component is
+// either a single image or an image index holding one image per target.
+type Artifact struct {
+	Digest v1.Hash
+	Image  v1.Image
+	Index  v1.ImageIndex
+}
+
+// A Source resolves component buildpackage references.
+type Source interface {
+	Resolve(ref name.Reference) (Artifact, error)
+}
+
+// RegistrySource resolves component buildpackages from a registry. When Host
+// is set, references are resolved against that registry instead of the one
+// they name, so a local registry can stand in for docker.io.
+type RegistrySource struct {
+	Host    string
+	Options []remote.Option
+}
+
+// NewRegistrySource returns a RegistrySource that rewrites references to the
+// given host. An empty host resolves references as written.
+func NewRegistrySource(host string, options ...remote.Option) RegistrySource {
+	return RegistrySource{
+		Host:    host,
+		Options: options,
+	}
+}
+
+func (s RegistrySource) Resolve(ref name.Reference) (Artifact, error) {
+	if s.Host != "" {
+		var err error
+		ref, err = name.ParseReference(fmt.Sprintf("%s/%s%s%s", s.Host, ref.Context().RepositoryStr(), separator(ref), ref.Identifier()))
+		if err != nil {
+			return Artifact{}, fmt.Errorf("failed to rewrite reference: %w", err)
+		}
+	}
+
+	descriptor, err := remote.Get(ref, s.Options...)
+	if err != nil {
+		return Artifact{}, fmt.Errorf("failed to fetch %s: %w", ref, err)
+	}
+
+	artifact := Artifact{Digest: descriptor.Digest}
+	if descriptor.MediaType.IsIndex() {
+		artifact.Index, err = descriptor.ImageIndex()
+	} else {
+		artifact.Image, err = descriptor.Image()
+	}
+	if err != nil {
+		return Artifact{}, fmt.Errorf("failed to read %s: %w", ref, err)
+	}
+
+	return artifact, nil
+}
+
+// LayoutSource resolves component buildpackages from an OCI image layout
+// directory. Images in the layout are matched using their ref name
+// annotation, which must hold the full image reference (as written by
+// `crane pull --format oci` or `skopeo copy oci:<dir>:<ref>`).
+type LayoutSource struct {
+	Path string
+}
+
+// NewLayoutSource returns a LayoutSource reading from the given directory.
+func NewLayoutSource(path string) LayoutSource {
+	return LayoutSource{Path: path}
+}
+
+func (s LayoutSource) Resolve(ref name.Reference) (Artifact, error) {
+	index, err := layout.ImageIndexFromPath(s.Path)
+	if err != nil {
+		return Artifact{}, fmt.Errorf("failed to read OCI layout: %w", err)
+	}
+
+	manifest, err := index.IndexManifest()
+	if err != nil {
+		return Artifact{}, fmt.Errorf("failed to read OCI layout: %w", err)
+	}
+
+	for _, descriptor := range manifest.Manifests {
+		annotated, err := name.ParseReference(descriptor.Annotations[RefNameAnnotation])
+		if err != nil || annotated.Name() != ref.Name() {
+			continue
+		}
+
+		artifact := Artifact{Digest: descriptor.Digest}
+		if descriptor.MediaType.IsIndex() {
+			artifact.Index, err = index.ImageIndex(descriptor.Digest)
+		} else {
+			artifact.Image, err = index.Image(descriptor.Digest)
+		}
+		if err != nil {
+			return Artifact{}, fmt.Errorf("failed to read %s from OCI layout: %w", ref, err)
+		}
+
+		return artifact, nil
+	}
+
+	return Artifact{}, fmt.Errorf("failed to find %s in OCI layout %s", ref, s.Path)
+}
+
+// ImageFor returns the image in the artifact built for the given platform.
+func (a Artifact) ImageFor(platform v1.Platform) (v1.Image, error) {
+	if a.Index == nil {
+		return a.Image, nil
+	}
+
+	manifest, err := a.Index.IndexManifest()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read image index: %w", err)
+	}
+
+	for _, descriptor := range manifest.Manifests {
+		if descriptor.Platform != nil && descriptor.Platform.Satisfies(platform) {
+			return a.Index.Image(descriptor.Digest)
+		}
+	}
+
+	return nil, fmt.Errorf("failed to find image for platform %s", platform)
+}
+
+func separator(ref name.Reference) string {
+	if _, ok := ref.(name.Digest); ok {
+		return "@"
+	}
+
+	return ":"
+}
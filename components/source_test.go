@@ -0,0 +1,176 @@
+package components_test
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/layout"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/paketo-buildpacks/go/components"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testSource(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		ref name.Reference
+	)
+
+	it.Before(func() {
+		var err error
+		ref, err = name.ParseReference("docker.io/paketobuildpacks/go-build:2.4.20")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	context("RegistrySource", func() {
+		var (
+			server *httptest.Server
+			host   string
+		)
+
+		it.Before(func() {
+			server = httptest.NewServer(registry.New())
+			host = strings.TrimPrefix(server.URL, "http://")
+		})
+
+		it.After(func() {
+			server.Close()
+		})
+
+		it("resolves images from the rewritten registry host", func() {
+			image, err := random.Image(64, 1)
+			Expect(err).NotTo(HaveOccurred())
+
+			mirrored, err := name.ParseReference(host + "/paketobuildpacks/go-build:2.4.20")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(remote.Write(mirrored, image)).To(Succeed())
+
+			artifact, err := components.NewRegistrySource(host).Resolve(ref)
+			Expect(err).NotTo(HaveOccurred())
+
+			digest, err := image.Digest()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(artifact.Digest).To(Equal(digest))
+			Expect(artifact.Image).NotTo(BeNil())
+			Expect(artifact.Index).To(BeNil())
+		})
+
+		it("resolves image indexes from the rewritten registry host", func() {
+			index, err := random.Index(64, 1, 2)
+			Expect(err).NotTo(HaveOccurred())
+
+			mirrored, err := name.ParseReference(host + "/paketobuildpacks/go-build:2.4.20")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(remote.WriteIndex(mirrored, index)).To(Succeed())
+
+			artifact, err := components.NewRegistrySource(host).Resolve(ref)
+			Expect(err).NotTo(HaveOccurred())
+
+			digest, err := index.Digest()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(artifact.Digest).To(Equal(digest))
+			Expect(artifact.Index).NotTo(BeNil())
+		})
+
+		context("failure cases", func() {
+			context("when the image does not exist", func() {
+				it("returns an error", func() {
+					_, err := components.NewRegistrySource(host).Resolve(ref)
+					Expect(err).To(MatchError(ContainSubstring("failed to fetch")))
+				})
+			})
+		})
+	})
+
+	context("LayoutSource", func() {
+		var dir string
+
+		it.Before(func() {
+			dir = t.TempDir()
+		})
+
+		it("resolves images by their ref name annotation", func() {
+			p, err := layout.Write(dir, empty.Index)
+			Expect(err).NotTo(HaveOccurred())
+
+			other, err := random.Image(64, 1)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(p.AppendImage(other, layout.WithAnnotations(map[string]string{
+				components.RefNameAnnotation: "docker.io/paketobuildpacks/go-dist:2.10.9",
+			}))).To(Succeed())
+
+			image, err := random.Image(64, 1)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(p.AppendImage(image, layout.WithAnnotations(map[string]string{
+				components.RefNameAnnotation: "index.docker.io/paketobuildpacks/go-build:2.4.20",
+			}))).To(Succeed())
+
+			artifact, err := components.NewLayoutSource(dir).Resolve(ref)
+			Expect(err).NotTo(HaveOccurred())
+
+			digest, err := image.Digest()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(artifact.Digest).To(Equal(digest))
+		})
+
+		context("failure cases", func() {
+			context("when the layout does not exist", func() {
+				it("returns an error", func() {
+					_, err := components.NewLayoutSource(dir).Resolve(ref)
+					Expect(err).To(MatchError(ContainSubstring("failed to read OCI layout")))
+				})
+			})
+
+			context("when the image is not in the layout", func() {
+				it("returns an error", func() {
+					_, err := layout.Write(dir, empty.Index)
+					Expect(err).NotTo(HaveOccurred())
+
+					_, err = components.NewLayoutSource(dir).Resolve(ref)
+					Expect(err).To(MatchError(ContainSubstring("failed to find docker.io/paketobuildpacks/go-build:2.4.20 in OCI layout")))
+				})
+			})
+		})
+	})
+
+	context("Artifact.ImageFor", func() {
+		it("selects the image for the platform from an index", func() {
+			index, err := random.Index(64, 1, 2)
+			Expect(err).NotTo(HaveOccurred())
+
+			manifest, err := index.IndexManifest()
+			Expect(err).NotTo(HaveOccurred())
+
+			p, err := layout.Write(t.TempDir(), empty.Index)
+			Expect(err).NotTo(HaveOccurred())
+
+			for i, arch := range []string{"amd64", "arm64"} {
+				image, err := index.Image(manifest.Manifests[i].Digest)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(p.AppendImage(image, layout.WithPlatform(v1.Platform{OS: "linux", Architecture: arch}))).To(Succeed())
+			}
+
+			platformed, err := p.ImageIndex()
+			Expect(err).NotTo(HaveOccurred())
+
+			image, err := components.Artifact{Index: platformed}.ImageFor(v1.Platform{OS: "linux", Architecture: "arm64"})
+			Expect(err).NotTo(HaveOccurred())
+
+			digest, err := image.Digest()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(digest).To(Equal(manifest.Manifests[1].Digest))
+
+			_, err = components.Artifact{Index: platformed}.ImageFor(v1.Platform{OS: "linux", Architecture: "s390x"})
+			Expect(err).To(MatchError("failed to find image for platform linux/s390x"))
+		})
+	})
+}
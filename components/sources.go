@@ -0,0 +1,119 @@
+package components
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// SourceChanges reports whether the source of a component buildpack kept in
+// this repository changed. Those components keep version 1.0.0 in the order
+// groups, so their version never shows that they changed.
+type SourceChanges func(component Component) (bool, error)
+
+// NewGitSourceChanges returns SourceChanges that compare the repository at
+// repo with the given git ref. The source of a component is its directory in
+// buildpacks, every package of the repository it imports, and go.mod and
+// go.sum, which pin its dependencies and the Go version it is built with.
+func NewGitSourceChanges(repo, ref string) SourceChanges {
+	return func(component Component) (bool, error) {
+		root, err := filepath.Abs(repo)
+		if err != nil {
+			return false, err
+		}
+
+		list := exec.Command("go", "list", "-deps", "-f", "{{if not .Standard}}{{.Dir}}{{end}}", "./"+path.Join("buildpacks", path.Base(component.ID))+"/...")
+		list.Dir = root
+		output, err := list.Output()
+		if err != nil {
+			var exitErr *exec.ExitError
+			if errors.As(err, &exitErr) {
+				return false, fmt.Errorf("failed to list the packages of %s: %w\n%s", component.ID, err, exitErr.Stderr)
+			}
+			return false, fmt.Errorf("failed to list the packages of %s: %w", component.ID, err)
+		}
+
+		paths := []string{"go.mod", "go.sum"}
+		for _, dir := range strings.Fields(string(output)) {
+			rel, err := filepath.Rel(root, dir)
+			if err != nil || rel == ".." || strings.HasPrefix(rel, "../") || rel == "vendor" || strings.HasPrefix(rel, "vendor/") {
+				continue
+			}
+
+			paths = append(paths, rel)
+		}
+
+		diff := exec.Command("git", append([]string{"diff", "--quiet", ref, "--"}, paths...)...)
+		diff.Dir = root
+		err = diff.Run()
+		if err == nil {
+			return false, nil
+		}
+
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+			return true, nil
+		}
+
+		return false, fmt.Errorf("failed to compare %s with %s: %w", component.ID, ref, err)
+	}
+}
+
+// MarkSourceChanges adds the source changes of the component buildpacks kept
+// in this repository to the changes of a Diff against current. A component
+// whose source changed but whose entry did not gets an update of its own.
+// The changes stay in the order of Diff.
+func MarkSourceChanges(changes []Change, current []Component, changed SourceChanges) ([]Change, error) {
+	index := map[string]int{}
+	for i, change := range changes {
+		index[change.ID] = i
+	}
+
+	var result []Change
+	for _, component := range current {
+		i, ok := index[component.ID]
+		if ok && changes[i].Type != Updated {
+			result = append(result, changes[i])
+			continue
+		}
+
+		if !component.InRepository() {
+			if ok {
+				result = append(result, changes[i])
+			}
+			continue
+		}
+
+		sourceChanged, err := changed(component)
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case ok:
+			change := changes[i]
+			change.SourceChanged = sourceChanged
+			result = append(result, change)
+		case sourceChanged:
+			result = append(result, Change{
+				ID:            component.ID,
+				Type:          Updated,
+				Old:           component,
+				New:           component,
+				Bump:          NoBump,
+				SourceChanged: true,
+			})
+		}
+	}
+
+	for _, change := range changes {
+		if change.Type == Removed {
+			result = append(result, change)
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,156 @@
+package components_test
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/paketo-buildpacks/go/components"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testSources(t *testing.T, context spec.G, it spec.S) {
+	var Expect = NewWithT(t).Expect
+
+	context("MarkSourceChanges", func() {
+		var (
+			old     []components.Component
+			current []components.Component
+			changed map[string]bool
+		)
+
+		it.Before(func() {
+			old = []components.Component{
+				{ID: "paketo-buildpacks/go-dist", Version: "2.10.9", URI: "docker://docker.io/paketobuildpacks/go-dist:2.10.9"},
+				{ID: "paketo-buildpacks/go-health", Version: "1.0.0", Optional: true, URI: "build/components/go-health.tgz"},
+				{ID: "paketo-buildpacks/go-otel", Version: "1.0.0", Optional: true, URI: "build/components/go-otel.tgz"},
+				{ID: "paketo-buildpacks/go-licenses", Version: "1.0.0", Optional: true, URI: "build/components/go-licenses.tgz"},
+				{ID: "paketo-buildpacks/go-bazel", Version: "1.0.0", URI: "build/components/go-bazel.tgz"},
+			}
+			current = []components.Component{
+				{ID: "paketo-buildpacks/go-dist", Version: "2.10.10", URI: "docker://docker.io/paketobuildpacks/go-dist:2.10.10"},
+				{ID: "paketo-buildpacks/go-health", Version: "1.0.0", Optional: true, URI: "build/components/go-health.tgz"},
+				{ID: "paketo-buildpacks/go-otel", Version: "1.0.0", Optional: true, URI: "build/components/go-otel.tgz"},
+				{ID: "paketo-buildpacks/go-licenses", Version: "1.0.0", URI: "build/components/go-licenses.tgz"},
+			}
+			changed = map[string]bool{
+				"paketo-buildpacks/go-otel":     true,
+				"paketo-buildpacks/go-licenses": true,
+			}
+		})
+
+		it("adds the source changes of the components kept in this repository", func() {
+			changes, err := components.MarkSourceChanges(components.Diff(old, current), current, func(component components.Component) (bool, error) {
+				return changed[component.ID], nil
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(changes).To(HaveLen(4))
+			Expect(changes[0].ID).To(Equal("paketo-buildpacks/go-dist"))
+			Expect(changes[0].Bump).To(Equal(components.PatchBump))
+			Expect(changes[0].SourceChanged).To(BeFalse())
+			Expect(changes[1]).To(Equal(components.Change{
+				ID:            "paketo-buildpacks/go-otel",
+				Type:          components.Updated,
+				Old:           current[2],
+				New:           current[2],
+				Bump:          components.NoBump,
+				SourceChanged: true,
+			}))
+			Expect(changes[2].ID).To(Equal("paketo-buildpacks/go-licenses"))
+			Expect(changes[2].Old).To(Equal(old[3]))
+			Expect(changes[2].SourceChanged).To(BeTrue())
+			Expect(changes[3].ID).To(Equal("paketo-buildpacks/go-bazel"))
+			Expect(changes[3].Type).To(Equal(components.Removed))
+		})
+
+		context("failure cases", func() {
+			context("when the source changes cannot be read", func() {
+				it("returns an error", func() {
+					_, err := components.MarkSourceChanges(components.Diff(old, current), current, func(components.Component) (bool, error) {
+						return false, errors.New("failed to compare")
+					})
+					Expect(err).To(MatchError("failed to compare"))
+				})
+			})
+		})
+	})
+
+	context("NewGitSourceChanges", func() {
+		var (
+			repo    string
+			changed components.SourceChanges
+		)
+
+		git := func(args ...string) {
+			command := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+			command.Dir = repo
+			output, err := command.CombinedOutput()
+			Expect(err).NotTo(HaveOccurred(), string(output))
+		}
+
+		write := func(path, content string) {
+			Expect(os.MkdirAll(filepath.Join(repo, filepath.Dir(path)), os.ModePerm)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(repo, path), []byte(content), 0600)).To(Succeed())
+		}
+
+		it.Before(func() {
+			repo = t.TempDir()
+			write("go.mod", "module example.com/repo\n\ngo 1.22\n")
+			write("buildpacks/go-otel/run/main.go", "package main\n\nimport \"example.com/repo/otel\"\n\nfunc main() { otel.Run() }\n")
+			write("otel/otel.go", "package otel\n\nfunc Run() {}\n")
+			write("health/health.go", "package health\n")
+
+			git("init", "--quiet")
+			git("add", "-A")
+			git("commit", "--quiet", "-m", "initial")
+			git("tag", "v1.0.0")
+
+			changed = components.NewGitSourceChanges(repo, "v1.0.0")
+		})
+
+		it("reports no change when the source is the same", func() {
+			write("health/health.go", "package health\n\nfunc Check() {}\n")
+
+			ok, err := changed(components.Component{ID: "paketo-buildpacks/go-otel"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ok).To(BeFalse())
+		})
+
+		it("reports a change to its directory", func() {
+			write("buildpacks/go-otel/run/main.go", "package main\n\nimport \"example.com/repo/otel\"\n\nfunc main() {\n\totel.Run()\n}\n")
+
+			ok, err := changed(components.Component{ID: "paketo-buildpacks/go-otel"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ok).To(BeTrue())
+		})
+
+		it("reports a change to a package it imports", func() {
+			write("otel/otel.go", "package otel\n\nfunc Run() { println() }\n")
+
+			ok, err := changed(components.Component{ID: "paketo-buildpacks/go-otel"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ok).To(BeTrue())
+		})
+
+		context("failure cases", func() {
+			context("when the ref does not exist", func() {
+				it("returns an error", func() {
+					_, err := components.NewGitSourceChanges(repo, "v0.0.0")(components.Component{ID: "paketo-buildpacks/go-otel"})
+					Expect(err).To(MatchError(ContainSubstring("failed to compare paketo-buildpacks/go-otel with v0.0.0")))
+				})
+			})
+
+			context("when the component has no directory", func() {
+				it("returns an error", func() {
+					_, err := changed(components.Component{ID: "paketo-buildpacks/go-missing"})
+					Expect(err).To(MatchError(ContainSubstring("failed to list the packages of paketo-buildpacks/go-missing")))
+				})
+			})
+		})
+	})
+}
@@ -0,0 +1,129 @@
+package components
+
+import (
+	"archive/tar"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/layout"
+)
+
+// Vendor resolves every image dependency in the given package config from the
+// source, verifies its digest against the lockfile and writes it to the output
+// directory as a .cnb buildpackage for the given platform. It returns a copy
+// of the config whose dependencies point at the vendored files and whose
+// targets are narrowed to the platform, so that pack can package the
+// composite without network access.
+func Vendor(config PackageConfig, lockfile Lockfile, source Source, platform v1.Platform, output string) (PackageConfig, error) {
+	vendored := PackageConfig{
+		Buildpack: config.Buildpack,
+		Targets: []PackageTarget{
+			{OS: platform.OS, Arch: platform.Architecture},
+		},
+	}
+
+	for _, dependency := range config.Dependencies {
+		if !dependency.IsImage() {
+			vendored.Dependencies = append(vendored.Dependencies, dependency)
+			continue
+		}
+
+		artifact, err := resolveLocked(dependency, lockfile, source)
+		if err != nil {
+			return PackageConfig{}, fmt.Errorf("failed to vendor %s: %w", dependency.URI, err)
+		}
+
+		image, err := artifact.ImageFor(platform)
+		if err != nil {
+			return PackageConfig{}, fmt.Errorf("failed to vendor %s: %w", dependency.URI, err)
+		}
+
+		path := filepath.Join(output, fmt.Sprintf("%s.cnb", dependency.Name()))
+		err = WriteBuildpackage(path, image)
+		if err != nil {
+			return PackageConfig{}, fmt.Errorf("failed to vendor %s: %w", dependency.URI, err)
+		}
+
+		vendored.Dependencies = append(vendored.Dependencies, PackageDependency{URI: path})
+	}
+
+	return vendored, nil
+}
+
+// WriteBuildpackage writes the image to the given path as a .cnb file, which
+// is a tarball of a single-image OCI layout.
+func WriteBuildpackage(path string, image v1.Image) error {
+	dir, err := os.MkdirTemp("", "buildpackage")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	p, err := layout.Write(dir, empty.Index)
+	if err != nil {
+		return err
+	}
+
+	err = p.AppendImage(image)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	tw := tar.NewWriter(file)
+	err = filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+
+		if rel == "." {
+			return nil
+		}
+
+		header, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		header.Name = filepath.ToSlash(rel)
+		if info.IsDir() {
+			header.Name += "/"
+		}
+
+		err = tw.WriteHeader(header)
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() {
+			return nil
+		}
+
+		content, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer content.Close()
+
+		_, err = io.Copy(tw, content)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	return tw.Close()
+}
@@ -0,0 +1,126 @@
+package components_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/layout"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/paketo-buildpacks/go/components"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testVendor(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		source   components.LayoutSource
+		config   components.PackageConfig
+		lockfile components.Lockfile
+		platform v1.Platform
+		output   string
+		image    v1.Image
+	)
+
+	it.Before(func() {
+		dir := t.TempDir()
+		output = t.TempDir()
+
+		p, err := layout.Write(dir, empty.Index)
+		Expect(err).NotTo(HaveOccurred())
+
+		image, err = random.Image(64, 2)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(p.AppendImage(image, layout.WithAnnotations(map[string]string{
+			components.RefNameAnnotation: "docker.io/paketobuildpacks/go-build:2.4.20",
+		}))).To(Succeed())
+
+		digest, err := image.Digest()
+		Expect(err).NotTo(HaveOccurred())
+
+		source = components.NewLayoutSource(dir)
+		config = components.PackageConfig{
+			Buildpack: components.PackageBuildpack{URI: "build/buildpack.tgz"},
+			Dependencies: []components.PackageDependency{
+				{URI: "docker://docker.io/paketobuildpacks/go-build:2.4.20"},
+				{URI: "local/buildpack.cnb"},
+			},
+			Targets: []components.PackageTarget{
+				{OS: "linux", Arch: "amd64"},
+				{OS: "linux", Arch: "arm64"},
+			},
+		}
+		lockfile = components.Lockfile{
+			Dependencies: []components.LockedDependency{
+				{URI: "docker://docker.io/paketobuildpacks/go-build:2.4.20", Digest: digest.String()},
+			},
+		}
+		platform = v1.Platform{OS: "linux", Architecture: "arm64"}
+	})
+
+	it("writes the verified components as buildpackages and rewrites the config", func() {
+		vendored, err := components.Vendor(config, lockfile, source, platform, output)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(vendored).To(Equal(components.PackageConfig{
+			Buildpack: components.PackageBuildpack{URI: "build/buildpack.tgz"},
+			Dependencies: []components.PackageDependency{
+				{URI: filepath.Join(output, "go-build.cnb")},
+				{URI: "local/buildpack.cnb"},
+			},
+			Targets: []components.PackageTarget{{OS: "linux", Arch: "arm64"}},
+		}))
+
+		extracted := t.TempDir()
+		Expect(untar(filepath.Join(output, "go-build.cnb"), extracted)).To(Succeed())
+		Expect(filepath.Join(extracted, "oci-layout")).To(BeARegularFile())
+
+		index, err := layout.ImageIndexFromPath(extracted)
+		Expect(err).NotTo(HaveOccurred())
+
+		manifest, err := index.IndexManifest()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(manifest.Manifests).To(HaveLen(1))
+
+		digest, err := image.Digest()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(manifest.Manifests[0].Digest).To(Equal(digest))
+	})
+
+	context("failure cases", func() {
+		context("when a dependency is missing from the lockfile", func() {
+			it("returns an error", func() {
+				_, err := components.Vendor(config, components.Lockfile{}, source, platform, output)
+				Expect(err).To(MatchError("failed to vendor docker://docker.io/paketobuildpacks/go-build:2.4.20: dependency is missing from the lockfile"))
+			})
+		})
+
+		context("when the resolved digest does not match the lockfile", func() {
+			it("returns an error", func() {
+				lockfile.Dependencies[0].Digest = "sha256:0000000000000000000000000000000000000000000000000000000000000000"
+
+				_, err := components.Vendor(config, lockfile, source, platform, output)
+				Expect(err).To(MatchError(ContainSubstring("digest mismatch: locked sha256:0000000000000000000000000000000000000000000000000000000000000000, resolved sha256:")))
+				Expect(filepath.Join(output, "go-build.cnb")).NotTo(BeAnExistingFile())
+			})
+		})
+
+		context("when the component cannot be resolved", func() {
+			it("returns an error", func() {
+				_, err := components.Vendor(config, lockfile, components.NewLayoutSource(t.TempDir()), platform, output)
+				Expect(err).To(MatchError(ContainSubstring("failed to vendor docker://docker.io/paketobuildpacks/go-build:2.4.20: failed to read OCI layout")))
+			})
+		})
+
+		context("when the output directory does not exist", func() {
+			it("returns an error", func() {
+				_, err := components.Vendor(config, lockfile, source, platform, filepath.Join(output, "missing"))
+				Expect(err).To(MatchError(ContainSubstring("no such file or directory")))
+			})
+		})
+	})
+}
@@ -0,0 +1,104 @@
+// Package debugsymbols splits the DWARF debug information off Go binaries into
+// separate symbol files, keyed by the Go build ID, so that production images
+// can ship stripped binaries while crash dumps and profiles can still be
+// symbolized.
+package debugsymbols
+
+import (
+	"debug/elf"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	// Env enables the extraction of debug symbols from the binaries go-build
+	// builds.
+	Env = "BP_GO_DEBUG_SYMBOLS"
+
+	goNoteSection  = ".note.go.buildid"
+	gnuNoteSection = ".note.gnu.build-id"
+)
+
+// BuildID identifies a binary. The Go build ID is what the go command and
+// pprof use, the GNU build ID is what debuggers and debuginfod use. The Go
+// linker only writes a GNU build ID on some versions or with -B.
+type BuildID struct {
+	Go  string
+	GNU string
+}
+
+// Key is the Go build ID in a form that can be used as a file name. The Go
+// build ID is made of base64url encoded parts separated by slashes, which
+// never contain a dot.
+func (id BuildID) Key() string {
+	return strings.ReplaceAll(id.Go, "/", ".")
+}
+
+// ReadBuildID reads the build IDs from the notes of an ELF file, which are kept
+// both in the stripped binary and in its symbol file.
+func ReadBuildID(path string) (BuildID, error) {
+	file, err := elf.Open(path)
+	if err != nil {
+		return BuildID{}, err
+	}
+	defer file.Close()
+
+	var id BuildID
+	if section := file.Section(goNoteSection); section != nil {
+		desc, err := readNote(file, section, "Go")
+		if err != nil {
+			return BuildID{}, fmt.Errorf("failed to read %s: %w", goNoteSection, err)
+		}
+		id.Go = string(desc)
+	}
+
+	if section := file.Section(gnuNoteSection); section != nil {
+		desc, err := readNote(file, section, "GNU")
+		if err != nil {
+			return BuildID{}, fmt.Errorf("failed to read %s: %w", gnuNoteSection, err)
+		}
+		id.GNU = hex.EncodeToString(desc)
+	}
+
+	if id.Go == "" {
+		return BuildID{}, fmt.Errorf("%s has no Go build ID", path)
+	}
+
+	return id, nil
+}
+
+// readNote returns the description of the single note in a note section,
+// which has to belong to owner.
+func readNote(file *elf.File, section *elf.Section, owner string) ([]byte, error) {
+	data, err := section.Data()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) < 12 {
+		return nil, errors.New("note is truncated")
+	}
+
+	nameSize := file.ByteOrder.Uint32(data[0:4])
+	descSize := file.ByteOrder.Uint32(data[4:8])
+	data = data[12:]
+
+	nameEnd := align(nameSize)
+	if uint64(len(data)) < uint64(nameEnd)+uint64(descSize) {
+		return nil, errors.New("note is truncated")
+	}
+
+	name := strings.TrimRight(string(data[:nameSize]), "\x00")
+	if name != owner {
+		return nil, fmt.Errorf("unexpected note owner %q", name)
+	}
+
+	return data[nameEnd : nameEnd+descSize], nil
+}
+
+// align rounds a note field size up to the 4 byte alignment of ELF notes.
+func align(size uint32) uint32 {
+	return (size + 3) &^ 3
+}
@@ -0,0 +1,66 @@
+package debugsymbols_test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/paketo-buildpacks/go/debugsymbols"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+// goBuild builds a program that does nothing with the given ldflags and
+// returns the path of the binary.
+func goBuild(t *testing.T, ldflags string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	err := os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n\nfunc main() {}\n"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	output, err := exec.Command("go", "build", "-C", dir, "-o", "server", "-ldflags", ldflags, "main.go").CombinedOutput()
+	if err != nil {
+		t.Fatalf("failed to build: %s\n%s", err, output)
+	}
+
+	return filepath.Join(dir, "server")
+}
+
+func testBuildID(t *testing.T, context spec.G, it spec.S) {
+	var Expect = NewWithT(t).Expect
+
+	context("ReadBuildID", func() {
+		it("reads the build ID the go command reports", func() {
+			binary := goBuild(t, "")
+
+			output, err := exec.Command("go", "tool", "buildid", binary).Output()
+			Expect(err).NotTo(HaveOccurred())
+
+			id, err := debugsymbols.ReadBuildID(binary)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(id.Go).To(Equal(strings.TrimSpace(string(output))))
+			Expect(id.Key()).To(Equal(strings.ReplaceAll(id.Go, "/", ".")))
+			Expect(id.Key()).NotTo(ContainSubstring("/"))
+		})
+
+		it("reads the GNU build ID the linker is asked for", func() {
+			id, err := debugsymbols.ReadBuildID(goBuild(t, "-B 0x0123456789abcdef"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(id.GNU).To(Equal("0123456789abcdef"))
+		})
+
+		it("returns an error for files that are not ELF binaries", func() {
+			path := filepath.Join(t.TempDir(), "script")
+			Expect(os.WriteFile(path, []byte("#!/bin/sh\n"), 0755)).To(Succeed())
+
+			_, err := debugsymbols.ReadBuildID(path)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+}
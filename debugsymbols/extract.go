@@ -0,0 +1,132 @@
+package debugsymbols
+
+import (
+	"crypto/sha256"
+	"debug/elf"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+)
+
+// Symbols is the symbol file of a binary.
+type Symbols struct {
+	Binary     string `json:"binary"`
+	BuildID    string `json:"buildID"`
+	GNUBuildID string `json:"gnuBuildID,omitempty"`
+
+	// File is the path of the symbol file, relative to the symbols directory.
+	File string `json:"file"`
+
+	SHA256 string `json:"sha256"`
+}
+
+// Objcopy splits binaries with objcopy from GNU binutils or LLVM.
+type Objcopy struct {
+	// Executable is the objcopy executable.
+	Executable string
+
+	Stdout io.Writer
+	Stderr io.Writer
+}
+
+// Extract copies the DWARF debug information of the binary at path into
+// <dir>/<build ID key>.debug and strips the binary in place, leaving its build
+// IDs and a debug link to the symbol file. The binary has to be built without
+// -w, which drops the DWARF debug information.
+func (o Objcopy) Extract(path, dir string) (Symbols, error) {
+	id, err := ReadBuildID(path)
+	if err != nil {
+		return Symbols{}, err
+	}
+
+	name := filepath.Base(path)
+	ok, err := hasDWARF(path)
+	if err != nil {
+		return Symbols{}, err
+	}
+
+	if !ok {
+		return Symbols{}, fmt.Errorf("%s has no DWARF debug information, remove -w from BP_GO_BUILD_LDFLAGS", name)
+	}
+
+	err = os.MkdirAll(dir, os.ModePerm)
+	if err != nil {
+		return Symbols{}, err
+	}
+
+	file := id.Key() + ".debug"
+	err = o.run("--only-keep-debug", path, filepath.Join(dir, file))
+	if err != nil {
+		return Symbols{}, fmt.Errorf("failed to extract the debug symbols of %s: %w", name, err)
+	}
+
+	err = o.run("--strip-all", "--add-gnu-debuglink="+filepath.Join(dir, file), path)
+	if err != nil {
+		return Symbols{}, fmt.Errorf("failed to strip %s: %w", name, err)
+	}
+
+	stripped, err := ReadBuildID(path)
+	if err != nil {
+		return Symbols{}, err
+	}
+
+	if stripped != id {
+		return Symbols{}, fmt.Errorf("stripping %s changed its build ID from %s to %s", name, id.Go, stripped.Go)
+	}
+
+	sum, err := sha256File(filepath.Join(dir, file))
+	if err != nil {
+		return Symbols{}, err
+	}
+
+	return Symbols{
+		Binary:     name,
+		BuildID:    id.Go,
+		GNUBuildID: id.GNU,
+		File:       file,
+		SHA256:     sum,
+	}, nil
+}
+
+func (o Objcopy) run(args ...string) error {
+	command := exec.Command(o.Executable, args...)
+	command.Stdout = o.Stdout
+	command.Stderr = o.Stderr
+
+	return command.Run()
+}
+
+func hasDWARF(path string) (bool, error) {
+	file, err := elf.Open(path)
+	if err != nil {
+		return false, err
+	}
+	defer file.Close()
+
+	for _, section := range file.Sections {
+		if section.Name == ".debug_info" || section.Name == ".zdebug_info" {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func sha256File(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	_, err = io.Copy(hash, file)
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
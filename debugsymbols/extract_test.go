@@ -0,0 +1,82 @@
+package debugsymbols_test
+
+import (
+	"bytes"
+	"debug/elf"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/paketo-buildpacks/go/debugsymbols"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testExtract(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		objcopy debugsymbols.Objcopy
+		dir     string
+	)
+
+	it.Before(func() {
+		dir = filepath.Join(t.TempDir(), "symbols")
+	})
+
+	context("with objcopy", func() {
+		it.Before(func() {
+			executable, err := exec.LookPath("objcopy")
+			Expect(err).NotTo(HaveOccurred(), "the specs need objcopy from GNU binutils or LLVM")
+
+			objcopy = debugsymbols.Objcopy{Executable: executable, Stdout: &bytes.Buffer{}, Stderr: &bytes.Buffer{}}
+		})
+
+		it("moves the DWARF into a symbol file named after the build ID", func() {
+			binary := goBuild(t, "")
+			id, err := debugsymbols.ReadBuildID(binary)
+			Expect(err).NotTo(HaveOccurred())
+
+			symbols, err := objcopy.Extract(binary, dir)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(symbols.Binary).To(Equal("server"))
+			Expect(symbols.BuildID).To(Equal(id.Go))
+			Expect(symbols.GNUBuildID).To(Equal(id.GNU))
+			Expect(symbols.File).To(Equal(id.Key() + ".debug"))
+			Expect(symbols.SHA256).To(HaveLen(64))
+
+			// the symbol file has the DWARF and the build ID of the binary
+			file, err := elf.Open(filepath.Join(dir, symbols.File))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(file.Section(".debug_info")).NotTo(BeNil())
+			Expect(file.Close()).To(Succeed())
+			Expect(debugsymbols.ReadBuildID(filepath.Join(dir, symbols.File))).To(Equal(id))
+
+			// the binary keeps its build ID and still runs
+			file, err = elf.Open(binary)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(file.Section(".debug_info")).To(BeNil())
+			Expect(file.Section(".symtab")).To(BeNil())
+			Expect(file.Section(".gnu_debuglink")).NotTo(BeNil())
+			Expect(file.Close()).To(Succeed())
+			Expect(debugsymbols.ReadBuildID(binary)).To(Equal(id))
+			Expect(exec.Command(binary).Run()).To(Succeed())
+		})
+
+		it("returns an error for binaries built without DWARF", func() {
+			_, err := objcopy.Extract(goBuild(t, "-w"), dir)
+			Expect(err).To(MatchError("server has no DWARF debug information, remove -w from BP_GO_BUILD_LDFLAGS"))
+		})
+	})
+
+	it("returns an error when objcopy fails", func() {
+		executable := filepath.Join(t.TempDir(), "objcopy")
+		Expect(os.WriteFile(executable, []byte("#!/bin/sh\nexit 1\n"), 0755)).To(Succeed())
+
+		objcopy = debugsymbols.Objcopy{Executable: executable}
+		_, err := objcopy.Extract(goBuild(t, ""), dir)
+		Expect(err).To(MatchError("failed to extract the debug symbols of server: exit status 1"))
+	})
+}
@@ -0,0 +1,156 @@
+package debugsymbols
+
+import (
+	"archive/tar"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
+)
+
+const (
+	// ImageDir is where the debug image holds the symbols directory.
+	ImageDir = "/symbols"
+
+	// BuildIDsLabel lists the Go build IDs of the symbol files in a debug
+	// image, separated by commas, so that they can be matched without pulling
+	// the image.
+	BuildIDsLabel = "io.paketo.go.debug-symbols.build-ids"
+
+	// LaunchDir is where the go-debug-symbols buildpack exports the symbols
+	// directory in the application image.
+	LaunchDir = "/layers/paketo-buildpacks_go-debug-symbols/debug-symbols"
+)
+
+// Image packs the symbol files and the manifest of a symbols directory into a
+// single layer image. The image has no timestamps, so the same symbols always
+// produce the same image.
+func Image(dir string) (v1.Image, error) {
+	manifest, err := ReadManifest(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(manifest.Symbols) == 0 {
+		return nil, fmt.Errorf("no symbols in %s", dir)
+	}
+
+	var buffer bytes.Buffer
+	tw := tar.NewWriter(&buffer)
+
+	files := []string{ManifestFile}
+	var buildIDs []string
+	for _, symbols := range manifest.Symbols {
+		files = append(files, symbols.File)
+		buildIDs = append(buildIDs, symbols.BuildID)
+	}
+
+	err = tw.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeDir,
+		Name:     strings.TrimPrefix(ImageDir, "/") + "/",
+		Mode:     0755,
+		ModTime:  time.Unix(0, 0),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, file := range files {
+		content, err := os.ReadFile(filepath.Join(dir, file))
+		if err != nil {
+			return nil, err
+		}
+
+		err = tw.WriteHeader(&tar.Header{
+			Typeflag: tar.TypeReg,
+			Name:     strings.TrimPrefix(ImageDir, "/") + "/" + file,
+			Mode:     0644,
+			Size:     int64(len(content)),
+			ModTime:  time.Unix(0, 0),
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		_, err = tw.Write(content)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = tw.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	layer, err := tarball.LayerFromOpener(func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(buffer.Bytes())), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	image, err := mutate.AppendLayers(empty.Image, layer)
+	if err != nil {
+		return nil, err
+	}
+
+	return mutate.Config(image, v1.Config{
+		Labels: map[string]string{BuildIDsLabel: strings.Join(buildIDs, ",")},
+	})
+}
+
+// Unpack copies the symbols directory that go-debug-symbols exported to an
+// application image into dir, so that it can be packed into a debug image.
+func Unpack(image v1.Image, dir string) error {
+	filesystem := mutate.Extract(image)
+	defer filesystem.Close()
+
+	prefix := strings.TrimPrefix(LaunchDir, "/") + "/"
+	found := false
+
+	tr := tar.NewReader(filesystem)
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		name, ok := strings.CutPrefix(strings.TrimPrefix(header.Name, "/"), prefix)
+		if !ok || header.Typeflag != tar.TypeReg || name != filepath.Base(name) {
+			continue
+		}
+
+		content, err := io.ReadAll(tr)
+		if err != nil {
+			return err
+		}
+
+		err = os.WriteFile(filepath.Join(dir, name), content, 0644)
+		if err != nil {
+			return err
+		}
+
+		if name == ManifestFile {
+			found = true
+		}
+	}
+
+	if !found {
+		return fmt.Errorf("the image has no symbols in %s", LaunchDir)
+	}
+
+	return nil
+}
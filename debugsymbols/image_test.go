@@ -0,0 +1,134 @@
+package debugsymbols_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
+	"github.com/paketo-buildpacks/go/debugsymbols"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testImage(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		dir string
+	)
+
+	it.Before(func() {
+		dir = t.TempDir()
+
+		manifest := debugsymbols.Manifest{}
+		manifest.Add(debugsymbols.Symbols{Binary: "server", BuildID: "a/b", File: "a.b.debug"})
+		manifest.Add(debugsymbols.Symbols{Binary: "worker", BuildID: "c/d", File: "c.d.debug"})
+		Expect(manifest.Write(dir)).To(Succeed())
+
+		Expect(os.WriteFile(filepath.Join(dir, "a.b.debug"), []byte("server symbols"), 0644)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(dir, "c.d.debug"), []byte("worker symbols"), 0644)).To(Succeed())
+	})
+
+	it("packs the symbols into a labelled, reproducible image", func() {
+		image, err := debugsymbols.Image(dir)
+		Expect(err).NotTo(HaveOccurred())
+
+		config, err := image.ConfigFile()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(config.Config.Labels).To(Equal(map[string]string{debugsymbols.BuildIDsLabel: "a/b,c/d"}))
+
+		layers, err := image.Layers()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(layers).To(HaveLen(1))
+
+		content, err := layers[0].Uncompressed()
+		Expect(err).NotTo(HaveOccurred())
+		defer content.Close()
+
+		files := map[string]string{}
+		tr := tar.NewReader(content)
+		for {
+			header, err := tr.Next()
+			if err == io.EOF {
+				break
+			}
+			Expect(err).NotTo(HaveOccurred())
+
+			data, err := io.ReadAll(tr)
+			Expect(err).NotTo(HaveOccurred())
+			files[header.Name] = string(data)
+		}
+		Expect(files).To(HaveKey("symbols/"))
+		Expect(files).To(HaveKey("symbols/symbols.json"))
+		Expect(files).To(HaveKeyWithValue("symbols/a.b.debug", "server symbols"))
+		Expect(files).To(HaveKeyWithValue("symbols/c.d.debug", "worker symbols"))
+
+		digest, err := image.Digest()
+		Expect(err).NotTo(HaveOccurred())
+
+		again, err := debugsymbols.Image(dir)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(again.Digest()).To(Equal(digest))
+	})
+
+	it("returns an error without symbols", func() {
+		_, err := debugsymbols.Image(t.TempDir())
+		Expect(err).To(MatchError(ContainSubstring("no symbols in")))
+	})
+
+	context("Unpack", func() {
+		it("copies the symbols directory out of an application image", func() {
+			symbols, err := debugsymbols.Image(dir)
+			Expect(err).NotTo(HaveOccurred())
+			digest, err := symbols.Digest()
+			Expect(err).NotTo(HaveOccurred())
+
+			// the application image holds the same files under the exported
+			// layer of go-debug-symbols
+			var buffer bytes.Buffer
+			tw := tar.NewWriter(&buffer)
+			for _, file := range []string{"symbols.json", "a.b.debug", "c.d.debug"} {
+				content, err := os.ReadFile(filepath.Join(dir, file))
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(tw.WriteHeader(&tar.Header{
+					Typeflag: tar.TypeReg,
+					Name:     strings.TrimPrefix(debugsymbols.LaunchDir, "/") + "/" + file,
+					Mode:     0644,
+					Size:     int64(len(content)),
+				})).To(Succeed())
+				_, err = tw.Write(content)
+				Expect(err).NotTo(HaveOccurred())
+			}
+			Expect(tw.Close()).To(Succeed())
+
+			layer, err := tarball.LayerFromOpener(func() (io.ReadCloser, error) {
+				return io.NopCloser(bytes.NewReader(buffer.Bytes())), nil
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			app, err := mutate.AppendLayers(empty.Image, layer)
+			Expect(err).NotTo(HaveOccurred())
+
+			unpacked := t.TempDir()
+			Expect(debugsymbols.Unpack(app, unpacked)).To(Succeed())
+
+			again, err := debugsymbols.Image(unpacked)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(again.Digest()).To(Equal(digest))
+		})
+
+		it("returns an error when the image has no symbols", func() {
+			err := debugsymbols.Unpack(empty.Image, t.TempDir())
+			Expect(err).To(MatchError("the image has no symbols in " + debugsymbols.LaunchDir))
+		})
+	})
+}
@@ -0,0 +1,17 @@
+package debugsymbols_test
+
+import (
+	"testing"
+
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+)
+
+func TestUnitDebugSymbols(t *testing.T) {
+	suite := spec.New("debugsymbols", spec.Report(report.Terminal{}), spec.Parallel())
+	suite("BuildID", testBuildID)
+	suite("Extract", testExtract)
+	suite("Image", testImage)
+	suite("Manifest", testManifest)
+	suite.Run(t)
+}
@@ -0,0 +1,73 @@
+package debugsymbols
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// ManifestFile lists the symbol files of a symbols directory.
+const ManifestFile = "symbols.json"
+
+// Manifest is the content of the manifest file.
+type Manifest struct {
+	Symbols []Symbols `json:"symbols"`
+}
+
+// ReadManifest reads the manifest of a symbols directory. A directory without
+// a manifest has no symbols yet.
+func ReadManifest(dir string) (Manifest, error) {
+	content, err := os.ReadFile(filepath.Join(dir, ManifestFile))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return Manifest{}, nil
+		}
+		return Manifest{}, err
+	}
+
+	var manifest Manifest
+	err = json.Unmarshal(content, &manifest)
+	if err != nil {
+		return Manifest{}, err
+	}
+
+	return manifest, nil
+}
+
+// Add adds symbols to the manifest, replacing the symbols of the same binary.
+func (m *Manifest) Add(symbols Symbols) {
+	for i, s := range m.Symbols {
+		if s.Binary == symbols.Binary {
+			m.Symbols[i] = symbols
+			return
+		}
+	}
+
+	m.Symbols = append(m.Symbols, symbols)
+	sort.Slice(m.Symbols, func(i, j int) bool {
+		return m.Symbols[i].Binary < m.Symbols[j].Binary
+	})
+}
+
+// Find returns the symbols of the binary with the given Go build ID.
+func (m Manifest) Find(buildID string) (Symbols, bool) {
+	for _, symbols := range m.Symbols {
+		if symbols.BuildID == buildID {
+			return symbols, true
+		}
+	}
+
+	return Symbols{}, false
+}
+
+// Write writes the manifest to a symbols directory.
+func (m Manifest) Write(dir string) error {
+	content, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filepath.Join(dir, ManifestFile), append(content, '\n'), 0644)
+}
@@ -0,0 +1,41 @@
+package debugsymbols_test
+
+import (
+	"testing"
+
+	"github.com/paketo-buildpacks/go/debugsymbols"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testManifest(t *testing.T, context spec.G, it spec.S) {
+	var Expect = NewWithT(t).Expect
+
+	it("reads back the symbols it wrote", func() {
+		dir := t.TempDir()
+
+		manifest, err := debugsymbols.ReadManifest(dir)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(manifest.Symbols).To(BeEmpty())
+
+		manifest.Add(debugsymbols.Symbols{Binary: "worker", BuildID: "a/b", File: "a.b.debug"})
+		manifest.Add(debugsymbols.Symbols{Binary: "server", BuildID: "c/d", File: "c.d.debug"})
+		manifest.Add(debugsymbols.Symbols{Binary: "worker", BuildID: "e/f", File: "e.f.debug"})
+		Expect(manifest.Write(dir)).To(Succeed())
+
+		manifest, err = debugsymbols.ReadManifest(dir)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(manifest.Symbols).To(Equal([]debugsymbols.Symbols{
+			{Binary: "server", BuildID: "c/d", File: "c.d.debug"},
+			{Binary: "worker", BuildID: "e/f", File: "e.f.debug"},
+		}))
+
+		symbols, ok := manifest.Find("e/f")
+		Expect(ok).To(BeTrue())
+		Expect(symbols.File).To(Equal("e.f.debug"))
+
+		_, ok = manifest.Find("a/b")
+		Expect(ok).To(BeFalse())
+	})
+}
@@ -0,0 +1,144 @@
+// Package diagnostics links a diagnostics listener into the main packages of
+// an application at build time, through a go build overlay, so that pprof,
+// expvar style variables and runtime metrics can be turned on when the image
+// runs without changes to the application.
+package diagnostics
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// BuildEnv links the listener into the go-build targets.
+	BuildEnv = "BP_GO_DIAGNOSTICS"
+
+	// EnabledEnv starts the listener when the image runs.
+	EnabledEnv = "BPL_GO_DIAGNOSTICS_ENABLED"
+
+	// PortEnv is the port the listener listens on, DefaultPort by default.
+	PortEnv = "BPL_GO_DIAGNOSTICS_PORT"
+
+	// DefaultPort is the port of the listener when none is configured.
+	DefaultPort = 6060
+
+	// BindingType is the type of the service bindings that start the listener
+	// when the image runs. Their optional port entry overrides PortEnv.
+	BindingType = "go-diagnostics"
+
+	// FileName is the name the listener gets in every main package.
+	FileName = "zz_paketo_diagnostics.go"
+)
+
+// Source is the listener that is added to every main package, without the
+// build constraint that keeps it out of the packages of this repository.
+var Source = bytes.TrimPrefix(listener, []byte("//go:build "+BuildTag+"\n\n"))
+
+// BuildTag keeps listener/listener.go out of the builds of this repository,
+// while go vet -tags paketo_diagnostics still checks it.
+const BuildTag = "paketo_diagnostics"
+
+//go:embed listener/listener.go
+var listener []byte
+
+// Overlay is the format of go build -overlay.
+type Overlay struct {
+	Replace map[string]string `json:"Replace"`
+}
+
+// Inject writes the listener and an overlay that adds it to the main package
+// of every target of the application in appDir into dir, which keeps both out
+// of the application. The path of the overlay is returned.
+func Inject(appDir, dir string, targets []string) (string, error) {
+	if len(targets) == 0 {
+		targets = []string{"."}
+	}
+
+	overlay := Overlay{Replace: map[string]string{}}
+	source := filepath.Join(dir, "diagnostics.go")
+	for _, target := range targets {
+		target = filepath.Clean(target)
+		if filepath.IsAbs(target) || target == ".." || strings.HasPrefix(target, "../") {
+			return "", fmt.Errorf("target %s is not inside the application directory", target)
+		}
+
+		ok, err := isMainPackage(filepath.Join(appDir, target))
+		if err != nil {
+			return "", err
+		}
+
+		if !ok {
+			return "", fmt.Errorf("target %s is not a main package", target)
+		}
+
+		overlay.Replace[filepath.Join(appDir, target, FileName)] = source
+	}
+
+	err := os.MkdirAll(dir, os.ModePerm)
+	if err != nil {
+		return "", err
+	}
+
+	err = os.WriteFile(source, Source, 0644)
+	if err != nil {
+		return "", err
+	}
+
+	content, err := json.MarshalIndent(overlay, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	err = os.WriteFile(filepath.Join(dir, "overlay.json"), append(content, '\n'), 0644)
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, "overlay.json"), nil
+}
+
+// BuildFlags adds the overlay to BP_GO_BUILD_FLAGS. The go command takes a
+// single overlay, so flags that already use one are rejected.
+func BuildFlags(flags, overlay string) (string, error) {
+	fields := strings.Fields(flags)
+	for _, field := range fields {
+		if field == "-overlay" || strings.HasPrefix(field, "-overlay=") || field == "--overlay" || strings.HasPrefix(field, "--overlay=") {
+			return "", errors.New("BP_GO_BUILD_FLAGS already sets an overlay, which cannot be combined with the diagnostics overlay")
+		}
+	}
+
+	return strings.Join(append(fields, "-overlay="+overlay), " "), nil
+}
+
+// isMainPackage reports whether dir holds a main package.
+func isMainPackage(dir string) (bool, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return false, err
+	}
+
+	for _, path := range files {
+		if strings.HasSuffix(path, "_test.go") {
+			continue
+		}
+
+		file, err := parser.ParseFile(token.NewFileSet(), path, nil, parser.PackageClauseOnly)
+		if err != nil {
+			return false, err
+		}
+
+		if file.Name.Name == "main" {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
@@ -0,0 +1,97 @@
+package diagnostics_test
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/paketo-buildpacks/go/diagnostics"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testDiagnostics(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		dir      string
+		layerDir string
+	)
+
+	it.Before(func() {
+		dir = t.TempDir()
+		layerDir = filepath.Join(t.TempDir(), "diagnostics")
+		Expect(os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n\nfunc main() {}\n"), 0644)).To(Succeed())
+		Expect(os.MkdirAll(filepath.Join(dir, "cmd", "worker"), os.ModePerm)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(dir, "cmd", "worker", "main.go"), []byte("package main\n\nfunc main() {}\n"), 0644)).To(Succeed())
+		Expect(os.MkdirAll(filepath.Join(dir, "internal"), os.ModePerm)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(dir, "internal", "internal.go"), []byte("package internal\n"), 0644)).To(Succeed())
+	})
+
+	context("Inject", func() {
+		it("adds the listener to every target through an overlay", func() {
+			overlay, err := diagnostics.Inject(dir, layerDir, []string{".", "./cmd/worker"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(overlay).To(Equal(filepath.Join(layerDir, "overlay.json")))
+
+			content, err := os.ReadFile(overlay)
+			Expect(err).NotTo(HaveOccurred())
+
+			var written diagnostics.Overlay
+			Expect(json.Unmarshal(content, &written)).To(Succeed())
+			Expect(written.Replace).To(Equal(map[string]string{
+				filepath.Join(dir, "zz_paketo_diagnostics.go"):                  filepath.Join(layerDir, "diagnostics.go"),
+				filepath.Join(dir, "cmd", "worker", "zz_paketo_diagnostics.go"): filepath.Join(layerDir, "diagnostics.go"),
+			}))
+
+			Expect(os.ReadFile(filepath.Join(layerDir, "diagnostics.go"))).To(Equal(diagnostics.Source))
+
+			// nothing is written into the application
+			entries, err := os.ReadDir(dir)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(entries).To(HaveLen(3))
+		})
+
+		it("defaults to the application directory", func() {
+			_, err := diagnostics.Inject(dir, layerDir, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(filepath.Join(layerDir, "overlay.json")).To(BeARegularFile())
+		})
+
+		it("rejects targets that are not main packages", func() {
+			_, err := diagnostics.Inject(dir, layerDir, []string{"./internal"})
+			Expect(err).To(MatchError("target internal is not a main package"))
+		})
+
+		it("rejects targets outside of the application directory", func() {
+			_, err := diagnostics.Inject(dir, layerDir, []string{"../other"})
+			Expect(err).To(MatchError("target ../other is not inside the application directory"))
+		})
+	})
+
+	context("Source", func() {
+		it("is the listener without its build constraint", func() {
+			Expect(string(diagnostics.Source)).To(HavePrefix("// Code generated by the Paketo Buildpack for Go. DO NOT EDIT."))
+			Expect(string(diagnostics.Source)).NotTo(ContainSubstring("go:build"))
+		})
+
+		it("passes go vet", func() {
+			output, err := exec.Command("go", "vet", "-tags", diagnostics.BuildTag, "./listener").CombinedOutput()
+			Expect(err).NotTo(HaveOccurred(), string(output))
+		})
+	})
+
+	context("BuildFlags", func() {
+		it("adds the overlay to the flags", func() {
+			Expect(diagnostics.BuildFlags("-mod=vendor", "/layers/paketo-buildpacks_go-diagnostics/diagnostics/overlay.json")).To(Equal("-mod=vendor -overlay=/layers/paketo-buildpacks_go-diagnostics/diagnostics/overlay.json"))
+		})
+
+		it("rejects flags that already use an overlay", func() {
+			_, err := diagnostics.BuildFlags("-overlay=mine.json", "overlay.json")
+			Expect(err).To(MatchError("BP_GO_BUILD_FLAGS already sets an overlay, which cannot be combined with the diagnostics overlay"))
+		})
+	})
+}
@@ -0,0 +1,15 @@
+package diagnostics_test
+
+import (
+	"testing"
+
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+)
+
+func TestUnitDiagnostics(t *testing.T) {
+	suite := spec.New("diagnostics", spec.Report(report.Terminal{}), spec.Parallel())
+	suite("Diagnostics", testDiagnostics)
+	suite("Listener", testListener)
+	suite.Run(t)
+}
@@ -0,0 +1,137 @@
+package diagnostics_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/paketo-buildpacks/go/diagnostics"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testListener(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect       = NewWithT(t).Expect
+		Eventually   = NewWithT(t).Eventually
+		Consistently = NewWithT(t).Consistently
+
+		binary string
+		port   string
+	)
+
+	freePort := func() string {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).NotTo(HaveOccurred())
+		defer listener.Close()
+
+		return strconv.Itoa(listener.Addr().(*net.TCPAddr).Port)
+	}
+
+	get := func(url string) (string, error) {
+		response, err := http.Get(url)
+		if err != nil {
+			return "", err
+		}
+		defer response.Body.Close()
+
+		if response.StatusCode != http.StatusOK {
+			return "", fmt.Errorf("unexpected status %s", response.Status)
+		}
+
+		content, err := io.ReadAll(response.Body)
+		return string(content), err
+	}
+
+	start := func(env ...string) {
+		command := exec.Command(binary)
+		command.Env = append(os.Environ(), env...)
+		Expect(command.Start()).To(Succeed())
+
+		t.Cleanup(func() {
+			_ = command.Process.Kill()
+			_ = command.Wait()
+		})
+	}
+
+	it.Before(func() {
+		// an app that waits forever without any handlers of its own
+		dir := t.TempDir()
+		Expect(os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module app\n\ngo 1.18\n"), 0644)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n\nfunc main() {\n\tselect {}\n}\n"), 0644)).To(Succeed())
+
+		overlay, err := diagnostics.Inject(dir, t.TempDir(), nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		binary = filepath.Join(dir, "app")
+		command := exec.Command("go", "build", "-overlay="+overlay, "-o", binary, ".")
+		command.Dir = dir
+		output, err := command.CombinedOutput()
+		Expect(err).NotTo(HaveOccurred(), string(output))
+
+		port = freePort()
+	})
+
+	it("serves pprof, the variables and the runtime metrics when enabled", func() {
+		start(diagnostics.EnabledEnv+"=true", diagnostics.PortEnv+"="+port)
+
+		Eventually(func() (string, error) {
+			return get(fmt.Sprintf("http://127.0.0.1:%s/debug/pprof/", port))
+		}).Should(ContainSubstring("heap"))
+
+		heap, err := get(fmt.Sprintf("http://127.0.0.1:%s/debug/pprof/heap", port))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(heap).To(HavePrefix("\x1f\x8b"), "profiles are gzipped protobuf")
+
+		Expect(get(fmt.Sprintf("http://127.0.0.1:%s/debug/pprof/goroutine?debug=1", port))).To(ContainSubstring("goroutine profile:"))
+
+		content, err := get(fmt.Sprintf("http://127.0.0.1:%s/debug/vars", port))
+		Expect(err).NotTo(HaveOccurred())
+
+		var vars map[string]json.RawMessage
+		Expect(json.Unmarshal([]byte(content), &vars)).To(Succeed())
+		Expect(vars).To(HaveKey("cmdline"))
+		Expect(vars).To(HaveKey("memstats"))
+
+		content, err = get(fmt.Sprintf("http://127.0.0.1:%s/debug/metrics", port))
+		Expect(err).NotTo(HaveOccurred())
+
+		var samples map[string]float64
+		Expect(json.Unmarshal([]byte(content), &samples)).To(Succeed())
+		Expect(samples).To(HaveKey("/sched/goroutines:goroutines"))
+
+		_, err = get(fmt.Sprintf("http://127.0.0.1:%s/debug/pprof/missing", port))
+		Expect(err).To(MatchError("unexpected status 404 Not Found"))
+	})
+
+	it("is enabled by a go-diagnostics binding", func() {
+		root := t.TempDir()
+		Expect(os.MkdirAll(filepath.Join(root, "diagnostics"), os.ModePerm)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(root, "diagnostics", "type"), []byte("go-diagnostics\n"), 0644)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(root, "diagnostics", "port"), []byte(port), 0644)).To(Succeed())
+
+		start("SERVICE_BINDING_ROOT=" + root)
+
+		Eventually(func() (string, error) {
+			return get(fmt.Sprintf("http://127.0.0.1:%s/debug/pprof/heap", port))
+		}).Should(HavePrefix("\x1f\x8b"))
+	})
+
+	it("does not listen unless enabled", func() {
+		start(diagnostics.PortEnv + "=" + port)
+
+		Consistently(func() error {
+			_, err := get(fmt.Sprintf("http://127.0.0.1:%s/debug/pprof/", port))
+			return err
+		}, 2*time.Second).Should(HaveOccurred())
+	})
+}
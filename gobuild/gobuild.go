@@ -0,0 +1,30 @@
+// Package gobuild describes the layer of the paketo-buildpacks/go-build
+// buildpack that the components of this repository work on after it ran.
+//
+// go-build builds the binaries into its launch layer named targets, and the
+// process types it assigns run them from there. The components that process
+// the binaries therefore change them in that layer, in place, instead of
+// writing copies to a layer of their own: go-debug-symbols strips them and
+// go-optimize-size compresses them with UPX. This works because the lifecycle
+// exports the layers of every buildpack only after the last buildpack of the
+// group has run, but it also means that the targets layer in the image no
+// longer holds what go-build wrote, and a component that reads the binaries
+// sees the changes of the components ordered before it.
+package gobuild
+
+import "path/filepath"
+
+// TargetsDir is the directory go-build puts the binaries in, relative to the
+// layers directory of the build.
+const TargetsDir = "paketo-buildpacks_go-build/targets/bin"
+
+// ImageTargetsDir is the directory of the image in which the binaries of
+// go-build are exported.
+const ImageTargetsDir = "/layers/" + TargetsDir
+
+// Targets returns the directory of the binaries of go-build for a buildpack
+// whose layers are in the given directory. The layers directories of all
+// buildpacks of a build share their parent.
+func Targets(layersPath string) string {
+	return filepath.Join(filepath.Dir(layersPath), TargetsDir)
+}
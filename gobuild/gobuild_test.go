@@ -0,0 +1,19 @@
+package gobuild_test
+
+import (
+	"testing"
+
+	"github.com/paketo-buildpacks/go/gobuild"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testTargets(t *testing.T, context spec.G, it spec.S) {
+	var Expect = NewWithT(t).Expect
+
+	it("returns the targets layer of go-build next to the layers of the buildpack", func() {
+		Expect(gobuild.Targets("/layers/paketo-buildpacks_go-debug-symbols")).To(Equal("/layers/paketo-buildpacks_go-build/targets/bin"))
+		Expect(gobuild.Targets("/layers/paketo-buildpacks_go-debug-symbols")).To(Equal(gobuild.ImageTargetsDir))
+	})
+}
@@ -0,0 +1,14 @@
+package gobuild_test
+
+import (
+	"testing"
+
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+)
+
+func TestUnitGoBuild(t *testing.T) {
+	suite := spec.New("gobuild", spec.Report(report.Terminal{}), spec.Parallel())
+	suite("Targets", testTargets)
+	suite.Run(t)
+}
@@ -0,0 +1,101 @@
+// Package goreleaser translates the builds of a GoReleaser configuration into
+// the environment variables go-build reads, so that an image is built with the
+// same targets, flags and environment as the released binaries.
+package goreleaser
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// EnabledEnv opts an application into being built with its GoReleaser
+	// configuration.
+	EnabledEnv = "BP_GO_BUILD_GORELEASER"
+
+	// IDEnv chooses the build to translate by its id, for configurations whose
+	// builds do not share their flags and environment.
+	IDEnv = "BP_GO_BUILD_GORELEASER_ID"
+
+	// TagEnv and CommitEnv are the tag and commit of the release that the
+	// templates are rendered for, which the source of an image does not know.
+	TagEnv    = "BP_GO_BUILD_GORELEASER_TAG"
+	CommitEnv = "BP_GO_BUILD_GORELEASER_COMMIT"
+)
+
+// configFiles are the names GoReleaser looks for, in the order it looks for
+// them.
+var configFiles = []string{".config/goreleaser.yml", ".config/goreleaser.yaml", ".goreleaser.yml", ".goreleaser.yaml", "goreleaser.yml", "goreleaser.yaml"}
+
+// Config is the part of a GoReleaser configuration that describes how
+// binaries are built.
+type Config struct {
+	ProjectName string  `yaml:"project_name"`
+	Builds      []Build `yaml:"builds"`
+}
+
+// Build is an entry of the builds section.
+type Build struct {
+	ID       string   `yaml:"id"`
+	Builder  string   `yaml:"builder"`
+	Skip     bool     `yaml:"skip"`
+	Dir      string   `yaml:"dir"`
+	Main     string   `yaml:"main"`
+	Binary   string   `yaml:"binary"`
+	Env      []string `yaml:"env"`
+	Flags    []string `yaml:"flags"`
+	Tags     []string `yaml:"tags"`
+	Ldflags  []string `yaml:"ldflags"`
+	Gcflags  []string `yaml:"gcflags"`
+	Asmflags []string `yaml:"asmflags"`
+	Goos     []string `yaml:"goos"`
+	Goarch   []string `yaml:"goarch"`
+	Goamd64  []string `yaml:"goamd64"`
+	Goarm64  []string `yaml:"goarm64"`
+	Ignore   []Target `yaml:"ignore"`
+}
+
+// Target is a platform excluded from a build.
+type Target struct {
+	Goos   string `yaml:"goos"`
+	Goarch string `yaml:"goarch"`
+}
+
+// FindConfig returns the path of the GoReleaser configuration in dir.
+func FindConfig(dir string) (string, bool, error) {
+	for _, name := range configFiles {
+		path := filepath.Join(dir, name)
+		_, err := os.Stat(path)
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			return "", false, err
+		}
+
+		return path, true, nil
+	}
+
+	return "", false, nil
+}
+
+// LoadConfig reads a GoReleaser configuration. Only the fields of the builds
+// section that go-build can reproduce are read, everything else is ignored.
+func LoadConfig(path string) (Config, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return Config{}, err
+	}
+
+	var config Config
+	err = yaml.Unmarshal(content, &config)
+	if err != nil {
+		return Config{}, fmt.Errorf("failed to parse %s: %w", filepath.Base(path), err)
+	}
+
+	return config, nil
+}
@@ -0,0 +1,92 @@
+package goreleaser_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/paketo-buildpacks/go/goreleaser"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testConfig(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		dir string
+	)
+
+	it.Before(func() {
+		dir = t.TempDir()
+	})
+
+	context("FindConfig", func() {
+		it("finds the configuration GoReleaser would use", func() {
+			Expect(os.WriteFile(filepath.Join(dir, "goreleaser.yaml"), nil, 0644)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(dir, ".goreleaser.yaml"), nil, 0644)).To(Succeed())
+
+			path, ok, err := goreleaser.FindConfig(dir)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ok).To(BeTrue())
+			Expect(path).To(Equal(filepath.Join(dir, ".goreleaser.yaml")))
+		})
+
+		it("finds nothing without a configuration", func() {
+			_, ok, err := goreleaser.FindConfig(dir)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ok).To(BeFalse())
+		})
+	})
+
+	context("LoadConfig", func() {
+		it("reads the builds", func() {
+			Expect(os.WriteFile(filepath.Join(dir, ".goreleaser.yaml"), []byte(`version: 2
+project_name: some-app
+builds:
+  - id: server
+    main: ./cmd/server
+    binary: server
+    env: [CGO_ENABLED=0]
+    flags: [-trimpath]
+    tags: [netgo]
+    ldflags: ["-s -w -X main.version={{.Version}}"]
+    goos: [linux]
+    goarch: [amd64, arm64]
+    ignore:
+      - goos: linux
+        goarch: arm64
+archives:
+  - format: tar.gz
+`), 0644)).To(Succeed())
+
+			config, err := goreleaser.LoadConfig(filepath.Join(dir, ".goreleaser.yaml"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(config).To(Equal(goreleaser.Config{
+				ProjectName: "some-app",
+				Builds: []goreleaser.Build{
+					{
+						ID:      "server",
+						Main:    "./cmd/server",
+						Binary:  "server",
+						Env:     []string{"CGO_ENABLED=0"},
+						Flags:   []string{"-trimpath"},
+						Tags:    []string{"netgo"},
+						Ldflags: []string{"-s -w -X main.version={{.Version}}"},
+						Goos:    []string{"linux"},
+						Goarch:  []string{"amd64", "arm64"},
+						Ignore:  []goreleaser.Target{{Goos: "linux", Goarch: "arm64"}},
+					},
+				},
+			}))
+		})
+
+		it("returns an error for a configuration that is not valid YAML", func() {
+			Expect(os.WriteFile(filepath.Join(dir, ".goreleaser.yaml"), []byte("builds: [unterminated\n"), 0644)).To(Succeed())
+
+			_, err := goreleaser.LoadConfig(filepath.Join(dir, ".goreleaser.yaml"))
+			Expect(err).To(MatchError(ContainSubstring("failed to parse .goreleaser.yaml")))
+		})
+	})
+}
@@ -0,0 +1,15 @@
+package goreleaser_test
+
+import (
+	"testing"
+
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+)
+
+func TestUnitGoReleaser(t *testing.T) {
+	suite := spec.New("goreleaser", spec.Report(report.Terminal{}), spec.Parallel())
+	suite("Config", testConfig)
+	suite("Translate", testTranslate)
+	suite.Run(t)
+}
@@ -0,0 +1,338 @@
+package goreleaser
+
+import (
+	"bytes"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// Release is what GoReleaser templates are rendered with, as it would be for a
+// release of the given tag and commit.
+type Release struct {
+	ProjectName string
+	Tag         string
+	Commit      string
+	Date        time.Time
+
+	// Env holds the variables templates can read through .Env.
+	Env map[string]string
+}
+
+// Translation is a build expressed in the environment variables of go-build.
+type Translation struct {
+	// Builds are the ids of the builds the translation was made from.
+	Builds []string
+
+	Targets []string
+	Flags   []string
+	Ldflags []string
+
+	// Env holds the environment of the build, as KEY=VALUE.
+	Env []string
+
+	// Warnings describe what go-build does differently from GoReleaser.
+	Warnings []string
+}
+
+// Environ returns the translation as the variables to pass to pack build,
+// as KEY=VALUE.
+func (t Translation) Environ() []string {
+	environ := []string{"BP_GO_TARGETS=" + strings.Join(t.Targets, ":")}
+	if len(t.Flags) > 0 {
+		environ = append(environ, "BP_GO_BUILD_FLAGS="+strings.Join(t.Flags, " "))
+	}
+	if len(t.Ldflags) > 0 {
+		environ = append(environ, "BP_GO_BUILD_LDFLAGS="+strings.Join(t.Ldflags, " "))
+	}
+
+	return append(environ, t.Env...)
+}
+
+// Translate translates the builds of a configuration that target linux on the
+// given architecture. When id is empty every such build is translated, which
+// only works when they share their flags and environment, as go-build applies
+// the same ones to all of its targets.
+func Translate(config Config, id, arch string, release Release) (Translation, error) {
+	var builds []Build
+	for i, build := range config.Builds {
+		if build.ID == "" {
+			build.ID = config.ProjectName
+			if len(config.Builds) > 1 {
+				build.ID = fmt.Sprintf("%s-%d", config.ProjectName, i)
+			}
+		}
+
+		if id != "" && build.ID != id {
+			continue
+		}
+
+		if build.Builder != "" && build.Builder != "go" {
+			if id != "" {
+				return Translation{}, fmt.Errorf("build %s uses the %s builder, only go builds are supported", id, build.Builder)
+			}
+			continue
+		}
+
+		if build.Skip || !build.targets("linux", arch) {
+			if id != "" {
+				return Translation{}, fmt.Errorf("build %s does not target linux/%s", id, arch)
+			}
+			continue
+		}
+
+		builds = append(builds, build)
+	}
+
+	if len(builds) == 0 {
+		if id != "" {
+			return Translation{}, fmt.Errorf("no build with id %s", id)
+		}
+		return Translation{}, fmt.Errorf("no build targets linux/%s", arch)
+	}
+
+	var translation Translation
+	for i, build := range builds {
+		next, err := build.translate(config.ProjectName, arch, release)
+		if err != nil {
+			return Translation{}, fmt.Errorf("build %s: %w", build.ID, err)
+		}
+
+		if i > 0 && (!slices.Equal(translation.Flags, next.Flags) || !slices.Equal(translation.Ldflags, next.Ldflags) || !slices.Equal(translation.Env, next.Env)) {
+			return Translation{}, fmt.Errorf("builds %s and %s use different flags or environments, choose one of them", builds[0].ID, build.ID)
+		}
+
+		translation.Builds = append(translation.Builds, build.ID)
+		translation.Targets = append(translation.Targets, next.Targets...)
+		translation.Flags = next.Flags
+		translation.Ldflags = next.Ldflags
+		translation.Env = next.Env
+		translation.Warnings = append(translation.Warnings, next.Warnings...)
+	}
+
+	return translation, nil
+}
+
+// targets reports whether a build produces a binary for a platform, using the
+// GoReleaser defaults for the operating systems and architectures.
+func (b Build) targets(goos, goarch string) bool {
+	oses := b.Goos
+	if len(oses) == 0 {
+		oses = []string{"darwin", "linux", "windows"}
+	}
+
+	arches := b.Goarch
+	if len(arches) == 0 {
+		arches = []string{"386", "amd64", "arm64"}
+	}
+
+	if !slices.Contains(oses, goos) || !slices.Contains(arches, goarch) {
+		return false
+	}
+
+	for _, ignore := range b.Ignore {
+		if (ignore.Goos == "" || ignore.Goos == goos) && (ignore.Goarch == "" || ignore.Goarch == goarch) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (b Build) translate(projectName, arch string, release Release) (Translation, error) {
+	data := newTemplateData(release, projectName, arch)
+
+	var translation Translation
+	for _, variable := range b.Env {
+		rendered, err := render(variable, data)
+		if err != nil {
+			return Translation{}, err
+		}
+
+		name, value, ok := strings.Cut(rendered, "=")
+		if !ok {
+			return Translation{}, fmt.Errorf("env entry %q is not KEY=VALUE", variable)
+		}
+
+		// later entries and templates see the variables set before them, as in
+		// GoReleaser
+		data.Env[name] = value
+		translation.Env = append(translation.Env, rendered)
+	}
+
+	var level string
+	var levels []string
+	switch arch {
+	case "amd64":
+		level, levels = "GOAMD64", b.Goamd64
+	case "arm64":
+		level, levels = "GOARM64", b.Goarm64
+	}
+
+	if len(levels) > 0 {
+		translation.Env = append(translation.Env, fmt.Sprintf("%s=%s", level, levels[0]))
+		if len(levels) > 1 {
+			translation.Warnings = append(translation.Warnings, fmt.Sprintf("build %s targets %s %s, only %s=%s is built", b.ID, level, strings.Join(levels, ", "), level, levels[0]))
+		}
+	}
+
+	main := b.Main
+	if main == "" {
+		main = "."
+	}
+
+	// go-build targets are packages relative to the app, such as ./cmd/server
+	pkg := main
+	if strings.HasSuffix(main, ".go") {
+		pkg = path.Dir(main)
+	}
+
+	target := path.Join(b.Dir, pkg)
+	if target != "." {
+		target = "./" + target
+	}
+	translation.Targets = []string{target}
+
+	if strings.HasSuffix(main, ".go") {
+		translation.Warnings = append(translation.Warnings, fmt.Sprintf("build %s builds the file %s, go-build builds its package %s instead", b.ID, main, target))
+	}
+
+	if b.Binary != "" && target != "." {
+		binary, err := render(b.Binary, data)
+		if err != nil {
+			return Translation{}, err
+		}
+
+		if path.Base(binary) != path.Base(target) {
+			translation.Warnings = append(translation.Warnings, fmt.Sprintf("build %s names its binary %s, go-build names it %s after its package", b.ID, binary, path.Base(target)))
+		}
+	}
+
+	for _, flag := range b.Flags {
+		rendered, err := render(flag, data)
+		if err != nil {
+			return Translation{}, err
+		}
+		translation.Flags = append(translation.Flags, rendered)
+	}
+
+	if len(b.Tags) > 0 {
+		translation.Flags = append(translation.Flags, "-tags="+strings.Join(b.Tags, ","))
+	}
+
+	for _, flag := range []struct {
+		name   string
+		values []string
+	}{
+		{"-gcflags", b.Gcflags},
+		{"-asmflags", b.Asmflags},
+	} {
+		for _, value := range flag.values {
+			rendered, err := render(value, data)
+			if err != nil {
+				return Translation{}, err
+			}
+			translation.Flags = append(translation.Flags, flag.name+"="+quote(rendered))
+		}
+	}
+
+	for _, ldflag := range b.Ldflags {
+		rendered, err := render(ldflag, data)
+		if err != nil {
+			return Translation{}, err
+		}
+		translation.Ldflags = append(translation.Ldflags, rendered)
+	}
+
+	return translation, nil
+}
+
+// templateData holds the fields GoReleaser templates read.
+type templateData struct {
+	ProjectName string
+	Version     string
+	Tag         string
+	Commit      string
+	FullCommit  string
+	ShortCommit string
+	Date        string
+	Timestamp   int64
+	Os          string
+	Arch        string
+	Env         map[string]string
+
+	date time.Time
+}
+
+func newTemplateData(release Release, projectName, arch string) templateData {
+	if release.ProjectName != "" {
+		projectName = release.ProjectName
+	}
+
+	env := map[string]string{}
+	for name, value := range release.Env {
+		env[name] = value
+	}
+
+	shortCommit := release.Commit
+	if len(shortCommit) > 7 {
+		shortCommit = shortCommit[:7]
+	}
+
+	return templateData{
+		ProjectName: projectName,
+		Version:     strings.TrimPrefix(release.Tag, "v"),
+		Tag:         release.Tag,
+		Commit:      release.Commit,
+		FullCommit:  release.Commit,
+		ShortCommit: shortCommit,
+		Date:        release.Date.UTC().Format(time.RFC3339),
+		Timestamp:   release.Date.Unix(),
+		Os:          "linux",
+		Arch:        arch,
+		Env:         env,
+		date:        release.Date.UTC(),
+	}
+}
+
+// render renders a GoReleaser template. Only the functions that are commonly
+// used in builds are available, and time formats the release date rather than
+// the current time, so that builds are reproducible.
+func render(text string, data templateData) (string, error) {
+	tmpl, err := template.New("goreleaser").
+		Option("missingkey=error").
+		Funcs(template.FuncMap{
+			"tolower":    strings.ToLower,
+			"toupper":    strings.ToUpper,
+			"trim":       strings.TrimSpace,
+			"trimprefix": strings.TrimPrefix,
+			"trimsuffix": strings.TrimSuffix,
+			"replace":    strings.ReplaceAll,
+			"time":       data.date.Format,
+		}).
+		Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse template %q: %w", text, err)
+	}
+
+	var buffer bytes.Buffer
+	err = tmpl.Execute(&buffer, data)
+	if err != nil {
+		return "", fmt.Errorf("failed to render template %q: %w", text, err)
+	}
+
+	return buffer.String(), nil
+}
+
+// quote quotes a flag value that holds spaces, as go-build splits its flags
+// like a shell does.
+func quote(value string) string {
+	if !strings.ContainsAny(value, " \t") {
+		return value
+	}
+
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
@@ -0,0 +1,169 @@
+package goreleaser_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/paketo-buildpacks/go/goreleaser"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testTranslate(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		release goreleaser.Release
+	)
+
+	it.Before(func() {
+		release = goreleaser.Release{
+			Tag:    "v1.2.3",
+			Commit: "0123456789abcdef0123456789abcdef01234567",
+			Date:   time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC),
+			Env:    map[string]string{"BUILDER": "ci"},
+		}
+	})
+
+	context("Translate", func() {
+		it("translates a build into go-build variables", func() {
+			config := goreleaser.Config{
+				ProjectName: "some-app",
+				Builds: []goreleaser.Build{
+					{
+						ID:       "server",
+						Main:     "./cmd/server",
+						Binary:   "server",
+						Env:      []string{"CGO_ENABLED=0", "BUILT_BY={{.Env.BUILDER}}"},
+						Flags:    []string{"-trimpath", "-mod=readonly"},
+						Tags:     []string{"netgo", "osusergo"},
+						Gcflags:  []string{"all=-N -l"},
+						Ldflags:  []string{"-s -w", "-X main.version={{.Version}} -X main.commit={{.ShortCommit}}", "-X main.date={{.Date}} -X main.by={{.Env.BUILT_BY}} -X main.year={{time \"2006\"}}"},
+						Goos:     []string{"linux", "darwin"},
+						Goamd64:  []string{"v3"},
+						Asmflags: []string{"all=-trimpath={{.ProjectName}}"},
+					},
+				},
+			}
+
+			translation, err := goreleaser.Translate(config, "", "amd64", release)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(translation).To(Equal(goreleaser.Translation{
+				Builds:  []string{"server"},
+				Targets: []string{"./cmd/server"},
+				Flags:   []string{"-trimpath", "-mod=readonly", "-tags=netgo,osusergo", "-gcflags='all=-N -l'", "-asmflags=all=-trimpath=some-app"},
+				Ldflags: []string{"-s -w", "-X main.version=1.2.3 -X main.commit=0123456", "-X main.date=2024-05-06T07:08:09Z -X main.by=ci -X main.year=2024"},
+				Env:     []string{"CGO_ENABLED=0", "BUILT_BY=ci", "GOAMD64=v3"},
+			}))
+
+			Expect(translation.Environ()).To(Equal([]string{
+				"BP_GO_TARGETS=./cmd/server",
+				"BP_GO_BUILD_FLAGS=-trimpath -mod=readonly -tags=netgo,osusergo -gcflags='all=-N -l' -asmflags=all=-trimpath=some-app",
+				"BP_GO_BUILD_LDFLAGS=-s -w -X main.version=1.2.3 -X main.commit=0123456 -X main.date=2024-05-06T07:08:09Z -X main.by=ci -X main.year=2024",
+				"CGO_ENABLED=0",
+				"BUILT_BY=ci",
+				"GOAMD64=v3",
+			}))
+		})
+
+		it("combines builds that share their flags and skips builds for other platforms", func() {
+			config := goreleaser.Config{
+				ProjectName: "some-app",
+				Builds: []goreleaser.Build{
+					{ID: "server", Main: "./cmd/server", Flags: []string{"-trimpath"}},
+					{ID: "windows", Main: "./cmd/tray", Goos: []string{"windows"}},
+					{ID: "arm", Main: "./cmd/arm", Ignore: []goreleaser.Target{{Goarch: "amd64"}}},
+					{ID: "skipped", Main: "./cmd/skipped", Skip: true},
+					{ID: "rust", Builder: "rust"},
+					{ID: "worker", Dir: "workers", Main: "./cmd/worker", Flags: []string{"-trimpath"}},
+				},
+			}
+
+			translation, err := goreleaser.Translate(config, "", "amd64", release)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(translation.Builds).To(Equal([]string{"server", "worker"}))
+			Expect(translation.Targets).To(Equal([]string{"./cmd/server", "./workers/cmd/worker"}))
+			Expect(translation.Environ()).To(Equal([]string{
+				"BP_GO_TARGETS=./cmd/server:./workers/cmd/worker",
+				"BP_GO_BUILD_FLAGS=-trimpath",
+			}))
+		})
+
+		it("warns about what go-build does differently", func() {
+			config := goreleaser.Config{
+				Builds: []goreleaser.Build{
+					{ID: "cli", Main: "./cmd/cli/main.go", Binary: "{{.ProjectName}}-cli", Goarm64: []string{"v8.0", "v9.0"}},
+				},
+				ProjectName: "some-app",
+			}
+
+			translation, err := goreleaser.Translate(config, "cli", "arm64", release)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(translation.Targets).To(Equal([]string{"./cmd/cli"}))
+			Expect(translation.Env).To(Equal([]string{"GOARM64=v8.0"}))
+			Expect(translation.Warnings).To(Equal([]string{
+				"build cli targets GOARM64 v8.0, v9.0, only GOARM64=v8.0 is built",
+				"build cli builds the file ./cmd/cli/main.go, go-build builds its package ./cmd/cli instead",
+				"build cli names its binary some-app-cli, go-build names it cli after its package",
+			}))
+		})
+
+		it("names builds without an id after the project", func() {
+			translation, err := goreleaser.Translate(goreleaser.Config{ProjectName: "some-app", Builds: []goreleaser.Build{{}}}, "", "amd64", release)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(translation.Builds).To(Equal([]string{"some-app"}))
+			Expect(translation.Targets).To(Equal([]string{"."}))
+		})
+
+		context("failure cases", func() {
+			it("returns an error when builds differ and none is chosen", func() {
+				config := goreleaser.Config{
+					Builds: []goreleaser.Build{
+						{ID: "server", Main: "./cmd/server", Flags: []string{"-trimpath"}},
+						{ID: "worker", Main: "./cmd/worker"},
+					},
+				}
+
+				_, err := goreleaser.Translate(config, "", "amd64", release)
+				Expect(err).To(MatchError("builds server and worker use different flags or environments, choose one of them"))
+
+				translation, err := goreleaser.Translate(config, "worker", "amd64", release)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(translation.Targets).To(Equal([]string{"./cmd/worker"}))
+			})
+
+			it("returns an error when the chosen build cannot be built", func() {
+				config := goreleaser.Config{
+					Builds: []goreleaser.Build{
+						{ID: "darwin", Goos: []string{"darwin"}},
+						{ID: "zig", Builder: "zig"},
+					},
+				}
+
+				_, err := goreleaser.Translate(config, "darwin", "amd64", release)
+				Expect(err).To(MatchError("build darwin does not target linux/amd64"))
+
+				_, err = goreleaser.Translate(config, "zig", "amd64", release)
+				Expect(err).To(MatchError("build zig uses the zig builder, only go builds are supported"))
+
+				_, err = goreleaser.Translate(config, "missing", "amd64", release)
+				Expect(err).To(MatchError("no build with id missing"))
+
+				_, err = goreleaser.Translate(config, "", "amd64", release)
+				Expect(err).To(MatchError("no build targets linux/amd64"))
+			})
+
+			it("returns an error for templates that cannot be rendered", func() {
+				config := goreleaser.Config{
+					Builds: []goreleaser.Build{
+						{ID: "server", Ldflags: []string{"-X main.token={{.Env.MISSING}}"}},
+					},
+				}
+
+				_, err := goreleaser.Translate(config, "", "amd64", release)
+				Expect(err).To(MatchError(ContainSubstring(`build server: failed to render template "-X main.token={{.Env.MISSING}}"`)))
+			})
+		})
+	})
+}
@@ -0,0 +1,76 @@
+// Package hardening prepares Go application images for Kubernetes policies
+// that require runAsNonRoot and readOnlyRootFilesystem: the paths an app
+// writes to are relocated to a writable volume, kept files that would need
+// write access are rejected, and the image user is checked.
+package hardening
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+)
+
+const (
+	// EnabledEnv opts an application into the hardened build.
+	EnabledEnv = "BP_GO_HARDENING"
+
+	// WritableDirEnv is the directory a writable volume, such as an emptyDir or
+	// a tmpfs, is mounted at when the image runs.
+	WritableDirEnv = "BP_GO_WRITABLE_DIR"
+
+	// WritablePathsEnv lists the paths, relative to the application, that the
+	// app writes to, as colon separated globs.
+	WritablePathsEnv = "BP_GO_WRITABLE_PATHS"
+
+	// DefaultWritableDir is used when no writable directory is configured.
+	DefaultWritableDir = "/tmp"
+)
+
+// WritableVariables name the directories Go programs write to by default.
+var WritableVariables = []string{"TMPDIR", "GOCOVERDIR", "XDG_CACHE_HOME"}
+
+// LaunchEnvironment returns the values that point every writable variable at
+// dir when the image runs. They are meant as launch defaults, so that they can
+// still be overridden at runtime.
+func LaunchEnvironment(dir string) (map[string]string, error) {
+	if !filepath.IsAbs(dir) {
+		return nil, fmt.Errorf("writable directory %s is not absolute", dir)
+	}
+
+	dir = filepath.Clean(dir)
+	for _, readOnly := range []string{"/workspace", "/layers", "/cnb"} {
+		if dir == readOnly || strings.HasPrefix(dir, readOnly+"/") {
+			return nil, fmt.Errorf("writable directory %s is inside %s, which is part of the image", dir, readOnly)
+		}
+	}
+
+	environment := map[string]string{}
+	for _, variable := range WritableVariables {
+		environment[variable] = dir
+	}
+
+	return environment, nil
+}
+
+// VerifyUser checks that an image runs as a non-root user that Kubernetes can
+// verify, which has to be given as a numeric user id.
+func VerifyUser(config v1.ConfigFile) error {
+	user, _, _ := strings.Cut(config.Config.User, ":")
+	if user == "" || user == "root" {
+		return fmt.Errorf("image runs as root, the run image must set a non-root user")
+	}
+
+	uid, err := strconv.Atoi(user)
+	if err != nil {
+		return fmt.Errorf("image user %q is not numeric, so runAsNonRoot cannot verify it", config.Config.User)
+	}
+
+	if uid == 0 {
+		return fmt.Errorf("image runs as root, the run image must set a non-root user")
+	}
+
+	return nil
+}
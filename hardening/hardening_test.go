@@ -0,0 +1,59 @@
+package hardening_test
+
+import (
+	"testing"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/paketo-buildpacks/go/hardening"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testHardening(t *testing.T, context spec.G, it spec.S) {
+	var Expect = NewWithT(t).Expect
+
+	context("LaunchEnvironment", func() {
+		it("points every writable variable at the directory", func() {
+			environment, err := hardening.LaunchEnvironment("/var/run/app/")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(environment).To(Equal(map[string]string{
+				"TMPDIR":         "/var/run/app",
+				"GOCOVERDIR":     "/var/run/app",
+				"XDG_CACHE_HOME": "/var/run/app",
+			}))
+		})
+
+		it("rejects directories that are relative or part of the image", func() {
+			_, err := hardening.LaunchEnvironment("tmp")
+			Expect(err).To(MatchError("writable directory tmp is not absolute"))
+
+			_, err = hardening.LaunchEnvironment("/workspace/tmp")
+			Expect(err).To(MatchError("writable directory /workspace/tmp is inside /workspace, which is part of the image"))
+
+			_, err = hardening.LaunchEnvironment("/layers")
+			Expect(err).To(MatchError("writable directory /layers is inside /layers, which is part of the image"))
+
+			_, err = hardening.LaunchEnvironment("/workspaces")
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+
+	context("VerifyUser", func() {
+		it("accepts numeric non-root users", func() {
+			Expect(hardening.VerifyUser(v1.ConfigFile{Config: v1.Config{User: "1002:1000"}})).To(Succeed())
+			Expect(hardening.VerifyUser(v1.ConfigFile{Config: v1.Config{User: "65532"}})).To(Succeed())
+		})
+
+		it("rejects root", func() {
+			for _, user := range []string{"", "root", "0", "0:0", "root:1000"} {
+				Expect(hardening.VerifyUser(v1.ConfigFile{Config: v1.Config{User: user}})).To(MatchError("image runs as root, the run image must set a non-root user"), user)
+			}
+		})
+
+		it("rejects users that Kubernetes cannot verify", func() {
+			err := hardening.VerifyUser(v1.ConfigFile{Config: v1.Config{User: "cnb:cnb"}})
+			Expect(err).To(MatchError(`image user "cnb:cnb" is not numeric, so runAsNonRoot cannot verify it`))
+		})
+	})
+}
@@ -0,0 +1,15 @@
+package hardening_test
+
+import (
+	"testing"
+
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+)
+
+func TestUnitHardening(t *testing.T) {
+	suite := spec.New("hardening", spec.Report(report.Terminal{}), spec.Parallel())
+	suite("Hardening", testHardening)
+	suite("KeepFiles", testKeepFiles)
+	suite.Run(t)
+}
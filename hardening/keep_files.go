@@ -0,0 +1,114 @@
+package hardening
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// writtenFiles match the names of files that apps usually write to.
+var writtenFiles = []string{"*.db", "*.lock", "*.log", "*.pid", "*.sqlite", "*.sqlite3"}
+
+// Violation is a kept file that would need write access.
+type Violation struct {
+	Path   string
+	Reason string
+}
+
+func (v Violation) String() string {
+	return fmt.Sprintf("%s %s", v.Path, v.Reason)
+}
+
+// CheckKeptFiles finds the files that BP_KEEP_FILES keeps in the application
+// directory that would need write access, which they do not have on a
+// read-only root filesystem. A file needs write access when it matches one of
+// the writable globs, looks like a file apps write to, or is an empty
+// directory.
+func CheckKeptFiles(dir, keepFiles string, writable []string) ([]Violation, error) {
+	var violations []Violation
+	seen := map[string]bool{}
+	for _, pattern := range SplitGlobs(keepFiles) {
+		matches, err := filepath.Glob(filepath.Join(dir, pattern))
+		if err != nil {
+			return nil, fmt.Errorf("invalid BP_KEEP_FILES glob %q: %w", pattern, err)
+		}
+
+		for _, match := range matches {
+			path, err := filepath.Rel(dir, match)
+			if err != nil {
+				return nil, err
+			}
+
+			if seen[path] {
+				continue
+			}
+			seen[path] = true
+
+			reason, err := needsWriteAccess(dir, path, writable)
+			if err != nil {
+				return nil, err
+			}
+
+			if reason != "" {
+				violations = append(violations, Violation{Path: path, Reason: reason})
+			}
+		}
+	}
+
+	sort.Slice(violations, func(i, j int) bool {
+		return violations[i].Path < violations[j].Path
+	})
+
+	return violations, nil
+}
+
+// SplitGlobs splits a colon separated list of globs, as in BP_KEEP_FILES.
+func SplitGlobs(value string) []string {
+	var globs []string
+	for _, glob := range strings.Split(value, ":") {
+		if glob = strings.TrimSpace(glob); glob != "" {
+			globs = append(globs, glob)
+		}
+	}
+
+	return globs
+}
+
+func needsWriteAccess(dir, path string, writable []string) (string, error) {
+	for _, pattern := range writable {
+		ok, err := filepath.Match(pattern, path)
+		if err != nil {
+			return "", fmt.Errorf("invalid %s glob %q: %w", WritablePathsEnv, pattern, err)
+		}
+
+		if ok || strings.HasPrefix(path, strings.TrimSuffix(pattern, "/")+"/") {
+			return fmt.Sprintf("is declared writable in %s", WritablePathsEnv), nil
+		}
+	}
+
+	for _, pattern := range writtenFiles {
+		if ok, _ := filepath.Match(pattern, filepath.Base(path)); ok {
+			return fmt.Sprintf("looks like a file the app writes to (%s)", pattern), nil
+		}
+	}
+
+	info, err := os.Stat(filepath.Join(dir, path))
+	if err != nil {
+		return "", err
+	}
+
+	if info.IsDir() {
+		entries, err := os.ReadDir(filepath.Join(dir, path))
+		if err != nil {
+			return "", err
+		}
+
+		if len(entries) == 0 {
+			return "is an empty directory, which only makes sense to write to", nil
+		}
+	}
+
+	return "", nil
+}
@@ -0,0 +1,66 @@
+package hardening_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/paketo-buildpacks/go/hardening"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testKeepFiles(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		dir string
+	)
+
+	it.Before(func() {
+		dir = t.TempDir()
+
+		Expect(os.MkdirAll(filepath.Join(dir, "assets", "css"), os.ModePerm)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(dir, "assets", "css", "site.css"), nil, 0644)).To(Succeed())
+		Expect(os.MkdirAll(filepath.Join(dir, "uploads"), os.ModePerm)).To(Succeed())
+		Expect(os.MkdirAll(filepath.Join(dir, "data"), os.ModePerm)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(dir, "data", "seed.json"), nil, 0644)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(dir, "config.yml"), nil, 0644)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(dir, "app.sqlite"), nil, 0644)).To(Succeed())
+	})
+
+	context("CheckKeptFiles", func() {
+		it("finds kept files that would need write access", func() {
+			violations, err := hardening.CheckKeptFiles(dir, "assets:config.yml:*.sqlite:uploads:data/*:assets", []string{"data/"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(violations).To(Equal([]hardening.Violation{
+				{Path: "app.sqlite", Reason: "looks like a file the app writes to (*.sqlite)"},
+				{Path: "data/seed.json", Reason: "is declared writable in BP_GO_WRITABLE_PATHS"},
+				{Path: "uploads", Reason: "is an empty directory, which only makes sense to write to"},
+			}))
+			Expect(violations[0].String()).To(Equal("app.sqlite looks like a file the app writes to (*.sqlite)"))
+		})
+
+		it("accepts kept files that are only read", func() {
+			violations, err := hardening.CheckKeptFiles(dir, "assets:config.yml:missing.txt", nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(violations).To(BeEmpty())
+		})
+
+		it("returns an error for invalid globs", func() {
+			_, err := hardening.CheckKeptFiles(dir, "[", nil)
+			Expect(err).To(MatchError(ContainSubstring(`invalid BP_KEEP_FILES glob "["`)))
+
+			_, err = hardening.CheckKeptFiles(dir, "config.yml", []string{"["})
+			Expect(err).To(MatchError(ContainSubstring(`invalid BP_GO_WRITABLE_PATHS glob "["`)))
+		})
+	})
+
+	context("SplitGlobs", func() {
+		it("splits a colon separated list", func() {
+			Expect(hardening.SplitGlobs(" assets/*: config.yml::")).To(Equal([]string{"assets/*", "config.yml"}))
+			Expect(hardening.SplitGlobs("")).To(BeEmpty())
+		})
+	})
+}
@@ -0,0 +1,15 @@
+package health_test
+
+import (
+	"testing"
+
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+)
+
+func TestUnitHealth(t *testing.T) {
+	suite := spec.New("health", spec.Report(report.Terminal{}), spec.Parallel())
+	suite("Metadata", testMetadata)
+	suite("Probe", testProbe)
+	suite.Run(t)
+}
@@ -0,0 +1,96 @@
+package health
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+)
+
+const (
+	// BuildTargetEnv is the target the health process type checks by default.
+	// BPL_GO_HEALTH_CHECK_TARGET overrides it when the image runs.
+	BuildTargetEnv = "BP_GO_HEALTH_CHECK_TARGET"
+
+	// BuildIntervalEnv, BuildTimeoutEnv, BuildStartPeriodEnv and
+	// BuildRetriesEnv set the optional fields of the health check label.
+	BuildIntervalEnv    = "BP_GO_HEALTH_CHECK_INTERVAL"
+	BuildTimeoutEnv     = "BP_GO_HEALTH_CHECK_TIMEOUT"
+	BuildStartPeriodEnv = "BP_GO_HEALTH_CHECK_START_PERIOD"
+	BuildRetriesEnv     = "BP_GO_HEALTH_CHECK_RETRIES"
+
+	// Label holds the Metadata of an image as JSON.
+	Label = "io.paketo.go.health-check"
+)
+
+// Metadata describes the health check of an image like a Dockerfile
+// HEALTHCHECK instruction. Buildpacks cannot set the health check of an
+// image, so it is kept in a label until it is applied.
+type Metadata struct {
+	// Probe is the path of the probe in the image.
+	Probe  string `json:"probe"`
+	Target string `json:"target"`
+
+	Interval    string `json:"interval,omitempty"`
+	Timeout     string `json:"timeout,omitempty"`
+	StartPeriod string `json:"startPeriod,omitempty"`
+	Retries     int    `json:"retries,omitempty"`
+}
+
+// ParseMetadata reads the metadata from the value of Label.
+func ParseMetadata(value string) (Metadata, error) {
+	var metadata Metadata
+	err := json.Unmarshal([]byte(value), &metadata)
+	if err != nil {
+		return Metadata{}, fmt.Errorf("invalid %s label: %w", Label, err)
+	}
+
+	return metadata, nil
+}
+
+// String returns the value of Label.
+func (m Metadata) String() string {
+	content, _ := json.Marshal(m)
+	return string(content)
+}
+
+// HealthConfig returns the health check of an image config. The probe runs
+// without the launcher, so the target is passed as an argument instead of
+// through the launch environment.
+func (m Metadata) HealthConfig() (v1.HealthConfig, error) {
+	if m.Probe == "" {
+		return v1.HealthConfig{}, fmt.Errorf("health check has no probe")
+	}
+
+	_, err := ParseTarget(m.Target)
+	if err != nil {
+		return v1.HealthConfig{}, err
+	}
+
+	config := v1.HealthConfig{
+		Test:    []string{"CMD", m.Probe, m.Target},
+		Retries: m.Retries,
+	}
+
+	for _, duration := range []struct {
+		name  string
+		value string
+		field *time.Duration
+	}{
+		{"interval", m.Interval, &config.Interval},
+		{"timeout", m.Timeout, &config.Timeout},
+		{"start period", m.StartPeriod, &config.StartPeriod},
+	} {
+		if duration.value == "" {
+			continue
+		}
+
+		*duration.field, err = time.ParseDuration(duration.value)
+		if err != nil {
+			return v1.HealthConfig{}, fmt.Errorf("invalid health check %s %q: %w", duration.name, duration.value, err)
+		}
+	}
+
+	return config, nil
+}
@@ -0,0 +1,48 @@
+package health_test
+
+import (
+	"testing"
+	"time"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/paketo-buildpacks/go/health"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testMetadata(t *testing.T, context spec.G, it spec.S) {
+	var Expect = NewWithT(t).Expect
+
+	it("round trips through the label and becomes a health check", func() {
+		metadata := health.Metadata{
+			Probe:    "/layers/paketo-buildpacks_go-health/probe/bin/health-probe",
+			Target:   "http://localhost:8080/",
+			Interval: "10s",
+			Timeout:  "2s",
+			Retries:  3,
+		}
+
+		parsed, err := health.ParseMetadata(metadata.String())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(parsed).To(Equal(metadata))
+
+		Expect(parsed.HealthConfig()).To(Equal(v1.HealthConfig{
+			Test:     []string{"CMD", "/layers/paketo-buildpacks_go-health/probe/bin/health-probe", "http://localhost:8080/"},
+			Interval: 10 * time.Second,
+			Timeout:  2 * time.Second,
+			Retries:  3,
+		}))
+	})
+
+	it("rejects invalid health checks", func() {
+		_, err := health.ParseMetadata("{")
+		Expect(err).To(MatchError(ContainSubstring("invalid io.paketo.go.health-check label")))
+
+		_, err = health.Metadata{Target: "tcp://localhost:8080"}.HealthConfig()
+		Expect(err).To(MatchError("health check has no probe"))
+
+		_, err = health.Metadata{Probe: "/probe", Target: "tcp://localhost:8080", StartPeriod: "soon"}.HealthConfig()
+		Expect(err).To(MatchError(ContainSubstring(`invalid health check start period "soon"`)))
+	})
+}
@@ -0,0 +1,229 @@
+// Package health probes Go applications over HTTP, TCP or the gRPC health
+// checking protocol, with the standard library only, so that the probe can be
+// shipped as a small static binary into images that have no curl. It also
+// describes the probe as image health check metadata.
+package health
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// TargetEnv is the target the probe checks when it is given none.
+	TargetEnv = "BPL_GO_HEALTH_CHECK_TARGET"
+
+	// TimeoutEnv is how long the probe waits, DefaultTimeout by default.
+	TimeoutEnv = "BPL_GO_HEALTH_CHECK_TIMEOUT"
+
+	// DefaultTimeout is how long the probe waits when no timeout is
+	// configured.
+	DefaultTimeout = 2 * time.Second
+
+	// ProcessType is the process type that runs the probe.
+	ProcessType = "health"
+)
+
+// Target is what a probe checks.
+type Target struct {
+	// Scheme is http, https, tcp or grpc.
+	Scheme string
+
+	// Address is the host and port to connect to.
+	Address string
+
+	// URL is the URL to get, for http and https.
+	URL string
+
+	// Service is the service to check, for grpc. The empty service is the
+	// health of the whole server.
+	Service string
+}
+
+// ParseTarget parses a target such as http://localhost:8080/healthz,
+// tcp://localhost:8080 or grpc://localhost:9090/my.Service.
+func ParseTarget(value string) (Target, error) {
+	u, err := url.Parse(strings.TrimSpace(value))
+	if err != nil {
+		return Target{}, fmt.Errorf("invalid health check target %q: %w", value, err)
+	}
+
+	if u.Host == "" || u.Port() == "" && u.Scheme != "http" && u.Scheme != "https" {
+		return Target{}, fmt.Errorf("invalid health check target %q: expected <scheme>://<host>:<port>", value)
+	}
+
+	target := Target{Scheme: u.Scheme, Address: u.Host}
+	switch u.Scheme {
+	case "http", "https":
+		target.URL = u.String()
+	case "tcp":
+		if strings.Trim(u.Path, "/") != "" {
+			return Target{}, fmt.Errorf("invalid health check target %q: tcp targets have no path", value)
+		}
+	case "grpc":
+		target.Service = strings.Trim(u.Path, "/")
+	default:
+		return Target{}, fmt.Errorf("invalid health check target %q: unsupported scheme %q, expected http, https, tcp or grpc", value, u.Scheme)
+	}
+
+	return target, nil
+}
+
+func (t Target) String() string {
+	switch t.Scheme {
+	case "http", "https":
+		return t.URL
+	case "grpc":
+		return fmt.Sprintf("grpc://%s/%s", t.Address, t.Service)
+	default:
+		return fmt.Sprintf("%s://%s", t.Scheme, t.Address)
+	}
+}
+
+// Probe checks a target once. HTTP targets are healthy when they respond
+// with a status below 400, TCP targets when they accept a connection, and
+// gRPC targets when the health service reports them as SERVING.
+func Probe(ctx context.Context, target Target) error {
+	switch target.Scheme {
+	case "http", "https":
+		return probeHTTP(ctx, target)
+	case "tcp":
+		var dialer net.Dialer
+		connection, err := dialer.DialContext(ctx, "tcp", target.Address)
+		if err != nil {
+			return err
+		}
+		return connection.Close()
+	case "grpc":
+		return probeGRPC(ctx, target)
+	default:
+		return fmt.Errorf("unsupported scheme %q", target.Scheme)
+	}
+}
+
+func probeHTTP(ctx context.Context, target Target) error {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, target.URL, nil)
+	if err != nil {
+		return err
+	}
+
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	_, _ = io.Copy(io.Discard, response.Body)
+	if response.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("%s responded with %s", target, response.Status)
+	}
+
+	return nil
+}
+
+// servingStatus is the SERVING value of grpc.health.v1.HealthCheckResponse.
+const servingStatus = 1
+
+// probeGRPC calls grpc.health.v1.Health/Check over HTTP/2 without TLS. The
+// request and response messages are small enough to encode by hand, which
+// keeps the probe free of the gRPC and protobuf modules.
+func probeGRPC(ctx context.Context, target Target) error {
+	// HealthCheckRequest has the service as field 1
+	var message []byte
+	if target.Service != "" {
+		message = append([]byte{0x0a}, binary.AppendUvarint(nil, uint64(len(target.Service)))...)
+		message = append(message, target.Service...)
+	}
+
+	body := binary.BigEndian.AppendUint32([]byte{0}, uint32(len(message)))
+	body = append(body, message...)
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf("http://%s/grpc.health.v1.Health/Check", target.Address), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/grpc")
+	request.Header.Set("TE", "trailers")
+
+	var protocols http.Protocols
+	protocols.SetUnencryptedHTTP2(true)
+	client := http.Client{Transport: &http.Transport{Protocols: &protocols}}
+
+	response, err := client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	content, err := io.ReadAll(response.Body)
+	if err != nil {
+		return err
+	}
+
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s responded with %s", target, response.Status)
+	}
+
+	// the status is a trailer, or a header for responses without a body
+	status := response.Trailer.Get("Grpc-Status")
+	if status == "" {
+		status = response.Header.Get("Grpc-Status")
+	}
+
+	if status != "0" {
+		return fmt.Errorf("%s responded with gRPC status %s: %s", target, status, response.Trailer.Get("Grpc-Message"))
+	}
+
+	if len(content) < 5 || int(binary.BigEndian.Uint32(content[1:5])) != len(content)-5 {
+		return fmt.Errorf("%s responded with an invalid message", target)
+	}
+
+	// HealthCheckResponse has the status as field 1, an enum that is left out
+	// when it is UNKNOWN
+	serving, err := readStatus(content[5:])
+	if err != nil {
+		return fmt.Errorf("%s responded with an invalid message: %w", target, err)
+	}
+
+	if serving != servingStatus {
+		return fmt.Errorf("%s is not serving, its status is %d", target, serving)
+	}
+
+	return nil
+}
+
+func readStatus(message []byte) (uint64, error) {
+	var status uint64
+	for len(message) > 0 {
+		key, n := binary.Uvarint(message)
+		if n <= 0 {
+			return 0, errors.New("truncated field")
+		}
+		message = message[n:]
+
+		if key&7 != 0 {
+			return 0, fmt.Errorf("unexpected wire type %d", key&7)
+		}
+
+		value, n := binary.Uvarint(message)
+		if n <= 0 {
+			return 0, errors.New("truncated field")
+		}
+		message = message[n:]
+
+		if key>>3 == 1 {
+			status = value
+		}
+	}
+
+	return status, nil
+}
@@ -0,0 +1,129 @@
+package health_test
+
+import (
+	"encoding/binary"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/paketo-buildpacks/go/health"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testProbe(t *testing.T, context spec.G, it spec.S) {
+	var Expect = NewWithT(t).Expect
+
+	probe := func(value string) error {
+		target, err := health.ParseTarget(value)
+		Expect(err).NotTo(HaveOccurred())
+
+		return health.Probe(t.Context(), target)
+	}
+
+	context("ParseTarget", func() {
+		it("parses every scheme", func() {
+			Expect(health.ParseTarget("http://localhost:8080/healthz")).To(Equal(health.Target{Scheme: "http", Address: "localhost:8080", URL: "http://localhost:8080/healthz"}))
+			Expect(health.ParseTarget("https://localhost/")).To(Equal(health.Target{Scheme: "https", Address: "localhost", URL: "https://localhost/"}))
+			Expect(health.ParseTarget("tcp://localhost:8080")).To(Equal(health.Target{Scheme: "tcp", Address: "localhost:8080"}))
+			Expect(health.ParseTarget("grpc://localhost:9090/my.Service")).To(Equal(health.Target{Scheme: "grpc", Address: "localhost:9090", Service: "my.Service"}))
+		})
+
+		it("rejects invalid targets", func() {
+			for value, message := range map[string]string{
+				"localhost:8080":            `expected <scheme>://<host>:<port>`,
+				"tcp://localhost":           `expected <scheme>://<host>:<port>`,
+				"tcp://localhost:8080/path": `tcp targets have no path`,
+				"udp://localhost:53":        `unsupported scheme "udp", expected http, https, tcp or grpc`,
+			} {
+				_, err := health.ParseTarget(value)
+				Expect(err).To(MatchError(ContainSubstring(message)), value)
+			}
+		})
+	})
+
+	context("http", func() {
+		it("is healthy below status 400", func() {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				if req.URL.Path == "/broken" {
+					w.WriteHeader(http.StatusServiceUnavailable)
+				}
+			}))
+			defer server.Close()
+
+			Expect(probe(server.URL + "/")).To(Succeed())
+			Expect(probe(server.URL + "/broken")).To(MatchError(ContainSubstring("responded with 503 Service Unavailable")))
+		})
+	})
+
+	context("tcp", func() {
+		it("is healthy when a connection is accepted", func() {
+			listener, err := net.Listen("tcp", "127.0.0.1:0")
+			Expect(err).NotTo(HaveOccurred())
+			address := listener.Addr().String()
+
+			Expect(probe("tcp://" + address)).To(Succeed())
+
+			Expect(listener.Close()).To(Succeed())
+			Expect(probe("tcp://" + address)).To(MatchError(ContainSubstring("connection refused")))
+		})
+	})
+
+	context("grpc", func() {
+		var (
+			server   *httptest.Server
+			services map[string]byte
+		)
+
+		it.Before(func() {
+			// a health service that answers like grpc-go, over HTTP/2 without TLS
+			services = map[string]byte{"": 1, "my.Service": 1, "my.Draining": 2}
+			server = httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				Expect(req.URL.Path).To(Equal("/grpc.health.v1.Health/Check"))
+				Expect(req.ProtoMajor).To(Equal(2))
+
+				body, err := io.ReadAll(req.Body)
+				Expect(err).NotTo(HaveOccurred())
+
+				service := ""
+				if len(body) > 5 {
+					service = string(body[7:])
+				}
+
+				w.Header().Set("Content-Type", "application/grpc")
+				w.Header().Set("Trailer", "Grpc-Status, Grpc-Message")
+
+				status, ok := services[service]
+				if !ok {
+					w.Header().Set("Grpc-Status", "5")
+					w.Header().Set("Grpc-Message", "unknown service")
+					return
+				}
+
+				w.Write(binary.BigEndian.AppendUint32([]byte{0}, 2))
+				w.Write([]byte{0x08, status})
+				w.Header().Set("Grpc-Status", "0")
+			}))
+			server.Config.Protocols = &http.Protocols{}
+			server.Config.Protocols.SetUnencryptedHTTP2(true)
+			server.Start()
+		})
+
+		it.After(func() {
+			server.Close()
+		})
+
+		it("is healthy when the service is serving", func() {
+			address := strings.TrimPrefix(server.URL, "http://")
+
+			Expect(probe("grpc://" + address)).To(Succeed())
+			Expect(probe("grpc://" + address + "/my.Service")).To(Succeed())
+			Expect(probe("grpc://" + address + "/my.Draining")).To(MatchError(ContainSubstring("is not serving, its status is 2")))
+			Expect(probe("grpc://" + address + "/my.Missing")).To(MatchError(ContainSubstring("responded with gRPC status 5: unknown service")))
+		})
+	})
+}
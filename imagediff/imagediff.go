@@ -0,0 +1,423 @@
+// Package imagediff explains why two images that were expected to be
+// identical are not, by comparing their configs, their layers and the files
+// in every layer that differs.
+package imagediff
+
+import (
+	"archive/tar"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+)
+
+// maxValueLength is the length at which values are truncated in a report.
+const maxValueLength = 120
+
+// Difference is a single field that differs between two images.
+type Difference struct {
+	Field string `json:"field"`
+	A     string `json:"a"`
+	B     string `json:"b"`
+}
+
+// LayerDiff describes a layer that differs between two images.
+type LayerDiff struct {
+	// Name is the buildpack layer name of the layer, as given by LayerNames.
+	// Layers without a name, such as those of the run image, are matched by
+	// position instead.
+	Name string `json:"name,omitempty"`
+
+	// Index is the position of the layer in the first image, or in the second
+	// image when the first one does not have it.
+	Index int `json:"index"`
+
+	// A and B are the diff IDs of the layer in each image. A missing layer
+	// has an empty hash.
+	A v1.Hash `json:"a,omitzero"`
+	B v1.Hash `json:"b,omitzero"`
+
+	Files []FileDiff `json:"files,omitempty"`
+}
+
+// FileDiff describes a file that differs between two versions of a layer.
+type FileDiff struct {
+	Path        string       `json:"path"`
+	Differences []Difference `json:"differences"`
+}
+
+// Report describes every difference between two images.
+type Report struct {
+	A v1.Hash `json:"a"`
+	B v1.Hash `json:"b"`
+
+	Config []Difference `json:"config,omitempty"`
+	Layers []LayerDiff  `json:"layers,omitempty"`
+}
+
+// Empty reports whether the images are identical.
+func (r Report) Empty() bool {
+	return r.A == r.B
+}
+
+// String renders the report for humans.
+func (r Report) String() string {
+	if r.Empty() {
+		return fmt.Sprintf("images are identical (%s)\n", r.A)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "images differ: %s != %s\n", r.A, r.B)
+
+	if len(r.Config) > 0 {
+		fmt.Fprintln(&b, "config:")
+		for _, difference := range r.Config {
+			writeDifference(&b, "  ", difference)
+		}
+	}
+
+	for _, layer := range r.Layers {
+		if layer.Name != "" {
+			fmt.Fprintf(&b, "layer %d (%s): %s != %s\n", layer.Index, layer.Name, hashString(layer.A), hashString(layer.B))
+		} else {
+			fmt.Fprintf(&b, "layer %d: %s != %s\n", layer.Index, hashString(layer.A), hashString(layer.B))
+		}
+		for _, file := range layer.Files {
+			fmt.Fprintf(&b, "  %s\n", file.Path)
+			for _, difference := range file.Differences {
+				writeDifference(&b, "    ", difference)
+			}
+		}
+	}
+
+	return b.String()
+}
+
+// Compare compares two images. Layers exported by the lifecycle are matched by
+// their buildpack layer name, so that a layer which moved, appeared or
+// disappeared does not make every following layer differ. Other layers are
+// matched by position.
+func Compare(a, b v1.Image) (Report, error) {
+	var report Report
+	var err error
+
+	report.A, err = a.ConfigName()
+	if err != nil {
+		return Report{}, err
+	}
+
+	report.B, err = b.ConfigName()
+	if err != nil {
+		return Report{}, err
+	}
+
+	if report.Empty() {
+		return report, nil
+	}
+
+	configA, err := a.ConfigFile()
+	if err != nil {
+		return Report{}, err
+	}
+
+	configB, err := b.ConfigFile()
+	if err != nil {
+		return Report{}, err
+	}
+
+	report.Config = compareConfigs(*configA, *configB)
+
+	layersA, err := namedLayers(a)
+	if err != nil {
+		return Report{}, err
+	}
+
+	layersB, err := namedLayers(b)
+	if err != nil {
+		return Report{}, err
+	}
+
+	var keys []string
+	seen := map[string]bool{}
+	for _, layers := range [][]namedLayer{layersA, layersB} {
+		for _, layer := range layers {
+			if !seen[layer.key] {
+				seen[layer.key] = true
+				keys = append(keys, layer.key)
+			}
+		}
+	}
+
+	find := func(layers []namedLayer, key string) (namedLayer, bool) {
+		for _, layer := range layers {
+			if layer.key == key {
+				return layer, true
+			}
+		}
+		return namedLayer{}, false
+	}
+
+	for _, key := range keys {
+		layerA, okA := find(layersA, key)
+		layerB, okB := find(layersB, key)
+
+		diff, err := CompareLayers(layerA.layer, layerB.layer)
+		if err != nil {
+			return Report{}, fmt.Errorf("failed to compare layer %s: %w", key, err)
+		}
+
+		if diff.A == diff.B {
+			continue
+		}
+
+		diff.Name = layerA.name
+		diff.Index = layerA.index
+		if !okA && okB {
+			diff.Name = layerB.name
+			diff.Index = layerB.index
+		}
+
+		report.Layers = append(report.Layers, diff)
+	}
+
+	return report, nil
+}
+
+type namedLayer struct {
+	key   string
+	name  string
+	index int
+	layer v1.Layer
+}
+
+func namedLayers(image v1.Image) ([]namedLayer, error) {
+	names, err := LayerNames(image)
+	if err != nil {
+		return nil, err
+	}
+
+	layers, err := image.Layers()
+	if err != nil {
+		return nil, err
+	}
+
+	var named []namedLayer
+	for i, layer := range layers {
+		diffID, err := layer.DiffID()
+		if err != nil {
+			return nil, err
+		}
+
+		n := namedLayer{key: fmt.Sprintf("#%d", i), index: i, layer: layer}
+		if name, ok := names[diffID]; ok {
+			n.key = name
+			n.name = name
+		}
+
+		named = append(named, n)
+	}
+
+	return named, nil
+}
+
+// CompareLayers compares the files of two layers. Either layer may be nil,
+// in which case every file of the other layer is reported.
+func CompareLayers(a, b v1.Layer) (LayerDiff, error) {
+	var diff LayerDiff
+	var filesA, filesB map[string]file
+	var err error
+
+	if a != nil {
+		diff.A, err = a.DiffID()
+		if err != nil {
+			return LayerDiff{}, err
+		}
+	}
+
+	if b != nil {
+		diff.B, err = b.DiffID()
+		if err != nil {
+			return LayerDiff{}, err
+		}
+	}
+
+	if diff.A == diff.B {
+		return diff, nil
+	}
+
+	filesA, err = readFiles(a)
+	if err != nil {
+		return LayerDiff{}, err
+	}
+
+	filesB, err = readFiles(b)
+	if err != nil {
+		return LayerDiff{}, err
+	}
+
+	paths := map[string]bool{}
+	for path := range filesA {
+		paths[path] = true
+	}
+	for path := range filesB {
+		paths[path] = true
+	}
+
+	var sorted []string
+	for path := range paths {
+		sorted = append(sorted, path)
+	}
+	sort.Strings(sorted)
+
+	for _, path := range sorted {
+		fileA, okA := filesA[path]
+		fileB, okB := filesB[path]
+
+		var differences []Difference
+		switch {
+		case !okA:
+			differences = []Difference{{Field: "presence", A: "missing", B: "present"}}
+		case !okB:
+			differences = []Difference{{Field: "presence", A: "present", B: "missing"}}
+		default:
+			differences = compareFiles(fileA, fileB)
+		}
+
+		if len(differences) > 0 {
+			diff.Files = append(diff.Files, FileDiff{Path: path, Differences: differences})
+		}
+	}
+
+	return diff, nil
+}
+
+type file struct {
+	header tar.Header
+	digest string
+}
+
+func readFiles(layer v1.Layer) (map[string]file, error) {
+	files := map[string]file{}
+	if layer == nil {
+		return files, nil
+	}
+
+	reader, err := layer.Uncompressed()
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	tr := tar.NewReader(reader)
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		f := file{header: *header}
+		if header.Typeflag == tar.TypeReg {
+			hash := sha256.New()
+			_, err = io.Copy(hash, tr)
+			if err != nil {
+				return nil, err
+			}
+			f.digest = "sha256:" + hex.EncodeToString(hash.Sum(nil))
+		}
+
+		files[strings.TrimPrefix(header.Name, "./")] = f
+	}
+
+	return files, nil
+}
+
+func compareFiles(a, b file) []Difference {
+	var differences []Difference
+	add := func(field, valueA, valueB string) {
+		if valueA != valueB {
+			differences = append(differences, Difference{Field: field, A: valueA, B: valueB})
+		}
+	}
+
+	add("type", string(a.header.Typeflag), string(b.header.Typeflag))
+	add("content", a.digest, b.digest)
+	add("size", fmt.Sprint(a.header.Size), fmt.Sprint(b.header.Size))
+	add("mode", fmt.Sprintf("%o", a.header.Mode), fmt.Sprintf("%o", b.header.Mode))
+	add("mtime", a.header.ModTime.UTC().Format(time.RFC3339), b.header.ModTime.UTC().Format(time.RFC3339))
+	add("uid", fmt.Sprint(a.header.Uid), fmt.Sprint(b.header.Uid))
+	add("gid", fmt.Sprint(a.header.Gid), fmt.Sprint(b.header.Gid))
+	add("linkname", a.header.Linkname, b.header.Linkname)
+
+	return differences
+}
+
+func compareConfigs(a, b v1.ConfigFile) []Difference {
+	var differences []Difference
+	add := func(field, valueA, valueB string) {
+		if valueA != valueB {
+			differences = append(differences, Difference{Field: field, A: valueA, B: valueB})
+		}
+	}
+
+	add("created", a.Created.UTC().Format(time.RFC3339), b.Created.UTC().Format(time.RFC3339))
+	add("architecture", a.Architecture, b.Architecture)
+	add("os", a.OS, b.OS)
+	add("user", a.Config.User, b.Config.User)
+	add("working dir", a.Config.WorkingDir, b.Config.WorkingDir)
+	add("entrypoint", strings.Join(a.Config.Entrypoint, " "), strings.Join(b.Config.Entrypoint, " "))
+	add("cmd", strings.Join(a.Config.Cmd, " "), strings.Join(b.Config.Cmd, " "))
+	add("env", strings.Join(a.Config.Env, " "), strings.Join(b.Config.Env, " "))
+
+	keys := map[string]bool{}
+	for key := range a.Config.Labels {
+		keys[key] = true
+	}
+	for key := range b.Config.Labels {
+		keys[key] = true
+	}
+
+	var sorted []string
+	for key := range keys {
+		sorted = append(sorted, key)
+	}
+	sort.Strings(sorted)
+
+	for _, key := range sorted {
+		add("label "+key, a.Config.Labels[key], b.Config.Labels[key])
+	}
+
+	return differences
+}
+
+func writeDifference(b *strings.Builder, indent string, difference Difference) {
+	fmt.Fprintf(b, "%s%s: %s != %s\n", indent, difference.Field, truncate(difference.A), truncate(difference.B))
+}
+
+func truncate(value string) string {
+	if value == "" {
+		return `""`
+	}
+
+	if len(value) > maxValueLength {
+		return value[:maxValueLength] + "..."
+	}
+
+	return value
+}
+
+func hashString(hash v1.Hash) string {
+	if hash == (v1.Hash{}) {
+		return "(missing)"
+	}
+
+	return hash.String()
+}
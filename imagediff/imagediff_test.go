@@ -0,0 +1,224 @@
+package imagediff_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
+	"github.com/paketo-buildpacks/go/imagediff"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+type entry struct {
+	name    string
+	content string
+	mode    int64
+	modTime time.Time
+	uid     int
+}
+
+func testImageDiff(t *testing.T, context spec.G, it spec.S) {
+	var Expect = NewWithT(t).Expect
+
+	layer := func(entries ...entry) v1.Layer {
+		buffer := bytes.NewBuffer(nil)
+		tw := tar.NewWriter(buffer)
+		for _, e := range entries {
+			Expect(tw.WriteHeader(&tar.Header{
+				Name:     e.name,
+				Typeflag: tar.TypeReg,
+				Mode:     e.mode,
+				Size:     int64(len(e.content)),
+				ModTime:  e.modTime,
+				Uid:      e.uid,
+			})).To(Succeed())
+			_, err := tw.Write([]byte(e.content))
+			Expect(err).NotTo(HaveOccurred())
+		}
+		Expect(tw.Close()).To(Succeed())
+
+		l, err := tarball.LayerFromOpener(func() (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewReader(buffer.Bytes())), nil
+		})
+		Expect(err).NotTo(HaveOccurred())
+		return l
+	}
+
+	image := func(created time.Time, labels map[string]string, layers ...v1.Layer) v1.Image {
+		img, err := mutate.AppendLayers(empty.Image, layers...)
+		Expect(err).NotTo(HaveOccurred())
+
+		img, err = mutate.CreatedAt(img, v1.Time{Time: created})
+		Expect(err).NotTo(HaveOccurred())
+
+		img, err = mutate.Config(img, v1.Config{Labels: labels})
+		Expect(err).NotTo(HaveOccurred())
+		return img
+	}
+
+	epoch := time.Unix(0, 0).UTC()
+	later := time.Date(2026, time.October, 19, 0, 0, 0, 0, time.UTC)
+
+	context("Compare", func() {
+		it("reports identical images as empty", func() {
+			a := image(epoch, nil, layer(entry{name: "workspace/app", content: "app", mode: 0755, modTime: epoch}))
+			b := image(epoch, nil, layer(entry{name: "workspace/app", content: "app", mode: 0755, modTime: epoch}))
+
+			report, err := imagediff.Compare(a, b)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(report.Empty()).To(BeTrue())
+			Expect(report.String()).To(HavePrefix("images are identical"))
+		})
+
+		it("reports the config, layers and files that differ", func() {
+			base := layer(entry{name: "etc/os-release", content: "jammy", mode: 0644, modTime: epoch})
+
+			a := image(epoch, map[string]string{"some-label": "some-value"},
+				base,
+				layer(
+					entry{name: "layers/go-build/bin/app", content: "binary-a", mode: 0755, modTime: epoch},
+					entry{name: "layers/go-build/bin/helper", content: "helper", mode: 0755, modTime: epoch},
+				),
+			)
+			b := image(later, map[string]string{"some-label": "other-value"},
+				base,
+				layer(
+					entry{name: "layers/go-build/bin/app", content: "binary-b", mode: 0755, modTime: later, uid: 1000},
+					entry{name: "layers/go-build/bin/extra", content: "extra", mode: 0644, modTime: epoch},
+				),
+				layer(entry{name: "workspace/extra", content: "extra", mode: 0644, modTime: epoch}),
+			)
+
+			report, err := imagediff.Compare(a, b)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(report.Empty()).To(BeFalse())
+
+			Expect(report.Config).To(Equal([]imagediff.Difference{
+				{Field: "created", A: "1970-01-01T00:00:00Z", B: "2026-10-19T00:00:00Z"},
+				{Field: "label some-label", A: "some-value", B: "other-value"},
+			}))
+
+			Expect(report.Layers).To(HaveLen(2))
+			Expect(report.Layers[0].Index).To(Equal(1))
+			Expect(report.Layers[0].Files).To(Equal([]imagediff.FileDiff{
+				{Path: "layers/go-build/bin/app", Differences: []imagediff.Difference{
+					{Field: "content", A: "sha256:353797a12e4fbdb499f56f11b2b24ab86179b605b65b259358c00aed8b53541d", B: "sha256:c19071f0c029f1a0ec6522cd09848a66b5c1eca6ebd723a868a42332ba1f998e"},
+					{Field: "mtime", A: "1970-01-01T00:00:00Z", B: "2026-10-19T00:00:00Z"},
+					{Field: "uid", A: "0", B: "1000"},
+				}},
+				{Path: "layers/go-build/bin/extra", Differences: []imagediff.Difference{
+					{Field: "presence", A: "missing", B: "present"},
+				}},
+				{Path: "layers/go-build/bin/helper", Differences: []imagediff.Difference{
+					{Field: "presence", A: "present", B: "missing"},
+				}},
+			}))
+
+			Expect(report.Layers[1].Index).To(Equal(2))
+			Expect(report.Layers[1].A).To(Equal(v1.Hash{}))
+			Expect(report.Layers[1].Files).To(ConsistOf(HaveField("Path", "workspace/extra")))
+
+			Expect(report.String()).To(ContainSubstring("layer 1: sha256:"))
+			Expect(report.String()).To(ContainSubstring("layer 2: (missing) != sha256:"))
+			Expect(report.String()).To(ContainSubstring("  layers/go-build/bin/app\n    content: sha256:"))
+			Expect(report.String()).To(ContainSubstring("    uid: 0 != 1000\n"))
+		})
+
+		it("matches buildpack layers by name", func() {
+			base := layer(entry{name: "etc/os-release", content: "jammy", mode: 0644, modTime: epoch})
+			gopath := layer(entry{name: "layers/go-build/gopath/pkg/mod/cache", content: "gopath", mode: 0755, modTime: epoch})
+			targetsA := layer(entry{name: "layers/go-build/targets/bin/app", content: "binary-a", mode: 0755, modTime: epoch})
+			targetsB := layer(entry{name: "layers/go-build/targets/bin/app", content: "binary-b", mode: 0755, modTime: epoch})
+			cache := layer(entry{name: "layers/go-build/gocache/entry", content: "cache", mode: 0644, modTime: epoch})
+
+			type buildpackLayer struct {
+				name  string
+				layer v1.Layer
+			}
+
+			metadata := func(layers ...buildpackLayer) map[string]string {
+				var entries []string
+				for _, l := range layers {
+					diffID, err := l.layer.DiffID()
+					Expect(err).NotTo(HaveOccurred())
+					entries = append(entries, fmt.Sprintf(`{"key": "paketo-buildpacks/go-build", "layers": {%q: {"sha": %q}}}`, l.name, diffID))
+				}
+
+				return map[string]string{
+					imagediff.LifecycleMetadataLabel: fmt.Sprintf(`{"buildpacks": [%s]}`, strings.Join(entries, ", ")),
+				}
+			}
+
+			a := image(epoch, metadata(buildpackLayer{"gopath", gopath}, buildpackLayer{"targets", targetsA}),
+				base, gopath, targetsA)
+			b := image(epoch, metadata(buildpackLayer{"gocache", cache}, buildpackLayer{"gopath", gopath}, buildpackLayer{"targets", targetsB}),
+				base, cache, gopath, targetsB)
+
+			report, err := imagediff.Compare(a, b)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(report.Layers).To(HaveLen(2))
+			Expect(report.Layers[0].Name).To(Equal("paketo-buildpacks/go-build:targets"))
+			Expect(report.Layers[0].Index).To(Equal(2))
+			Expect(report.Layers[0].Files).To(ConsistOf(HaveField("Path", "layers/go-build/targets/bin/app")))
+
+			Expect(report.Layers[1].Name).To(Equal("paketo-buildpacks/go-build:gocache"))
+			Expect(report.Layers[1].Index).To(Equal(1))
+			Expect(report.Layers[1].A).To(Equal(v1.Hash{}))
+
+			Expect(report.String()).To(ContainSubstring("layer 2 (paketo-buildpacks/go-build:targets): sha256:"))
+			Expect(report.String()).To(ContainSubstring("layer 1 (paketo-buildpacks/go-build:gocache): (missing) != sha256:"))
+		})
+	})
+
+	context("LayerNames", func() {
+		it("names the layers recorded in the lifecycle metadata", func() {
+			app := layer(entry{name: "workspace/main.go", content: "package main", mode: 0644, modTime: epoch})
+			launcher := layer(entry{name: "cnb/lifecycle/launcher", content: "launcher", mode: 0755, modTime: epoch})
+			targets := layer(entry{name: "layers/go-build/targets/bin/app", content: "binary", mode: 0755, modTime: epoch})
+
+			diffID := func(l v1.Layer) v1.Hash {
+				hash, err := l.DiffID()
+				Expect(err).NotTo(HaveOccurred())
+				return hash
+			}
+
+			img := image(epoch, map[string]string{
+				imagediff.LifecycleMetadataLabel: fmt.Sprintf(`{
+					"app": [{"sha": %q}],
+					"launcher": {"sha": %q},
+					"buildpacks": [{"key": "paketo-buildpacks/go-build", "layers": {"targets": {"sha": %q}}}]
+				}`, diffID(app), diffID(launcher), diffID(targets)),
+			}, app, launcher, targets)
+
+			names, err := imagediff.LayerNames(img)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(names).To(Equal(map[v1.Hash]string{
+				diffID(app):      "app",
+				diffID(launcher): "launcher",
+				diffID(targets):  "paketo-buildpacks/go-build:targets",
+			}))
+		})
+
+		it("returns no names for images without lifecycle metadata", func() {
+			names, err := imagediff.LayerNames(image(epoch, nil))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(names).To(BeEmpty())
+		})
+
+		it("returns an error when the lifecycle metadata is malformed", func() {
+			_, err := imagediff.LayerNames(image(epoch, map[string]string{imagediff.LifecycleMetadataLabel: "%%%"}))
+			Expect(err).To(MatchError(ContainSubstring("failed to parse io.buildpacks.lifecycle.metadata label")))
+		})
+	})
+}
@@ -0,0 +1,14 @@
+package imagediff_test
+
+import (
+	"testing"
+
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+)
+
+func TestUnitImageDiff(t *testing.T) {
+	suite := spec.New("imagediff", spec.Report(report.Terminal{}), spec.Parallel())
+	suite("ImageDiff", testImageDiff)
+	suite.Run(t)
+}
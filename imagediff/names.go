@@ -0,0 +1,106 @@
+package imagediff
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+)
+
+// LifecycleMetadataLabel is the image label in which the lifecycle records
+// the layers it exported.
+const LifecycleMetadataLabel = "io.buildpacks.lifecycle.metadata"
+
+type layerMetadata struct {
+	SHA string `json:"sha"`
+}
+
+type lifecycleMetadata struct {
+	App          []layerMetadata `json:"app"`
+	Config       layerMetadata   `json:"config"`
+	Launcher     layerMetadata   `json:"launcher"`
+	ProcessTypes layerMetadata   `json:"process-types"`
+	Buildpacks   []struct {
+		Key    string                   `json:"key"`
+		Layers map[string]layerMetadata `json:"layers"`
+	} `json:"buildpacks"`
+}
+
+// LayerNames names the layers of an image exported by the lifecycle, keyed by
+// diff ID. Buildpack layers are named <buildpack id>:<layer name>, and the
+// layers the lifecycle adds itself are named app, config, launcher and
+// process-types. Images without lifecycle metadata have no named layers.
+func LayerNames(image v1.Image) (map[v1.Hash]string, error) {
+	config, err := image.ConfigFile()
+	if err != nil {
+		return nil, err
+	}
+
+	names := map[v1.Hash]string{}
+
+	label, ok := config.Config.Labels[LifecycleMetadataLabel]
+	if !ok {
+		return names, nil
+	}
+
+	var metadata lifecycleMetadata
+	err = json.Unmarshal([]byte(label), &metadata)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s label: %w", LifecycleMetadataLabel, err)
+	}
+
+	add := func(sha, name string) error {
+		if sha == "" {
+			return nil
+		}
+
+		hash, err := v1.NewHash(sha)
+		if err != nil {
+			return fmt.Errorf("failed to parse %s label: layer %s: %w", LifecycleMetadataLabel, name, err)
+		}
+
+		names[hash] = name
+		return nil
+	}
+
+	for i, app := range metadata.App {
+		name := "app"
+		if i > 0 {
+			name = fmt.Sprintf("app:%d", i)
+		}
+
+		err = add(app.SHA, name)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for sha, name := range map[string]string{
+		metadata.Config.SHA:       "config",
+		metadata.Launcher.SHA:     "launcher",
+		metadata.ProcessTypes.SHA: "process-types",
+	} {
+		err = add(sha, name)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, buildpack := range metadata.Buildpacks {
+		var layers []string
+		for layer := range buildpack.Layers {
+			layers = append(layers, layer)
+		}
+		sort.Strings(layers)
+
+		for _, layer := range layers {
+			err = add(buildpack.Layers[layer].SHA, fmt.Sprintf("%s:%s", buildpack.Key, layer))
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return names, nil
+}
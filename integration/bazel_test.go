@@ -0,0 +1,106 @@
+package integration_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/paketo-buildpacks/go/bazel"
+	"github.com/paketo-buildpacks/occam"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+	. "github.com/paketo-buildpacks/occam/matchers"
+)
+
+func testBazel(t *testing.T, context spec.G, it spec.S) {
+	for _, c := range []struct {
+		workspace string
+		file      string
+	}{
+		{workspace: "module", file: "MODULE.bazel"},
+		{workspace: "workspace", file: "WORKSPACE"},
+	} {
+		c := c
+
+		context("when the app is built with rules_go from a "+c.file, func() {
+			var (
+				Expect     = NewWithT(t).Expect
+				Eventually = NewWithT(t).Eventually
+
+				pack   occam.Pack
+				docker occam.Docker
+
+				images    map[string]struct{}
+				container occam.Container
+
+				name   string
+				source string
+			)
+
+			it.Before(func() {
+				pack = occam.NewPack()
+				docker = occam.NewDocker()
+				images = map[string]struct{}{}
+
+				var err error
+				name, err = occam.RandomName()
+				Expect(err).NotTo(HaveOccurred())
+
+				source, err = occam.Source(filepath.Join("testdata", "bazel", c.workspace))
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			it.After(func() {
+				if container.ID != "" {
+					Expect(docker.Container.Remove.Execute(container.ID)).To(Succeed())
+				}
+				for id := range images {
+					Expect(docker.Image.Remove.Execute(id)).To(Succeed())
+				}
+				Expect(docker.Volume.Remove.Execute(occam.CacheVolumeNames(name))).To(Succeed())
+				Expect(os.RemoveAll(source)).To(Succeed())
+			})
+
+			build := func() (occam.Image, fmt.Stringer, error) {
+				image, logs, err := executeBuild(t, pack.WithNoColor().Build.
+					WithBuilder(builder).
+					WithBuildpacks(goBuildpack).
+					WithPullPolicy("never").
+					WithEnv(map[string]string{bazel.TargetEnv: "//cmd/server"}),
+					name, source)
+				if err == nil {
+					images[image.ID] = struct{}{}
+				}
+
+				return image, logs, err
+			}
+
+			it("builds the target, keeps the output base in the cache and assigns a process type to the binary", func() {
+				image, logs, err := build()
+				Expect(err).NotTo(HaveOccurred(), logs.String())
+
+				Expect(logs).To(ContainLines(ContainSubstring("Paketo Buildpack for Go Bazel")))
+				Expect(logs).To(ContainLines(ContainSubstring("Running bazel build //cmd/server from %s", c.file)))
+
+				Expect(imageProcessTypes(image)).To(ConsistOf("server"))
+
+				container, err = docker.Container.Run.
+					WithEnv(map[string]string{"PORT": "8080"}).
+					WithPublish("8080").
+					WithPublishAll().
+					Execute(image.ID)
+				Expect(err).NotTo(HaveOccurred())
+
+				Eventually(container).Should(Serve(ContainSubstring("Hello from Bazel!")).OnPort(8080))
+
+				// the output base is restored from the cache, so the rebuild
+				// reuses the action cache instead of linking again
+				_, logs, err = build()
+				Expect(err).NotTo(HaveOccurred(), logs.String())
+				Expect(logs).To(ContainLines(ContainSubstring("1 process: 1 internal")))
+			})
+		})
+	}
+}
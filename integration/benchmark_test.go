@@ -0,0 +1,287 @@
+package integration_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/paketo-buildpacks/go/buildlogs"
+	"github.com/paketo-buildpacks/go/components"
+	"github.com/paketo-buildpacks/occam"
+
+	. "github.com/onsi/gomega"
+)
+
+const (
+	// BenchmarkOutputEnv is the path the benchmark results are written to,
+	// build/benchmark.json by default.
+	BenchmarkOutputEnv = "BENCHMARK_OUTPUT"
+
+	// BenchmarkBaselineEnv is the path to the results of an earlier run to
+	// compare against, such as the results before a buildpack.toml bump.
+	BenchmarkBaselineEnv = "BENCHMARK_BASELINE"
+)
+
+// benchmarkResults are the results of a benchmark run on one builder. The
+// results of every builder are written as a JSON array.
+type benchmarkResults struct {
+	Builder string `json:"builder"`
+
+	// Components are the component buildpacks in buildpack.toml, as
+	// <id>@<version>, so that results can be attributed to a bump.
+	Components []string `json:"components"`
+
+	Fixtures []fixtureBenchmark `json:"fixtures"`
+}
+
+type fixtureBenchmark struct {
+	Fixture string `json:"fixture"`
+
+	// Cold is a build without a build cache, Warm a rebuild from the cache of
+	// the cold build.
+	Cold buildBenchmark `json:"cold"`
+	Warm buildBenchmark `json:"warm"`
+}
+
+// buildBenchmark holds durations in seconds, averaged over the iterations of
+// the benchmark, and the image size in bytes.
+type buildBenchmark struct {
+	Duration   float64            `json:"duration"`
+	ImageSize  int64              `json:"image_size"`
+	Phases     map[string]float64 `json:"phases,omitempty"`
+	Buildpacks map[string]float64 `json:"buildpacks"`
+}
+
+// BenchmarkBuilds builds every fixture cold and then warm on every builder,
+// and records how long the whole build, each lifecycle phase and each
+// component buildpack took along with the size of the image. Every iteration
+// is a full pair of builds, so it is meant to be run once:
+//
+//	go test ./integration -run '^$' -bench Builds -benchtime 1x -timeout 0
+func BenchmarkBuilds(b *testing.B) {
+	Expect := NewWithT(b).Expect
+
+	buildpack, err := packageBuildpack()
+	Expect(err).NotTo(HaveOccurred())
+
+	builders, err := integrationBuilders("../integration.json")
+	Expect(err).NotTo(HaveOccurred())
+
+	composite, err := components.LoadComposite("../buildpack.toml", "../package.toml")
+	Expect(err).NotTo(HaveOccurred())
+
+	var versions []string
+	for _, component := range composite {
+		versions = append(versions, fmt.Sprintf("%s@%s", component.ID, component.Version))
+	}
+
+	pack := occam.NewPack().WithNoColor()
+	docker := occam.NewDocker()
+
+	fixtures := []struct {
+		name string
+		path string
+	}{
+		{name: "build"},
+		{name: "go_mod"},
+		{name: "go_mod_vendored"},
+		{name: "ca_certificate_apps", path: "build"},
+	}
+
+	var all []benchmarkResults
+	for _, builder := range builders {
+		b.Run(builder, func(b *testing.B) {
+			results := benchmarkResults{Builder: builder, Components: versions}
+
+			// b.Run may run a benchmark more than once while it settles on
+			// b.N, so only the last run of every fixture is kept
+			byFixture := map[string]fixtureBenchmark{}
+			for _, fixture := range fixtures {
+				b.Run(fixture.name, func(b *testing.B) {
+					Expect := NewWithT(b).Expect
+
+					result := fixtureBenchmark{Fixture: filepath.Join(fixture.name, fixture.path)}
+					for i := 0; i < b.N; i++ {
+						b.StopTimer()
+						name, err := occam.RandomName()
+						Expect(err).NotTo(HaveOccurred())
+
+						source, err := occam.Source(filepath.Join("testdata", fixture.name))
+						Expect(err).NotTo(HaveOccurred())
+						b.StartTimer()
+
+						build := pack.Build.
+							WithBuilder(builder).
+							WithBuildpacks(buildpack).
+							WithPullPolicy("never").
+							WithAdditionalBuildArgs("--timestamps")
+
+						cold, coldImage, err := benchmarkBuild(build.WithClearCache(), name, filepath.Join(source, fixture.path))
+						Expect(err).NotTo(HaveOccurred())
+
+						warm, warmImage, err := benchmarkBuild(build, name, filepath.Join(source, fixture.path))
+						Expect(err).NotTo(HaveOccurred())
+
+						b.StopTimer()
+						// the warm build takes over the tag, so the cold image
+						// is only left behind when the builds differ
+						Expect(docker.Image.Remove.Execute(warmImage.ID)).To(Succeed())
+						if coldImage.ID != warmImage.ID {
+							Expect(docker.Image.Remove.Execute(coldImage.ID)).To(Succeed())
+						}
+						Expect(docker.Volume.Remove.Execute(occam.CacheVolumeNames(name))).To(Succeed())
+						Expect(os.RemoveAll(source)).To(Succeed())
+						b.StartTimer()
+
+						result.Cold = result.Cold.add(cold, b.N)
+						result.Warm = result.Warm.add(warm, b.N)
+					}
+
+					b.ReportMetric(result.Cold.Duration, "cold-s/op")
+					b.ReportMetric(result.Warm.Duration, "warm-s/op")
+					b.ReportMetric(float64(result.Warm.ImageSize), "image-bytes")
+
+					byFixture[fixture.name] = result
+				})
+			}
+
+			for _, fixture := range fixtures {
+				if result, ok := byFixture[fixture.name]; ok {
+					results.Fixtures = append(results.Fixtures, result)
+				}
+			}
+
+			all = append(all, results)
+		})
+	}
+
+	output := filepath.Join("..", "build", "benchmark.json")
+	if value, ok := os.LookupEnv(BenchmarkOutputEnv); ok && value != "" {
+		output = value
+	}
+
+	content, err := json.MarshalIndent(all, "", "  ")
+	Expect(err).NotTo(HaveOccurred())
+	Expect(os.MkdirAll(filepath.Dir(output), os.ModePerm)).To(Succeed())
+	Expect(os.WriteFile(output, content, 0644)).To(Succeed())
+	b.Logf("benchmark results written to %s", output)
+
+	if path, ok := os.LookupEnv(BenchmarkBaselineEnv); ok && path != "" {
+		content, err := os.ReadFile(path)
+		Expect(err).NotTo(HaveOccurred())
+
+		var baseline []benchmarkResults
+		Expect(json.Unmarshal(content, &baseline)).To(Succeed())
+
+		for _, results := range all {
+			for _, base := range baseline {
+				if base.Builder != results.Builder {
+					continue
+				}
+
+				for _, line := range compareBenchmarks(base, results) {
+					b.Logf("%s: %s", results.Builder, line)
+				}
+			}
+		}
+	}
+}
+
+// benchmarkBuild runs a single timestamped build and measures it.
+func benchmarkBuild(build occam.PackBuild, name, path string) (buildBenchmark, occam.Image, error) {
+	start := time.Now()
+	image, logs, err := build.Execute(name, path)
+	if err != nil {
+		return buildBenchmark{}, occam.Image{}, fmt.Errorf("%w\n%s", err, logs)
+	}
+
+	result := buildBenchmark{
+		Duration:   time.Since(start).Seconds(),
+		Phases:     map[string]float64{},
+		Buildpacks: map[string]float64{},
+	}
+
+	result.ImageSize, err = imageSize(image.ID)
+	if err != nil {
+		return buildBenchmark{}, occam.Image{}, err
+	}
+
+	timings := buildlogs.Parse(logs.String())
+	for _, phase := range timings.Phases {
+		result.Phases[phase.Name] = phase.Duration.Seconds()
+	}
+	for _, buildpack := range timings.Buildpacks {
+		result.Buildpacks[buildpack.Name] = buildpack.Duration.Seconds()
+	}
+
+	return result, image, nil
+}
+
+// add adds the share of a single iteration to an average over n iterations.
+func (b buildBenchmark) add(other buildBenchmark, n int) buildBenchmark {
+	if b.Phases == nil {
+		b.Phases = map[string]float64{}
+	}
+	if b.Buildpacks == nil {
+		b.Buildpacks = map[string]float64{}
+	}
+
+	b.Duration += other.Duration / float64(n)
+	b.ImageSize = other.ImageSize
+	for name, duration := range other.Phases {
+		b.Phases[name] += duration / float64(n)
+	}
+	for name, duration := range other.Buildpacks {
+		b.Buildpacks[name] += duration / float64(n)
+	}
+
+	return b
+}
+
+// compareBenchmarks describes how the duration of every build and buildpack
+// changed since the baseline.
+func compareBenchmarks(baseline, current benchmarkResults) []string {
+	var lines []string
+	change := func(name string, before, after float64) {
+		if before == 0 {
+			return
+		}
+		lines = append(lines, fmt.Sprintf("%s: %.1fs -> %.1fs (%+.0f%%)", name, before, after, (after-before)/before*100))
+	}
+
+	for _, fixture := range current.Fixtures {
+		for _, base := range baseline.Fixtures {
+			if base.Fixture != fixture.Fixture {
+				continue
+			}
+
+			for _, build := range []struct {
+				kind          string
+				before, after buildBenchmark
+			}{
+				{"cold", base.Cold, fixture.Cold},
+				{"warm", base.Warm, fixture.Warm},
+			} {
+				change(fmt.Sprintf("%s %s", fixture.Fixture, build.kind), build.before.Duration, build.after.Duration)
+				var names []string
+				for name := range build.after.Buildpacks {
+					names = append(names, name)
+				}
+				sort.Strings(names)
+
+				for _, name := range names {
+					change(fmt.Sprintf("%s %s %s", fixture.Fixture, build.kind, name), build.before.Buildpacks[name], build.after.Buildpacks[name])
+				}
+				if build.before.ImageSize != build.after.ImageSize {
+					lines = append(lines, fmt.Sprintf("%s %s image size: %d -> %d bytes", fixture.Fixture, build.kind, build.before.ImageSize, build.after.ImageSize))
+				}
+			}
+		}
+	}
+
+	return lines
+}
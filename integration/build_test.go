@@ -65,11 +65,12 @@ func testBuild(t *testing.T, context spec.G, it spec.S) {
 		it("creates a working OCI image", func() {
 			var err error
 			var logs fmt.Stringer
-			image, logs, err = pack.WithNoColor().Build.
+			image, logs, err = executeBuild(t, pack.WithNoColor().Build.
+				WithBuilder(builder).
 				WithBuildpacks(goBuildpack).
 				WithSBOMOutputDir(sbomDir).
-				WithPullPolicy("never").
-				Execute(name, source)
+				WithPullPolicy("never"),
+				name, source)
 			Expect(err).NotTo(HaveOccurred(), logs.String())
 
 			container, err = docker.Container.Run.
@@ -98,6 +99,14 @@ func testBuild(t *testing.T, context spec.G, it spec.S) {
 			Expect(filepath.Join(sbomDir, "sbom", "launch", "paketo-buildpacks_go-build", "targets", "sbom.cdx.json")).To(BeARegularFile())
 			Expect(filepath.Join(sbomDir, "sbom", "launch", "paketo-buildpacks_go-build", "targets", "sbom.spdx.json")).To(BeARegularFile())
 			Expect(filepath.Join(sbomDir, "sbom", "launch", "paketo-buildpacks_go-build", "targets", "sbom.syft.json")).To(BeARegularFile())
+
+			// check that every SBOM file matches the schema for its version
+			validateSBOMs(t, filepath.Join(sbomDir, "sbom"))
+
+			// check that the SBOMs record the Go version installed by go-dist
+			version := goDistVersion(t, logs.String())
+			Expect(cycloneDXVersions(t, filepath.Join(sbomDir, "sbom", "build", "paketo-buildpacks_go-dist", "go", "sbom.cdx.json"), "go")).To(ContainElement(version))
+			Expect(cycloneDXVersions(t, filepath.Join(sbomDir, "sbom", "launch", "paketo-buildpacks_go-build", "targets", "sbom.cdx.json"), "stdlib")).To(ContainElement(version))
 		})
 
 		context("using optional utility buildpacks", func() {
@@ -115,19 +124,21 @@ func testBuild(t *testing.T, context spec.G, it spec.S) {
 			it("builds a working OCI image with start command from the Procfile and incorporating the utility buildpacks' effects", func() {
 				var err error
 				var logs fmt.Stringer
-				image, logs, err = pack.WithNoColor().Build.
+				image, logs, err = executeBuild(t, pack.WithNoColor().Build.
+					WithBuilder(builder).
 					WithBuildpacks(goBuildpack).
 					WithPullPolicy("never").
 					WithEnv(map[string]string{
 						"BPE_SOME_VARIABLE":      "some-value",
 						"BP_IMAGE_LABELS":        "some-label=some-value",
 						"BP_LIVE_RELOAD_ENABLED": "true",
-					}).
-					Execute(name, source)
+					}),
+					name, source)
 				Expect(err).NotTo(HaveOccurred(), logs.String())
 
-				Expect(image.Buildpacks[5].Key).To(Equal("paketo-buildpacks/environment-variables"))
-				Expect(image.Buildpacks[5].Layers["environment-variables"].Metadata["variables"]).To(Equal(map[string]interface{}{"SOME_VARIABLE": "some-value"}))
+				environmentVariables, err := image.BuildpackForKey("paketo-buildpacks/environment-variables")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(environmentVariables.Layers["environment-variables"].Metadata["variables"]).To(Equal(map[string]interface{}{"SOME_VARIABLE": "some-value"}))
 				Expect(image.Labels["some-label"]).To(Equal("some-value"))
 
 				Expect(logs).To(ContainLines(ContainSubstring("Buildpack for Go Distribution")))
@@ -162,10 +173,11 @@ func testBuild(t *testing.T, context spec.G, it spec.S) {
 			it("creates a working OCI image", func() {
 				var err error
 				var logs fmt.Stringer
-				image, logs, err = pack.WithNoColor().Build.
+				image, logs, err = executeBuild(t, pack.WithNoColor().Build.
+					WithBuilder(builder).
 					WithBuildpacks(goBuildpack).
-					WithPullPolicy("never").
-					Execute(name, source)
+					WithPullPolicy("never"),
+					name, source)
 				Expect(err).NotTo(HaveOccurred(), logs.String())
 
 				container, err = docker.Container.Run.
@@ -221,13 +233,14 @@ func testBuild(t *testing.T, context spec.G, it spec.S) {
 			it("builds a working OCI image and uses a client-side CA cert for requests", func() {
 				var err error
 				var logs fmt.Stringer
-				image, logs, err = pack.WithNoColor().Build.
+				image, logs, err = executeBuild(t, pack.WithNoColor().Build.
+					WithBuilder(builder).
 					WithBuildpacks(goBuildpack).
 					WithPullPolicy("never").
 					WithEnv(map[string]string{
 						"BP_KEEP_FILES": "key.pem:cert.pem",
-					}).
-					Execute(name, filepath.Join(source, "build"))
+					}),
+					name, filepath.Join(source, "build"))
 				Expect(err).NotTo(HaveOccurred())
 
 				Expect(logs).To(ContainLines(ContainSubstring("Buildpack for CA Certificates")))
@@ -0,0 +1,111 @@
+package integration_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/paketo-buildpacks/go/buildtool"
+	"github.com/paketo-buildpacks/occam"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+	. "github.com/paketo-buildpacks/occam/matchers"
+)
+
+func testBuildTool(t *testing.T, context spec.G, it spec.S) {
+	for _, c := range []struct {
+		fixture string
+		tool    buildtool.Tool
+		file    string
+	}{
+		{fixture: "make_build", tool: buildtool.Make, file: "Makefile"},
+		{fixture: "task_build", tool: buildtool.Task, file: "Taskfile.yml"},
+	} {
+		c := c
+
+		context("when the app is built with a "+string(c.tool)+" target", func() {
+			var (
+				Expect     = NewWithT(t).Expect
+				Eventually = NewWithT(t).Eventually
+
+				pack   occam.Pack
+				docker occam.Docker
+
+				image     occam.Image
+				container occam.Container
+
+				name   string
+				source string
+			)
+
+			it.Before(func() {
+				pack = occam.NewPack()
+				docker = occam.NewDocker()
+
+				var err error
+				name, err = occam.RandomName()
+				Expect(err).NotTo(HaveOccurred())
+
+				source, err = occam.Source(filepath.Join("testdata", c.fixture))
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			it.After(func() {
+				if container.ID != "" {
+					Expect(docker.Container.Remove.Execute(container.ID)).To(Succeed())
+				}
+				if image.ID != "" {
+					Expect(docker.Image.Remove.Execute(image.ID)).To(Succeed())
+				}
+				Expect(docker.Volume.Remove.Execute(occam.CacheVolumeNames(name))).To(Succeed())
+				Expect(os.RemoveAll(source)).To(Succeed())
+			})
+
+			it("runs the target and assigns a process type to each output", func() {
+				var err error
+				var logs fmt.Stringer
+				image, logs, err = executeBuild(t, pack.WithNoColor().Build.
+					WithBuilder(builder).
+					WithBuildpacks(goBuildpack).
+					WithPullPolicy("never").
+					WithEnv(map[string]string{
+						buildtool.TargetEnv:  "build",
+						buildtool.OutputsEnv: "bin/server",
+					}),
+					name, source)
+				Expect(err).NotTo(HaveOccurred(), logs.String())
+
+				Expect(logs).To(ContainLines(ContainSubstring("Paketo Buildpack for Go Build Tool")))
+				Expect(logs).To(ContainLines(ContainSubstring("Running %s build from %s", c.tool, c.file)))
+				Expect(logs).To(ContainLines(ContainSubstring("-X main.version=1.2.3")))
+
+				Expect(imageProcessTypes(image)).To(ConsistOf("server"))
+
+				container, err = docker.Container.Run.
+					WithEnv(map[string]string{"PORT": "8080"}).
+					WithPublish("8080").
+					WithPublishAll().
+					Execute(image.ID)
+				Expect(err).NotTo(HaveOccurred())
+
+				Eventually(container).Should(Serve(ContainSubstring("Hello from %s, version 1.2.3!", c.tool)).OnPort(8080))
+			})
+
+			it("fails when the target does not produce a declared output", func() {
+				_, logs, err := executeBuild(t, pack.WithNoColor().Build.
+					WithBuilder(builder).
+					WithBuildpacks(goBuildpack).
+					WithPullPolicy("never").
+					WithEnv(map[string]string{
+						buildtool.TargetEnv:  "build",
+						buildtool.OutputsEnv: "bin/server,bin/worker",
+					}),
+					name, source)
+				Expect(err).To(HaveOccurred())
+				Expect(logs).To(ContainLines(ContainSubstring("output bin/worker was not produced")))
+			})
+		})
+	}
+}
@@ -0,0 +1,157 @@
+package integration_test
+
+import (
+	"debug/elf"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	ggcrname "github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/daemon"
+	"github.com/paketo-buildpacks/go/debugsymbols"
+	"github.com/paketo-buildpacks/occam"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+	. "github.com/paketo-buildpacks/occam/matchers"
+)
+
+func testDebugSymbols(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect     = NewWithT(t).Expect
+		Eventually = NewWithT(t).Eventually
+
+		pack   occam.Pack
+		docker occam.Docker
+	)
+
+	it.Before(func() {
+		pack = occam.NewPack()
+		docker = occam.NewDocker()
+	})
+
+	context("when BP_GO_DEBUG_SYMBOLS is set", func() {
+		var (
+			image     occam.Image
+			container occam.Container
+
+			name      string
+			debugName string
+			source    string
+		)
+
+		it.Before(func() {
+			var err error
+			name, err = occam.RandomName()
+			Expect(err).NotTo(HaveOccurred())
+			debugName = name + "-debug"
+
+			source, err = occam.Source(filepath.Join("testdata", "build"))
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		it.After(func() {
+			if container.ID != "" {
+				Expect(docker.Container.Remove.Execute(container.ID)).To(Succeed())
+			}
+			if image.ID != "" {
+				Expect(docker.Image.Remove.Execute(image.ID)).To(Succeed())
+				Expect(docker.Image.Remove.Execute(debugName)).To(Succeed())
+			}
+			Expect(docker.Volume.Remove.Execute(occam.CacheVolumeNames(name))).To(Succeed())
+			Expect(os.RemoveAll(source)).To(Succeed())
+		})
+
+		// buildID reads a file out of the image and reports its build ID with
+		// the go command, independently of the debugsymbols package.
+		buildID := func(path string) (string, string) {
+			content, err := imageFile(image.ID, path)
+			Expect(err).NotTo(HaveOccurred())
+
+			file := filepath.Join(t.TempDir(), filepath.Base(path))
+			Expect(os.WriteFile(file, content, 0644)).To(Succeed())
+
+			output, err := exec.Command("go", "tool", "buildid", file).Output()
+			Expect(err).NotTo(HaveOccurred())
+
+			return strings.TrimSpace(string(output)), file
+		}
+
+		it("strips the binary and exports symbols that match its build ID", func() {
+			var err error
+			var logs fmt.Stringer
+			image, logs, err = executeBuild(t, pack.WithNoColor().Build.
+				WithBuilder(builder).
+				WithBuildpacks(goBuildpack).
+				WithPullPolicy("never").
+				WithEnv(map[string]string{debugsymbols.Env: "true"}),
+				name, source)
+			Expect(err).NotTo(HaveOccurred(), logs.String())
+
+			Expect(logs).To(ContainLines(ContainSubstring("Paketo Buildpack for Go Debug Symbols")))
+			Expect(logs).To(ContainLines(ContainSubstring("Extracted the debug symbols of workspace")))
+
+			container, err = docker.Container.Run.
+				WithEnv(map[string]string{"PORT": "8080"}).
+				WithPublish("8080").
+				WithPublishAll().
+				Execute(image.ID)
+			Expect(err).NotTo(HaveOccurred())
+
+			Eventually(container).Should(Serve(ContainSubstring("Hello, World!")).OnPort(8080))
+
+			id, binary := buildID("/layers/paketo-buildpacks_go-build/targets/bin/workspace")
+
+			file, err := elf.Open(binary)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(file.Section(".debug_info")).To(BeNil())
+			Expect(file.Close()).To(Succeed())
+
+			content, err := imageFile(image.ID, debugsymbols.LaunchDir+"/"+debugsymbols.ManifestFile)
+			Expect(err).NotTo(HaveOccurred())
+
+			var manifest debugsymbols.Manifest
+			Expect(json.Unmarshal(content, &manifest)).To(Succeed())
+
+			symbols, ok := manifest.Find(id)
+			Expect(ok).To(BeTrue(), fmt.Sprintf("no symbols for build ID %s in %+v", id, manifest))
+			Expect(symbols.Binary).To(Equal("workspace"))
+
+			symbolsID, symbolFile := buildID(debugsymbols.LaunchDir + "/" + symbols.File)
+			Expect(symbolsID).To(Equal(id))
+
+			file, err = elf.Open(symbolFile)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(file.Section(".debug_info")).NotTo(BeNil())
+			Expect(file.Close()).To(Succeed())
+
+			Expect(image.Labels).To(HaveKeyWithValue(debugsymbols.BuildIDsLabel, id))
+
+			// the platform publishes the exported symbols as a debug image
+			ref, err := ggcrname.ParseReference(image.ID)
+			Expect(err).NotTo(HaveOccurred())
+
+			app, err := daemon.Image(ref)
+			Expect(err).NotTo(HaveOccurred())
+
+			dir := t.TempDir()
+			Expect(debugsymbols.Unpack(app, dir)).To(Succeed())
+
+			debugImage, err := debugsymbols.Image(dir)
+			Expect(err).NotTo(HaveOccurred())
+
+			tag, err := ggcrname.NewTag(debugName)
+			Expect(err).NotTo(HaveOccurred())
+			_, err = daemon.Write(tag, debugImage)
+			Expect(err).NotTo(HaveOccurred())
+
+			config, err := debugImage.ConfigFile()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(config.Config.Labels).To(HaveKeyWithValue(debugsymbols.BuildIDsLabel, id))
+		})
+	})
+}
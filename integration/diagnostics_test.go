@@ -0,0 +1,145 @@
+package integration_test
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/paketo-buildpacks/go/diagnostics"
+	"github.com/paketo-buildpacks/occam"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+	. "github.com/paketo-buildpacks/occam/matchers"
+)
+
+func testDiagnostics(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect     = NewWithT(t).Expect
+		Eventually = NewWithT(t).Eventually
+
+		pack   occam.Pack
+		docker occam.Docker
+	)
+
+	it.Before(func() {
+		pack = occam.NewPack()
+		docker = occam.NewDocker()
+	})
+
+	context("when the diagnostics listener is linked into the app", func() {
+		var (
+			image      occam.Image
+			containers []occam.Container
+
+			name   string
+			source string
+		)
+
+		it.Before(func() {
+			var err error
+			name, err = occam.RandomName()
+			Expect(err).NotTo(HaveOccurred())
+
+			source, err = occam.Source(filepath.Join("testdata", "build"))
+			Expect(err).NotTo(HaveOccurred())
+
+			var logs fmt.Stringer
+			image, logs, err = executeBuild(t, pack.WithNoColor().Build.
+				WithBuilder(builder).
+				WithBuildpacks(goBuildpack).
+				WithPullPolicy("never").
+				WithEnv(map[string]string{"BP_GO_DIAGNOSTICS": "true"}),
+				name, source)
+			Expect(err).NotTo(HaveOccurred(), logs.String())
+
+			Expect(logs).To(ContainLines(ContainSubstring("Paketo Buildpack for Go Diagnostics")))
+			Expect(logs).To(ContainLines(ContainSubstring("Linking the diagnostics listener into .")))
+			Expect(logs).To(ContainLines(MatchRegexp(`BP_GO_BUILD_FLAGS=-overlay=/layers/paketo-buildpacks_go-diagnostics/diagnostics/overlay\.json`)))
+		})
+
+		it.After(func() {
+			for _, container := range containers {
+				Expect(docker.Container.Remove.Execute(container.ID)).To(Succeed())
+			}
+			containers = nil
+			Expect(docker.Image.Remove.Execute(image.ID)).To(Succeed())
+			Expect(docker.Volume.Remove.Execute(occam.CacheVolumeNames(name))).To(Succeed())
+			Expect(os.RemoveAll(source)).To(Succeed())
+		})
+
+		get := func(url string) (int, string, error) {
+			response, err := http.Get(url)
+			if err != nil {
+				return 0, "", err
+			}
+			defer response.Body.Close()
+
+			content, err := io.ReadAll(response.Body)
+			return response.StatusCode, string(content), err
+		}
+
+		it("serves pprof on a separate port when enabled at launch", func() {
+			container, err := docker.Container.Run.
+				WithEnv(map[string]string{
+					"PORT":                 "8080",
+					diagnostics.EnabledEnv: "true",
+				}).
+				WithPublish("8080").
+				WithPublish("6060").
+				Execute(image.ID)
+			Expect(err).NotTo(HaveOccurred())
+			containers = append(containers, container)
+
+			Eventually(container).Should(Serve(ContainSubstring("Hello, World!")).OnPort(8080))
+
+			var heap string
+			Eventually(func() (int, error) {
+				status, content, err := get(fmt.Sprintf("http://%s:%s/debug/pprof/heap", container.Host(), container.HostPort("6060")))
+				heap = content
+				return status, err
+			}).Should(Equal(http.StatusOK))
+			Expect(heap).To(HavePrefix("\x1f\x8b"), "heap profiles are gzipped protobuf")
+
+			status, content, err := get(fmt.Sprintf("http://%s:%s/debug/vars", container.Host(), container.HostPort("6060")))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(status).To(Equal(http.StatusOK))
+			Expect(content).To(ContainSubstring(`"memstats"`))
+
+			// the handlers of the app are left alone
+			_, content, err = get(fmt.Sprintf("http://%s:%s/debug/pprof/heap", container.Host(), container.HostPort("8080")))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(content).To(Equal("Hello, World!"))
+		})
+
+		it("keeps the listener source and the overlay out of the image", func() {
+			for _, path := range []string{
+				"/workspace/zz_paketo_diagnostics.go",
+				"/workspace/.paketo-diagnostics/overlay.json",
+				"/layers/paketo-buildpacks_go-diagnostics/diagnostics/overlay.json",
+				"/layers/paketo-buildpacks_go-diagnostics/diagnostics/diagnostics.go",
+			} {
+				_, err := imageFile(image.ID, path)
+				Expect(err).To(MatchError(ContainSubstring("%s is not in image", path)))
+			}
+		})
+
+		it("does not listen unless enabled at launch", func() {
+			container, err := docker.Container.Run.
+				WithEnv(map[string]string{"PORT": "8080"}).
+				WithPublish("8080").
+				WithPublish("6060").
+				Execute(image.ID)
+			Expect(err).NotTo(HaveOccurred())
+			containers = append(containers, container)
+
+			Eventually(container).Should(Serve(ContainSubstring("Hello, World!")).OnPort(8080))
+
+			_, _, err = get(fmt.Sprintf("http://%s:%s/debug/pprof/heap", container.Host(), container.HostPort("6060")))
+			Expect(err).To(HaveOccurred())
+		})
+	})
+}
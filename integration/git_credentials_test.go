@@ -0,0 +1,126 @@
+package integration_test
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/paketo-buildpacks/occam"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+	. "github.com/paketo-buildpacks/occam/matchers"
+)
+
+func testGitCredentials(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect     = NewWithT(t).Expect
+		Eventually = NewWithT(t).Eventually
+
+		pack   occam.Pack
+		docker occam.Docker
+	)
+
+	it.Before(func() {
+		pack = occam.NewPack()
+		docker = occam.NewDocker()
+	})
+
+	context("when the app depends on a module in a private git repository", func() {
+		var (
+			image     occam.Image
+			container occam.Container
+
+			name     string
+			network  string
+			source   string
+			bindings string
+			server   *gitServer
+			build    occam.PackBuild
+		)
+
+		it.Before(func() {
+			var err error
+			name, err = occam.RandomName()
+			Expect(err).NotTo(HaveOccurred())
+
+			source, err = occam.Source(filepath.Join("testdata", "git_credentials"))
+			Expect(err).NotTo(HaveOccurred())
+
+			network = name + "-network"
+			output, err := exec.Command("docker", "network", "create", network).CombinedOutput()
+			Expect(err).NotTo(HaveOccurred(), string(output))
+
+			server, err = startGitServer(t, network, filepath.Join("testdata", "git_credentials", "repositories"), "some-user", "some-password")
+			Expect(err).NotTo(HaveOccurred())
+
+			bindings = t.TempDir()
+			Expect(os.Chmod(bindings, 0755)).To(Succeed())
+			Expect(os.MkdirAll(filepath.Join(bindings, "git-credentials"), 0755)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(bindings, "git-credentials", "type"), []byte("git-credentials"), 0644)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(bindings, "git-credentials", "credentials"), []byte("username=some-user\npassword=some-password\n"), 0644)).To(Succeed())
+
+			// The module is imported as git.paketo.test/private/greeting.git, and
+			// git rewrites that host to the server, which the build reaches
+			// through the network they share
+			build = pack.WithNoColor().Build.
+				WithBuilder(builder).
+				WithBuildpacks(goBuildpack).
+				WithPullPolicy("never").
+				WithNetwork(network).
+				WithEnv(map[string]string{
+					"GOPRIVATE":          "git.paketo.test",
+					"GIT_CONFIG_COUNT":   "1",
+					"GIT_CONFIG_KEY_0":   fmt.Sprintf("url.%s.insteadOf", server.URL),
+					"GIT_CONFIG_VALUE_0": "https://git.paketo.test/private/",
+				})
+		})
+
+		it.After(func() {
+			Expect(server.Close()).To(Succeed())
+			if container.ID != "" {
+				Expect(docker.Container.Remove.Execute(container.ID)).To(Succeed())
+			}
+			if image.ID != "" {
+				Expect(docker.Image.Remove.Execute(image.ID)).To(Succeed())
+			}
+			Expect(docker.Volume.Remove.Execute(occam.CacheVolumeNames(name))).To(Succeed())
+
+			output, err := exec.Command("docker", "network", "rm", network).CombinedOutput()
+			Expect(err).NotTo(HaveOccurred(), string(output))
+			Expect(os.RemoveAll(source)).To(Succeed())
+		})
+
+		it("fetches the module with the git-credentials binding", func() {
+			var err error
+			var logs fmt.Stringer
+			image, logs, err = executeBuild(t, build.
+				WithAdditionalEnv(map[string]string{"SERVICE_BINDING_ROOT": "/bindings"}).
+				WithVolumes(fmt.Sprintf("%s:/bindings", bindings)),
+				name, filepath.Join(source, "app"))
+			Expect(err).NotTo(HaveOccurred(), logs.String())
+
+			Expect(logs).To(ContainLines(ContainSubstring("Buildpack for Git")))
+			Expect(logs).To(ContainLines(ContainSubstring("Buildpack for Go Mod Vendor")))
+
+			container, err = docker.Container.Run.
+				WithEnv(map[string]string{"PORT": "8080"}).
+				WithPublish("8080").
+				WithPublishAll().
+				Execute(image.ID)
+			Expect(err).NotTo(HaveOccurred())
+
+			Eventually(container).Should(Serve(ContainSubstring("Hello from a private module!")).OnPort(8080))
+		})
+
+		it("fails to fetch the module without the binding", func() {
+			_, logs, err := executeBuild(t, build, name, filepath.Join(source, "app"))
+			Expect(err).To(HaveOccurred(), logs.String())
+
+			Expect(logs).To(ContainLines(ContainSubstring("Buildpack for Go Mod Vendor")))
+			Expect(logs).To(ContainLines(ContainSubstring("git.paketo.test/private/greeting.git: no secure protocol found for repository")))
+		})
+	})
+}
@@ -0,0 +1,124 @@
+package integration_test
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/paketo-buildpacks/occam"
+)
+
+// gitServer is a container that serves git repositories over smart HTTP and
+// requires basic authentication, as a stand-in for a private git host.
+type gitServer struct {
+	// URL is the base URL of the server on its network, such as
+	// http://172.18.0.2:8080/.
+	URL string
+
+	container occam.Container
+	image     string
+	docker    occam.Docker
+}
+
+// startGitServer builds the server image of testdata/git_server and runs it
+// on a user-defined network, serving the repositories created by
+// createRepositories. Builds reach the server by joining the network.
+func startGitServer(t *testing.T, network, repositories, username, password string) (*gitServer, error) {
+	t.Helper()
+
+	root, err := createRepositories(t, repositories)
+	if err != nil {
+		return nil, err
+	}
+
+	// the server reads the repositories as another user
+	err = os.Chmod(root, 0755)
+	if err != nil {
+		return nil, err
+	}
+
+	image, err := occam.RandomName()
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := exec.Command("docker", "build", "--tag", image, filepath.Join("testdata", "git_server")).CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build the git server: %w\n%s", err, output)
+	}
+
+	docker := occam.NewDocker()
+	container, err := docker.Container.Run.
+		WithNetwork(network).
+		WithEnv(map[string]string{
+			"GIT_USERNAME": username,
+			"GIT_PASSWORD": password,
+		}).
+		WithVolume(fmt.Sprintf("%s:/srv/git:ro", root)).
+		Execute(image)
+	if err != nil {
+		return nil, errors.Join(err, docker.Image.Remove.Execute(image))
+	}
+
+	return &gitServer{
+		URL:       fmt.Sprintf("http://%s:8080/", container.IPAddresses[network]),
+		container: container,
+		image:     image,
+		docker:    docker,
+	}, nil
+}
+
+// Close removes the container and the image of the server.
+func (s *gitServer) Close() error {
+	err := s.docker.Container.Remove.Execute(s.container.ID)
+	if err != nil {
+		return err
+	}
+
+	return s.docker.Image.Remove.Execute(s.image)
+}
+
+// createRepositories turns every directory of repositories into a bare
+// repository named after the directory, with ".git" appended, and returns the
+// directory that holds them. The content of each directory is committed and
+// tagged v1.0.0.
+func createRepositories(t *testing.T, repositories string) (string, error) {
+	t.Helper()
+
+	root := t.TempDir()
+
+	entries, err := os.ReadDir(repositories)
+	if err != nil {
+		return "", err
+	}
+
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		work := filepath.Join(t.TempDir(), entry.Name())
+		err = os.CopyFS(work, os.DirFS(filepath.Join(repositories, entry.Name())))
+		if err != nil {
+			return "", err
+		}
+
+		for _, args := range [][]string{
+			{"init", "--quiet", "--initial-branch", "main"},
+			{"add", "."},
+			{"-c", "user.name=paketo", "-c", "user.email=paketo@example.com", "commit", "--quiet", "--message", "initial commit"},
+			{"tag", "v1.0.0"},
+			{"clone", "--quiet", "--bare", ".", filepath.Join(root, entry.Name()+".git")},
+		} {
+			output, err := exec.Command("git", append([]string{"-C", work}, args...)...).CombinedOutput()
+			if err != nil {
+				return "", fmt.Errorf("failed to run git %v: %w\n%s", args, err, output)
+			}
+		}
+	}
+
+	return root, nil
+}
@@ -3,13 +3,16 @@ package integration_test
 import (
 	"crypto/tls"
 	"crypto/x509"
+	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/paketo-buildpacks/go/sbom"
 	"github.com/paketo-buildpacks/occam"
 	"github.com/sclevine/spec"
 
@@ -65,11 +68,12 @@ func testGoMod(t *testing.T, context spec.G, it spec.S) {
 		it("creates a working OCI image", func() {
 			var err error
 			var logs fmt.Stringer
-			image, logs, err = pack.WithNoColor().Build.
+			image, logs, err = executeBuild(t, pack.WithNoColor().Build.
+				WithBuilder(builder).
 				WithBuildpacks(goBuildpack).
 				WithPullPolicy("never").
-				WithSBOMOutputDir(sbomDir).
-				Execute(name, source)
+				WithSBOMOutputDir(sbomDir),
+				name, source)
 			Expect(err).NotTo(HaveOccurred(), logs.String())
 
 			container, err = docker.Container.Run.
@@ -84,6 +88,7 @@ func testGoMod(t *testing.T, context spec.G, it spec.S) {
 			Expect(logs).To(ContainLines(ContainSubstring("Buildpack for Go Distribution")))
 			Expect(logs).To(ContainLines(ContainSubstring("Buildpack for Go Mod Vendor")))
 			Expect(logs).To(ContainLines(ContainSubstring("Buildpack for Go Build")))
+			Expect(logs).To(ContainLines(ContainSubstring("Buildpack for SBOM Aggregation")))
 
 			Expect(logs).NotTo(ContainLines(ContainSubstring("Buildpack for Procfile")))
 			Expect(logs).NotTo(ContainLines(ContainSubstring("Buildpack for Environment Variables")))
@@ -112,6 +117,38 @@ func testGoMod(t *testing.T, context spec.G, it spec.S) {
 			contents, err = os.ReadFile(filepath.Join(sbomDir, "sbom", "launch", "paketo-buildpacks_go-build", "targets", "sbom.cdx.json"))
 			Expect(err).NotTo(HaveOccurred())
 			Expect(string(contents)).To(ContainSubstring(`"name": "github.com/BurntSushi/toml"`))
+
+			// check that every SBOM file matches the schema for its version
+			validateSBOMs(t, filepath.Join(sbomDir, "sbom"))
+
+			// check that the SBOMs record the Go version installed by go-dist
+			version := goDistVersion(t, logs.String())
+			Expect(cycloneDXVersions(t, filepath.Join(sbomDir, "sbom", "build", "paketo-buildpacks_go-dist", "go", "sbom.cdx.json"), "go")).To(ContainElement(version))
+			Expect(cycloneDXVersions(t, filepath.Join(sbomDir, "sbom", "launch", "paketo-buildpacks_go-build", "targets", "sbom.cdx.json"), "stdlib")).To(ContainElement(version))
+
+			// check that every module in go.sum is recorded at its locked version
+			for module, moduleVersion := range goSumModules(t, filepath.Join("testdata", "go_mod", "go.sum")) {
+				Expect(cycloneDXVersions(t, filepath.Join(sbomDir, "sbom", "build", "paketo-buildpacks_go-mod-vendor", "sbom.cdx.json"), module)).To(ContainElement(strings.TrimPrefix(moduleVersion, "v")), module)
+			}
+
+			// check that the image carries a merged launch SBOM that lists
+			// every module exactly once
+			Expect(filepath.Join(sbomDir, "sbom", "launch", "paketo-buildpacks_sbom-aggregator", "sbom.cdx.json")).To(BeARegularFile())
+			Expect(filepath.Join(sbomDir, "sbom", "launch", "paketo-buildpacks_sbom-aggregator", "sbom.spdx.json")).To(BeARegularFile())
+
+			contents, err = os.ReadFile(filepath.Join(sbomDir, "sbom", "launch", "paketo-buildpacks_sbom-aggregator", "sbom.cdx.json"))
+			Expect(err).NotTo(HaveOccurred())
+
+			var cyclonedx sbom.CycloneDXDocument
+			Expect(json.Unmarshal(contents, &cyclonedx)).To(Succeed())
+			Expect(countNamed(cyclonedx.Components, "github.com/BurntSushi/toml")).To(Equal(1))
+
+			contents, err = os.ReadFile(filepath.Join(sbomDir, "sbom", "launch", "paketo-buildpacks_sbom-aggregator", "sbom.spdx.json"))
+			Expect(err).NotTo(HaveOccurred())
+
+			var spdx sbom.SPDXDocument
+			Expect(json.Unmarshal(contents, &spdx)).To(Succeed())
+			Expect(countNamed(spdx.Packages, "github.com/BurntSushi/toml")).To(Equal(1))
 		})
 
 		context("when using utility buildpacks", func() {
@@ -129,7 +166,8 @@ func testGoMod(t *testing.T, context spec.G, it spec.S) {
 			it("builds a working OCI image with start command from the Procfile and incorporating the utility buildpacks' effects", func() {
 				var err error
 				var logs fmt.Stringer
-				image, logs, err = pack.WithNoColor().Build.
+				image, logs, err = executeBuild(t, pack.WithNoColor().Build.
+					WithBuilder(builder).
 					WithBuildpacks(goBuildpack).
 					WithPullPolicy("never").
 					WithEnv(map[string]string{
@@ -138,12 +176,13 @@ func testGoMod(t *testing.T, context spec.G, it spec.S) {
 						"BP_LIVE_RELOAD_ENABLED": "true",
 						"SERVICE_BINDING_ROOT":   "/bindings",
 					}).
-					WithVolumes(fmt.Sprintf("%s:/bindings/git-credentials", filepath.Join(source, "git-credentials"))).
-					Execute(name, source)
+					WithVolumes(fmt.Sprintf("%s:/bindings/git-credentials", filepath.Join(source, "git-credentials"))),
+					name, source)
 				Expect(err).NotTo(HaveOccurred(), logs.String())
 
-				Expect(image.Buildpacks[7].Key).To(Equal("paketo-buildpacks/environment-variables"))
-				Expect(image.Buildpacks[7].Layers["environment-variables"].Metadata["variables"]).To(Equal(map[string]interface{}{"SOME_VARIABLE": "some-value"}))
+				environmentVariables, err := image.BuildpackForKey("paketo-buildpacks/environment-variables")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(environmentVariables.Layers["environment-variables"].Metadata["variables"]).To(Equal(map[string]interface{}{"SOME_VARIABLE": "some-value"}))
 				Expect(image.Labels["some-label"]).To(Equal("some-value"))
 
 				Expect(logs).To(ContainLines(ContainSubstring("Buildpack for Go Distribution")))
@@ -210,11 +249,12 @@ func testGoMod(t *testing.T, context spec.G, it spec.S) {
 			it("builds a working OCI image and uses a client-side CA cert for requests", func() {
 				var err error
 				var logs fmt.Stringer
-				image, logs, err = pack.WithNoColor().Build.
+				image, logs, err = executeBuild(t, pack.WithNoColor().Build.
+					WithBuilder(builder).
 					WithBuildpacks(goBuildpack).
 					WithPullPolicy("never").
-					WithEnv(map[string]string{"BP_KEEP_FILES": "key.pem:cert.pem"}).
-					Execute(name, filepath.Join(source, "go_mod"))
+					WithEnv(map[string]string{"BP_KEEP_FILES": "key.pem:cert.pem"}),
+					name, filepath.Join(source, "go_mod"))
 				Expect(err).NotTo(HaveOccurred())
 
 				Expect(logs).To(ContainLines(ContainSubstring("Buildpack for CA Certificates")))
@@ -292,10 +332,11 @@ func testGoMod(t *testing.T, context spec.G, it spec.S) {
 		it("creates a working OCI image", func() {
 			var err error
 			var logs fmt.Stringer
-			image, logs, err = pack.WithNoColor().Build.
+			image, logs, err = executeBuild(t, pack.WithNoColor().Build.
+				WithBuilder(builder).
 				WithBuildpacks(goBuildpack).
-				WithPullPolicy("never").
-				Execute(name, source)
+				WithPullPolicy("never"),
+				name, source)
 			Expect(err).NotTo(HaveOccurred(), logs.String())
 
 			container, err = docker.Container.Run.
@@ -320,15 +361,16 @@ func testGoMod(t *testing.T, context spec.G, it spec.S) {
 		it("when using git utility buildpack", func() {
 			var err error
 			var logs fmt.Stringer
-			image, logs, err = pack.WithNoColor().Build.
+			image, logs, err = executeBuild(t, pack.WithNoColor().Build.
+				WithBuilder(builder).
 				WithBuildpacks(goBuildpack).
 				WithEnv(map[string]string{
 					"PORT":                 "8080",
 					"SERVICE_BINDING_ROOT": "/bindings",
 				}).
 				WithVolumes(fmt.Sprintf("%s:/bindings/git-credentials", filepath.Join(source, "git-credentials"))).
-				WithPullPolicy("never").
-				Execute(name, source)
+				WithPullPolicy("never"),
+				name, source)
 			Expect(err).NotTo(HaveOccurred(), logs.String())
 
 			container, err = docker.Container.Run.
@@ -352,4 +394,74 @@ func testGoMod(t *testing.T, context spec.G, it spec.S) {
 			Expect(logs).NotTo(ContainLines(ContainSubstring("Buildpack for Image Labels")))
 		})
 	})
+
+	context("when building a go mod app with the race detector", func() {
+		var (
+			image     occam.Image
+			container occam.Container
+
+			name   string
+			source string
+		)
+
+		it.Before(func() {
+			var err error
+			name, err = occam.RandomName()
+			Expect(err).NotTo(HaveOccurred())
+
+			source, err = occam.Source(filepath.Join("testdata", "go_mod"))
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		it.After(func() {
+			if container.ID != "" {
+				Expect(docker.Container.Remove.Execute(container.ID)).To(Succeed())
+			}
+			if image.ID != "" {
+				Expect(docker.Image.Remove.Execute(image.ID)).To(Succeed())
+			}
+			Expect(docker.Volume.Remove.Execute(occam.CacheVolumeNames(name))).To(Succeed())
+			Expect(os.RemoveAll(source)).To(Succeed())
+		})
+
+		it("creates a working OCI image", func() {
+			skipOnBuilders(t, "race detector binaries link against the C library, which tiny run images do not ship", "tiny")
+
+			var err error
+			var logs fmt.Stringer
+			image, logs, err = executeBuild(t, pack.WithNoColor().Build.
+				WithBuilder(builder).
+				WithBuildpacks(goBuildpack).
+				WithPullPolicy("never").
+				WithEnv(map[string]string{
+					"BP_GO_BUILD_FLAGS": "-race",
+					"CGO_ENABLED":       "1",
+				}),
+				name, source)
+			Expect(err).NotTo(HaveOccurred(), logs.String())
+
+			container, err = docker.Container.Run.
+				WithEnv(map[string]string{"PORT": "8080"}).
+				WithPublish("8080").
+				WithPublishAll().
+				Execute(image.ID)
+			Expect(err).NotTo(HaveOccurred())
+
+			Eventually(container).Should(Serve(ContainSubstring("Hello, World!")).OnPort(8080))
+
+			Expect(logs).To(ContainLines(ContainSubstring("-race")))
+		})
+	})
+}
+
+// countNamed counts the SBOM components or packages with the given name.
+func countNamed(objects []map[string]any, name string) int {
+	var count int
+	for _, object := range objects {
+		if object["name"] == name {
+			count++
+		}
+	}
+
+	return count
 }
@@ -0,0 +1,109 @@
+package integration_test
+
+import (
+	"debug/buildinfo"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/paketo-buildpacks/go/goreleaser"
+	"github.com/paketo-buildpacks/occam"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+	. "github.com/paketo-buildpacks/occam/matchers"
+)
+
+func testGoReleaser(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect     = NewWithT(t).Expect
+		Eventually = NewWithT(t).Eventually
+
+		pack   occam.Pack
+		docker occam.Docker
+	)
+
+	it.Before(func() {
+		pack = occam.NewPack()
+		docker = occam.NewDocker()
+	})
+
+	context("when the app is released with GoReleaser", func() {
+		var (
+			image     occam.Image
+			container occam.Container
+
+			name   string
+			source string
+		)
+
+		it.Before(func() {
+			var err error
+			name, err = occam.RandomName()
+			Expect(err).NotTo(HaveOccurred())
+
+			source, err = occam.Source(filepath.Join("testdata", "goreleaser"))
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		it.After(func() {
+			Expect(docker.Container.Remove.Execute(container.ID)).To(Succeed())
+			Expect(docker.Image.Remove.Execute(image.ID)).To(Succeed())
+			Expect(docker.Volume.Remove.Execute(occam.CacheVolumeNames(name))).To(Succeed())
+			Expect(os.RemoveAll(source)).To(Succeed())
+		})
+
+		it("builds the binary with the flags of its GoReleaser build without any extra step", func() {
+			var err error
+			var logs fmt.Stringer
+			image, logs, err = executeBuild(t, pack.WithNoColor().Build.
+				WithBuilder(builder).
+				WithBuildpacks(goBuildpack).
+				WithPullPolicy("never").
+				WithEnv(map[string]string{
+					goreleaser.EnabledEnv: "true",
+					goreleaser.TagEnv:     "v1.2.3",
+					goreleaser.CommitEnv:  "0123456789abcdef0123456789abcdef01234567",
+				}),
+				name, source)
+			Expect(err).NotTo(HaveOccurred(), logs.String())
+
+			Expect(logs).To(ContainLines(ContainSubstring("Paketo Buildpack for Go GoReleaser")))
+			Expect(logs).To(ContainLines(ContainSubstring("Translating build server of .goreleaser.yaml for v1.2.3")))
+			Expect(logs).To(ContainLines(ContainSubstring("BP_GO_TARGETS=./cmd/server")))
+			Expect(logs).To(ContainLines(ContainSubstring("BP_GO_BUILD_FLAGS=-trimpath -tags=netgo")))
+			Expect(logs).To(ContainLines(ContainSubstring("BP_GO_BUILD_LDFLAGS=-s -w -X main.version=1.2.3 -X main.commit=0123456")))
+			Expect(logs).To(ContainLines(ContainSubstring("CGO_ENABLED=0")))
+
+			container, err = docker.Container.Run.
+				WithEnv(map[string]string{"PORT": "8080"}).
+				WithPublish("8080").
+				WithPublishAll().
+				Execute(image.ID)
+			Expect(err).NotTo(HaveOccurred())
+
+			Eventually(container).Should(Serve(ContainSubstring("Hello from version 1.2.3 (0123456)!")).OnPort(8080))
+
+			binary := filepath.Join(t.TempDir(), "server")
+			Expect(docker.Container.Copy.Execute(fmt.Sprintf("%s:/layers/paketo-buildpacks_go-build/targets/bin/server", container.ID), binary)).To(Succeed())
+
+			info, err := buildinfo.ReadFile(binary)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(info.Path).To(Equal("goreleaser-app/cmd/server"))
+
+			settings := map[string]string{}
+			for _, setting := range info.Settings {
+				settings[setting.Key] = setting.Value
+			}
+			Expect(settings).To(HaveKeyWithValue("-trimpath", "true"))
+			Expect(settings).To(HaveKeyWithValue("-tags", "netgo"))
+			Expect(settings).To(HaveKeyWithValue("CGO_ENABLED", "0"))
+			Expect(settings).To(HaveKeyWithValue("GOOS", "linux"))
+
+			// go does not record -ldflags for -trimpath builds, which is why the
+			// version stamped by the ldflags is checked through the app instead
+			Expect(settings).NotTo(HaveKey("-ldflags"))
+		})
+	})
+}
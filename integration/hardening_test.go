@@ -0,0 +1,139 @@
+package integration_test
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/paketo-buildpacks/go/hardening"
+	"github.com/paketo-buildpacks/occam"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+	. "github.com/paketo-buildpacks/occam/matchers"
+)
+
+func testHardening(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect     = NewWithT(t).Expect
+		Eventually = NewWithT(t).Eventually
+
+		pack   occam.Pack
+		docker occam.Docker
+	)
+
+	it.Before(func() {
+		pack = occam.NewPack()
+		docker = occam.NewDocker()
+	})
+
+	context("when the image runs on a read-only root filesystem", func() {
+		var (
+			image     occam.Image
+			container occam.Container
+
+			name   string
+			source string
+		)
+
+		it.Before(func() {
+			var err error
+			name, err = occam.RandomName()
+			Expect(err).NotTo(HaveOccurred())
+
+			source, err = occam.Source(filepath.Join("testdata", "hardening"))
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		it.After(func() {
+			if container.ID != "" {
+				Expect(docker.Container.Remove.Execute(container.ID)).To(Succeed())
+			}
+			if image.ID != "" {
+				Expect(docker.Image.Remove.Execute(image.ID)).To(Succeed())
+			}
+			Expect(docker.Volume.Remove.Execute(occam.CacheVolumeNames(name))).To(Succeed())
+			Expect(os.RemoveAll(source)).To(Succeed())
+		})
+
+		build := func(env map[string]string) (fmt.Stringer, error) {
+			var err error
+			var logs fmt.Stringer
+			image, logs, err = executeBuild(t, pack.WithNoColor().Build.
+				WithBuilder(builder).
+				WithBuildpacks(goBuildpack).
+				WithPullPolicy("never").
+				WithEnv(env),
+				name, source)
+			return logs, err
+		}
+
+		it("runs as a non-root user and writes to the writable directory only", func() {
+			logs, err := build(map[string]string{
+				hardening.EnabledEnv:     "true",
+				hardening.WritableDirEnv: "/var/run/app",
+				"BP_KEEP_FILES":          "config.yml",
+			})
+			Expect(err).NotTo(HaveOccurred(), logs.String())
+
+			Expect(logs).To(ContainLines(ContainSubstring("Paketo Buildpack for Go Hardening")))
+			Expect(logs).To(ContainLines(ContainSubstring("No kept file needs write access")))
+			Expect(logs).To(ContainLines(ContainSubstring("TMPDIR -> /var/run/app")))
+
+			config, err := imageConfig(name)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(hardening.VerifyUser(*config)).To(Succeed())
+
+			container, err = docker.Container.Run.
+				WithEnv(map[string]string{"PORT": "8080"}).
+				WithPublish("8080").
+				WithReadOnly().
+				WithMounts("type=tmpfs,destination=/var/run/app").
+				Execute(image.ID)
+			Expect(err).NotTo(HaveOccurred())
+
+			Eventually(container).Should(Serve(MatchRegexp(`^Hello from uid [1-9]\d*, wrote /var/run/app/request-\d+, read greeting: hello$`)).OnPort(8080))
+		})
+
+		it("cannot write without the writable directory", func() {
+			logs, err := build(map[string]string{"BP_KEEP_FILES": "config.yml"})
+			Expect(err).NotTo(HaveOccurred(), logs.String())
+			Expect(logs).NotTo(ContainLines(ContainSubstring("Paketo Buildpack for Go Hardening")))
+
+			container, err = docker.Container.Run.
+				WithEnv(map[string]string{"PORT": "8080"}).
+				WithPublish("8080").
+				WithReadOnly().
+				Execute(image.ID)
+			Expect(err).NotTo(HaveOccurred())
+
+			Eventually(func() (string, error) {
+				response, err := http.Get(fmt.Sprintf("http://%s:%s", container.Host(), container.HostPort("8080")))
+				if err != nil {
+					return "", err
+				}
+				defer response.Body.Close()
+
+				content, err := io.ReadAll(response.Body)
+				return string(content), err
+			}).Should(ContainSubstring("read-only file system"))
+		})
+
+		it("fails the build when a kept file would need write access", func() {
+			Expect(os.WriteFile(filepath.Join(source, "app.log"), nil, 0644)).To(Succeed())
+
+			logs, err := build(map[string]string{
+				hardening.EnabledEnv: "true",
+				"BP_KEEP_FILES":      "config.yml:*.log",
+			})
+			Expect(err).To(HaveOccurred(), logs.String())
+
+			Expect(logs).To(ContainLines(ContainSubstring("BP_KEEP_FILES keeps files that would need write access on a read-only root filesystem:")))
+			Expect(logs).To(ContainLines(ContainSubstring("app.log looks like a file the app writes to (*.log)")))
+			Expect(logs).NotTo(ContainLines(ContainSubstring("Paketo Buildpack for Go Build")))
+		})
+	})
+}
@@ -0,0 +1,165 @@
+package integration_test
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/paketo-buildpacks/go/health"
+	"github.com/paketo-buildpacks/occam"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+	. "github.com/paketo-buildpacks/occam/matchers"
+)
+
+func testHealth(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect     = NewWithT(t).Expect
+		Eventually = NewWithT(t).Eventually
+
+		pack   occam.Pack
+		docker occam.Docker
+	)
+
+	it.Before(func() {
+		pack = occam.NewPack()
+		docker = occam.NewDocker()
+	})
+
+	context("when BP_GO_HEALTH_CHECK_TARGET is set", func() {
+		const probe = "/layers/paketo-buildpacks_go-health/probe/bin/health-probe"
+
+		var (
+			image      occam.Image
+			containers []occam.Container
+
+			name        string
+			healthImage string
+			source      string
+		)
+
+		it.Before(func() {
+			var err error
+			name, err = occam.RandomName()
+			Expect(err).NotTo(HaveOccurred())
+
+			source, err = occam.Source(filepath.Join("testdata", "build"))
+			Expect(err).NotTo(HaveOccurred())
+
+			var logs fmt.Stringer
+			image, logs, err = executeBuild(t, pack.WithNoColor().Build.
+				WithBuilder(builder).
+				WithBuildpacks(goBuildpack).
+				WithPullPolicy("never").
+				WithEnv(map[string]string{
+					health.BuildTargetEnv:   "http://localhost:8080/",
+					health.BuildIntervalEnv: "1s",
+					health.BuildTimeoutEnv:  "1s",
+					health.BuildRetriesEnv:  "3",
+				}),
+				name, source)
+			Expect(err).NotTo(HaveOccurred(), logs.String())
+
+			Expect(logs).To(ContainLines(ContainSubstring("Paketo Buildpack for Go Health")))
+			Expect(logs).To(ContainLines(ContainSubstring("Assigning process type %s to probe http://localhost:8080/", health.ProcessType)))
+		})
+
+		it.After(func() {
+			for _, container := range containers {
+				Expect(docker.Container.Remove.Execute(container.ID)).To(Succeed())
+			}
+			containers = nil
+			if healthImage != "" {
+				Expect(docker.Image.Remove.Execute(healthImage)).To(Succeed())
+				healthImage = ""
+			}
+			Expect(docker.Image.Remove.Execute(image.ID)).To(Succeed())
+			Expect(docker.Volume.Remove.Execute(occam.CacheVolumeNames(name))).To(Succeed())
+			Expect(os.RemoveAll(source)).To(Succeed())
+		})
+
+		// runProbe runs the health process type of the image in the network
+		// namespace of a container and returns its output and whether it
+		// passed.
+		runProbe := func(container occam.Container, env map[string]string) (string, bool) {
+			arguments := []string{"run", "--rm", "--network", "container:" + container.ID, "--entrypoint", "/cnb/process/" + health.ProcessType}
+			for key, value := range env {
+				arguments = append(arguments, "--env", fmt.Sprintf("%s=%s", key, value))
+			}
+			arguments = append(arguments, image.ID)
+
+			output, err := exec.Command("docker", arguments...).CombinedOutput()
+			return string(output), err == nil
+		}
+
+		it("ships the probe with a health process type and labels the health check", func() {
+			Expect(imageProcessTypes(image)).To(ContainElement(health.ProcessType))
+
+			content, err := imageFile(image.ID, probe)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(content).NotTo(BeEmpty())
+
+			config, err := imageConfig(image.ID)
+			Expect(err).NotTo(HaveOccurred())
+
+			metadata, err := health.ParseMetadata(config.Config.Labels[health.Label])
+			Expect(err).NotTo(HaveOccurred())
+			Expect(metadata).To(Equal(health.Metadata{
+				Probe:    probe,
+				Target:   "http://localhost:8080/",
+				Interval: "1s",
+				Timeout:  "1s",
+				Retries:  3,
+			}))
+		})
+
+		it("probes the serving container with the health process type", func() {
+			container, err := docker.Container.Run.
+				WithEnv(map[string]string{"PORT": "8080"}).
+				WithPublish("8080").
+				Execute(image.ID)
+			Expect(err).NotTo(HaveOccurred())
+			containers = append(containers, container)
+
+			Eventually(container).Should(Serve(ContainSubstring("Hello, World!")).OnPort(8080))
+
+			output, ok := runProbe(container, nil)
+			Expect(ok).To(BeTrue(), output)
+			Expect(output).To(ContainSubstring("http://localhost:8080/ is healthy"))
+
+			output, ok = runProbe(container, map[string]string{health.TargetEnv: "tcp://localhost:8080"})
+			Expect(ok).To(BeTrue(), output)
+			Expect(output).To(ContainSubstring("tcp://localhost:8080 is healthy"))
+
+			output, ok = runProbe(container, map[string]string{health.TargetEnv: "tcp://localhost:9999"})
+			Expect(ok).To(BeFalse(), output)
+			Expect(output).To(ContainSubstring("connection refused"))
+		})
+
+		it("becomes healthy in Docker once the health check label is applied", func() {
+			healthImage = name + "-health"
+			output, err := exec.Command("docker", "tag", image.ID, healthImage).CombinedOutput()
+			Expect(err).NotTo(HaveOccurred(), string(output))
+
+			output, err = exec.Command("go", "run", "../cmd/health", "apply", "--image", healthImage).CombinedOutput()
+			Expect(err).NotTo(HaveOccurred(), string(output))
+
+			container, err := docker.Container.Run.
+				WithEnv(map[string]string{"PORT": "8080"}).
+				WithPublish("8080").
+				Execute(healthImage)
+			Expect(err).NotTo(HaveOccurred())
+			containers = append(containers, container)
+
+			Eventually(func() (string, error) {
+				output, err := exec.Command("docker", "inspect", "--format", "{{.State.Health.Status}}", container.ID).CombinedOutput()
+				return strings.TrimSpace(string(output)), err
+			}, 30*time.Second, time.Second).Should(Equal("healthy"))
+		})
+	})
+}
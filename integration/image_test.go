@@ -0,0 +1,85 @@
+package integration_test
+
+import (
+	"archive/tar"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	ggcrname "github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/daemon"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/paketo-buildpacks/occam"
+)
+
+// imageFile reads a file from the flattened filesystem of an image in the
+// Docker daemon, such as a file that a buildpack wrote to a launch layer.
+func imageFile(ref, path string) ([]byte, error) {
+	reference, err := ggcrname.ParseReference(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	image, err := daemon.Image(reference)
+	if err != nil {
+		return nil, err
+	}
+
+	filesystem := mutate.Extract(image)
+	defer filesystem.Close()
+
+	name := strings.TrimPrefix(path, "/")
+	reader := tar.NewReader(filesystem)
+	for {
+		header, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("%s is not in image %s", path, ref)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if strings.TrimPrefix(header.Name, "/") == name && header.Typeflag == tar.TypeReg {
+			return io.ReadAll(reader)
+		}
+	}
+}
+
+// imageConfig reads the config file of an image in the Docker daemon.
+func imageConfig(ref string) (*v1.ConfigFile, error) {
+	reference, err := ggcrname.ParseReference(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	image, err := daemon.Image(reference)
+	if err != nil {
+		return nil, err
+	}
+
+	return image.ConfigFile()
+}
+
+// imageProcessTypes lists the process types of an image, as the lifecycle
+// records them in the io.buildpacks.build.metadata label.
+func imageProcessTypes(image occam.Image) ([]string, error) {
+	var metadata struct {
+		Processes []struct {
+			Type string `json:"type"`
+		} `json:"processes"`
+	}
+	err := json.Unmarshal([]byte(image.Labels["io.buildpacks.build.metadata"]), &metadata)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the build metadata of image %s: %w", image.ID, err)
+	}
+
+	var types []string
+	for _, process := range metadata.Processes {
+		types = append(types, process.Type)
+	}
+
+	return types, nil
+}
@@ -1,33 +1,176 @@
 package integration_test
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/BurntSushi/toml"
 	"github.com/sclevine/spec"
-	"github.com/sclevine/spec/report"
 
 	. "github.com/onsi/gomega"
 )
 
-var goBuildpack string
+// BuildersEnv overrides the builders listed in integration.json with a comma
+// separated list of builders.
+const BuildersEnv = "INTEGRATION_BUILDERS"
+
+var (
+	goBuildpack string
+
+	// builder is the builder the suite is currently running against. Builders
+	// run one after another, so every spec of a suite sees the same builder.
+	builder string
+)
 
 func TestIntegration(t *testing.T) {
 	Expect := NewWithT(t).Expect
 
-	output, err := exec.Command("bash", "-c", "../scripts/package.sh --version 1.2.3").CombinedOutput()
-	Expect(err).NotTo(HaveOccurred(), string(output))
+	var err error
+	goBuildpack, err = packageBuildpack()
+	Expect(err).NotTo(HaveOccurred())
 
-	goBuildpack, err = filepath.Abs("../build/buildpackage.cnb")
+	builders, err := integrationBuilders("../integration.json")
 	Expect(err).NotTo(HaveOccurred())
 
 	SetDefaultEventuallyTimeout(10 * time.Second)
 
-	suite := spec.New("Integration", spec.Parallel(), spec.Report(report.Terminal{}))
-	suite("Build", testBuild)
-	suite("GoMod", testGoMod)
-	suite("ReproducibleBuilds", testReproducibleBuilds)
-	suite.Run(t)
+	var results []*builderResult
+	for _, b := range builders {
+		result := &builderResult{Builder: b}
+		results = append(results, result)
+
+		builder = b
+		t.Run(b, func(t *testing.T) {
+			suite := spec.New("Integration", spec.Parallel(), spec.Report(summaryReporter{result: result}))
+			suite("Bazel", recorded(testBazel))
+			suite("Build", recorded(testBuild))
+			suite("BuildTool", recorded(testBuildTool))
+			suite("DebugSymbols", recorded(testDebugSymbols))
+			suite("Diagnostics", recorded(testDiagnostics))
+			suite("GitCredentials", recorded(testGitCredentials))
+			suite("GoMod", recorded(testGoMod))
+			suite("GoReleaser", recorded(testGoReleaser))
+			suite("Hardening", recorded(testHardening))
+			suite("Health", recorded(testHealth))
+			suite("Licenses", recorded(testLicenses))
+			suite("NegativePaths", recorded(testNegativePaths))
+			suite("OfflinePackage", recorded(testOfflinePackage))
+			suite("OptimizeSize", recorded(testOptimizeSize))
+			suite("Otel", recorded(testOtel))
+			suite("Provenance", recorded(testProvenance))
+			suite("ReproducibleBuilds", recorded(testReproducibleBuilds))
+			suite("SSHKey", recorded(testSSHKey))
+			suite.Run(t)
+		})
+	}
+
+	fmt.Println("Builders:")
+	for _, result := range results {
+		fmt.Printf("  %s\n    Passed: %d | Failed: %d | Skipped: %d\n", result.Builder, result.Passed, result.Failed, result.Skipped)
+	}
+
+	if dir, ok := os.LookupEnv(ReportDirEnv); ok && dir != "" {
+		Expect(writeReports(dir, results)).To(Succeed())
+	}
+}
+
+// packageBuildpack packages the composite buildpack and returns the path to
+// the package.
+func packageBuildpack() (string, error) {
+	output, err := exec.Command("bash", "-c", "../scripts/package.sh --version 1.2.3").CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("failed to package the buildpack: %w\n%s", err, output)
+	}
+
+	return filepath.Abs("../build/buildpackage.cnb")
+}
+
+// integrationBuilders lists the builders to run the suite against, from
+// INTEGRATION_BUILDERS, the default builder of pack, or the builder or
+// builders of integration.json. scripts/integration.sh is synced from
+// github-config and runs the suite once per builder after making it the
+// default builder of pack, so the default builder wins over
+// integration.json.
+func integrationBuilders(path string) ([]string, error) {
+	if value, ok := os.LookupEnv(BuildersEnv); ok && value != "" {
+		return strings.Split(value, ","), nil
+	}
+
+	defaultBuilder, err := packDefaultBuilder()
+	if err != nil {
+		return nil, err
+	}
+
+	if defaultBuilder != "" {
+		return []string{defaultBuilder}, nil
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var config struct {
+		Builder  string   `json:"builder"`
+		Builders []string `json:"builders"`
+	}
+	err = json.Unmarshal(content, &config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	if config.Builder != "" {
+		return []string{config.Builder}, nil
+	}
+
+	if len(config.Builders) == 0 {
+		return nil, fmt.Errorf("%s does not list any builders", path)
+	}
+
+	return config.Builders, nil
+}
+
+// packDefaultBuilder reads the builder set by `pack config default-builder`
+// from the pack config in PACK_HOME, or ~/.pack by default.
+func packDefaultBuilder() (string, error) {
+	home, ok := os.LookupEnv("PACK_HOME")
+	if !ok || home == "" {
+		userHome, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		home = filepath.Join(userHome, ".pack")
+	}
+
+	var config struct {
+		DefaultBuilderImage string `toml:"default-builder-image"`
+	}
+	_, err := toml.DecodeFile(filepath.Join(home, "config.toml"), &config)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to parse the pack config: %w", err)
+	}
+
+	return config.DefaultBuilderImage, nil
+}
+
+// skipOnBuilders skips a spec that cannot pass on some builders. Builders are
+// matched by substring, so "tiny" matches every tiny builder.
+func skipOnBuilders(t *testing.T, reason string, builders ...string) {
+	t.Helper()
+
+	for _, b := range builders {
+		if strings.Contains(builder, b) {
+			t.Skipf("incompatible with builder %s: %s", builder, reason)
+		}
+	}
 }
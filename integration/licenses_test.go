@@ -0,0 +1,146 @@
+package integration_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/paketo-buildpacks/go/licenses"
+	"github.com/paketo-buildpacks/occam"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+	. "github.com/paketo-buildpacks/occam/matchers"
+)
+
+func testLicenses(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect     = NewWithT(t).Expect
+		Eventually = NewWithT(t).Eventually
+
+		pack   occam.Pack
+		docker occam.Docker
+
+		image     occam.Image
+		container occam.Container
+
+		name   string
+		source string
+	)
+
+	it.Before(func() {
+		pack = occam.NewPack()
+		docker = occam.NewDocker()
+
+		var err error
+		name, err = occam.RandomName()
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	it.After(func() {
+		if container.ID != "" {
+			Expect(docker.Container.Remove.Execute(container.ID)).To(Succeed())
+		}
+		if image.ID != "" {
+			Expect(docker.Image.Remove.Execute(image.ID)).To(Succeed())
+		}
+		Expect(docker.Volume.Remove.Execute(occam.CacheVolumeNames(name))).To(Succeed())
+		Expect(os.RemoveAll(source)).To(Succeed())
+	})
+
+	context("when the vendored modules are published under allowed licenses", func() {
+		it.Before(func() {
+			var err error
+			source, err = occam.Source(filepath.Join("testdata", "licenses_allowed"))
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		it("builds the app and writes the license report to a launch layer", func() {
+			var err error
+			var logs fmt.Stringer
+			image, logs, err = executeBuild(t, pack.WithNoColor().Build.
+				WithBuilder(builder).
+				WithBuildpacks(goBuildpack).
+				WithPullPolicy("never").
+				WithEnv(map[string]string{"BP_GO_LICENSE_CHECK": "true"}),
+				name, source)
+			Expect(err).NotTo(HaveOccurred(), logs.String())
+
+			Expect(logs).To(ContainLines(ContainSubstring("Paketo Buildpack for Go Licenses")))
+			Expect(logs).To(ContainLines(ContainSubstring("example.com/greeting@v1.0.0: MIT")))
+
+			container, err = docker.Container.Run.
+				WithEnv(map[string]string{"PORT": "8080"}).
+				WithPublish("8080").
+				WithPublishAll().
+				Execute(image.ID)
+			Expect(err).NotTo(HaveOccurred())
+
+			Eventually(container).Should(Serve(ContainSubstring("Hello, World!")).OnPort(8080))
+
+			content, err := imageFile(name, "/layers/paketo-buildpacks_go-licenses/licenses/licenses.json")
+			Expect(err).NotTo(HaveOccurred())
+
+			var report licenses.Report
+			Expect(json.Unmarshal(content, &report)).To(Succeed())
+			Expect(report.Modules).To(ConsistOf(SatisfyAll(
+				HaveField("Module.Path", "example.com/greeting"),
+				HaveField("Licenses", Equal([]string{"MIT"})),
+				HaveField("Files", Equal([]string{"LICENSE"})),
+			)))
+		})
+	})
+
+	context("when a vendored module is published under a denied license", func() {
+		it.Before(func() {
+			var err error
+			source, err = occam.Source(filepath.Join("testdata", "licenses_denied"))
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		it("fails the build and names the module and license", func() {
+			_, logs, err := executeBuild(t, pack.WithNoColor().Build.
+				WithBuilder(builder).
+				WithBuildpacks(goBuildpack).
+				WithPullPolicy("never").
+				WithEnv(map[string]string{"BP_GO_LICENSE_CHECK": "true"}),
+				name, source)
+			Expect(err).To(HaveOccurred(), logs.String())
+
+			Expect(logs).To(ContainLines(ContainSubstring("modules are published under denied licenses (BP_GO_LICENSE_DENY_LIST=GPL-*,AGPL-*):")))
+			Expect(logs).To(ContainLines(ContainSubstring("example.com/greeting@v1.0.0: GPL-3.0-only")))
+		})
+
+		it("builds the app when the license is not on the configured deny-list", func() {
+			var err error
+			var logs fmt.Stringer
+			image, logs, err = executeBuild(t, pack.WithNoColor().Build.
+				WithBuilder(builder).
+				WithBuildpacks(goBuildpack).
+				WithPullPolicy("never").
+				WithEnv(map[string]string{
+					"BP_GO_LICENSE_CHECK":     "true",
+					"BP_GO_LICENSE_DENY_LIST": "AGPL-*",
+				}),
+				name, source)
+			Expect(err).NotTo(HaveOccurred(), logs.String())
+
+			Expect(logs).To(ContainLines(ContainSubstring("example.com/greeting@v1.0.0: GPL-3.0-only")))
+		})
+
+		it("builds the app without checking the licenses unless BP_GO_LICENSE_CHECK is set", func() {
+			var err error
+			var logs fmt.Stringer
+			image, logs, err = executeBuild(t, pack.WithNoColor().Build.
+				WithBuilder(builder).
+				WithBuildpacks(goBuildpack).
+				WithPullPolicy("never"),
+				name, source)
+			Expect(err).NotTo(HaveOccurred(), logs.String())
+
+			Expect(logs).NotTo(ContainLines(ContainSubstring("Paketo Buildpack for Go Licenses")))
+		})
+	})
+}
@@ -0,0 +1,139 @@
+package integration_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/paketo-buildpacks/occam"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+	. "github.com/paketo-buildpacks/occam/matchers"
+)
+
+func testNegativePaths(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		pack   occam.Pack
+		docker occam.Docker
+	)
+
+	it.Before(func() {
+		pack = occam.NewPack()
+		docker = occam.NewDocker()
+	})
+
+	for _, c := range []struct {
+		description string
+		fixture     string
+		env         map[string]string
+
+		// prepare breaks the copy of the fixture
+		prepare func(source string) error
+
+		// component is the buildpack expected to fail the build, and messages
+		// are what it must tell the user
+		component string
+		messages  []string
+	}{
+		{
+			description: "a go.mod requirement has no go.sum entry",
+			fixture:     "go_mod",
+			prepare: func(source string) error {
+				content, err := os.ReadFile(filepath.Join(source, "go.sum"))
+				if err != nil {
+					return err
+				}
+
+				var lines []string
+				for _, line := range strings.Split(string(content), "\n") {
+					if !strings.HasPrefix(line, "github.com/satori/go.uuid ") {
+						lines = append(lines, line)
+					}
+				}
+
+				return os.WriteFile(filepath.Join(source, "go.sum"), []byte(strings.Join(lines, "\n")), 0644)
+			},
+			component: "Buildpack for Go Mod Vendor",
+			messages:  []string{"missing go.sum entry", "github.com/satori/go.uuid"},
+		},
+		{
+			description: "the app does not compile",
+			fixture:     "build",
+			prepare: func(source string) error {
+				return os.WriteFile(filepath.Join(source, "broken.go"), []byte("package main\n\nfunc broken() { doesNotExist() }\n"), 0644)
+			},
+			component: "Buildpack for Go Build",
+			messages:  []string{"broken.go:3:17: undefined: doesNotExist"},
+		},
+		{
+			description: "BP_GO_VERSION matches no Go version",
+			fixture:     "build",
+			env:         map[string]string{"BP_GO_VERSION": "0.0.1"},
+			component:   "Buildpack for Go Distribution",
+			messages:    []string{`failed to satisfy "go" dependency version constraint "0.0.1"`, "Supported versions are"},
+		},
+		{
+			description: "BP_GO_TARGETS names a path that does not exist",
+			fixture:     "build",
+			env:         map[string]string{"BP_GO_TARGETS": "./does-not-exist"},
+			component:   "Buildpack for Go Build",
+			messages:    []string{"BP_GO_TARGETS", "does-not-exist"},
+		},
+		{
+			description: "the Procfile is not valid YAML",
+			fixture:     "build",
+			prepare: func(source string) error {
+				return os.WriteFile(filepath.Join(source, "Procfile"), []byte("web: [unterminated\n"), 0644)
+			},
+			component: "paketo-buildpacks/procfile",
+			messages:  []string{"Procfile", "yaml: line 1"},
+		},
+	} {
+		c := c
+
+		context(fmt.Sprintf("when %s", c.description), func() {
+			var (
+				name   string
+				source string
+			)
+
+			it.Before(func() {
+				var err error
+				name, err = occam.RandomName()
+				Expect(err).NotTo(HaveOccurred())
+
+				source, err = occam.Source(filepath.Join("testdata", c.fixture))
+				Expect(err).NotTo(HaveOccurred())
+
+				if c.prepare != nil {
+					Expect(c.prepare(source)).To(Succeed())
+				}
+			})
+
+			it.After(func() {
+				Expect(docker.Volume.Remove.Execute(occam.CacheVolumeNames(name))).To(Succeed())
+				Expect(os.RemoveAll(source)).To(Succeed())
+			})
+
+			it("fails the build with an actionable message", func() {
+				_, logs, err := executeBuild(t, pack.WithNoColor().Build.
+					WithBuilder(builder).
+					WithBuildpacks(goBuildpack).
+					WithPullPolicy("never").
+					WithEnv(c.env),
+					name, source)
+				Expect(err).To(HaveOccurred(), logs.String())
+
+				Expect(logs).To(ContainLines(ContainSubstring(c.component)), logs.String())
+				for _, message := range c.messages {
+					Expect(logs.String()).To(ContainSubstring(message))
+				}
+			})
+		})
+	}
+}
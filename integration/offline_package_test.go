@@ -0,0 +1,204 @@
+package integration_test
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/layout"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/paketo-buildpacks/go/components"
+	"github.com/paketo-buildpacks/occam"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+	. "github.com/paketo-buildpacks/occam/matchers"
+)
+
+func testOfflinePackage(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect     = NewWithT(t).Expect
+		Eventually = NewWithT(t).Eventually
+
+		docker occam.Docker
+
+		registry occam.Container
+		host     string
+		root     string
+		lockfile components.Lockfile
+	)
+
+	it.Before(func() {
+		docker = occam.NewDocker()
+
+		var err error
+		registry, err = docker.Container.Run.
+			WithPublish("5000").
+			Execute("registry:2")
+		Expect(err).NotTo(HaveOccurred())
+
+		Eventually(registry).Should(BeAvailable())
+		host = fmt.Sprintf("localhost:%s", registry.HostPort("5000"))
+
+		// Package from a copy of the repository so that the build directory
+		// used by the other suites is left alone.
+		root, err = os.MkdirTemp("", "offline-package")
+		Expect(err).NotTo(HaveOccurred())
+
+		// The component buildpacks kept in this repository are compiled
+		// while packaging, so every package they import has to be copied.
+		entries, err := os.ReadDir("..")
+		Expect(err).NotTo(HaveOccurred())
+
+		for _, entry := range entries {
+			if entry.Name() == ".git" || entry.Name() == "build" {
+				continue
+			}
+
+			output, err := exec.Command("cp", "-R", filepath.Join("..", entry.Name()), filepath.Join(root, entry.Name())).CombinedOutput()
+			Expect(err).NotTo(HaveOccurred(), string(output))
+		}
+
+		config, err := components.ParsePackageConfig(filepath.Join(root, "package.toml"))
+		Expect(err).NotTo(HaveOccurred())
+
+		var images int
+		for _, dependency := range config.Dependencies {
+			if !dependency.IsImage() {
+				continue
+			}
+			images++
+
+			ref, err := dependency.Reference()
+			Expect(err).NotTo(HaveOccurred())
+
+			mirrored := fmt.Sprintf("%s/%s:%s", host, ref.Context().RepositoryStr(), ref.Identifier())
+			publishFixtureComponent(t, root, fmt.Sprintf("paketo-buildpacks/%s", dependency.Name()), ref.Identifier(), mirrored)
+		}
+
+		command := exec.Command("go", "run", "./cmd/components", "lock", "--registry", host)
+		command.Dir = root
+		output, err := command.CombinedOutput()
+		Expect(err).NotTo(HaveOccurred(), string(output))
+
+		lockfile, err = components.ReadLockfile(filepath.Join(root, components.LockfileName))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(lockfile.Dependencies).To(HaveLen(images))
+	})
+
+	it.After(func() {
+		Expect(docker.Container.Remove.Execute(registry.ID)).To(Succeed())
+		Expect(os.RemoveAll(root)).To(Succeed())
+	})
+
+	context("when the components are served by a local registry", func() {
+		it("packages the composite from the locked components", func() {
+			output, err := exec.Command("bash", "-c", fmt.Sprintf("%s --version 1.2.3 --components-registry %s", filepath.Join(root, "scripts", "package.sh"), host)).CombinedOutput()
+			Expect(err).NotTo(HaveOccurred(), string(output))
+
+			Expect(string(output)).To(ContainSubstring(fmt.Sprintf("Vendoring component buildpackages from registry %s", host)))
+			Expect(string(output)).To(ContainSubstring("go-build.cnb"))
+			Expect(filepath.Join(root, "build", "buildpackage.cnb")).To(BeARegularFile())
+
+			// the release artifact surfaces the locked digests
+			output, err = exec.Command("tar", "-xzOf", filepath.Join(root, "build", "buildpack-release-artifact.tgz"), "package.lock.toml").CombinedOutput()
+			Expect(err).NotTo(HaveOccurred(), string(output))
+			for _, dependency := range lockfile.Dependencies {
+				Expect(string(output)).To(ContainSubstring(dependency.Digest))
+			}
+		})
+
+		context("when a component digest differs from the lockfile", func() {
+			it.Before(func() {
+				for i, dependency := range lockfile.Dependencies {
+					if strings.Contains(dependency.URI, "/go-build:") {
+						lockfile.Dependencies[i].Digest = "sha256:0000000000000000000000000000000000000000000000000000000000000000"
+					}
+				}
+
+				Expect(components.WriteLockfile(filepath.Join(root, components.LockfileName), lockfile)).To(Succeed())
+			})
+
+			it("refuses to package the composite", func() {
+				output, err := exec.Command("bash", "-c", fmt.Sprintf("%s --version 1.2.3 --components-registry %s", filepath.Join(root, "scripts", "package.sh"), host)).CombinedOutput()
+				Expect(err).To(HaveOccurred(), string(output))
+
+				Expect(string(output)).To(ContainSubstring("digest mismatch: locked sha256:0000000000000000000000000000000000000000000000000000000000000000"))
+				Expect(filepath.Join(root, "build", "buildpackage.cnb")).NotTo(BeAnExistingFile())
+			})
+		})
+	})
+
+	context("when the components are stored in an OCI layout", func() {
+		var layoutDir string
+
+		it.Before(func() {
+			layoutDir = filepath.Join(root, "components-layout")
+
+			p, err := layout.Write(layoutDir, empty.Index)
+			Expect(err).NotTo(HaveOccurred())
+
+			for _, dependency := range lockfile.Dependencies {
+				ref, err := components.PackageDependency{URI: dependency.URI}.Reference()
+				Expect(err).NotTo(HaveOccurred())
+
+				mirrored, err := name.ParseReference(fmt.Sprintf("%s/%s:%s", host, ref.Context().RepositoryStr(), ref.Identifier()))
+				Expect(err).NotTo(HaveOccurred())
+
+				image, err := remote.Image(mirrored)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(p.AppendImage(image, layout.WithAnnotations(map[string]string{
+					components.RefNameAnnotation: ref.String(),
+				}))).To(Succeed())
+			}
+		})
+
+		it("packages the composite from the locked components", func() {
+			output, err := exec.Command("bash", "-c", fmt.Sprintf("%s --version 1.2.3 --components-layout %s", filepath.Join(root, "scripts", "package.sh"), layoutDir)).CombinedOutput()
+			Expect(err).NotTo(HaveOccurred(), string(output))
+
+			Expect(string(output)).To(ContainSubstring(fmt.Sprintf("Vendoring component buildpackages from OCI layout %s", layoutDir)))
+			Expect(filepath.Join(root, "build", "buildpackage.cnb")).To(BeARegularFile())
+		})
+	})
+}
+
+// publishFixtureComponent packages a no-op buildpack with the given id and
+// version and publishes it to the given image reference.
+func publishFixtureComponent(t *testing.T, root, id, version, ref string) {
+	t.Helper()
+	Expect := NewWithT(t).Expect
+
+	dir := filepath.Join(root, "fixture-components", filepath.Base(id))
+	Expect(os.MkdirAll(filepath.Join(dir, "bin"), os.ModePerm)).To(Succeed())
+
+	Expect(os.WriteFile(filepath.Join(dir, "buildpack.toml"), []byte(fmt.Sprintf(`api = "0.10"
+
+[buildpack]
+  id = %q
+  version = %q
+
+[[targets]]
+  os = "linux"
+  arch = %q
+`, id, version, runtime.GOARCH)), 0600)).To(Succeed())
+
+	for _, phase := range []string{"detect", "build"} {
+		Expect(os.WriteFile(filepath.Join(dir, "bin", phase), []byte("#!/usr/bin/env bash\nexit 0\n"), 0755)).To(Succeed())
+	}
+
+	Expect(os.WriteFile(filepath.Join(dir, "package.toml"), []byte("[buildpack]\n  uri = \".\"\n"), 0600)).To(Succeed())
+
+	output, err := exec.Command("pack", "buildpack", "package", ref,
+		"--config", filepath.Join(dir, "package.toml"),
+		"--publish",
+	).CombinedOutput()
+	Expect(err).NotTo(HaveOccurred(), string(output))
+}
@@ -0,0 +1,139 @@
+package integration_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/paketo-buildpacks/go/optimize"
+	"github.com/paketo-buildpacks/occam"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+	. "github.com/paketo-buildpacks/occam/matchers"
+)
+
+func testOptimizeSize(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect     = NewWithT(t).Expect
+		Eventually = NewWithT(t).Eventually
+
+		pack   occam.Pack
+		docker occam.Docker
+	)
+
+	it.Before(func() {
+		pack = occam.NewPack()
+		docker = occam.NewDocker()
+	})
+
+	context("when BP_GO_OPTIMIZE_SIZE is set", func() {
+		var (
+			images     map[string]occam.Image
+			containers map[string]occam.Container
+
+			source string
+		)
+
+		it.Before(func() {
+			images = map[string]occam.Image{}
+			containers = map[string]occam.Container{}
+
+			var err error
+			source, err = occam.Source(filepath.Join("testdata", "build"))
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		it.After(func() {
+			for _, container := range containers {
+				Expect(docker.Container.Remove.Execute(container.ID)).To(Succeed())
+			}
+			for name, image := range images {
+				Expect(docker.Image.Remove.Execute(image.ID)).To(Succeed())
+				Expect(docker.Volume.Remove.Execute(occam.CacheVolumeNames(name))).To(Succeed())
+			}
+			Expect(os.RemoveAll(source)).To(Succeed())
+		})
+
+		// build builds the build fixture with env, checks that it serves
+		// "Hello, World!" and copies its binary out of the image.
+		build := func(env map[string]string) (occam.Image, fmt.Stringer, string) {
+			name, err := occam.RandomName()
+			Expect(err).NotTo(HaveOccurred())
+
+			image, logs, err := executeBuild(t, pack.WithNoColor().Build.
+				WithBuilder(builder).
+				WithBuildpacks(goBuildpack).
+				WithPullPolicy("never").
+				WithEnv(env),
+				name, source)
+			Expect(err).NotTo(HaveOccurred(), logs.String())
+			images[name] = image
+
+			container, err := docker.Container.Run.
+				WithEnv(map[string]string{"PORT": "8080"}).
+				WithPublish("8080").
+				WithPublishAll().
+				Execute(image.ID)
+			Expect(err).NotTo(HaveOccurred())
+			containers[name] = container
+
+			Eventually(container).Should(Serve(ContainSubstring("Hello, World!")).OnPort(8080))
+
+			content, err := imageFile(image.ID, "/layers/paketo-buildpacks_go-build/targets/bin/workspace")
+			Expect(err).NotTo(HaveOccurred())
+
+			binary := filepath.Join(t.TempDir(), "workspace")
+			Expect(os.WriteFile(binary, content, 0755)).To(Succeed())
+
+			return image, logs, binary
+		}
+
+		it("builds a smaller binary without debug information and labels the image with its size", func() {
+			_, _, baseline := build(map[string]string{})
+			image, logs, optimized := build(map[string]string{optimize.Env: "true"})
+
+			Expect(logs).To(ContainLines(ContainSubstring("Paketo Buildpack for Go Optimize Size Flags")))
+			Expect(logs).To(ContainLines(ContainSubstring("BP_GO_BUILD_FLAGS=-trimpath")))
+			Expect(logs).To(ContainLines(ContainSubstring("BP_GO_BUILD_LDFLAGS=-s -w")))
+			Expect(logs).To(ContainLines(ContainSubstring("Paketo Buildpack for Go Optimize Size")))
+
+			Expect(optimize.HasDebugInfo(baseline)).To(BeTrue())
+			Expect(optimize.HasDebugInfo(optimized)).To(BeFalse())
+
+			baselineInfo, err := os.Stat(baseline)
+			Expect(err).NotTo(HaveOccurred())
+			optimizedInfo, err := os.Stat(optimized)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(optimizedInfo.Size()).To(BeNumerically("<", baselineInfo.Size()))
+
+			// without UPX the binary is measured as go-build left it, so both
+			// labels hold the size of the binary in the image
+			size := strconv.FormatInt(optimizedInfo.Size(), 10)
+			Expect(image.Labels).To(HaveKeyWithValue("io.paketo.go.optimize-size.workspace.before", size))
+			Expect(image.Labels).To(HaveKeyWithValue("io.paketo.go.optimize-size.workspace.after", size))
+
+			Expect(logs).To(ContainLines(ContainSubstring("workspace: %s -> %s", optimize.FormatSize(optimizedInfo.Size()), optimize.FormatSize(optimizedInfo.Size()))))
+		})
+
+		it("compresses the binary with upx, or only strips it when the builder has no upx", func() {
+			image, logs, binary := build(map[string]string{optimize.Env: "upx"})
+
+			info, err := os.Stat(binary)
+			Expect(err).NotTo(HaveOccurred())
+
+			before, err := strconv.ParseInt(image.Labels["io.paketo.go.optimize-size.workspace.before"], 10, 64)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(image.Labels).To(HaveKeyWithValue("io.paketo.go.optimize-size.workspace.after", strconv.FormatInt(info.Size(), 10)))
+
+			if before == info.Size() {
+				Expect(logs).To(ContainLines(ContainSubstring("upx is not installed, binaries are only stripped")))
+			} else {
+				Expect(info.Size()).To(BeNumerically("<", before))
+				Expect(logs).To(ContainLines(ContainSubstring("workspace: %s -> %s", optimize.FormatSize(before), optimize.FormatSize(info.Size()))))
+			}
+		})
+	})
+}
@@ -0,0 +1,190 @@
+package integration_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/paketo-buildpacks/go/otel"
+	"github.com/paketo-buildpacks/occam"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+	. "github.com/paketo-buildpacks/occam/matchers"
+)
+
+// SkipOtelEnv skips the Otel suite on hosts whose Docker cannot run the
+// privileged containers that eBPF needs.
+const SkipOtelEnv = "INTEGRATION_SKIP_OTEL"
+
+func testOtel(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect     = NewWithT(t).Expect
+		Eventually = NewWithT(t).Eventually
+
+		pack   occam.Pack
+		docker occam.Docker
+	)
+
+	it.Before(func() {
+		pack = occam.NewPack()
+		docker = occam.NewDocker()
+	})
+
+	context("when an otel binding points at a collector", func() {
+		var (
+			image     occam.Image
+			collector occam.Container
+			container occam.Container
+
+			name           string
+			network        string
+			collectorImage string
+			sidecar        string
+			source         string
+			bindings       string
+		)
+
+		it.Before(func() {
+			if os.Getenv(SkipOtelEnv) != "" {
+				t.Skipf("%s is set", SkipOtelEnv)
+			}
+
+			var err error
+			name, err = occam.RandomName()
+			Expect(err).NotTo(HaveOccurred())
+			network = name + "-network"
+			collectorImage = name + "-collector"
+
+			output, err := exec.Command("docker", "network", "create", network).CombinedOutput()
+			Expect(err).NotTo(HaveOccurred(), string(output))
+
+			output, err = exec.Command("docker", "build", "--tag", collectorImage, filepath.Join("testdata", "otel", "collector")).CombinedOutput()
+			Expect(err).NotTo(HaveOccurred(), string(output))
+
+			collector, err = docker.Container.Run.
+				WithNetwork(network).
+				WithPublish("4318").
+				Execute(collectorImage)
+			Expect(err).NotTo(HaveOccurred())
+
+			bindings = t.TempDir()
+			Expect(os.Chmod(bindings, 0755)).To(Succeed())
+			binding := filepath.Join(bindings, "tracing")
+			Expect(os.MkdirAll(binding, 0755)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(binding, "type"), []byte(otel.BindingType), 0644)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(binding, otel.EndpointEntry), fmt.Appendf(nil, "http://%s:4318", collector.IPAddresses[network]), 0644)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(binding, otel.HeadersEntry), []byte("x-api-key=secret\n"), 0644)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(binding, otel.ServiceNameEntry), []byte("build-fixture\n"), 0644)).To(Succeed())
+
+			source, err = occam.Source(filepath.Join("testdata", "build"))
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		it.After(func() {
+			if sidecar != "" {
+				output, err := exec.Command("docker", "rm", "--force", sidecar).CombinedOutput()
+				Expect(err).NotTo(HaveOccurred(), string(output))
+			}
+			if container.ID != "" {
+				Expect(docker.Container.Remove.Execute(container.ID)).To(Succeed())
+			}
+			if image.ID != "" {
+				Expect(docker.Image.Remove.Execute(image.ID)).To(Succeed())
+				Expect(docker.Volume.Remove.Execute(occam.CacheVolumeNames(name))).To(Succeed())
+			}
+			Expect(docker.Container.Remove.Execute(collector.ID)).To(Succeed())
+			Expect(docker.Image.Remove.Execute(collectorImage)).To(Succeed())
+
+			output, err := exec.Command("docker", "network", "rm", network).CombinedOutput()
+			Expect(err).NotTo(HaveOccurred(), string(output))
+			Expect(os.RemoveAll(source)).To(Succeed())
+		})
+
+		it("assigns a process type that exports spans of the app to the collector of the binding", func() {
+			var err error
+			var logs fmt.Stringer
+			image, logs, err = executeBuild(t, pack.WithNoColor().Build.
+				WithBuilder(builder).
+				WithBuildpacks(goBuildpack).
+				WithPullPolicy("never").
+				WithEnv(map[string]string{"SERVICE_BINDING_ROOT": "/bindings"}).
+				WithVolumes(fmt.Sprintf("%s:/bindings", bindings)),
+				name, source)
+			Expect(err).NotTo(HaveOccurred(), logs.String())
+
+			Expect(logs).To(ContainLines(ContainSubstring("Paketo Buildpack for Go OpenTelemetry")))
+			Expect(logs).To(ContainLines(ContainSubstring("Assigning process type %s to instrument workspace", otel.ProcessType)))
+			Expect(logs.String()).NotTo(ContainSubstring("secret"))
+
+			Expect(imageProcessTypes(image)).To(ContainElement(otel.ProcessType))
+
+			container, err = docker.Container.Run.
+				WithEnv(map[string]string{"PORT": "8080"}).
+				WithNetwork(network).
+				WithPublish("8080").
+				Execute(image.ID)
+			Expect(err).NotTo(HaveOccurred())
+
+			Eventually(container).Should(Serve(ContainSubstring("Hello, World!")).OnPort(8080))
+
+			// the sidecar runs the process type of the image in the process
+			// namespace of the app, with the binding the launch helper reads
+			// the headers from
+			output, err := exec.Command("docker", "run", "--detach",
+				"--privileged",
+				"--user", "0",
+				"--pid", "container:"+container.ID,
+				"--network", network,
+				"--env", "SERVICE_BINDING_ROOT=/bindings",
+				"--volume", fmt.Sprintf("%s:/bindings", bindings),
+				"--entrypoint", "/cnb/process/"+otel.ProcessType,
+				image.ID,
+			).CombinedOutput()
+			Expect(err).NotTo(HaveOccurred(), string(output))
+			sidecar = strings.TrimSpace(string(output))
+
+			type export struct {
+				APIKey      string `json:"apiKey"`
+				ContentType string `json:"contentType"`
+				Body        []byte `json:"body"`
+			}
+
+			// the instrumentation attaches asynchronously, so requests are sent
+			// until their spans arrive
+			Eventually(func() ([]export, error) {
+				response, err := http.Get(fmt.Sprintf("http://%s:%s", container.Host(), container.HostPort("8080")))
+				if err != nil {
+					return nil, err
+				}
+				response.Body.Close()
+
+				response, err = http.Get(fmt.Sprintf("http://%s:%s/received", collector.Host(), collector.HostPort("4318")))
+				if err != nil {
+					return nil, err
+				}
+				defer response.Body.Close()
+
+				var received []export
+				err = json.NewDecoder(response.Body).Decode(&received)
+				return received, err
+			}, 2*time.Minute, time.Second).Should(ContainElement(And(
+				HaveField("APIKey", "secret"),
+				HaveField("ContentType", "application/x-protobuf"),
+				HaveField("Body", WithTransform(func(body []byte) string { return string(body) }, And(
+					ContainSubstring("build-fixture"),
+					ContainSubstring("GET"),
+				))),
+			)), func() string {
+				logs, _ := exec.Command("docker", "logs", sidecar).CombinedOutput()
+				return string(logs)
+			})
+		})
+	})
+}
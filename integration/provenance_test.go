@@ -0,0 +1,222 @@
+package integration_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/paketo-buildpacks/go/provenance"
+	"github.com/paketo-buildpacks/occam"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+	. "github.com/paketo-buildpacks/occam/matchers"
+)
+
+func testProvenance(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect     = NewWithT(t).Expect
+		Eventually = NewWithT(t).Eventually
+
+		pack   occam.Pack
+		docker occam.Docker
+	)
+
+	it.Before(func() {
+		pack = occam.NewPack()
+		docker = occam.NewDocker()
+	})
+
+	// attached reads the provenance documents attached to an image, keyed by
+	// media type.
+	attached := func(image name.Digest) map[string][]byte {
+		index, err := remote.Referrers(image)
+		Expect(err).NotTo(HaveOccurred())
+
+		manifest, err := index.IndexManifest()
+		Expect(err).NotTo(HaveOccurred())
+
+		documents := map[string][]byte{}
+		for _, descriptor := range manifest.Manifests {
+			artifact, err := remote.Image(image.Context().Digest(descriptor.Digest.String()))
+			Expect(err).NotTo(HaveOccurred())
+
+			layers, err := artifact.Layers()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(layers).To(HaveLen(1))
+
+			mediaType, err := layers[0].MediaType()
+			Expect(err).NotTo(HaveOccurred())
+
+			reader, err := layers[0].Compressed()
+			Expect(err).NotTo(HaveOccurred())
+
+			content, err := io.ReadAll(reader)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(reader.Close()).To(Succeed())
+
+			documents[string(mediaType)] = content
+		}
+
+		return documents
+	}
+
+	context("when generating provenance for a pushed image", func() {
+		var (
+			image     occam.Image
+			container occam.Container
+			registry  occam.Container
+
+			imageName   string
+			source      string
+			pushed      string
+			bindingRoot string
+			key         *ecdsa.PrivateKey
+		)
+
+		it.Before(func() {
+			var err error
+			imageName, err = occam.RandomName()
+			Expect(err).NotTo(HaveOccurred())
+
+			source, err = occam.Source(filepath.Join("testdata", "build"))
+			Expect(err).NotTo(HaveOccurred())
+
+			registry, err = docker.Container.Run.
+				WithPublish("5000").
+				Execute("registry:2")
+			Expect(err).NotTo(HaveOccurred())
+			Eventually(registry).Should(BeAvailable())
+
+			pushed = fmt.Sprintf("localhost:%s/%s:latest", registry.HostPort("5000"), imageName)
+
+			// the signing key is generated for every run rather than kept
+			// in the repository
+			key, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+			Expect(err).NotTo(HaveOccurred())
+
+			der, err := x509.MarshalPKCS8PrivateKey(key)
+			Expect(err).NotTo(HaveOccurred())
+
+			bindingRoot = t.TempDir()
+			binding := filepath.Join(bindingRoot, "slsa-signing-key")
+			Expect(os.MkdirAll(binding, os.ModePerm)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(binding, "type"), []byte(provenance.BindingType), 0600)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(binding, provenance.BindingKeyEntry), pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0600)).To(Succeed())
+		})
+
+		it.After(func() {
+			Expect(docker.Container.Remove.Execute(container.ID)).To(Succeed())
+			Expect(docker.Container.Remove.Execute(registry.ID)).To(Succeed())
+			Expect(docker.Image.Remove.Execute(pushed)).To(Succeed())
+			Expect(docker.Image.Remove.Execute(image.ID)).To(Succeed())
+			Expect(docker.Volume.Remove.Execute(occam.CacheVolumeNames(imageName))).To(Succeed())
+			Expect(os.RemoveAll(source)).To(Succeed())
+		})
+
+		it("attaches provenance signed with the key from the service binding", func() {
+			var err error
+			var logs fmt.Stringer
+			image, logs, err = executeBuild(t, pack.WithNoColor().Build.
+				WithBuilder(builder).
+				WithBuildpacks(goBuildpack).
+				WithPullPolicy("never").
+				WithEnv(map[string]string{"BP_GO_BUILD_LDFLAGS": "-s -w"}),
+				imageName, source)
+			Expect(err).NotTo(HaveOccurred(), logs.String())
+
+			container, err = docker.Container.Run.
+				WithEnv(map[string]string{"PORT": "8080"}).
+				WithPublish("8080").
+				WithPublishAll().
+				Execute(image.ID)
+			Expect(err).NotTo(HaveOccurred())
+
+			Eventually(container).Should(Serve(ContainSubstring("Hello, World!")).OnPort(8080))
+
+			for _, args := range [][]string{{"tag", image.ID, pushed}, {"push", pushed}} {
+				output, err := exec.Command("docker", args...).CombinedOutput()
+				Expect(err).NotTo(HaveOccurred(), string(output))
+			}
+
+			ref, err := name.ParseReference(pushed)
+			Expect(err).NotTo(HaveOccurred())
+
+			descriptor, err := remote.Head(ref)
+			Expect(err).NotTo(HaveOccurred())
+			digest := ref.Context().Digest(descriptor.Digest.String())
+
+			revision, err := exec.Command("git", "rev-parse", "HEAD").Output()
+			Expect(err).NotTo(HaveOccurred())
+
+			outputDir := t.TempDir()
+			command := exec.Command("go", "run", "../cmd/provenance",
+				"--image", pushed,
+				"--builder", builder,
+				"--source-uri", "git+https://github.com/paketo-buildpacks/go",
+				"--source-revision", strings.TrimSpace(string(revision)),
+				"--output-dir", outputDir,
+			)
+			command.Env = append(os.Environ(), fmt.Sprintf("SERVICE_BINDING_ROOT=%s", bindingRoot))
+			output, err := command.CombinedOutput()
+			Expect(err).NotTo(HaveOccurred(), string(output))
+			Expect(filepath.Join(outputDir, "provenance.intoto.dsse.json")).To(BeARegularFile())
+
+			documents := attached(digest)
+			Expect(documents).To(HaveKey(string(provenance.EnvelopeMediaType)))
+
+			var envelope provenance.Envelope
+			Expect(json.Unmarshal(documents[string(provenance.EnvelopeMediaType)], &envelope)).To(Succeed())
+
+			// verify the signature with the public half of the generated key
+			statement, err := provenance.Verify(envelope, key.Public())
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(statement.PredicateType).To(Equal(provenance.PredicateType))
+			Expect(statement.Subject).To(ConsistOf(HaveField("Digest", HaveKeyWithValue("sha256", descriptor.Digest.Hex))))
+			Expect(statement.Predicate.RunDetails.Builder.ID).To(Equal(builder))
+			Expect(statement.Predicate.BuildDefinition.ExternalParameters.Source.Revision).To(Equal(strings.TrimSpace(string(revision))))
+			Expect(statement.Predicate.BuildDefinition.ExternalParameters.Binaries).To(ConsistOf(SatisfyAll(
+				HaveField("Path", HavePrefix(provenance.BinaryDir)),
+				HaveField("Settings", HaveKeyWithValue("-ldflags", "-s -w")),
+			)))
+			// the buildpacks and their versions are read from the image label
+			// rather than from the buildpack.toml of this repository
+			Expect(statement.Predicate.BuildDefinition.InternalParameters.Buildpacks).To(ContainElement(HaveField("ID", "paketo-buildpacks/go-build")))
+			for _, buildpack := range statement.Predicate.BuildDefinition.InternalParameters.Buildpacks {
+				Expect(statement.Predicate.BuildDefinition.ResolvedDependencies).To(ContainElement(SatisfyAll(
+					HaveField("Name", buildpack.ID),
+					HaveField("Annotations", HaveKeyWithValue("version", buildpack.Version)),
+				)))
+			}
+
+			// without a signing key binding the statement is attached unsigned
+			command = exec.Command("go", "run", "../cmd/provenance",
+				"--image", pushed,
+				"--builder", builder,
+			)
+			command.Env = append(os.Environ(), fmt.Sprintf("SERVICE_BINDING_ROOT=%s", t.TempDir()))
+			output, err = command.CombinedOutput()
+			Expect(err).NotTo(HaveOccurred(), string(output))
+
+			documents = attached(digest)
+			Expect(documents).To(HaveKey(string(provenance.StatementMediaType)))
+
+			var unsigned provenance.Statement
+			Expect(json.Unmarshal(documents[string(provenance.StatementMediaType)], &unsigned)).To(Succeed())
+			Expect(unsigned.Subject).To(Equal(statement.Subject))
+		})
+	})
+}
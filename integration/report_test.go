@@ -0,0 +1,299 @@
+package integration_test
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/paketo-buildpacks/occam"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+)
+
+// ReportDirEnv names the directory the JUnit XML and JSON reports of the
+// suite are written to. No reports are written when it is not set.
+const ReportDirEnv = "INTEGRATION_REPORT_DIR"
+
+// builderResult collects the specs that ran against a builder.
+type builderResult struct {
+	Builder string `json:"builder"`
+
+	Passed  int `json:"passed"`
+	Failed  int `json:"failed"`
+	Skipped int `json:"skipped"`
+
+	Cases []caseResult `json:"cases"`
+}
+
+// caseResult describes a single spec.
+type caseResult struct {
+	Suite  string `json:"suite"`
+	Name   string `json:"name"`
+	Status string `json:"status"`
+
+	// Duration and BuildTime are in seconds, ImageSize is in bytes.
+	Duration  float64 `json:"duration"`
+	BuildTime float64 `json:"build_time,omitempty"`
+	ImageSize int64   `json:"image_size,omitempty"`
+
+	// Logs are the pack logs of the builds of a failed spec.
+	Logs string `json:"logs,omitempty"`
+}
+
+// caseRecord is what a running spec records about itself, keyed by the name
+// of its test.
+type caseRecord struct {
+	start     time.Time
+	buildTime time.Duration
+	imageSize int64
+	logs      []string
+}
+
+var (
+	recordsMutex sync.Mutex
+	records      = map[string]*caseRecord{}
+)
+
+func record(t *testing.T, f func(*caseRecord)) {
+	recordsMutex.Lock()
+	defer recordsMutex.Unlock()
+
+	r, ok := records[t.Name()]
+	if !ok {
+		r = &caseRecord{}
+		records[t.Name()] = r
+	}
+	f(r)
+}
+
+// recorded times every spec of a suite.
+func recorded(f func(*testing.T, spec.G, spec.S)) func(*testing.T, spec.G, spec.S) {
+	return func(t *testing.T, context spec.G, it spec.S) {
+		it.Before(func() {
+			record(t, func(r *caseRecord) { r.start = time.Now() })
+		})
+
+		f(t, context, it)
+	}
+}
+
+// executeBuild runs a pack build and records its duration, logs and image size
+// in the report of the spec.
+func executeBuild(t *testing.T, build occam.PackBuild, name, path string) (occam.Image, fmt.Stringer, error) {
+	start := time.Now()
+	image, logs, err := build.Execute(name, path)
+	buildTime := time.Since(start)
+
+	var size int64
+	if err == nil {
+		size, _ = imageSize(image.ID)
+	}
+
+	record(t, func(r *caseRecord) {
+		r.buildTime += buildTime
+		if size > 0 {
+			r.imageSize = size
+		}
+		if logs != nil {
+			r.logs = append(r.logs, logs.String())
+		}
+	})
+
+	return image, logs, err
+}
+
+// imageSize returns the size of an image in the Docker daemon, in bytes.
+func imageSize(ref string) (int64, error) {
+	output, err := exec.Command("docker", "image", "inspect", "--format", "{{.Size}}", ref).Output()
+	if err != nil {
+		return 0, err
+	}
+
+	return strconv.ParseInt(strings.TrimSpace(string(output)), 10, 64)
+}
+
+// summaryReporter reports specs to the terminal and collects them into the
+// result of the current builder.
+type summaryReporter struct {
+	report.Terminal
+
+	result *builderResult
+}
+
+func (r summaryReporter) Specs(t *testing.T, specs <-chan spec.Spec) {
+	forward := make(chan spec.Spec)
+	done := make(chan struct{})
+	go func() {
+		r.Terminal.Specs(t, forward)
+		close(done)
+	}()
+
+	for s := range specs {
+		result := caseResult{
+			Suite: s.Text[0],
+			Name:  strings.Join(s.Text[1:], " "),
+		}
+
+		switch {
+		case s.Failed:
+			r.result.Failed++
+			result.Status = "failed"
+		case s.Skipped:
+			r.result.Skipped++
+			result.Status = "skipped"
+		default:
+			r.result.Passed++
+			result.Status = "passed"
+		}
+
+		// the test of a spec is named after the suite and the spec text
+		name := fmt.Sprintf("%s/Integration/%s", t.Name(), strings.ReplaceAll(strings.Join(s.Text, "/"), " ", "_"))
+
+		recordsMutex.Lock()
+		if rec, ok := records[name]; ok {
+			if !rec.start.IsZero() {
+				result.Duration = time.Since(rec.start).Seconds()
+			}
+			result.BuildTime = rec.buildTime.Seconds()
+			result.ImageSize = rec.imageSize
+			if s.Failed {
+				result.Logs = strings.Join(rec.logs, "\n")
+			}
+		}
+		recordsMutex.Unlock()
+
+		r.result.Cases = append(r.result.Cases, result)
+
+		forward <- s
+	}
+
+	close(forward)
+	<-done
+}
+
+// writeReports writes the results of every builder to dir as junit.xml and
+// results.json.
+func writeReports(dir string, results []*builderResult) error {
+	err := os.MkdirAll(dir, os.ModePerm)
+	if err != nil {
+		return err
+	}
+
+	content, err := json.MarshalIndent(results, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	err = os.WriteFile(filepath.Join(dir, "results.json"), content, 0644)
+	if err != nil {
+		return err
+	}
+
+	content, err = xml.MarshalIndent(junitReport(results), "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filepath.Join(dir, "junit.xml"), append([]byte(xml.Header), content...), 0644)
+}
+
+type junitTestSuites struct {
+	XMLName xml.Name         `xml:"testsuites"`
+	Suites  []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name       string          `xml:"name,attr"`
+	Tests      int             `xml:"tests,attr"`
+	Failures   int             `xml:"failures,attr"`
+	Skipped    int             `xml:"skipped,attr"`
+	Time       string          `xml:"time,attr"`
+	Properties []junitProperty `xml:"properties>property"`
+	Cases      []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name       string           `xml:"name,attr"`
+	ClassName  string           `xml:"classname,attr"`
+	Time       string           `xml:"time,attr"`
+	Properties *junitProperties `xml:"properties"`
+	Failure    *junitMessage    `xml:"failure"`
+	Skipped    *junitMessage    `xml:"skipped"`
+	SystemOut  string           `xml:"system-out,omitempty"`
+}
+
+type junitProperties struct {
+	Properties []junitProperty `xml:"property"`
+}
+
+type junitProperty struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+}
+
+// junitReport renders one test suite per builder.
+func junitReport(results []*builderResult) junitTestSuites {
+	var report junitTestSuites
+	for _, result := range results {
+		suite := junitTestSuite{
+			Name:       result.Builder,
+			Tests:      len(result.Cases),
+			Failures:   result.Failed,
+			Skipped:    result.Skipped,
+			Properties: []junitProperty{{Name: "builder", Value: result.Builder}},
+		}
+
+		var total float64
+		for _, c := range result.Cases {
+			total += c.Duration
+
+			testCase := junitTestCase{
+				Name:      c.Name,
+				ClassName: fmt.Sprintf("%s.%s", result.Builder, c.Suite),
+				Time:      seconds(c.Duration),
+				SystemOut: c.Logs,
+			}
+
+			var properties []junitProperty
+			if c.BuildTime > 0 {
+				properties = append(properties, junitProperty{Name: "build_time", Value: seconds(c.BuildTime)})
+			}
+			if c.ImageSize > 0 {
+				properties = append(properties, junitProperty{Name: "image_size", Value: strconv.FormatInt(c.ImageSize, 10)})
+			}
+			if len(properties) > 0 {
+				testCase.Properties = &junitProperties{Properties: properties}
+			}
+
+			switch c.Status {
+			case "failed":
+				testCase.Failure = &junitMessage{Message: "spec failed, see the test output for details"}
+			case "skipped":
+				testCase.Skipped = &junitMessage{}
+			}
+
+			suite.Cases = append(suite.Cases, testCase)
+		}
+		suite.Time = seconds(total)
+
+		report.Suites = append(report.Suites, suite)
+	}
+
+	return report
+}
+
+func seconds(s float64) string {
+	return strconv.FormatFloat(s, 'f', 3, 64)
+}
@@ -3,8 +3,12 @@ package integration_test
 import (
 	"fmt"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/paketo-buildpacks/occam"
 	"github.com/sclevine/spec"
@@ -12,114 +16,165 @@ import (
 	. "github.com/onsi/gomega"
 )
 
+// defaultSourceDateEpoch is used as the creation time of reproducible images
+// when SOURCE_DATE_EPOCH is not set.
+//
+// Buildpacks cannot set the creation time of an image, the lifecycle exporter
+// does, so SOURCE_DATE_EPOCH only reaches it through the platform: pack takes
+// it as --creation-time. In the build, SOURCE_DATE_EPOCH is read by
+// sbom-aggregator for the creation time of the merged SBOMs and by
+// go-goreleaser for the build date. The specs set both.
+const defaultSourceDateEpoch = "1700000000"
+
 func testReproducibleBuilds(t *testing.T, context spec.G, it spec.S) {
 	var (
 		Expect = NewWithT(t).Expect
 
 		pack   occam.Pack
 		docker occam.Docker
+
+		sourceDateEpoch string
 	)
 
 	it.Before(func() {
 		pack = occam.NewPack()
 		docker = occam.NewDocker()
-	})
-
-	context("when building a go app with no package manager", func() {
-		var (
-			image occam.Image
-
-			name   string
-			source string
-		)
-
-		it.Before(func() {
-			var err error
-			name, err = occam.RandomName()
-			Expect(err).NotTo(HaveOccurred())
-
-			source, err = occam.Source(filepath.Join("testdata", "build"))
-			Expect(err).NotTo(HaveOccurred())
-		})
-
-		it.After(func() {
-			Expect(docker.Image.Remove.Execute(image.ID)).To(Succeed())
-			Expect(docker.Volume.Remove.Execute(occam.CacheVolumeNames(name))).To(Succeed())
-			Expect(os.RemoveAll(source)).To(Succeed())
-		})
 
-		it("creates a two identical images from the same input", func() {
-			var err error
-			var logs fmt.Stringer
-			image, logs, err = pack.WithNoColor().Build.
-				WithBuildpacks(goBuildpack).
-				WithPullPolicy("never").
-				Execute(name, source)
-			Expect(err).NotTo(HaveOccurred(), logs.String())
-
-			firstID := image.ID
-
-			// Delete the first image
-			Expect(docker.Image.Remove.Execute(image.ID)).To(Succeed())
-			Expect(docker.Volume.Remove.Execute(occam.CacheVolumeNames(name))).To(Succeed())
-
-			image, logs, err = pack.WithNoColor().Build.
-				WithBuildpacks(goBuildpack).
-				WithPullPolicy("never").
-				WithClearCache().
-				Execute(name, source)
-			Expect(err).NotTo(HaveOccurred(), logs.String())
-
-			Expect(firstID).To(Equal(image.ID))
-		})
+		sourceDateEpoch = defaultSourceDateEpoch
+		if epoch, ok := os.LookupEnv("SOURCE_DATE_EPOCH"); ok {
+			sourceDateEpoch = epoch
+		}
 	})
 
-	context("when building a go app using go mod", func() {
-		var (
-			image occam.Image
-
-			name   string
-			source string
-		)
-
-		it.Before(func() {
-			var err error
-			name, err = occam.RandomName()
-			Expect(err).NotTo(HaveOccurred())
-
-			source, err = occam.Source(filepath.Join("testdata", "go_mod"))
-			Expect(err).NotTo(HaveOccurred())
-		})
-
-		it.After(func() {
-			Expect(docker.Image.Remove.Execute(image.ID)).To(Succeed())
-			Expect(docker.Volume.Remove.Execute(occam.CacheVolumeNames(name))).To(Succeed())
-			Expect(os.RemoveAll(source)).To(Succeed())
+	for _, app := range []struct {
+		description string
+		fixture     string
+		path        string
+		env         map[string]string
+		procfile    string
+	}{
+		{
+			description: "a go app with no package manager",
+			fixture:     "build",
+		},
+		{
+			description: "a go app using go mod",
+			fixture:     "go_mod",
+		},
+		{
+			description: "a go app with vendored modules",
+			fixture:     "go_mod_vendored",
+		},
+		{
+			description: "a go app using CA certificates",
+			fixture:     "ca_certificate_apps",
+			path:        "build",
+			env:         map[string]string{"BP_KEEP_FILES": "key.pem:cert.pem"},
+		},
+		{
+			description: "a go app using the utility buildpacks",
+			fixture:     "go_mod",
+			env: map[string]string{
+				"BPE_SOME_VARIABLE":      "some-value",
+				"BP_IMAGE_LABELS":        "some-label=some-value",
+				"BP_LIVE_RELOAD_ENABLED": "true",
+			},
+			procfile: "procfile: /layers/paketo-buildpacks_go-build/targets/bin/go-online --moon",
+		},
+	} {
+		app := app
+
+		context(fmt.Sprintf("when building %s", app.description), func() {
+			var (
+				name   string
+				source string
+			)
+
+			it.Before(func() {
+				var err error
+				name, err = occam.RandomName()
+				Expect(err).NotTo(HaveOccurred())
+
+				source, err = occam.Source(filepath.Join("testdata", app.fixture))
+				Expect(err).NotTo(HaveOccurred())
+
+				if app.procfile != "" {
+					Expect(os.WriteFile(filepath.Join(source, app.path, "Procfile"), []byte(app.procfile), 0644)).To(Succeed())
+				}
+			})
+
+			it.After(func() {
+				for _, ref := range []string{name, fmt.Sprintf("%s:first", name)} {
+					if _, err := docker.Image.Inspect.Execute(ref); err == nil {
+						Expect(docker.Image.Remove.Execute(ref)).To(Succeed())
+					}
+				}
+				Expect(docker.Volume.Remove.Execute(occam.CacheVolumeNames(name))).To(Succeed())
+				Expect(os.RemoveAll(source)).To(Succeed())
+			})
+
+			it("creates two identical images from the same input", func() {
+				env := map[string]string{"SOURCE_DATE_EPOCH": sourceDateEpoch}
+				for key, value := range app.env {
+					env[key] = value
+				}
+
+				// the creation time of the image comes from the platform flag,
+				// the environment variable from the buildpacks that read it
+				build := pack.WithNoColor().Build.
+					WithBuilder(builder).
+					WithBuildpacks(goBuildpack).
+					WithPullPolicy("never").
+					WithEnv(env).
+					WithAdditionalBuildArgs("--creation-time", sourceDateEpoch)
+
+				_, logs, err := executeBuild(t, build, name, filepath.Join(source, app.path))
+				Expect(err).NotTo(HaveOccurred(), logs.String())
+
+				// Keep the first image under another tag, so that the second
+				// build cannot reuse its layers
+				first := fmt.Sprintf("%s:first", name)
+				Expect(docker.Image.Tag.Execute(name, first)).To(Succeed())
+				Expect(docker.Image.Remove.Execute(name)).To(Succeed())
+				Expect(docker.Volume.Remove.Execute(occam.CacheVolumeNames(name))).To(Succeed())
+
+				_, logs, err = executeBuild(t, build.WithClearCache(), name, filepath.Join(source, app.path))
+				Expect(err).NotTo(HaveOccurred(), logs.String())
+
+				expectReproducible(t, first, name)
+
+				image, err := docker.Image.ExportToOCI.Execute(name)
+				Expect(err).NotTo(HaveOccurred())
+
+				config, err := image.ConfigFile()
+				Expect(err).NotTo(HaveOccurred())
+
+				seconds, err := strconv.ParseInt(sourceDateEpoch, 10, 64)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(config.Created.Time).To(BeTemporally("==", time.Unix(seconds, 0)))
+			})
 		})
+	}
+}
 
-		it("creates a two identical images from the same input", func() {
-			var err error
-			var logs fmt.Stringer
-			image, logs, err = pack.WithNoColor().Build.
-				WithBuildpacks(goBuildpack).
-				WithPullPolicy("never").
-				Execute(name, source)
-			Expect(err).NotTo(HaveOccurred(), logs.String())
-
-			firstID := image.ID
-
-			// Delete the first image
-			Expect(docker.Image.Remove.Execute(image.ID)).To(Succeed())
-			Expect(docker.Volume.Remove.Execute(occam.CacheVolumeNames(name))).To(Succeed())
-
-			image, logs, err = pack.WithNoColor().Build.
-				WithBuildpacks(goBuildpack).
-				WithPullPolicy("never").
-				WithClearCache().
-				Execute(name, source)
-			Expect(err).NotTo(HaveOccurred(), logs.String())
-
-			Expect(firstID).To(Equal(image.ID))
-		})
-	})
+// expectReproducible compares two images with cmd/image-diff and fails the
+// test with its layer-by-layer and file-by-file report when they differ. The
+// report is also kept as JSON in REPRODUCIBILITY_REPORT_DIR so that CI can
+// attach it to the failed run; without it, the report is written to a
+// directory that is removed with the test.
+func expectReproducible(t *testing.T, a, b string) {
+	t.Helper()
+	Expect := NewWithT(t).Expect
+
+	dir, ok := os.LookupEnv("REPRODUCIBILITY_REPORT_DIR")
+	if !ok {
+		dir = t.TempDir()
+	}
+	Expect(os.MkdirAll(dir, os.ModePerm)).To(Succeed())
+
+	reportPath := filepath.Join(dir, fmt.Sprintf("%s.json", strings.NewReplacer("/", "_", ":", "_").Replace(b)))
+
+	output, err := exec.Command("go", "run", "../cmd/image-diff", "--output", reportPath, a, b).CombinedOutput()
+	Expect(err).NotTo(HaveOccurred(), fmt.Sprintf("%s\nreport: %s", output, reportPath))
+	Expect(os.Remove(reportPath)).To(Succeed())
 }
@@ -0,0 +1,105 @@
+package integration_test
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/paketo-buildpacks/go/sbom"
+
+	. "github.com/onsi/gomega"
+)
+
+var selectedGoVersion = regexp.MustCompile(`Selected Go version \(using [^)]*\): (\S+)`)
+
+// validateSBOMs checks every CycloneDX, SPDX and Syft document below the
+// directory written by `pack build --sbom-output-dir` against the schema
+// for the version it declares.
+func validateSBOMs(t *testing.T, dir string) {
+	t.Helper()
+	Expect := NewWithT(t).Expect
+
+	var count int
+	for _, extension := range []string{sbom.CycloneDXExtension, sbom.SPDXExtension, sbom.SyftExtension} {
+		paths, err := sbom.Find(dir, extension)
+		Expect(err).NotTo(HaveOccurred())
+
+		for _, path := range paths {
+			content, err := os.ReadFile(path)
+			Expect(err).NotTo(HaveOccurred())
+
+			relative, err := filepath.Rel(dir, path)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(sbom.Validate(content)).To(Succeed(), relative)
+			count++
+		}
+	}
+
+	Expect(count).NotTo(BeZero(), "no SBOM documents found in %s", dir)
+}
+
+// goDistVersion returns the Go version that go-dist installed, as printed in
+// the build logs.
+func goDistVersion(t *testing.T, logs string) string {
+	t.Helper()
+
+	matches := selectedGoVersion.FindStringSubmatch(logs)
+	NewWithT(t).Expect(matches).To(HaveLen(2), "go-dist did not log the selected Go version")
+
+	return matches[1]
+}
+
+// cycloneDXVersions returns the versions of the components with the given
+// name in a CycloneDX document, without any "go" or "v" prefix.
+func cycloneDXVersions(t *testing.T, path, name string) []string {
+	t.Helper()
+	Expect := NewWithT(t).Expect
+
+	content, err := os.ReadFile(path)
+	Expect(err).NotTo(HaveOccurred())
+
+	var document sbom.CycloneDXDocument
+	Expect(json.Unmarshal(content, &document)).To(Succeed())
+
+	var versions []string
+	for _, component := range document.Components {
+		if componentName, ok := component["name"].(string); !ok || !strings.EqualFold(componentName, name) {
+			continue
+		}
+
+		version, _ := component["version"].(string)
+		versions = append(versions, strings.TrimPrefix(strings.TrimPrefix(version, "go"), "v"))
+	}
+
+	return versions
+}
+
+// goSumModules returns the module versions in a go.sum file that have a
+// hash for their content, rather than only for their go.mod file.
+func goSumModules(t *testing.T, path string) map[string]string {
+	t.Helper()
+	Expect := NewWithT(t).Expect
+
+	file, err := os.Open(path)
+	Expect(err).NotTo(HaveOccurred())
+	defer file.Close()
+
+	modules := map[string]string{}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 3 || strings.HasSuffix(fields[1], "/go.mod") {
+			continue
+		}
+
+		modules[fields[0]] = fields[1]
+	}
+	Expect(scanner.Err()).NotTo(HaveOccurred())
+
+	return modules
+}
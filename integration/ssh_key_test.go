@@ -0,0 +1,224 @@
+package integration_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/paketo-buildpacks/go/sshkey"
+	"github.com/paketo-buildpacks/occam"
+	"github.com/sclevine/spec"
+	"golang.org/x/crypto/ssh"
+
+	. "github.com/onsi/gomega"
+	. "github.com/paketo-buildpacks/occam/matchers"
+)
+
+func testSSHKey(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect     = NewWithT(t).Expect
+		Eventually = NewWithT(t).Eventually
+
+		pack   occam.Pack
+		docker occam.Docker
+	)
+
+	it.Before(func() {
+		pack = occam.NewPack()
+		docker = occam.NewDocker()
+	})
+
+	context("when a module is only reachable over SSH", func() {
+		var (
+			image     occam.Image
+			container occam.Container
+			server    occam.Container
+
+			name        string
+			network     string
+			serverImage string
+			source      string
+			bindings    string
+			privateKey  []byte
+			build       occam.PackBuild
+		)
+
+		// sshKeyPair returns a new ed25519 key as a PEM encoded OpenSSH private
+		// key and an authorized_keys line
+		sshKeyPair := func() ([]byte, []byte) {
+			public, private, err := ed25519.GenerateKey(rand.Reader)
+			Expect(err).NotTo(HaveOccurred())
+
+			block, err := ssh.MarshalPrivateKey(private, "")
+			Expect(err).NotTo(HaveOccurred())
+
+			publicKey, err := ssh.NewPublicKey(public)
+			Expect(err).NotTo(HaveOccurred())
+
+			return pem.EncodeToMemory(block), ssh.MarshalAuthorizedKey(publicKey)
+		}
+
+		it.Before(func() {
+			var err error
+			name, err = occam.RandomName()
+			Expect(err).NotTo(HaveOccurred())
+			network = name + "-network"
+			serverImage = name + "-git"
+
+			output, err := exec.Command("docker", "network", "create", network).CombinedOutput()
+			Expect(err).NotTo(HaveOccurred(), string(output))
+
+			output, err = exec.Command("docker", "build", "--tag", serverImage, filepath.Join("testdata", "ssh_key", "server")).CombinedOutput()
+			Expect(err).NotTo(HaveOccurred(), string(output))
+
+			// the git user of the server reads the repositories and its keys, so
+			// both must be readable by others
+			repositories, err := createRepositories(t, filepath.Join("testdata", "ssh_key", "repositories"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(os.Chmod(repositories, 0755)).To(Succeed())
+
+			hostKey, hostPublicKey := sshKeyPair()
+			var authorizedKey []byte
+			privateKey, authorizedKey = sshKeyPair()
+
+			keys := t.TempDir()
+			Expect(os.Chmod(keys, 0755)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(keys, "host_key"), hostKey, 0600)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(keys, "authorized_keys"), authorizedKey, 0644)).To(Succeed())
+
+			// the server is only reachable on the network, and only over SSH
+			server, err = docker.Container.Run.
+				WithNetwork(network).
+				WithVolumes(
+					fmt.Sprintf("%s:/etc/ssh/keys:ro", keys),
+					fmt.Sprintf("%s:/srv/git:ro", repositories),
+				).
+				Execute(serverImage)
+			Expect(err).NotTo(HaveOccurred())
+
+			host := server.IPAddresses[network]
+
+			bindings = t.TempDir()
+			Expect(os.Chmod(bindings, 0755)).To(Succeed())
+
+			binding := filepath.Join(bindings, "ssh")
+			Expect(os.MkdirAll(binding, 0755)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(binding, "type"), []byte(sshkey.BindingType), 0644)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(binding, sshkey.PrivateKeyEntry), privateKey, 0644)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(binding, sshkey.KnownHostsEntry), fmt.Appendf(nil, "%s %s", host, hostPublicKey), 0644)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(binding, sshkey.URLRewritesEntry), fmt.Appendf(nil, "https://git.paketo.test/private/=ssh://git@%s/srv/git/\n", host), 0644)).To(Succeed())
+
+			source, err = occam.Source(filepath.Join("testdata", "ssh_key", "app"))
+			Expect(err).NotTo(HaveOccurred())
+
+			build = pack.WithNoColor().Build.
+				WithBuilder(builder).
+				WithBuildpacks(goBuildpack).
+				WithPullPolicy("never").
+				WithNetwork(network).
+				WithEnv(map[string]string{"GOPRIVATE": "git.paketo.test"})
+		})
+
+		it.After(func() {
+			if container.ID != "" {
+				Expect(docker.Container.Remove.Execute(container.ID)).To(Succeed())
+			}
+			if image.ID != "" {
+				Expect(docker.Image.Remove.Execute(image.ID)).To(Succeed())
+			}
+			Expect(docker.Volume.Remove.Execute(occam.CacheVolumeNames(name))).To(Succeed())
+			Expect(docker.Container.Remove.Execute(server.ID)).To(Succeed())
+			Expect(docker.Image.Remove.Execute(serverImage)).To(Succeed())
+
+			output, err := exec.Command("docker", "network", "rm", network).CombinedOutput()
+			Expect(err).NotTo(HaveOccurred(), string(output))
+			Expect(os.RemoveAll(source)).To(Succeed())
+		})
+
+		it("fetches the module with the key and keeps the key out of the image and the cache", func() {
+			var err error
+			var logs fmt.Stringer
+			image, logs, err = executeBuild(t, build.
+				WithAdditionalEnv(map[string]string{"SERVICE_BINDING_ROOT": "/bindings"}).
+				WithVolumes(fmt.Sprintf("%s:/bindings", bindings)),
+				name, source)
+			Expect(err).NotTo(HaveOccurred(), logs.String())
+
+			Expect(logs).To(ContainLines(ContainSubstring("Paketo Buildpack for Go SSH Key")))
+			Expect(logs).To(ContainLines(ContainSubstring("Installing the SSH key of binding ssh")))
+			Expect(logs).To(ContainLines(ContainSubstring("Buildpack for Go Mod Vendor")))
+
+			container, err = docker.Container.Run.
+				WithEnv(map[string]string{"PORT": "8080"}).
+				WithPublish("8080").
+				WithPublishAll().
+				Execute(image.ID)
+			Expect(err).NotTo(HaveOccurred())
+
+			Eventually(container).Should(Serve(ContainSubstring("Hello over SSH!")).OnPort(8080))
+
+			// the body of the key is unique to this run, so finding any of it
+			// means the key leaked
+			keyLines := strings.Split(strings.TrimSpace(string(privateKey)), "\n")
+			needle := keyLines[1]
+
+			exported, err := docker.Image.ExportToOCI.Execute(image.ID)
+			Expect(err).NotTo(HaveOccurred())
+
+			layers, err := exported.Layers()
+			Expect(err).NotTo(HaveOccurred())
+
+			for _, layer := range layers {
+				reader, err := layer.Uncompressed()
+				Expect(err).NotTo(HaveOccurred())
+
+				tr := tar.NewReader(reader)
+				for {
+					header, err := tr.Next()
+					if errors.Is(err, io.EOF) {
+						break
+					}
+					Expect(err).NotTo(HaveOccurred())
+
+					content, err := io.ReadAll(tr)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(bytes.Contains(content, []byte(needle))).To(BeFalse(), fmt.Sprintf("%s holds the SSH private key", header.Name))
+				}
+
+				Expect(reader.Close()).To(Succeed())
+			}
+
+			// the cache volumes are searched with the grep of the server image,
+			// which exits with 1 when nothing matches
+			args := []string{"run", "--rm", "--entrypoint", "grep"}
+			for _, volume := range occam.CacheVolumeNames(name) {
+				args = append(args, "--volume", fmt.Sprintf("%s:/cache/%s:ro", volume, volume))
+			}
+			args = append(args, serverImage, "-rlF", needle, "/cache")
+
+			output, err := exec.Command("docker", args...).CombinedOutput()
+			var exitErr *exec.ExitError
+			Expect(errors.As(err, &exitErr)).To(BeTrue(), fmt.Sprintf("the SSH private key is in the build cache:\n%s", output))
+			Expect(exitErr.ExitCode()).To(Equal(1), string(output))
+		})
+
+		it("fails to fetch the module without the binding", func() {
+			_, logs, err := executeBuild(t, build, name, source)
+			Expect(err).To(HaveOccurred(), logs.String())
+
+			Expect(logs).NotTo(ContainLines(ContainSubstring("Paketo Buildpack for Go SSH Key")))
+			Expect(logs).To(ContainLines(ContainSubstring("Buildpack for Go Mod Vendor")))
+			Expect(logs).To(ContainLines(ContainSubstring("git.paketo.test/private/greeting.git")))
+		})
+	})
+}
@@ -0,0 +1,28 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+)
+
+const greeting = "Hello from Bazel!"
+
+func main() {
+	greetingPtr := flag.Bool("greeting", false, "print the greeting and exit")
+
+	flag.Parse()
+
+	if *greetingPtr {
+		fmt.Println(greeting)
+		return
+	}
+
+	http.HandleFunc("/", func(w http.ResponseWriter, req *http.Request) {
+		fmt.Fprint(w, greeting)
+	})
+
+	log.Fatal(http.ListenAndServe(":"+os.Getenv("PORT"), nil))
+}
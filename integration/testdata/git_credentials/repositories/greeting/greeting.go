@@ -0,0 +1,8 @@
+// Package greeting is only served by the authenticated git server of the
+// GitCredentials integration suite.
+package greeting
+
+// Hello returns the greeting the application serves.
+func Hello() string {
+	return "Hello from a private module!"
+}
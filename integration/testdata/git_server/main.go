@@ -0,0 +1,32 @@
+package main
+
+import (
+	"log"
+	"net/http"
+	"net/http/cgi"
+	"os"
+)
+
+func main() {
+	username := os.Getenv("GIT_USERNAME")
+	password := os.Getenv("GIT_PASSWORD")
+
+	backend := &cgi.Handler{
+		Path: "/usr/bin/git",
+		Args: []string{"http-backend"},
+		Env:  []string{"GIT_PROJECT_ROOT=/srv/git", "GIT_HTTP_EXPORT_ALL=1"},
+	}
+
+	http.HandleFunc("/", func(w http.ResponseWriter, req *http.Request) {
+		user, pass, ok := req.BasicAuth()
+		if !ok || user != username || pass != password {
+			w.Header().Set("WWW-Authenticate", `Basic realm="git"`)
+			http.Error(w, "authentication required", http.StatusUnauthorized)
+			return
+		}
+
+		backend.ServeHTTP(w, req)
+	})
+
+	log.Fatal(http.ListenAndServe(":8080", nil))
+}
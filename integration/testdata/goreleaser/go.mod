@@ -0,0 +1,3 @@
+module goreleaser-app
+
+go 1.18
@@ -0,0 +1,3 @@
+module hardening
+
+go 1.18
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+)
+
+func main() {
+	http.HandleFunc("/", func(w http.ResponseWriter, req *http.Request) {
+		// writes go to TMPDIR, which has to be writable on a read-only root
+		// filesystem
+		file, err := os.CreateTemp("", "request-*")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		defer os.Remove(file.Name())
+		file.Close()
+
+		// kept files are only read
+		config, err := os.ReadFile("config.yml")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		fmt.Fprintf(w, "Hello from uid %d, wrote %s, read %s", os.Getuid(), file.Name(), strings.TrimSpace(string(config)))
+	})
+
+	log.Fatal(http.ListenAndServe(":"+os.Getenv("PORT"), nil))
+}
@@ -0,0 +1,24 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+
+	"example.com/greeting"
+)
+
+func main() {
+	http.HandleFunc("/", func(res http.ResponseWriter, req *http.Request) {
+		fmt.Fprint(res, greeting.Hello())
+	})
+
+	port := "8080"
+	if systemPort := os.Getenv("PORT"); systemPort != "" {
+		port = systemPort
+	}
+
+	if err := http.ListenAndServe(":"+port, nil); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,5 @@
+module licenses-denied
+
+go 1.18
+
+require example.com/greeting v1.0.0
@@ -0,0 +1,3 @@
+module make-build
+
+go 1.18
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"sync"
+)
+
+// export is a trace export the collector received. The body is kept as it is,
+// since the names of services and spans are plain strings in the protobuf
+// encoding.
+type export struct {
+	APIKey      string `json:"apiKey"`
+	ContentType string `json:"contentType"`
+	Body        []byte `json:"body"`
+}
+
+func main() {
+	var (
+		mutex    sync.Mutex
+		received []export
+	)
+
+	http.HandleFunc("POST /v1/traces", func(w http.ResponseWriter, req *http.Request) {
+		body, err := io.ReadAll(req.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		mutex.Lock()
+		received = append(received, export{
+			APIKey:      req.Header.Get("X-Api-Key"),
+			ContentType: req.Header.Get("Content-Type"),
+			Body:        body,
+		})
+		mutex.Unlock()
+
+		// an empty ExportTraceServiceResponse
+		w.Header().Set("Content-Type", "application/x-protobuf")
+		w.WriteHeader(http.StatusOK)
+	})
+
+	http.HandleFunc("GET /received", func(w http.ResponseWriter, req *http.Request) {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(received)
+	})
+
+	log.Fatal(http.ListenAndServe(":4318", nil))
+}
@@ -0,0 +1,5 @@
+module ssh-key
+
+go 1.18
+
+require git.paketo.test/private/greeting.git v1.0.0
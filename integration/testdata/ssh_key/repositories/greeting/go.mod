@@ -0,0 +1,3 @@
+module git.paketo.test/private/greeting.git
+
+go 1.18
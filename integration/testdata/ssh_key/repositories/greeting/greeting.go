@@ -0,0 +1,8 @@
+// Package greeting is only served by the SSH git server of the SSHKey
+// integration suite.
+package greeting
+
+// Hello returns the greeting the application serves.
+func Hello() string {
+	return "Hello over SSH!"
+}
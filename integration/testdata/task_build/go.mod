@@ -0,0 +1,3 @@
+module task-build
+
+go 1.18
@@ -0,0 +1,29 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+)
+
+// version is set by the task target from the VERSION file.
+var version = "dev"
+
+func main() {
+	versionPtr := flag.Bool("version", false, "print the version")
+
+	flag.Parse()
+
+	if *versionPtr {
+		fmt.Println(version)
+		return
+	}
+
+	http.HandleFunc("/", func(w http.ResponseWriter, req *http.Request) {
+		fmt.Fprintf(w, "Hello from task, version %s!", version)
+	})
+
+	log.Fatal(http.ListenAndServe(":"+os.Getenv("PORT"), nil))
+}
@@ -0,0 +1,15 @@
+package licenses_test
+
+import (
+	"testing"
+
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+)
+
+func TestUnitLicenses(t *testing.T) {
+	suite := spec.New("licenses", spec.Report(report.Terminal{}), spec.Parallel())
+	suite("Licenses", testLicenses)
+	suite("Policy", testPolicy)
+	suite.Run(t)
+}
@@ -0,0 +1,204 @@
+// Package licenses takes an inventory of the licenses of the Go modules an
+// application is built from and checks it against a deny-list.
+package licenses
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/google/licensecheck"
+	"golang.org/x/mod/module"
+)
+
+// Unknown is recorded for modules without a license file that matches a
+// known license.
+const Unknown = "UNKNOWN"
+
+// minimumCoverage is the percentage of a license file that has to match known
+// licenses for its matches to be trusted.
+const minimumCoverage = 75
+
+var licenseFile = regexp.MustCompile(`(?i)^(licen[cs]e|copying|unlicense)([.\-_].*)?$`)
+
+// Module is a Go module whose source is available in Dir.
+type Module struct {
+	Path    string `json:"path"`
+	Version string `json:"version"`
+	Dir     string `json:"-"`
+}
+
+// Report is the license inventory of a set of modules.
+type Report struct {
+	Modules []ModuleLicenses `json:"modules"`
+}
+
+// ModuleLicenses lists the licenses detected for a module and the files they
+// were detected in, relative to the module directory.
+type ModuleLicenses struct {
+	Module
+	Licenses []string `json:"licenses"`
+	Files    []string `json:"files,omitempty"`
+}
+
+// VendoredModules lists the modules in a vendor directory, as recorded in its
+// modules.txt. Modules replaced by another module are recorded at the version
+// of the replacement. Modules that no package is vendored from are skipped
+// because their source is not part of the vendor directory.
+func VendoredModules(vendorDir string) ([]Module, error) {
+	file, err := os.Open(filepath.Join(vendorDir, "modules.txt"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read vendored modules: %w", err)
+	}
+	defer file.Close()
+
+	var modules []Module
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 3 || fields[0] != "#" {
+			continue
+		}
+
+		module := Module{Path: fields[1]}
+		rest := fields[2:]
+		if rest[0] != "=>" {
+			module.Version = rest[0]
+			rest = rest[1:]
+		}
+
+		if len(rest) >= 3 && rest[0] == "=>" {
+			module.Version = rest[2]
+		}
+
+		module.Dir = filepath.Join(vendorDir, filepath.FromSlash(module.Path))
+		if _, err := os.Stat(module.Dir); err != nil {
+			continue
+		}
+
+		modules = append(modules, module)
+	}
+
+	err = scanner.Err()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read vendored modules: %w", err)
+	}
+
+	return modules, nil
+}
+
+// CachedModules lists the modules with a content hash in a go.sum file whose
+// source is present in a module cache, such as the one go-mod-vendor fills
+// for applications that do not vendor their dependencies.
+func CachedModules(modCache, goSum string) ([]Module, error) {
+	file, err := os.Open(goSum)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read go.sum: %w", err)
+	}
+	defer file.Close()
+
+	var modules []Module
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 3 || strings.HasSuffix(fields[1], "/go.mod") {
+			continue
+		}
+
+		path, err := module.EscapePath(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("failed to read go.sum: %w", err)
+		}
+
+		version, err := module.EscapeVersion(fields[1])
+		if err != nil {
+			return nil, fmt.Errorf("failed to read go.sum: %w", err)
+		}
+
+		dir := filepath.Join(modCache, filepath.FromSlash(path)+"@"+version)
+		if _, err := os.Stat(dir); err != nil {
+			continue
+		}
+
+		modules = append(modules, Module{Path: fields[0], Version: fields[1], Dir: dir})
+	}
+
+	err = scanner.Err()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read go.sum: %w", err)
+	}
+
+	return modules, nil
+}
+
+// Inventory detects the licenses of every module.
+func Inventory(modules []Module) (Report, error) {
+	var report Report
+	for _, module := range modules {
+		licenses, files, err := Detect(module.Dir)
+		if err != nil {
+			return Report{}, fmt.Errorf("failed to detect licenses of %s: %w", module.Path, err)
+		}
+
+		if len(licenses) == 0 {
+			licenses = []string{Unknown}
+		}
+
+		report.Modules = append(report.Modules, ModuleLicenses{
+			Module:   module,
+			Licenses: licenses,
+			Files:    files,
+		})
+	}
+
+	sort.Slice(report.Modules, func(i, j int) bool {
+		return report.Modules[i].Path < report.Modules[j].Path
+	})
+
+	return report, nil
+}
+
+// Detect returns the SPDX identifiers of the licenses found in the license
+// files at the root of a module directory, together with the names of the
+// files they were found in.
+func Detect(dir string) ([]string, []string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ids := map[string]bool{}
+	var files []string
+	for _, entry := range entries {
+		if entry.IsDir() || !licenseFile.MatchString(entry.Name()) {
+			continue
+		}
+
+		content, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, nil, err
+		}
+
+		coverage := licensecheck.Scan(content)
+		if coverage.Percent < minimumCoverage {
+			continue
+		}
+
+		for _, match := range coverage.Match {
+			ids[match.ID] = true
+		}
+		files = append(files, entry.Name())
+	}
+
+	var licenses []string
+	for id := range ids {
+		licenses = append(licenses, id)
+	}
+	sort.Strings(licenses)
+
+	return licenses, files, nil
+}
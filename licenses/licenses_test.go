@@ -0,0 +1,98 @@
+package licenses_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/paketo-buildpacks/go/licenses"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testLicenses(t *testing.T, context spec.G, it spec.S) {
+	var Expect = NewWithT(t).Expect
+
+	context("VendoredModules", func() {
+		it("lists the modules that packages are vendored from", func() {
+			vendorDir := filepath.Join("testdata", "vendor")
+
+			modules, err := licenses.VendoredModules(vendorDir)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(modules).To(Equal([]licenses.Module{
+				{Path: "example.com/gpl", Version: "v1.2.0", Dir: filepath.Join(vendorDir, "example.com", "gpl")},
+				{Path: "example.com/local", Version: "v1.0.0", Dir: filepath.Join(vendorDir, "example.com", "local")},
+				{Path: "example.com/mit", Version: "v1.0.1", Dir: filepath.Join(vendorDir, "example.com", "mit")},
+				{Path: "example.com/none", Version: "v0.0.1", Dir: filepath.Join(vendorDir, "example.com", "none")},
+			}))
+		})
+
+		context("when there is no modules.txt", func() {
+			it("returns an error", func() {
+				_, err := licenses.VendoredModules(t.TempDir())
+				Expect(err).To(MatchError(ContainSubstring("failed to read vendored modules")))
+			})
+		})
+	})
+
+	context("CachedModules", func() {
+		it("lists the modules from go.sum that are in the module cache", func() {
+			modCache := filepath.Join("testdata", "modcache")
+
+			modules, err := licenses.CachedModules(modCache, filepath.Join("testdata", "go.sum"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(modules).To(Equal([]licenses.Module{
+				{Path: "example.com/SomeModule", Version: "v1.0.0", Dir: filepath.Join(modCache, "example.com", "!some!module@v1.0.0")},
+			}))
+		})
+
+		context("when go.sum does not exist", func() {
+			it("returns an error", func() {
+				_, err := licenses.CachedModules(t.TempDir(), filepath.Join(t.TempDir(), "go.sum"))
+				Expect(err).To(MatchError(ContainSubstring("failed to read go.sum")))
+			})
+		})
+	})
+
+	context("Inventory", func() {
+		it("detects the licenses of every module", func() {
+			modules, err := licenses.VendoredModules(filepath.Join("testdata", "vendor"))
+			Expect(err).NotTo(HaveOccurred())
+
+			report, err := licenses.Inventory(modules)
+			Expect(err).NotTo(HaveOccurred())
+
+			var summary []string
+			for _, module := range report.Modules {
+				summary = append(summary, module.Path+" "+module.Licenses[0]+" "+filepath.Join(module.Files...))
+			}
+
+			Expect(summary).To(Equal([]string{
+				"example.com/gpl GPL-3.0-only COPYING",
+				"example.com/local MIT LICENSE",
+				"example.com/mit MIT LICENSE",
+				"example.com/none UNKNOWN ",
+			}))
+		})
+
+		context("when a module directory cannot be read", func() {
+			it("returns an error", func() {
+				_, err := licenses.Inventory([]licenses.Module{{Path: "example.com/missing", Dir: filepath.Join(t.TempDir(), "missing")}})
+				Expect(err).To(MatchError(ContainSubstring("failed to detect licenses of example.com/missing")))
+			})
+		})
+	})
+
+	context("Detect", func() {
+		it("ignores files that are mostly not license text", func() {
+			dir := t.TempDir()
+			Expect(os.WriteFile(filepath.Join(dir, "LICENSE"), []byte("All rights reserved. Ask before you use this."), 0600)).To(Succeed())
+
+			ids, files, err := licenses.Detect(dir)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ids).To(BeEmpty())
+			Expect(files).To(BeEmpty())
+		})
+	})
+}
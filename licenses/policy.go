@@ -0,0 +1,84 @@
+package licenses
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DefaultDenyList denies the GPL and AGPL licenses in all of their versions.
+var DefaultDenyList = []string{"GPL-*", "AGPL-*"}
+
+// Policy denies modules that are only published under licenses in Deny. A
+// module published under several licenses, such as a dual-licensed one, may
+// be used under any of them, so it is only denied when all of them are.
+// Entries are SPDX identifiers, optionally ending in "*" to match every
+// identifier with that prefix, so "GPL-*" matches "GPL-3.0-only" but not
+// "LGPL-3.0-only".
+type Policy struct {
+	Deny []string
+}
+
+// ParseDenyList parses a comma separated list of deny-list entries.
+func ParseDenyList(value string) []string {
+	var entries []string
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry != "" {
+			entries = append(entries, entry)
+		}
+	}
+
+	return entries
+}
+
+// Violation is a module published under denied licenses only.
+type Violation struct {
+	Module   Module
+	Licenses []string
+}
+
+func (v Violation) String() string {
+	return fmt.Sprintf("%s@%s: %s", v.Module.Path, v.Module.Version, strings.Join(v.Licenses, ", "))
+}
+
+// Check returns every module in the report that is published under denied
+// licenses only.
+func (p Policy) Check(report Report) []Violation {
+	var violations []Violation
+	for _, module := range report.Modules {
+		if len(module.Licenses) == 0 {
+			continue
+		}
+
+		denied := true
+		for _, license := range module.Licenses {
+			if !p.denies(license) {
+				denied = false
+				break
+			}
+		}
+
+		if denied {
+			violations = append(violations, Violation{Module: module.Module, Licenses: module.Licenses})
+		}
+	}
+
+	return violations
+}
+
+func (p Policy) denies(license string) bool {
+	for _, entry := range p.Deny {
+		if prefix, ok := strings.CutSuffix(entry, "*"); ok {
+			if strings.HasPrefix(license, prefix) {
+				return true
+			}
+			continue
+		}
+
+		if license == entry {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,55 @@
+package licenses_test
+
+import (
+	"testing"
+
+	"github.com/paketo-buildpacks/go/licenses"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testPolicy(t *testing.T, context spec.G, it spec.S) {
+	var Expect = NewWithT(t).Expect
+
+	report := licenses.Report{
+		Modules: []licenses.ModuleLicenses{
+			{Module: licenses.Module{Path: "example.com/agpl", Version: "v1.0.0"}, Licenses: []string{"AGPL-3.0-only"}},
+			{Module: licenses.Module{Path: "example.com/dual", Version: "v1.0.0"}, Licenses: []string{"GPL-2.0-or-later", "MIT"}},
+			{Module: licenses.Module{Path: "example.com/gpl", Version: "v1.0.0"}, Licenses: []string{"AGPL-3.0-only", "GPL-3.0-only"}},
+			{Module: licenses.Module{Path: "example.com/lgpl", Version: "v1.0.0"}, Licenses: []string{"LGPL-3.0-only"}},
+			{Module: licenses.Module{Path: "example.com/mit", Version: "v1.0.0"}, Licenses: []string{"MIT"}},
+		},
+	}
+
+	context("Check", func() {
+		it("denies GPL and AGPL licenses by default", func() {
+			violations := licenses.Policy{Deny: licenses.DefaultDenyList}.Check(report)
+			Expect(violations).To(Equal([]licenses.Violation{
+				{Module: licenses.Module{Path: "example.com/agpl", Version: "v1.0.0"}, Licenses: []string{"AGPL-3.0-only"}},
+				{Module: licenses.Module{Path: "example.com/gpl", Version: "v1.0.0"}, Licenses: []string{"AGPL-3.0-only", "GPL-3.0-only"}},
+			}))
+			Expect(violations[0].String()).To(Equal("example.com/agpl@v1.0.0: AGPL-3.0-only"))
+			Expect(violations[1].String()).To(Equal("example.com/gpl@v1.0.0: AGPL-3.0-only, GPL-3.0-only"))
+		})
+
+		it("allows modules that are also published under an allowed license", func() {
+			violations := licenses.Policy{Deny: []string{"GPL-*"}}.Check(report)
+			Expect(violations).To(BeEmpty())
+		})
+
+		it("matches entries without a wildcard exactly", func() {
+			violations := licenses.Policy{Deny: []string{"LGPL-3.0-only", "MIT-0"}}.Check(report)
+			Expect(violations).To(Equal([]licenses.Violation{
+				{Module: licenses.Module{Path: "example.com/lgpl", Version: "v1.0.0"}, Licenses: []string{"LGPL-3.0-only"}},
+			}))
+		})
+	})
+
+	context("ParseDenyList", func() {
+		it("splits a comma separated list", func() {
+			Expect(licenses.ParseDenyList(" GPL-*, AGPL-3.0-only,,")).To(Equal([]string{"GPL-*", "AGPL-3.0-only"}))
+			Expect(licenses.ParseDenyList("")).To(BeEmpty())
+		})
+	})
+}
@@ -0,0 +1,15 @@
+package optimize_test
+
+import (
+	"testing"
+
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+)
+
+func TestUnitOptimize(t *testing.T) {
+	suite := spec.New("optimize", spec.Report(report.Terminal{}), spec.Parallel())
+	suite("Optimize", testOptimize)
+	suite("Shrink", testShrink)
+	suite.Run(t)
+}
@@ -0,0 +1,67 @@
+// Package optimize reduces the size of Go binaries: it strips the symbol table
+// and the DWARF debug information through go-build flags, compresses
+// binaries with UPX when it is available, and reports the sizes before and
+// after.
+package optimize
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Env configures the mode.
+const Env = "BP_GO_OPTIMIZE_SIZE"
+
+// Mode is how far binaries are shrunk.
+type Mode string
+
+const (
+	// Off leaves binaries as go-build builds them.
+	Off Mode = ""
+
+	// Strip builds binaries without a symbol table, DWARF and local paths.
+	Strip Mode = "strip"
+
+	// Compress strips binaries and compresses them with UPX.
+	Compress Mode = "upx"
+)
+
+// ParseMode reads the value of BP_GO_OPTIMIZE_SIZE.
+func ParseMode(value string) (Mode, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "", "false":
+		return Off, nil
+	case "true", "strip":
+		return Strip, nil
+	case "upx":
+		return Compress, nil
+	default:
+		return Off, fmt.Errorf("invalid %s %q: expected one of true, false, strip or upx", Env, value)
+	}
+}
+
+// BuildEnvironment adds the go-build flags of a mode to the given
+// BP_GO_BUILD_FLAGS and BP_GO_BUILD_LDFLAGS, keeping the flags that are
+// already set: -s and -w are added to the ldflags and -trimpath to the flags.
+func BuildEnvironment(mode Mode, flags, ldflags string) map[string]string {
+	if mode == Off {
+		return map[string]string{}
+	}
+
+	return map[string]string{
+		"BP_GO_BUILD_FLAGS":   appendFlags(flags, "-trimpath"),
+		"BP_GO_BUILD_LDFLAGS": appendFlags(ldflags, "-s", "-w"),
+	}
+}
+
+func appendFlags(value string, flags ...string) string {
+	fields := strings.Fields(value)
+	for _, flag := range flags {
+		if !slices.Contains(fields, flag) && !slices.Contains(fields, flag+"=true") {
+			fields = append(fields, flag)
+		}
+	}
+
+	return strings.Join(fields, " ")
+}
@@ -0,0 +1,53 @@
+package optimize_test
+
+import (
+	"testing"
+
+	"github.com/paketo-buildpacks/go/optimize"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testOptimize(t *testing.T, context spec.G, it spec.S) {
+	var Expect = NewWithT(t).Expect
+
+	context("ParseMode", func() {
+		it("parses every mode", func() {
+			for value, mode := range map[string]optimize.Mode{
+				"":      optimize.Off,
+				"false": optimize.Off,
+				"true":  optimize.Strip,
+				"Strip": optimize.Strip,
+				" upx ": optimize.Compress,
+			} {
+				Expect(optimize.ParseMode(value)).To(Equal(mode), value)
+			}
+		})
+
+		it("returns an error for other values", func() {
+			_, err := optimize.ParseMode("gzip")
+			Expect(err).To(MatchError(`invalid BP_GO_OPTIMIZE_SIZE "gzip": expected one of true, false, strip or upx`))
+		})
+	})
+
+	context("BuildEnvironment", func() {
+		it("adds the stripping flags to the flags that are already set", func() {
+			Expect(optimize.BuildEnvironment(optimize.Strip, "-mod=vendor", "-X main.version=1.0.0")).To(Equal(map[string]string{
+				"BP_GO_BUILD_FLAGS":   "-mod=vendor -trimpath",
+				"BP_GO_BUILD_LDFLAGS": "-X main.version=1.0.0 -s -w",
+			}))
+		})
+
+		it("does not repeat flags", func() {
+			Expect(optimize.BuildEnvironment(optimize.Compress, "-trimpath=true", "-w -s")).To(Equal(map[string]string{
+				"BP_GO_BUILD_FLAGS":   "-trimpath=true",
+				"BP_GO_BUILD_LDFLAGS": "-w -s",
+			}))
+		})
+
+		it("changes nothing when off", func() {
+			Expect(optimize.BuildEnvironment(optimize.Off, "-mod=vendor", "")).To(BeEmpty())
+		})
+	})
+}
@@ -0,0 +1,105 @@
+package optimize
+
+import (
+	"debug/elf"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// LabelPrefix starts the labels that record the sizes of a binary, followed
+// by the name of the binary and .before or .after.
+const LabelPrefix = "io.paketo.go.optimize-size"
+
+// Result is the size of a binary before and after it was shrunk, in bytes.
+type Result struct {
+	Name       string
+	Before     int64
+	After      int64
+	Compressed bool
+}
+
+// Shrink compresses the binary at path with the UPX executable at upx when the
+// mode asks for it. Without UPX the binary is left as it is, since stripping
+// already happened during the build.
+func Shrink(path string, mode Mode, upx string, stdout io.Writer) (Result, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return Result{}, err
+	}
+
+	result := Result{Name: info.Name(), Before: info.Size(), After: info.Size()}
+	if mode != Compress || upx == "" {
+		return result, nil
+	}
+
+	command := exec.Command(upx, "--best", "--lzma", "--no-progress", path)
+	command.Stdout = stdout
+	command.Stderr = stdout
+	err = command.Run()
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to compress %s with upx: %w", result.Name, err)
+	}
+
+	info, err = os.Stat(path)
+	if err != nil {
+		return Result{}, err
+	}
+
+	result.After = info.Size()
+	result.Compressed = true
+
+	return result, nil
+}
+
+// String describes the result for the build log.
+func (r Result) String() string {
+	description := fmt.Sprintf("%s: %s -> %s", r.Name, FormatSize(r.Before), FormatSize(r.After))
+	if r.Before > 0 && r.After != r.Before {
+		description = fmt.Sprintf("%s (%+.0f%%)", description, float64(r.After-r.Before)/float64(r.Before)*100)
+	}
+
+	return description
+}
+
+// Labels returns the image labels that record the result. Before is the size
+// of the binary as go-build built it, which is already stripped.
+func (r Result) Labels() map[string]string {
+	return map[string]string{
+		fmt.Sprintf("%s.%s.before", LabelPrefix, r.Name): strconv.FormatInt(r.Before, 10),
+		fmt.Sprintf("%s.%s.after", LabelPrefix, r.Name):  strconv.FormatInt(r.After, 10),
+	}
+}
+
+// HasDebugInfo reports whether an ELF binary still holds a symbol table or
+// DWARF sections.
+func HasDebugInfo(path string) (bool, error) {
+	file, err := elf.Open(path)
+	if err != nil {
+		return false, err
+	}
+	defer file.Close()
+
+	for _, section := range file.Sections {
+		if section.Name == ".symtab" || strings.HasPrefix(section.Name, ".debug_") || strings.HasPrefix(section.Name, ".zdebug_") {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// FormatSize formats a size in bytes for the build log.
+func FormatSize(size int64) string {
+	switch {
+	case size >= 1<<20:
+		return fmt.Sprintf("%.1f MiB", float64(size)/(1<<20))
+	case size >= 1<<10:
+		return fmt.Sprintf("%.1f KiB", float64(size)/(1<<10))
+	default:
+		return fmt.Sprintf("%d B", size)
+	}
+}
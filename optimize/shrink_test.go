@@ -0,0 +1,100 @@
+package optimize_test
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/paketo-buildpacks/go/optimize"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testShrink(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		binary string
+		upx    string
+	)
+
+	it.Before(func() {
+		dir := t.TempDir()
+		binary = filepath.Join(dir, "server")
+		Expect(os.WriteFile(binary, bytes.Repeat([]byte("x"), 4096), 0755)).To(Succeed())
+
+		// a stand-in for upx that halves the binary
+		upx = filepath.Join(dir, "upx")
+		Expect(os.WriteFile(upx, []byte("#!/bin/sh\nfor last; do :; done\nhead -c 2048 \"$last\" > \"$last.upx\" && mv \"$last.upx\" \"$last\"\necho compressed\n"), 0755)).To(Succeed())
+	})
+
+	context("Shrink", func() {
+		it("compresses the binary with upx", func() {
+			var stdout bytes.Buffer
+			result, err := optimize.Shrink(binary, optimize.Compress, upx, &stdout)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(Equal(optimize.Result{Name: "server", Before: 4096, After: 2048, Compressed: true}))
+			Expect(stdout.String()).To(Equal("compressed\n"))
+
+			Expect(result.String()).To(Equal("server: 4.0 KiB -> 2.0 KiB (-50%)"))
+			Expect(result.Labels()).To(Equal(map[string]string{
+				"io.paketo.go.optimize-size.server.before": "4096",
+				"io.paketo.go.optimize-size.server.after":  "2048",
+			}))
+		})
+
+		it("leaves the binary alone without upx or when only stripping", func() {
+			result, err := optimize.Shrink(binary, optimize.Compress, "", &bytes.Buffer{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(Equal(optimize.Result{Name: "server", Before: 4096, After: 4096}))
+			Expect(result.String()).To(Equal("server: 4.0 KiB -> 4.0 KiB"))
+
+			result, err = optimize.Shrink(binary, optimize.Strip, upx, &bytes.Buffer{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Compressed).To(BeFalse())
+		})
+
+		it("returns an error when upx fails", func() {
+			Expect(os.WriteFile(upx, []byte("#!/bin/sh\nexit 1\n"), 0755)).To(Succeed())
+
+			_, err := optimize.Shrink(binary, optimize.Compress, upx, &bytes.Buffer{})
+			Expect(err).To(MatchError("failed to compress server with upx: exit status 1"))
+		})
+	})
+
+	context("HasDebugInfo", func() {
+		it("finds the symbol table and DWARF of a binary", func() {
+			dir := t.TempDir()
+			Expect(os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n\nfunc main() {}\n"), 0644)).To(Succeed())
+
+			for _, build := range []struct {
+				ldflags string
+				debug   bool
+			}{
+				{"", true},
+				{"-s -w", false},
+			} {
+				output, err := exec.Command("go", "build", "-C", dir, "-o", "main", "-ldflags", build.ldflags, "main.go").CombinedOutput()
+				Expect(err).NotTo(HaveOccurred(), string(output))
+
+				Expect(optimize.HasDebugInfo(filepath.Join(dir, "main"))).To(Equal(build.debug), build.ldflags)
+			}
+		})
+
+		it("returns an error for files that are not ELF binaries", func() {
+			_, err := optimize.HasDebugInfo(binary)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	context("FormatSize", func() {
+		it("formats sizes in binary units", func() {
+			Expect(optimize.FormatSize(512)).To(Equal("512 B"))
+			Expect(optimize.FormatSize(1536)).To(Equal("1.5 KiB"))
+			Expect(optimize.FormatSize(5 << 20)).To(Equal("5.0 MiB"))
+		})
+	})
+}
@@ -0,0 +1,15 @@
+package otel_test
+
+import (
+	"testing"
+
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+)
+
+func TestUnitOtel(t *testing.T) {
+	suite := spec.New("otel", spec.Report(report.Terminal{}), spec.Parallel())
+	suite("Instrumentation", testInstrumentation)
+	suite("Otel", testOtel)
+	suite.Run(t)
+}
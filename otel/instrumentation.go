@@ -0,0 +1,113 @@
+package otel
+
+import (
+	"archive/tar"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/paketo-buildpacks/go/components"
+)
+
+const (
+	// InstrumentationImageEnv overrides the image the instrumentation is
+	// taken from, such as a mirror for builds without access to ghcr.io. It
+	// must reference the image by digest.
+	InstrumentationImageEnv = "BP_OTEL_INSTRUMENTATION_IMAGE"
+
+	// InstrumentationPath is the path of the instrumentation in its image.
+	InstrumentationPath = "/otel-go-instrumentation"
+)
+
+// InstrumentationImage returns the image the instrumentation is taken from,
+// by digest, so that pulling it verifies its content. It is the image listed
+// in the buildpack.toml of the buildpack in cnbPath, at the digest locked in
+// the package.lock.toml packaged with it, unless override, the value of
+// BP_OTEL_INSTRUMENTATION_IMAGE, names another one.
+func InstrumentationImage(cnbPath, override string) (name.Digest, error) {
+	if override != "" {
+		ref, err := name.NewDigest(override)
+		if err != nil {
+			return name.Digest{}, fmt.Errorf("%s must reference the image by digest, as <repository>@sha256:<digest>: %w", InstrumentationImageEnv, err)
+		}
+
+		return ref, nil
+	}
+
+	config, err := components.ParseBuildpackConfig(filepath.Join(cnbPath, "buildpack.toml"))
+	if err != nil {
+		return name.Digest{}, err
+	}
+
+	if len(config.Metadata.Images) != 1 {
+		return name.Digest{}, fmt.Errorf("buildpack.toml lists %d images, expected the instrumentation image only", len(config.Metadata.Images))
+	}
+	image := config.Metadata.Images[0]
+
+	lockfile, err := components.ReadLockfile(filepath.Join(cnbPath, components.LockfileName))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return name.Digest{}, fmt.Errorf("the buildpack was packaged without %s, so %s is not pinned to a digest: set %s to the image by digest", components.LockfileName, image.URI, InstrumentationImageEnv)
+		}
+		return name.Digest{}, err
+	}
+
+	digest, ok := lockfile.ImageDigest(image.URI)
+	if !ok {
+		return name.Digest{}, fmt.Errorf("%s is missing from %s: set %s to the image by digest", image.URI, components.LockfileName, InstrumentationImageEnv)
+	}
+
+	ref, err := image.Reference()
+	if err != nil {
+		return name.Digest{}, err
+	}
+
+	return ref.Context().Digest(digest), nil
+}
+
+// ExtractInstrumentation copies the instrumentation out of the filesystem of
+// its image into destination.
+func ExtractInstrumentation(image v1.Image, destination string) error {
+	filesystem := mutate.Extract(image)
+	defer filesystem.Close()
+
+	name := strings.TrimPrefix(InstrumentationPath, "/")
+	tr := tar.NewReader(filesystem)
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return fmt.Errorf("the image has no %s", InstrumentationPath)
+		}
+		if err != nil {
+			return err
+		}
+
+		if strings.TrimPrefix(header.Name, "/") != name || header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		err = os.MkdirAll(filepath.Dir(destination), os.ModePerm)
+		if err != nil {
+			return err
+		}
+
+		file, err := os.OpenFile(destination, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0755)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		_, err = io.Copy(file, tr)
+		if err != nil {
+			return err
+		}
+
+		return file.Close()
+	}
+}
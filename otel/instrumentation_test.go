@@ -0,0 +1,117 @@
+package otel_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
+	"github.com/paketo-buildpacks/go/otel"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testInstrumentation(t *testing.T, context spec.G, it spec.S) {
+	var Expect = NewWithT(t).Expect
+
+	context("InstrumentationImage", func() {
+		const digest = "sha256:1111111111111111111111111111111111111111111111111111111111111111"
+
+		var cnbDir string
+
+		it.Before(func() {
+			cnbDir = t.TempDir()
+			Expect(os.WriteFile(filepath.Join(cnbDir, "buildpack.toml"), []byte(`
+[[metadata.images]]
+  uri = "docker://ghcr.io/example/instrumentation:v1.0.0"
+`), 0600)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(cnbDir, "package.lock.toml"), []byte(`
+[[images]]
+  uri = "docker://ghcr.io/example/instrumentation:v1.0.0"
+  digest = "`+digest+`"
+`), 0600)).To(Succeed())
+		})
+
+		it("returns the image of buildpack.toml at its locked digest", func() {
+			ref, err := otel.InstrumentationImage(cnbDir, "")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ref.String()).To(Equal("ghcr.io/example/instrumentation@" + digest))
+		})
+
+		it("returns the override when it references an image by digest", func() {
+			ref, err := otel.InstrumentationImage(cnbDir, "registry.example.com/instrumentation@"+digest)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ref.String()).To(Equal("registry.example.com/instrumentation@" + digest))
+		})
+
+		context("failure cases", func() {
+			context("when the override references an image by tag", func() {
+				it("returns an error", func() {
+					_, err := otel.InstrumentationImage(cnbDir, "registry.example.com/instrumentation:v1.0.0")
+					Expect(err).To(MatchError(ContainSubstring("BP_OTEL_INSTRUMENTATION_IMAGE must reference the image by digest")))
+				})
+			})
+
+			context("when there is no lockfile", func() {
+				it("returns an error", func() {
+					Expect(os.Remove(filepath.Join(cnbDir, "package.lock.toml"))).To(Succeed())
+
+					_, err := otel.InstrumentationImage(cnbDir, "")
+					Expect(err).To(MatchError(ContainSubstring("the buildpack was packaged without package.lock.toml, so docker://ghcr.io/example/instrumentation:v1.0.0 is not pinned to a digest")))
+				})
+			})
+
+			context("when the lockfile does not record the image", func() {
+				it("returns an error", func() {
+					Expect(os.WriteFile(filepath.Join(cnbDir, "package.lock.toml"), nil, 0600)).To(Succeed())
+
+					_, err := otel.InstrumentationImage(cnbDir, "")
+					Expect(err).To(MatchError(ContainSubstring("docker://ghcr.io/example/instrumentation:v1.0.0 is missing from package.lock.toml")))
+				})
+			})
+		})
+	})
+
+	context("ExtractInstrumentation", func() {
+		it("copies the instrumentation out of its image", func() {
+			var buffer bytes.Buffer
+			tw := tar.NewWriter(&buffer)
+			for name, content := range map[string]string{
+				"etc/passwd":              "root:x:0:0::/root:/sbin/nologin\n",
+				"otel-go-instrumentation": "instrumentation",
+			} {
+				Expect(tw.WriteHeader(&tar.Header{Typeflag: tar.TypeReg, Name: name, Mode: 0755, Size: int64(len(content))})).To(Succeed())
+				_, err := tw.Write([]byte(content))
+				Expect(err).NotTo(HaveOccurred())
+			}
+			Expect(tw.Close()).To(Succeed())
+
+			layer, err := tarball.LayerFromOpener(func() (io.ReadCloser, error) {
+				return io.NopCloser(bytes.NewReader(buffer.Bytes())), nil
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			image, err := mutate.AppendLayers(empty.Image, layer)
+			Expect(err).NotTo(HaveOccurred())
+
+			destination := filepath.Join(t.TempDir(), "bin", "otel-go-instrumentation")
+			Expect(otel.ExtractInstrumentation(image, destination)).To(Succeed())
+
+			Expect(os.ReadFile(destination)).To(Equal([]byte("instrumentation")))
+			info, err := os.Stat(destination)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(info.Mode().Perm()).To(Equal(os.FileMode(0755)))
+		})
+
+		it("returns an error when the image has no instrumentation", func() {
+			err := otel.ExtractInstrumentation(empty.Image, filepath.Join(t.TempDir(), "otel-go-instrumentation"))
+			Expect(err).To(MatchError("the image has no /otel-go-instrumentation"))
+		})
+	})
+}
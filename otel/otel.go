@@ -0,0 +1,214 @@
+// Package otel configures the OpenTelemetry Go eBPF auto-instrumentation for
+// a Go application: it reads the collector to export to from an otel service
+// binding and returns the environment and the process type that run the
+// instrumentation next to the application, without changes to its code.
+package otel
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/paketo-buildpacks/packit/v2/servicebindings"
+)
+
+const (
+	// BindingType is the type of the service bindings that configure the
+	// collector.
+	BindingType = "otel"
+
+	// EnabledEnv enables the instrumentation without a binding, in which case
+	// it exports to the defaults of the OTEL_EXPORTER_OTLP_* variables, or to
+	// the variables set when the image runs.
+	EnabledEnv = "BP_OTEL_ENABLED"
+
+	// EndpointEntry holds the OTLP endpoint of the collector, such as
+	// http://collector:4318.
+	EndpointEntry = "endpoint"
+
+	// HeadersEntry optionally holds the headers sent with every export, one
+	// <name>=<value> per line, such as an API key.
+	HeadersEntry = "headers"
+
+	// ProtocolEntry optionally holds the OTLP protocol, http/protobuf or grpc.
+	ProtocolEntry = "protocol"
+
+	// ServiceNameEntry optionally holds the service name of the spans.
+	ServiceNameEntry = "service-name"
+
+	// ProcessType is the process type that runs the instrumentation.
+	ProcessType = "otel-instrumentation"
+
+	// DefaultProtocol is the OTLP protocol used when the binding names none.
+	DefaultProtocol = "http/protobuf"
+)
+
+// Config is where the instrumentation exports spans to.
+type Config struct {
+	Endpoint    string
+	Protocol    string
+	Headers     map[string]string
+	ServiceName string
+}
+
+// Find returns the otel binding of a build whose platform directory is
+// platformDir. The bindings are resolved by the packit servicebindings
+// resolver, from SERVICE_BINDING_ROOT, CNB_BINDINGS, VCAP_SERVICES or the
+// bindings directory of the platform.
+func Find(platformDir string) (servicebindings.Binding, bool, error) {
+	bindings, err := servicebindings.NewResolver().Resolve(BindingType, "", platformDir)
+	if err != nil {
+		return servicebindings.Binding{}, false, err
+	}
+
+	if len(bindings) == 0 {
+		return servicebindings.Binding{}, false, nil
+	}
+
+	binding := bindings[0]
+	if _, ok := binding.Entries[EndpointEntry]; !ok {
+		return servicebindings.Binding{}, false, fmt.Errorf("binding %s is missing its %s entry", binding.Name, EndpointEntry)
+	}
+
+	return binding, true, nil
+}
+
+// Detect returns the configuration of the otel binding found by Find, or an
+// empty configuration when there is no binding but enabled, the value of
+// BP_OTEL_ENABLED, is true. The instrumentation is not wanted otherwise.
+func Detect(platformDir, enabled string) (Config, bool, error) {
+	binding, ok, err := Find(platformDir)
+	if err != nil {
+		return Config{}, false, err
+	}
+
+	if ok {
+		config, err := Load(binding)
+		if err != nil {
+			return Config{}, false, err
+		}
+		return config, true, nil
+	}
+
+	if enabled == "" {
+		return Config{}, false, nil
+	}
+
+	on, err := strconv.ParseBool(enabled)
+	if err != nil {
+		return Config{}, false, fmt.Errorf("invalid %s %q: %w", EnabledEnv, enabled, err)
+	}
+
+	return Config{}, on, nil
+}
+
+// Load reads the configuration of a binding.
+func Load(binding servicebindings.Binding) (Config, error) {
+	entries := map[string]string{}
+	for _, name := range []string{EndpointEntry, HeadersEntry, ProtocolEntry, ServiceNameEntry} {
+		entry, ok := binding.Entries[name]
+		if !ok {
+			continue
+		}
+
+		content, err := entry.ReadString()
+		if err != nil {
+			return Config{}, err
+		}
+
+		entries[name] = content
+	}
+
+	config := Config{
+		Endpoint:    strings.TrimSpace(entries[EndpointEntry]),
+		Protocol:    strings.TrimSpace(entries[ProtocolEntry]),
+		ServiceName: strings.TrimSpace(entries[ServiceNameEntry]),
+	}
+
+	endpoint, err := url.Parse(config.Endpoint)
+	if err != nil || (endpoint.Scheme != "http" && endpoint.Scheme != "https") || endpoint.Host == "" {
+		return Config{}, fmt.Errorf("binding %s has an invalid %s %q, expected an http or https URL", binding.Name, EndpointEntry, config.Endpoint)
+	}
+
+	switch config.Protocol {
+	case "":
+		config.Protocol = DefaultProtocol
+	case "http/protobuf", "grpc":
+	default:
+		return Config{}, fmt.Errorf("binding %s has an unsupported %s %q, expected http/protobuf or grpc", binding.Name, ProtocolEntry, config.Protocol)
+	}
+
+	config.Headers, err = parseHeaders(entries[HeadersEntry])
+	if err != nil {
+		return Config{}, fmt.Errorf("binding %s has an invalid %s entry: %w", binding.Name, HeadersEntry, err)
+	}
+
+	return config, nil
+}
+
+// Environment returns the variables the instrumentation reads: target is the
+// path of the binary to instrument in the image, and service is the service
+// name used when the binding does not name one.
+func (c Config) Environment(target, service string) map[string]string {
+	environment := map[string]string{
+		"OTEL_GO_AUTO_TARGET_EXE": target,
+		"OTEL_SERVICE_NAME":       service,
+		"OTEL_TRACES_EXPORTER":    "otlp",
+	}
+
+	if c.ServiceName != "" {
+		environment["OTEL_SERVICE_NAME"] = c.ServiceName
+	}
+
+	if c.Endpoint != "" {
+		environment["OTEL_EXPORTER_OTLP_ENDPOINT"] = c.Endpoint
+		environment["OTEL_EXPORTER_OTLP_PROTOCOL"] = c.Protocol
+	}
+
+	if len(c.Headers) > 0 {
+		var names []string
+		for name := range c.Headers {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		var headers []string
+		for _, name := range names {
+			headers = append(headers, fmt.Sprintf("%s=%s", name, url.PathEscape(c.Headers[name])))
+		}
+
+		environment["OTEL_EXPORTER_OTLP_HEADERS"] = strings.Join(headers, ",")
+	}
+
+	return environment
+}
+
+// parseHeaders reads one <name>=<value> header per line. Commas separate
+// headers too, so that the value of OTEL_EXPORTER_OTLP_HEADERS can be copied
+// into a binding as it is.
+func parseHeaders(content string) (map[string]string, error) {
+	headers := map[string]string{}
+	for _, line := range strings.FieldsFunc(content, func(r rune) bool { return r == '\n' || r == ',' }) {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		name, value, ok := strings.Cut(line, "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid header %q, expected <name>=<value>", line)
+		}
+
+		unescaped, err := url.PathUnescape(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("invalid header %q: %w", name, err)
+		}
+
+		headers[name] = unescaped
+	}
+
+	return headers, nil
+}
@@ -0,0 +1,136 @@
+package otel_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/paketo-buildpacks/go/otel"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testOtel(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		platformDir string
+		root        string
+	)
+
+	writeBinding := func(name string, entries map[string]string) {
+		Expect(os.MkdirAll(filepath.Join(root, name), os.ModePerm)).To(Succeed())
+		for entry, content := range entries {
+			Expect(os.WriteFile(filepath.Join(root, name, entry), []byte(content), 0600)).To(Succeed())
+		}
+	}
+
+	it.Before(func() {
+		platformDir = t.TempDir()
+		root = filepath.Join(platformDir, "bindings")
+	})
+
+	context("Detect", func() {
+		it("reads the otel binding", func() {
+			writeBinding("other", map[string]string{"type": "ssh-key"})
+			writeBinding("tracing", map[string]string{
+				"type":                "otel\n",
+				otel.EndpointEntry:    "https://collector.example.com:4318\n",
+				otel.HeadersEntry:     "x-api-key=secret\n# a comment\nx-tenant = team a, x-region=eu%2Cwest\n",
+				otel.ServiceNameEntry: "checkout\n",
+			})
+
+			config, ok, err := otel.Detect(platformDir, "")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ok).To(BeTrue())
+			Expect(config).To(Equal(otel.Config{
+				Endpoint:    "https://collector.example.com:4318",
+				Protocol:    "http/protobuf",
+				ServiceName: "checkout",
+				Headers: map[string]string{
+					"x-api-key": "secret",
+					"x-tenant":  "team a",
+					"x-region":  "eu,west",
+				},
+			}))
+		})
+
+		it("is enabled without a binding by BP_OTEL_ENABLED", func() {
+			config, ok, err := otel.Detect(platformDir, "true")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ok).To(BeTrue())
+			Expect(config).To(Equal(otel.Config{}))
+
+			_, ok, err = otel.Detect(t.TempDir(), "false")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ok).To(BeFalse())
+
+			_, ok, err = otel.Detect(platformDir, "")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ok).To(BeFalse())
+		})
+
+		context("failure cases", func() {
+			it("rejects an invalid BP_OTEL_ENABLED", func() {
+				_, _, err := otel.Detect(platformDir, "yes please")
+				Expect(err).To(MatchError(ContainSubstring(`invalid BP_OTEL_ENABLED "yes please"`)))
+			})
+
+			it("rejects a binding without an endpoint", func() {
+				writeBinding("tracing", map[string]string{"type": "otel"})
+
+				_, _, err := otel.Detect(platformDir, "")
+				Expect(err).To(MatchError("binding tracing is missing its endpoint entry"))
+			})
+
+			it("rejects an endpoint that is not an http URL", func() {
+				writeBinding("tracing", map[string]string{"type": "otel", otel.EndpointEntry: "collector:4317"})
+
+				_, _, err := otel.Detect(platformDir, "")
+				Expect(err).To(MatchError(`binding tracing has an invalid endpoint "collector:4317", expected an http or https URL`))
+			})
+
+			it("rejects an unsupported protocol", func() {
+				writeBinding("tracing", map[string]string{"type": "otel", otel.EndpointEntry: "http://collector:4318", otel.ProtocolEntry: "http/json"})
+
+				_, _, err := otel.Detect(platformDir, "")
+				Expect(err).To(MatchError(`binding tracing has an unsupported protocol "http/json", expected http/protobuf or grpc`))
+			})
+
+			it("rejects malformed headers", func() {
+				writeBinding("tracing", map[string]string{"type": "otel", otel.EndpointEntry: "http://collector:4318", otel.HeadersEntry: "x-api-key"})
+
+				_, _, err := otel.Detect(platformDir, "")
+				Expect(err).To(MatchError(`binding tracing has an invalid headers entry: invalid header "x-api-key", expected <name>=<value>`))
+			})
+		})
+	})
+
+	context("Environment", func() {
+		it("configures the instrumentation from the binding", func() {
+			config := otel.Config{
+				Endpoint: "http://collector:4318",
+				Protocol: "grpc",
+				Headers:  map[string]string{"x-tenant": "team a", "x-api-key": "secret"},
+			}
+
+			Expect(config.Environment("/layers/paketo-buildpacks_go-build/targets/bin/server", "server")).To(Equal(map[string]string{
+				"OTEL_GO_AUTO_TARGET_EXE":     "/layers/paketo-buildpacks_go-build/targets/bin/server",
+				"OTEL_SERVICE_NAME":           "server",
+				"OTEL_TRACES_EXPORTER":        "otlp",
+				"OTEL_EXPORTER_OTLP_ENDPOINT": "http://collector:4318",
+				"OTEL_EXPORTER_OTLP_PROTOCOL": "grpc",
+				"OTEL_EXPORTER_OTLP_HEADERS":  "x-api-key=secret,x-tenant=team%20a",
+			}))
+		})
+
+		it("leaves the exporter to the runtime environment without a binding", func() {
+			Expect(otel.Config{ServiceName: "checkout"}.Environment("/workspace/server", "server")).To(Equal(map[string]string{
+				"OTEL_GO_AUTO_TARGET_EXE": "/workspace/server",
+				"OTEL_SERVICE_NAME":       "checkout",
+				"OTEL_TRACES_EXPORTER":    "otlp",
+			}))
+		})
+	})
+}
@@ -0,0 +1,111 @@
+package provenance
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/static"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+)
+
+// Media types of the documents attached to an image.
+const (
+	StatementMediaType types.MediaType = "application/vnd.in-toto+json"
+	EnvelopeMediaType  types.MediaType = "application/vnd.dsse.envelope.v1+json"
+)
+
+// PredicateTypeAnnotation is the annotation of an attached document that
+// holds the type of its predicate.
+const PredicateTypeAnnotation = "in-toto.io/predicate-type"
+
+// emptyMediaType is the media type of the empty config of an OCI artifact.
+const emptyMediaType types.MediaType = "application/vnd.oci.empty.v1+json"
+
+// Attach pushes a provenance document to the repository of an image as an OCI
+// artifact whose subject is the image manifest, so that it is listed by the
+// referrers API, or by the referrers tag schema on registries without it. The
+// media type is StatementMediaType for a statement and EnvelopeMediaType for
+// a signed envelope. Attach returns the digest of the artifact.
+func Attach(image name.Digest, document []byte, mediaType types.MediaType, options ...remote.Option) (name.Digest, error) {
+	subject, err := remote.Head(image, options...)
+	if err != nil {
+		return name.Digest{}, fmt.Errorf("failed to read image manifest: %w", err)
+	}
+
+	config := static.NewLayer([]byte("{}"), emptyMediaType)
+	layer := static.NewLayer(document, mediaType)
+
+	for _, blob := range []v1.Layer{config, layer} {
+		err = remote.WriteLayer(image.Context(), blob, options...)
+		if err != nil {
+			return name.Digest{}, fmt.Errorf("failed to push provenance: %w", err)
+		}
+	}
+
+	configDescriptor, err := descriptor(config, emptyMediaType)
+	if err != nil {
+		return name.Digest{}, err
+	}
+
+	layerDescriptor, err := descriptor(layer, mediaType)
+	if err != nil {
+		return name.Digest{}, err
+	}
+	layerDescriptor.Annotations = map[string]string{PredicateTypeAnnotation: PredicateType}
+
+	content, err := json.Marshal(v1.Manifest{
+		SchemaVersion: 2,
+		MediaType:     types.OCIManifestSchema1,
+		ArtifactType:  string(mediaType),
+		Config:        configDescriptor,
+		Layers:        []v1.Descriptor{layerDescriptor},
+		Annotations:   map[string]string{PredicateTypeAnnotation: PredicateType},
+		Subject: &v1.Descriptor{
+			MediaType: subject.MediaType,
+			Digest:    subject.Digest,
+			Size:      subject.Size,
+		},
+	})
+	if err != nil {
+		return name.Digest{}, err
+	}
+
+	digest, _, err := v1.SHA256(bytes.NewReader(content))
+	if err != nil {
+		return name.Digest{}, err
+	}
+
+	ref := image.Context().Digest(digest.String())
+	err = remote.Put(ref, rawManifest{content: content, mediaType: types.OCIManifestSchema1}, options...)
+	if err != nil {
+		return name.Digest{}, fmt.Errorf("failed to push provenance: %w", err)
+	}
+
+	return ref, nil
+}
+
+func descriptor(layer v1.Layer, mediaType types.MediaType) (v1.Descriptor, error) {
+	digest, err := layer.Digest()
+	if err != nil {
+		return v1.Descriptor{}, err
+	}
+
+	size, err := layer.Size()
+	if err != nil {
+		return v1.Descriptor{}, err
+	}
+
+	return v1.Descriptor{MediaType: mediaType, Digest: digest, Size: size}, nil
+}
+
+type rawManifest struct {
+	content   []byte
+	mediaType types.MediaType
+}
+
+func (m rawManifest) RawManifest() ([]byte, error)        { return m.content, nil }
+func (m rawManifest) MediaType() (types.MediaType, error) { return m.mediaType, nil }
@@ -0,0 +1,101 @@
+package provenance_test
+
+import (
+	"io"
+	"log"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/paketo-buildpacks/go/provenance"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testAttach(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		image name.Digest
+	)
+
+	for description, referrers := range map[string]bool{
+		"when the registry supports the referrers API":        true,
+		"when the registry only has the referrers tag schema": false,
+	} {
+		referrers := referrers
+
+		context(description, func() {
+			it.Before(func() {
+				server := httptest.NewServer(registry.New(registry.Logger(log.New(io.Discard, "", 0)), registry.WithReferrersSupport(referrers)))
+				t.Cleanup(server.Close)
+
+				img, err := random.Image(64, 1)
+				Expect(err).NotTo(HaveOccurred())
+
+				digest, err := img.Digest()
+				Expect(err).NotTo(HaveOccurred())
+
+				repository, err := name.NewRepository(strings.TrimPrefix(server.URL, "http://") + "/some-app")
+				Expect(err).NotTo(HaveOccurred())
+
+				image = repository.Digest(digest.String())
+				Expect(remote.Write(image, img)).To(Succeed())
+			})
+
+			it("pushes the document as a referrer of the image", func() {
+				artifact, err := provenance.Attach(image, []byte(`{"payloadType": "application/vnd.in-toto+json"}`), provenance.EnvelopeMediaType)
+				Expect(err).NotTo(HaveOccurred())
+
+				index, err := remote.Referrers(image)
+				Expect(err).NotTo(HaveOccurred())
+
+				manifest, err := index.IndexManifest()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(manifest.Manifests).To(HaveLen(1))
+				Expect(manifest.Manifests[0].Digest.String()).To(Equal(artifact.DigestStr()))
+
+				attached, err := remote.Image(artifact)
+				Expect(err).NotTo(HaveOccurred())
+
+				attachedManifest, err := attached.Manifest()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(attachedManifest.ArtifactType).To(Equal(string(provenance.EnvelopeMediaType)))
+				Expect(attachedManifest.Subject.Digest.String()).To(Equal(image.DigestStr()))
+				Expect(attachedManifest.Annotations).To(HaveKeyWithValue(provenance.PredicateTypeAnnotation, provenance.PredicateType))
+
+				layers, err := attached.Layers()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(layers).To(HaveLen(1))
+
+				reader, err := layers[0].Compressed()
+				Expect(err).NotTo(HaveOccurred())
+				defer reader.Close()
+
+				content, err := io.ReadAll(reader)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(content)).To(Equal(`{"payloadType": "application/vnd.in-toto+json"}`))
+			})
+		})
+	}
+
+	context("failure cases", func() {
+		context("when the image does not exist", func() {
+			it("returns an error", func() {
+				server := httptest.NewServer(registry.New(registry.Logger(log.New(io.Discard, "", 0))))
+				t.Cleanup(server.Close)
+
+				missing, err := name.NewDigest(strings.TrimPrefix(server.URL, "http://") + "/some-app@sha256:1111111111111111111111111111111111111111111111111111111111111111")
+				Expect(err).NotTo(HaveOccurred())
+
+				_, err = provenance.Attach(missing, []byte("{}"), provenance.StatementMediaType)
+				Expect(err).To(MatchError(ContainSubstring("failed to read image manifest")))
+			})
+		})
+	})
+}
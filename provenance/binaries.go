@@ -0,0 +1,90 @@
+package provenance
+
+import (
+	"archive/tar"
+	"bytes"
+	"debug/buildinfo"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/paketo-buildpacks/go/gobuild"
+)
+
+// BinaryDir is the directory of the image in which go-build puts the
+// binaries it builds.
+const BinaryDir = gobuild.ImageTargetsDir
+
+// Binary is a Go binary in the image, with the build settings that the Go
+// toolchain recorded in it, such as -ldflags, -tags, -trimpath and the VCS
+// revision.
+type Binary struct {
+	Path      string            `json:"path"`
+	GoVersion string            `json:"goVersion"`
+	Main      string            `json:"main,omitempty"`
+	Settings  map[string]string `json:"settings,omitempty"`
+}
+
+// BinariesFromImage reads the build information of the Go binaries in the
+// BinaryDir of an image. Files that are not Go binaries are skipped, but an
+// image without any Go binary is an error.
+func BinariesFromImage(image v1.Image) ([]Binary, error) {
+	reader := mutate.Extract(image)
+	defer reader.Close()
+
+	var binaries []Binary
+	archive := tar.NewReader(reader)
+	for {
+		header, err := archive.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read image filesystem: %w", err)
+		}
+
+		name := path.Join("/", header.Name)
+		if header.Typeflag != tar.TypeReg || path.Dir(name) != BinaryDir {
+			continue
+		}
+
+		content, err := io.ReadAll(archive)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", name, err)
+		}
+
+		info, err := buildinfo.Read(bytes.NewReader(content))
+		if err != nil {
+			continue
+		}
+
+		binary := Binary{
+			Path:      name,
+			GoVersion: info.GoVersion,
+			Main:      info.Main.Path,
+		}
+
+		for _, setting := range info.Settings {
+			if binary.Settings == nil {
+				binary.Settings = map[string]string{}
+			}
+			binary.Settings[setting.Key] = setting.Value
+		}
+
+		binaries = append(binaries, binary)
+	}
+
+	if len(binaries) == 0 {
+		return nil, fmt.Errorf("image has no Go binaries in %s", BinaryDir)
+	}
+
+	sort.Slice(binaries, func(i, j int) bool {
+		return binaries[i].Path < binaries[j].Path
+	})
+
+	return binaries, nil
+}
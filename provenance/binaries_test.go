@@ -0,0 +1,75 @@
+package provenance_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"io"
+	"os"
+	"runtime"
+	"testing"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
+	"github.com/paketo-buildpacks/go/provenance"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testBinaries(t *testing.T, context spec.G, it spec.S) {
+	var Expect = NewWithT(t).Expect
+
+	imageWith := func(files map[string][]byte) v1.Image {
+		buffer := bytes.NewBuffer(nil)
+		archive := tar.NewWriter(buffer)
+		for name, content := range files {
+			Expect(archive.WriteHeader(&tar.Header{Name: name, Mode: 0755, Size: int64(len(content)), Typeflag: tar.TypeReg})).To(Succeed())
+			_, err := archive.Write(content)
+			Expect(err).NotTo(HaveOccurred())
+		}
+		Expect(archive.Close()).To(Succeed())
+
+		layer, err := tarball.LayerFromOpener(func() (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewReader(buffer.Bytes())), nil
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		image, err := mutate.AppendLayers(empty.Image, layer)
+		Expect(err).NotTo(HaveOccurred())
+
+		return image
+	}
+
+	context("BinariesFromImage", func() {
+		it("reads the build information of the Go binaries built by go-build", func() {
+			// the test binary is a Go binary with build information of its own
+			binary, err := os.ReadFile(os.Args[0])
+			Expect(err).NotTo(HaveOccurred())
+
+			binaries, err := provenance.BinariesFromImage(imageWith(map[string][]byte{
+				"layers/paketo-buildpacks_go-build/targets/bin/app":       binary,
+				"layers/paketo-buildpacks_go-build/targets/bin/README":    []byte("not a binary"),
+				"layers/paketo-buildpacks_go-dist/go/bin/go":              binary,
+				"layers/paketo-buildpacks_go-build/targets/bin/sub/other": binary,
+			}))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(binaries).To(HaveLen(1))
+			Expect(binaries[0].Path).To(Equal("/layers/paketo-buildpacks_go-build/targets/bin/app"))
+			Expect(binaries[0].GoVersion).To(Equal(runtime.Version()))
+			Expect(binaries[0].Settings).To(HaveKeyWithValue("GOOS", runtime.GOOS))
+		})
+
+		context("failure cases", func() {
+			context("when the image has no Go binaries", func() {
+				it("returns an error", func() {
+					_, err := provenance.BinariesFromImage(imageWith(map[string][]byte{
+						"layers/paketo-buildpacks_go-build/targets/bin/README": []byte("not a binary"),
+					}))
+					Expect(err).To(MatchError("image has no Go binaries in /layers/paketo-buildpacks_go-build/targets/bin"))
+				})
+			})
+		})
+	})
+}
@@ -0,0 +1,18 @@
+package provenance_test
+
+import (
+	"testing"
+
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+)
+
+func TestUnitProvenance(t *testing.T) {
+	suite := spec.New("provenance", spec.Report(report.Terminal{}), spec.Parallel())
+	suite("Attach", testAttach)
+	suite("Binaries", testBinaries)
+	suite("Provenance", testProvenance)
+	suite("Sign", testSign)
+	suite("Subjects", testSubjects)
+	suite.Run(t)
+}
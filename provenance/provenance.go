@@ -0,0 +1,212 @@
+// Package provenance generates SLSA provenance for images built with the Go
+// buildpack and signs it as a DSSE envelope.
+package provenance
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/paketo-buildpacks/go/components"
+)
+
+// Types identifying the documents written by this package.
+const (
+	StatementType = "https://in-toto.io/Statement/v1"
+	PredicateType = "https://slsa.dev/provenance/v1"
+	BuildType     = "https://github.com/paketo-buildpacks/go/provenance/v1"
+)
+
+// BuildMetadataLabel is the image label in which the lifecycle records the
+// buildpacks that took part in the build.
+const BuildMetadataLabel = "io.buildpacks.build.metadata"
+
+// Statement is an in-toto statement carrying an SLSA provenance predicate.
+type Statement struct {
+	Type          string               `json:"_type"`
+	Subject       []ResourceDescriptor `json:"subject"`
+	PredicateType string               `json:"predicateType"`
+	Predicate     Predicate            `json:"predicate"`
+}
+
+// Predicate is an SLSA v1 provenance predicate.
+type Predicate struct {
+	BuildDefinition BuildDefinition `json:"buildDefinition"`
+	RunDetails      RunDetails      `json:"runDetails"`
+}
+
+// BuildDefinition describes the inputs of the build.
+type BuildDefinition struct {
+	BuildType            string               `json:"buildType"`
+	ExternalParameters   ExternalParameters   `json:"externalParameters"`
+	InternalParameters   InternalParameters   `json:"internalParameters,omitempty"`
+	ResolvedDependencies []ResourceDescriptor `json:"resolvedDependencies,omitempty"`
+}
+
+// ExternalParameters are the parameters of the build that the user controls.
+// The Go build configuration is recorded as the toolchain embedded it in the
+// binaries, rather than as the environment the build was asked to use.
+type ExternalParameters struct {
+	Source   Source   `json:"source"`
+	Binaries []Binary `json:"binaries,omitempty"`
+}
+
+// Source identifies the application source that was built.
+type Source struct {
+	URI      string `json:"uri,omitempty"`
+	Revision string `json:"revision,omitempty"`
+}
+
+// InternalParameters are the parameters of the build set by the platform.
+type InternalParameters struct {
+	Buildpacks []Buildpack `json:"buildpacks,omitempty"`
+}
+
+// Buildpack is a buildpack that took part in the build.
+type Buildpack struct {
+	ID      string `json:"id"`
+	Version string `json:"version"`
+}
+
+// RunDetails describes the build platform.
+type RunDetails struct {
+	Builder Builder `json:"builder"`
+}
+
+// Builder identifies the builder image that ran the build.
+type Builder struct {
+	ID string `json:"id"`
+}
+
+// ResourceDescriptor identifies an artifact by name, URI and digest.
+type ResourceDescriptor struct {
+	Name        string            `json:"name,omitempty"`
+	URI         string            `json:"uri,omitempty"`
+	Digest      map[string]string `json:"digest,omitempty"`
+	Annotations map[string]any    `json:"annotations,omitempty"`
+}
+
+// Config holds everything that is recorded in the provenance of an image.
+type Config struct {
+	// Image is the name of the built image and Digest the digest of its
+	// manifest in the registry.
+	Image  string
+	Digest v1.Hash
+
+	// Builder is the builder image that ran the build.
+	Builder string
+
+	// Buildpacks are the buildpacks that took part in the build, as read from
+	// the image with BuildpacksFromLabels. They are recorded as resolved
+	// dependencies, with the digests of their buildpackages from Lockfile, the
+	// package.lock.toml of the Go buildpack release that built the image,
+	// when it is given and locks the same version.
+	Buildpacks []Buildpack
+	Lockfile   components.Lockfile
+
+	Source Source
+
+	// Binaries are the Go binaries in the image, as read with
+	// BinariesFromImage.
+	Binaries []Binary
+}
+
+// Generate builds the SLSA provenance statement for an image.
+func Generate(config Config) Statement {
+	var dependencies []ResourceDescriptor
+	if config.Source.URI != "" {
+		source := ResourceDescriptor{URI: config.Source.URI}
+		if config.Source.Revision != "" {
+			source.Digest = map[string]string{"gitCommit": config.Source.Revision}
+		}
+		dependencies = append(dependencies, source)
+	}
+
+	for _, buildpack := range config.Buildpacks {
+		dependency := ResourceDescriptor{
+			Name:        buildpack.ID,
+			Annotations: map[string]any{"version": buildpack.Version},
+		}
+
+		locked, ok := lockedBuildpack(buildpack, config.Lockfile)
+		if ok {
+			dependency.URI = locked.URI
+			dependency.Digest = digestOf(locked.Digest)
+		}
+
+		dependencies = append(dependencies, dependency)
+	}
+
+	return Statement{
+		Type: StatementType,
+		Subject: []ResourceDescriptor{{
+			Name:   config.Image,
+			Digest: map[string]string{config.Digest.Algorithm: config.Digest.Hex},
+		}},
+		PredicateType: PredicateType,
+		Predicate: Predicate{
+			BuildDefinition: BuildDefinition{
+				BuildType: BuildType,
+				ExternalParameters: ExternalParameters{
+					Source:   config.Source,
+					Binaries: config.Binaries,
+				},
+				InternalParameters:   InternalParameters{Buildpacks: config.Buildpacks},
+				ResolvedDependencies: dependencies,
+			},
+			RunDetails: RunDetails{
+				Builder: Builder{ID: config.Builder},
+			},
+		},
+	}
+}
+
+// BuildpacksFromLabels reads the buildpacks that took part in a build from
+// the labels of the built image.
+func BuildpacksFromLabels(labels map[string]string) ([]Buildpack, error) {
+	label, ok := labels[BuildMetadataLabel]
+	if !ok {
+		return nil, fmt.Errorf("image has no %s label", BuildMetadataLabel)
+	}
+
+	var metadata struct {
+		Buildpacks []Buildpack `json:"buildpacks"`
+	}
+	err := json.Unmarshal([]byte(label), &metadata)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s label: %w", BuildMetadataLabel, err)
+	}
+
+	return metadata.Buildpacks, nil
+}
+
+// lockedBuildpack returns the dependency of the lockfile that packages the
+// given version of a buildpack. Lockfiles record the package.toml URIs of the
+// components, which are tagged with their versions, so a dependency matches
+// when its tag is the version and its repository is named after the
+// buildpack, as paketobuildpacks/go-build is for paketo-buildpacks/go-build.
+func lockedBuildpack(buildpack Buildpack, lockfile components.Lockfile) (components.LockedDependency, bool) {
+	for _, dependency := range lockfile.Dependencies {
+		ref, err := components.PackageDependency{URI: dependency.URI}.Reference()
+		if err != nil {
+			continue
+		}
+
+		if ref.Identifier() == buildpack.Version && path.Base(ref.Context().RepositoryStr()) == path.Base(buildpack.ID) {
+			return dependency, true
+		}
+	}
+
+	return components.LockedDependency{}, false
+}
+
+// digestOf returns a digest as a resource descriptor digest.
+func digestOf(digest string) map[string]string {
+	hash, err := v1.NewHash(digest)
+	if err != nil {
+		return nil
+	}
+
+	return map[string]string{hash.Algorithm: hash.Hex}
+}
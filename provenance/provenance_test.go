@@ -0,0 +1,130 @@
+package provenance_test
+
+import (
+	"testing"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/paketo-buildpacks/go/components"
+	"github.com/paketo-buildpacks/go/provenance"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testProvenance(t *testing.T, context spec.G, it spec.S) {
+	var Expect = NewWithT(t).Expect
+
+	context("Generate", func() {
+		it("records the image, builder, buildpacks, source and Go build configuration", func() {
+			statement := provenance.Generate(provenance.Config{
+				Image:   "some-image",
+				Digest:  v1.Hash{Algorithm: "sha256", Hex: "1111"},
+				Builder: "paketobuildpacks/builder-jammy-buildpackless-base",
+				Buildpacks: []provenance.Buildpack{
+					{ID: "paketo-buildpacks/go-dist", Version: "2.10.9"},
+					{ID: "paketo-buildpacks/go-build", Version: "2.4.20"},
+					{ID: "paketo-buildpacks/go-licenses", Version: "1.0.0"},
+				},
+				Lockfile: components.Lockfile{
+					Dependencies: []components.LockedDependency{
+						{URI: "docker://docker.io/paketobuildpacks/go-dist:2.10.9", Digest: "sha256:3333333333333333333333333333333333333333333333333333333333333333"},
+						// go-build 2.4.20 is not the version this lockfile locks
+						{URI: "docker://docker.io/paketobuildpacks/go-build:2.4.19", Digest: "sha256:2222222222222222222222222222222222222222222222222222222222222222"},
+					},
+				},
+				Source: provenance.Source{URI: "git+https://example.com/some-app", Revision: "abcdef"},
+				Binaries: []provenance.Binary{
+					{
+						Path:      "/layers/paketo-buildpacks_go-build/targets/bin/app",
+						GoVersion: "go1.26.3",
+						Main:      "example.com/some-app",
+						Settings:  map[string]string{"-ldflags": "-s -w", "-trimpath": "true"},
+					},
+				},
+			})
+
+			Expect(statement).To(Equal(provenance.Statement{
+				Type: provenance.StatementType,
+				Subject: []provenance.ResourceDescriptor{
+					{Name: "some-image", Digest: map[string]string{"sha256": "1111"}},
+				},
+				PredicateType: provenance.PredicateType,
+				Predicate: provenance.Predicate{
+					BuildDefinition: provenance.BuildDefinition{
+						BuildType: provenance.BuildType,
+						ExternalParameters: provenance.ExternalParameters{
+							Source: provenance.Source{URI: "git+https://example.com/some-app", Revision: "abcdef"},
+							Binaries: []provenance.Binary{
+								{
+									Path:      "/layers/paketo-buildpacks_go-build/targets/bin/app",
+									GoVersion: "go1.26.3",
+									Main:      "example.com/some-app",
+									Settings:  map[string]string{"-ldflags": "-s -w", "-trimpath": "true"},
+								},
+							},
+						},
+						InternalParameters: provenance.InternalParameters{
+							Buildpacks: []provenance.Buildpack{
+								{ID: "paketo-buildpacks/go-dist", Version: "2.10.9"},
+								{ID: "paketo-buildpacks/go-build", Version: "2.4.20"},
+								{ID: "paketo-buildpacks/go-licenses", Version: "1.0.0"},
+							},
+						},
+						ResolvedDependencies: []provenance.ResourceDescriptor{
+							{
+								URI:    "git+https://example.com/some-app",
+								Digest: map[string]string{"gitCommit": "abcdef"},
+							},
+							{
+								Name:        "paketo-buildpacks/go-dist",
+								URI:         "docker://docker.io/paketobuildpacks/go-dist:2.10.9",
+								Digest:      map[string]string{"sha256": "3333333333333333333333333333333333333333333333333333333333333333"},
+								Annotations: map[string]any{"version": "2.10.9"},
+							},
+							{
+								Name:        "paketo-buildpacks/go-build",
+								Annotations: map[string]any{"version": "2.4.20"},
+							},
+							{
+								Name:        "paketo-buildpacks/go-licenses",
+								Annotations: map[string]any{"version": "1.0.0"},
+							},
+						},
+					},
+					RunDetails: provenance.RunDetails{
+						Builder: provenance.Builder{ID: "paketobuildpacks/builder-jammy-buildpackless-base"},
+					},
+				},
+			}))
+		})
+	})
+
+	context("BuildpacksFromLabels", func() {
+		it("reads the buildpacks from the build metadata label", func() {
+			buildpacks, err := provenance.BuildpacksFromLabels(map[string]string{
+				provenance.BuildMetadataLabel: `{"buildpacks": [{"id": "paketo-buildpacks/go-dist", "version": "2.10.9", "homepage": "https://example.com"}, {"id": "paketo-buildpacks/go-build", "version": "2.4.20"}]}`,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(buildpacks).To(Equal([]provenance.Buildpack{
+				{ID: "paketo-buildpacks/go-dist", Version: "2.10.9"},
+				{ID: "paketo-buildpacks/go-build", Version: "2.4.20"},
+			}))
+		})
+
+		context("failure cases", func() {
+			context("when the label is missing", func() {
+				it("returns an error", func() {
+					_, err := provenance.BuildpacksFromLabels(map[string]string{})
+					Expect(err).To(MatchError("image has no io.buildpacks.build.metadata label"))
+				})
+			})
+
+			context("when the label is malformed", func() {
+				it("returns an error", func() {
+					_, err := provenance.BuildpacksFromLabels(map[string]string{provenance.BuildMetadataLabel: "%%%"})
+					Expect(err).To(MatchError(ContainSubstring("failed to parse io.buildpacks.build.metadata label")))
+				})
+			})
+		})
+	})
+}
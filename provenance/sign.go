@@ -0,0 +1,236 @@
+package provenance
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/paketo-buildpacks/packit/v2/servicebindings"
+)
+
+// PayloadType is the DSSE payload type of an in-toto statement.
+const PayloadType = "application/vnd.in-toto+json"
+
+// BindingType is the type of the service binding that holds the key used to
+// sign provenance. The binding holds a PEM encoded PKCS #8 ECDSA or Ed25519
+// private key in its BindingKeyEntry entry.
+const (
+	BindingType     = "slsa-signing-key"
+	BindingKeyEntry = "key.pem"
+)
+
+// Envelope is a DSSE envelope holding a signed statement.
+type Envelope struct {
+	PayloadType string      `json:"payloadType"`
+	Payload     string      `json:"payload"`
+	Signatures  []Signature `json:"signatures"`
+}
+
+// Signature is a single signature of a DSSE envelope.
+type Signature struct {
+	KeyID string `json:"keyid,omitempty"`
+	Sig   string `json:"sig"`
+}
+
+// Sign wraps the statement in a DSSE envelope signed with the given key. The
+// key ID is the hex encoded SHA-256 of the DER encoded public key.
+func Sign(statement Statement, key crypto.Signer) (Envelope, error) {
+	payload, err := json.Marshal(statement)
+	if err != nil {
+		return Envelope{}, err
+	}
+
+	keyID, err := KeyID(key.Public())
+	if err != nil {
+		return Envelope{}, err
+	}
+
+	message := pae(PayloadType, payload)
+
+	var sig []byte
+	switch key.(type) {
+	case ed25519.PrivateKey:
+		sig, err = key.Sign(rand.Reader, message, crypto.Hash(0))
+	case *ecdsa.PrivateKey:
+		digest := sha256.Sum256(message)
+		sig, err = key.Sign(rand.Reader, digest[:], crypto.SHA256)
+	default:
+		return Envelope{}, fmt.Errorf("unsupported signing key type %T", key)
+	}
+	if err != nil {
+		return Envelope{}, fmt.Errorf("failed to sign provenance: %w", err)
+	}
+
+	return Envelope{
+		PayloadType: PayloadType,
+		Payload:     base64.StdEncoding.EncodeToString(payload),
+		Signatures:  []Signature{{KeyID: keyID, Sig: base64.StdEncoding.EncodeToString(sig)}},
+	}, nil
+}
+
+// Verify checks that the envelope carries a valid signature from the given
+// public key and returns the statement it holds.
+func Verify(envelope Envelope, key crypto.PublicKey) (Statement, error) {
+	if envelope.PayloadType != PayloadType {
+		return Statement{}, fmt.Errorf("unexpected payload type %q", envelope.PayloadType)
+	}
+
+	payload, err := base64.StdEncoding.DecodeString(envelope.Payload)
+	if err != nil {
+		return Statement{}, fmt.Errorf("failed to decode payload: %w", err)
+	}
+
+	message := pae(envelope.PayloadType, payload)
+
+	verified := false
+	for _, signature := range envelope.Signatures {
+		sig, err := base64.StdEncoding.DecodeString(signature.Sig)
+		if err != nil {
+			continue
+		}
+
+		switch key := key.(type) {
+		case ed25519.PublicKey:
+			verified = ed25519.Verify(key, message, sig)
+		case *ecdsa.PublicKey:
+			digest := sha256.Sum256(message)
+			verified = ecdsa.VerifyASN1(key, digest[:], sig)
+		default:
+			return Statement{}, fmt.Errorf("unsupported verification key type %T", key)
+		}
+
+		if verified {
+			break
+		}
+	}
+
+	if !verified {
+		return Statement{}, errors.New("no signature matches the verification key")
+	}
+
+	var statement Statement
+	err = json.Unmarshal(payload, &statement)
+	if err != nil {
+		return Statement{}, fmt.Errorf("failed to parse statement: %w", err)
+	}
+
+	return statement, nil
+}
+
+// KeyID returns the hex encoded SHA-256 of the DER encoded public key.
+func KeyID(key crypto.PublicKey) (string, error) {
+	der, err := x509.MarshalPKIXPublicKey(key)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode public key: %w", err)
+	}
+
+	sum := sha256.Sum256(der)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// ReadPrivateKey reads a PEM encoded PKCS #8 private key.
+func ReadPrivateKey(path string) (crypto.Signer, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return parsePrivateKey(content, path)
+}
+
+// ReadPublicKey reads a PEM encoded PKIX public key.
+func ReadPublicKey(path string) (crypto.PublicKey, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := decodePEM(content, path)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse public key: %w", err)
+	}
+
+	return key, nil
+}
+
+// FindSigningKey returns the signing key of the first service binding of type
+// BindingType, if there is one. The bindings are resolved by the packit
+// servicebindings resolver, from SERVICE_BINDING_ROOT, CNB_BINDINGS,
+// VCAP_SERVICES or the bindings directory in platformDir.
+func FindSigningKey(platformDir string) (crypto.Signer, bool, error) {
+	bindings, err := servicebindings.NewResolver().Resolve(BindingType, "", platformDir)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if len(bindings) == 0 {
+		return nil, false, nil
+	}
+
+	binding := bindings[0]
+	entry, ok := binding.Entries[BindingKeyEntry]
+	if !ok {
+		return nil, false, fmt.Errorf("binding %s is missing its %s entry", binding.Name, BindingKeyEntry)
+	}
+
+	content, err := entry.ReadBytes()
+	if err != nil {
+		return nil, false, err
+	}
+
+	key, err := parsePrivateKey(content, fmt.Sprintf("binding %s", binding.Name))
+	if err != nil {
+		return nil, false, err
+	}
+
+	return key, true, nil
+}
+
+// parsePrivateKey parses a PEM encoded PKCS #8 private key read from source.
+func parsePrivateKey(content []byte, source string) (crypto.Signer, error) {
+	block, err := decodePEM(content, source)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse private key: %w", err)
+	}
+
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("unsupported signing key type %T", key)
+	}
+
+	return signer, nil
+}
+
+func decodePEM(content []byte, source string) (*pem.Block, error) {
+	block, _ := pem.Decode(content)
+	if block == nil {
+		return nil, fmt.Errorf("failed to decode PEM block in %s", source)
+	}
+
+	return block, nil
+}
+
+// pae is the DSSE pre-authentication encoding of a payload.
+func pae(payloadType string, payload []byte) []byte {
+	return []byte(fmt.Sprintf("DSSEv1 %d %s %d %s", len(payloadType), payloadType, len(payload), payload))
+}
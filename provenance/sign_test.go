@@ -0,0 +1,210 @@
+package provenance_test
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/paketo-buildpacks/go/provenance"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testSign(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		statement provenance.Statement
+	)
+
+	it.Before(func() {
+		statement = provenance.Statement{
+			Type:          provenance.StatementType,
+			Subject:       []provenance.ResourceDescriptor{{Name: "some-image", Digest: map[string]string{"sha256": "1111"}}},
+			PredicateType: provenance.PredicateType,
+		}
+	})
+
+	for _, keyType := range []string{"ECDSA", "Ed25519"} {
+		keyType := keyType
+
+		context("with an "+keyType+" key", func() {
+			var key crypto.Signer
+
+			it.Before(func() {
+				var err error
+				if keyType == "ECDSA" {
+					key, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+				} else {
+					_, key, err = ed25519.GenerateKey(rand.Reader)
+				}
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			it("signs the statement so that the public key verifies it", func() {
+				envelope, err := provenance.Sign(statement, key)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(envelope.PayloadType).To(Equal(provenance.PayloadType))
+
+				keyID, err := provenance.KeyID(key.Public())
+				Expect(err).NotTo(HaveOccurred())
+				Expect(envelope.Signatures).To(ConsistOf(HaveField("KeyID", keyID)))
+
+				verified, err := provenance.Verify(envelope, key.Public())
+				Expect(err).NotTo(HaveOccurred())
+				Expect(verified).To(Equal(statement))
+			})
+
+			it("rejects a tampered payload", func() {
+				envelope, err := provenance.Sign(statement, key)
+				Expect(err).NotTo(HaveOccurred())
+
+				statement.Subject[0].Digest["sha256"] = "2222"
+				tampered, err := provenance.Sign(statement, key)
+				Expect(err).NotTo(HaveOccurred())
+				envelope.Payload = tampered.Payload
+
+				_, err = provenance.Verify(envelope, key.Public())
+				Expect(err).To(MatchError("no signature matches the verification key"))
+			})
+		})
+	}
+
+	context("when the envelope is signed with another key", func() {
+		it("returns an error", func() {
+			key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+			Expect(err).NotTo(HaveOccurred())
+
+			other, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+			Expect(err).NotTo(HaveOccurred())
+
+			envelope, err := provenance.Sign(statement, key)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = provenance.Verify(envelope, other.Public())
+			Expect(err).To(MatchError("no signature matches the verification key"))
+		})
+	})
+
+	context("when the envelope has another payload type", func() {
+		it("returns an error", func() {
+			key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = provenance.Verify(provenance.Envelope{
+				PayloadType: "text/plain",
+				Payload:     base64.StdEncoding.EncodeToString([]byte("{}")),
+			}, key.Public())
+			Expect(err).To(MatchError(`unexpected payload type "text/plain"`))
+		})
+	})
+
+	context("ReadPrivateKey and ReadPublicKey", func() {
+		it("read PEM encoded keys", func() {
+			key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+			Expect(err).NotTo(HaveOccurred())
+
+			dir := t.TempDir()
+
+			der, err := x509.MarshalPKCS8PrivateKey(key)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(os.WriteFile(filepath.Join(dir, "key.pem"), pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0600)).To(Succeed())
+
+			der, err = x509.MarshalPKIXPublicKey(key.Public())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(os.WriteFile(filepath.Join(dir, "key.pub.pem"), pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0600)).To(Succeed())
+
+			signer, err := provenance.ReadPrivateKey(filepath.Join(dir, "key.pem"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(signer.Public()).To(Equal(key.Public()))
+
+			public, err := provenance.ReadPublicKey(filepath.Join(dir, "key.pub.pem"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(public).To(Equal(key.Public()))
+		})
+
+		context("when the file is not PEM", func() {
+			it("returns an error", func() {
+				path := filepath.Join(t.TempDir(), "key.pem")
+				Expect(os.WriteFile(path, []byte("some-key"), 0600)).To(Succeed())
+
+				_, err := provenance.ReadPrivateKey(path)
+				Expect(err).To(MatchError(ContainSubstring("failed to decode PEM block")))
+			})
+		})
+	})
+
+	context("FindSigningKey", func() {
+		var (
+			platformDir string
+			root        string
+		)
+
+		it.Before(func() {
+			platformDir = t.TempDir()
+			root = filepath.Join(platformDir, "bindings")
+
+			Expect(os.MkdirAll(filepath.Join(root, "git-credentials"), os.ModePerm)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(root, "git-credentials", "type"), []byte("git-credentials"), 0600)).To(Succeed())
+		})
+
+		it("reads the key of the slsa-signing-key binding", func() {
+			key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+			Expect(err).NotTo(HaveOccurred())
+
+			der, err := x509.MarshalPKCS8PrivateKey(key)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(os.MkdirAll(filepath.Join(root, "signing"), os.ModePerm)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(root, "signing", "type"), []byte("slsa-signing-key\n"), 0600)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(root, "signing", "key.pem"), pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0600)).To(Succeed())
+
+			signer, ok, err := provenance.FindSigningKey(platformDir)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ok).To(BeTrue())
+			Expect(signer.Public()).To(Equal(key.Public()))
+		})
+
+		it("reports when there is no such binding", func() {
+			_, ok, err := provenance.FindSigningKey(platformDir)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ok).To(BeFalse())
+
+			_, ok, err = provenance.FindSigningKey(t.TempDir())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ok).To(BeFalse())
+		})
+
+		context("failure cases", func() {
+			it.Before(func() {
+				Expect(os.MkdirAll(filepath.Join(root, "signing"), os.ModePerm)).To(Succeed())
+				Expect(os.WriteFile(filepath.Join(root, "signing", "type"), []byte("slsa-signing-key"), 0600)).To(Succeed())
+			})
+
+			context("when the binding has no key", func() {
+				it("returns an error", func() {
+					_, _, err := provenance.FindSigningKey(platformDir)
+					Expect(err).To(MatchError("binding signing is missing its key.pem entry"))
+				})
+			})
+
+			context("when the key is not PEM", func() {
+				it("returns an error", func() {
+					Expect(os.WriteFile(filepath.Join(root, "signing", "key.pem"), []byte("some-key"), 0600)).To(Succeed())
+
+					_, _, err := provenance.FindSigningKey(platformDir)
+					Expect(err).To(MatchError("failed to decode PEM block in binding signing"))
+				})
+			})
+		})
+	})
+}
@@ -0,0 +1,52 @@
+package provenance
+
+import (
+	"errors"
+	"fmt"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+)
+
+// Subject is an image that provenance is generated for: a single image, or a
+// platform image of an index.
+type Subject struct {
+	Digest   v1.Hash
+	Platform *v1.Platform
+	Image    v1.Image
+}
+
+// SubjectsFromIndex returns the platform images of an index. pack builds one
+// image per platform and an index only collects them, so every platform image
+// has its own build and provenance. Manifests that are not platform images,
+// such as the attestation manifests docker buildx adds with an unknown
+// platform, are skipped, and an index without any platform image is an error.
+func SubjectsFromIndex(index v1.ImageIndex) ([]Subject, error) {
+	manifest, err := index.IndexManifest()
+	if err != nil {
+		return nil, err
+	}
+
+	var subjects []Subject
+	for _, descriptor := range manifest.Manifests {
+		if !descriptor.MediaType.IsImage() || descriptor.Platform == nil || descriptor.Platform.OS == "unknown" {
+			continue
+		}
+
+		image, err := index.Image(descriptor.Digest)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the %s image of the index: %w", descriptor.Platform, err)
+		}
+
+		subjects = append(subjects, Subject{
+			Digest:   descriptor.Digest,
+			Platform: descriptor.Platform,
+			Image:    image,
+		})
+	}
+
+	if len(subjects) == 0 {
+		return nil, errors.New("index has no platform images")
+	}
+
+	return subjects, nil
+}
@@ -0,0 +1,60 @@
+package provenance_test
+
+import (
+	"testing"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/paketo-buildpacks/go/provenance"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testSubjects(t *testing.T, context spec.G, it spec.S) {
+	var Expect = NewWithT(t).Expect
+
+	context("SubjectsFromIndex", func() {
+		it("returns the platform images and skips attestation manifests", func() {
+			amd64, err := random.Image(64, 1)
+			Expect(err).NotTo(HaveOccurred())
+
+			arm64, err := random.Image(64, 1)
+			Expect(err).NotTo(HaveOccurred())
+
+			attestation, err := random.Image(64, 1)
+			Expect(err).NotTo(HaveOccurred())
+
+			index := mutate.AppendManifests(empty.Index,
+				mutate.IndexAddendum{Add: amd64, Descriptor: v1.Descriptor{Platform: &v1.Platform{OS: "linux", Architecture: "amd64"}}},
+				mutate.IndexAddendum{Add: attestation, Descriptor: v1.Descriptor{Platform: &v1.Platform{OS: "unknown", Architecture: "unknown"}}},
+				mutate.IndexAddendum{Add: arm64, Descriptor: v1.Descriptor{Platform: &v1.Platform{OS: "linux", Architecture: "arm64"}}},
+			)
+
+			subjects, err := provenance.SubjectsFromIndex(index)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(subjects).To(HaveLen(2))
+
+			digest, err := amd64.Digest()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(subjects[0].Digest).To(Equal(digest))
+			Expect(subjects[0].Platform.Architecture).To(Equal("amd64"))
+
+			digest, err = arm64.Digest()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(subjects[1].Digest).To(Equal(digest))
+			Expect(subjects[1].Platform.Architecture).To(Equal("arm64"))
+		})
+
+		context("failure cases", func() {
+			context("when the index has no platform images", func() {
+				it("returns an error", func() {
+					_, err := provenance.SubjectsFromIndex(empty.Index)
+					Expect(err).To(MatchError("index has no platform images"))
+				})
+			})
+		})
+	})
+}
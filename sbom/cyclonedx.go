@@ -0,0 +1,194 @@
+package sbom
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// CycloneDXDocument is the subset of a CycloneDX JSON document that is needed
+// to merge documents. Components are kept as generic objects so that fields
+// written by the component buildpacks survive the merge.
+type CycloneDXDocument struct {
+	BOMFormat    string                `json:"bomFormat"`
+	SpecVersion  string                `json:"specVersion"`
+	Version      int                   `json:"version"`
+	Metadata     *CycloneDXMetadata    `json:"metadata,omitempty"`
+	Components   []map[string]any      `json:"components,omitempty"`
+	Dependencies []CycloneDXDependency `json:"dependencies,omitempty"`
+}
+
+// CycloneDXMetadata is the metadata object of a CycloneDX document.
+type CycloneDXMetadata struct {
+	Timestamp string         `json:"timestamp,omitempty"`
+	Component map[string]any `json:"component,omitempty"`
+}
+
+// CycloneDXDependency is a single entry of the dependency graph of a
+// CycloneDX document.
+type CycloneDXDependency struct {
+	Ref       string   `json:"ref"`
+	DependsOn []string `json:"dependsOn,omitempty"`
+}
+
+// MergeCycloneDX merges the given CycloneDX documents into a single document
+// describing the whole image. Components are de-duplicated by package URL
+// (or by type, name and version when they have none), and the dependency
+// graphs of the documents are joined under a root component that stands for
+// the image.
+func MergeCycloneDX(name string, documents ...[]byte) ([]byte, error) {
+	// CycloneDX 1.3 requires every component to have a version, but the
+	// image is only given one when it is tagged.
+	root := map[string]any{
+		"bom-ref": rootRef,
+		"type":    "container",
+		"name":    name,
+		"version": "",
+	}
+
+	merged := CycloneDXDocument{
+		BOMFormat: "CycloneDX",
+		Version:   1,
+		Metadata:  &CycloneDXMetadata{Component: root},
+	}
+
+	components := map[string]map[string]any{}
+	refs := map[string]string{}
+	graph := map[string]map[string]bool{}
+	depend := func(from, to string) {
+		if from == to {
+			return
+		}
+		if graph[from] == nil {
+			graph[from] = map[string]bool{}
+		}
+		graph[from][to] = true
+	}
+
+	for i, content := range documents {
+		var document CycloneDXDocument
+		err := json.Unmarshal(content, &document)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CycloneDX document %d: %w", i, err)
+		}
+
+		if document.BOMFormat != "CycloneDX" {
+			return nil, fmt.Errorf("failed to parse CycloneDX document %d: unexpected bomFormat %q", i, document.BOMFormat)
+		}
+
+		if merged.SpecVersion == "" || document.SpecVersion > merged.SpecVersion {
+			merged.SpecVersion = document.SpecVersion
+		}
+
+		// Each document describes the layer it was generated for. That
+		// subject becomes a component of the image so the graph records
+		// which layer every component came from.
+		subject := fmt.Sprintf("layer-%d", i)
+		if document.Metadata != nil && document.Metadata.Component != nil {
+			component := document.Metadata.Component
+			if ref, ok := component["bom-ref"].(string); ok && ref != "" {
+				subject = ref
+			}
+
+			component = clone(component)
+			delete(component, "components")
+			component["bom-ref"] = subject
+			components[subject] = component
+		}
+		depend(rootRef, subject)
+
+		local := map[string]string{}
+		for _, component := range document.Components {
+			key := cycloneDXKey(component)
+			ref, _ := component["bom-ref"].(string)
+
+			existing, ok := refs[key]
+			if !ok {
+				existing = ref
+				if existing == "" {
+					existing = key
+				}
+				refs[key] = existing
+
+				component = clone(component)
+				component["bom-ref"] = existing
+				components[existing] = component
+			}
+
+			if ref != "" {
+				local[ref] = existing
+			}
+			local[key] = existing
+		}
+
+		resolve := func(ref string) string {
+			if merged, ok := local[ref]; ok {
+				return merged
+			}
+			return ref
+		}
+
+		describesSubject := false
+		for _, dependency := range document.Dependencies {
+			from := resolve(dependency.Ref)
+			if dependency.Ref == subject {
+				describesSubject = len(dependency.DependsOn) > 0
+			}
+
+			for _, to := range dependency.DependsOn {
+				depend(from, resolve(to))
+			}
+		}
+
+		if !describesSubject {
+			for _, component := range document.Components {
+				depend(subject, local[cycloneDXKey(component)])
+			}
+		}
+	}
+
+	var ids []string
+	for ref := range components {
+		ids = append(ids, ref)
+	}
+	sort.Strings(ids)
+
+	for _, ref := range ids {
+		merged.Components = append(merged.Components, components[ref])
+	}
+
+	var froms []string
+	for from := range graph {
+		froms = append(froms, from)
+	}
+	sort.Strings(froms)
+
+	for _, from := range froms {
+		var tos []string
+		for to := range graph[from] {
+			tos = append(tos, to)
+		}
+		sort.Strings(tos)
+
+		merged.Dependencies = append(merged.Dependencies, CycloneDXDependency{Ref: from, DependsOn: tos})
+	}
+
+	return json.MarshalIndent(merged, "", "  ")
+}
+
+func cycloneDXKey(component map[string]any) string {
+	if purl, ok := component["purl"].(string); ok && purl != "" {
+		return purl
+	}
+
+	return fmt.Sprintf("%v/%v@%v", component["type"], component["name"], component["version"])
+}
+
+func clone(object map[string]any) map[string]any {
+	copied := map[string]any{}
+	for key, value := range object {
+		copied[key] = value
+	}
+
+	return copied
+}
@@ -0,0 +1,110 @@
+package sbom_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/paketo-buildpacks/go/sbom"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testCycloneDX(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		documents [][]byte
+	)
+
+	it.Before(func() {
+		documents = nil
+		for _, name := range []string{"go-build.cdx.json", "go-mod-vendor.cdx.json"} {
+			content, err := os.ReadFile(filepath.Join("testdata", name))
+			Expect(err).NotTo(HaveOccurred())
+			documents = append(documents, content)
+		}
+	})
+
+	it("merges the documents into one with de-duplicated components", func() {
+		content, err := sbom.MergeCycloneDX("some-image", documents...)
+		Expect(err).NotTo(HaveOccurred())
+
+		var document sbom.CycloneDXDocument
+		Expect(json.Unmarshal(content, &document)).To(Succeed())
+
+		Expect(document.BOMFormat).To(Equal("CycloneDX"))
+		Expect(document.SpecVersion).To(Equal("1.4"))
+		Expect(document.Metadata.Timestamp).To(BeEmpty())
+		Expect(document.Metadata.Component).To(Equal(map[string]any{
+			"bom-ref": "image",
+			"type":    "container",
+			"name":    "some-image",
+			"version": "",
+		}))
+
+		var names []string
+		for _, component := range document.Components {
+			names = append(names, component["name"].(string))
+		}
+		Expect(names).To(ConsistOf(
+			"/layers/paketo-buildpacks_go-build/targets/bin/go-online",
+			"/workspace/go.mod",
+			"github.com/BurntSushi/toml",
+			"github.com/satori/go.uuid",
+			"gopkg.in/check.v1",
+			"stdlib",
+		))
+
+		Expect(document.Dependencies).To(Equal([]sbom.CycloneDXDependency{
+			{
+				Ref: "go-build-targets",
+				DependsOn: []string{
+					"pkg:golang/github.com/BurntSushi/toml@v1.2.0?package-id=1111",
+					"pkg:golang/github.com/satori/go.uuid@v1.2.0?package-id=2222",
+					"pkg:golang/stdlib@1.22.0?package-id=3333",
+				},
+			},
+			{
+				Ref:       "go-mod-vendor-modules",
+				DependsOn: []string{"pkg:golang/github.com/BurntSushi/toml@v1.2.0?package-id=1111"},
+			},
+			{
+				Ref:       "image",
+				DependsOn: []string{"go-build-targets", "go-mod-vendor-modules"},
+			},
+			{
+				Ref:       "pkg:golang/github.com/BurntSushi/toml@v1.2.0?package-id=1111",
+				DependsOn: []string{"check-in-vendor"},
+			},
+		}))
+	})
+
+	it("is deterministic", func() {
+		first, err := sbom.MergeCycloneDX("some-image", documents...)
+		Expect(err).NotTo(HaveOccurred())
+
+		second, err := sbom.MergeCycloneDX("some-image", documents...)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(first).To(Equal(second))
+	})
+
+	context("failure cases", func() {
+		context("when a document is malformed", func() {
+			it("returns an error", func() {
+				_, err := sbom.MergeCycloneDX("some-image", documents[0], []byte("%%%"))
+				Expect(err).To(MatchError(ContainSubstring("failed to parse CycloneDX document 1")))
+			})
+		})
+
+		context("when a document is not CycloneDX", func() {
+			it("returns an error", func() {
+				_, err := sbom.MergeCycloneDX("some-image", []byte(`{"spdxVersion": "SPDX-2.2"}`))
+				Expect(err).To(MatchError(`failed to parse CycloneDX document 0: unexpected bomFormat ""`))
+			})
+		})
+	})
+}
@@ -0,0 +1,18 @@
+package sbom_test
+
+import (
+	"testing"
+
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+)
+
+func TestUnitSBOM(t *testing.T) {
+	suite := spec.New("sbom", spec.Report(report.Terminal{}), spec.Parallel())
+	suite("CycloneDX", testCycloneDX)
+	suite("Find", testFind)
+	suite("FindLaunch", testFindLaunch)
+	suite("Schema", testSchema)
+	suite("SPDX", testSPDX)
+	suite.Run(t)
+}
@@ -0,0 +1,113 @@
+// Package sbom merges the per-layer SBOM documents that the component
+// buildpacks attach to an image into a single document per format.
+package sbom
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+const rootRef = "image"
+
+// Extensions of the SBOM documents written by the component buildpacks.
+const (
+	CycloneDXExtension = "cdx.json"
+	SPDXExtension      = "spdx.json"
+	SyftExtension      = "syft.json"
+)
+
+// Find returns the paths of the SBOM documents with the given extension below
+// dir, sorted so that merges are deterministic. Pointing it at the
+// sbom/launch directory written by `pack build --sbom-output-dir` lists the
+// launch-layer SBOMs of every buildpack.
+func Find(dir, extension string) ([]string, error) {
+	var paths []string
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if !entry.IsDir() && entry.Name() == "sbom."+extension {
+			paths = append(paths, path)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// FindLaunch returns the paths of the launch SBOM documents with the given
+// extension that the buildpacks which ran before the calling buildpack wrote
+// below the lifecycle layers directory, sorted so that merges are
+// deterministic. These are the buildpack-level launch.sbom.<ext> documents
+// and the <layer>.sbom.<ext> documents of layers whose <layer>.toml marks them
+// as launch layers. The layers directory of the calling buildpack is skipped.
+func FindLaunch(layersDir, self, extension string) ([]string, error) {
+	buildpacks, err := os.ReadDir(layersDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var paths []string
+	for _, buildpack := range buildpacks {
+		if !buildpack.IsDir() || buildpack.Name() == self {
+			continue
+		}
+
+		dir := filepath.Join(layersDir, buildpack.Name())
+		matches, err := filepath.Glob(filepath.Join(dir, "*.sbom."+extension))
+		if err != nil {
+			return nil, err
+		}
+
+		for _, match := range matches {
+			layer := strings.TrimSuffix(filepath.Base(match), ".sbom."+extension)
+			if layer != "launch" {
+				launch, err := isLaunchLayer(filepath.Join(dir, layer+".toml"))
+				if err != nil {
+					return nil, err
+				}
+
+				if !launch {
+					continue
+				}
+			}
+
+			paths = append(paths, match)
+		}
+	}
+
+	sort.Strings(paths)
+	return paths, nil
+}
+
+func isLaunchLayer(path string) (bool, error) {
+	var metadata struct {
+		Types struct {
+			Launch bool `toml:"launch"`
+		} `toml:"types"`
+	}
+
+	_, err := toml.DecodeFile(path, &metadata)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return false, nil
+		}
+
+		return false, fmt.Errorf("failed to parse layer metadata: %w", err)
+	}
+
+	return metadata.Types.Launch, nil
+}
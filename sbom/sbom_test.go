@@ -0,0 +1,113 @@
+package sbom_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/paketo-buildpacks/go/sbom"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testFind(t *testing.T, context spec.G, it spec.S) {
+	var Expect = NewWithT(t).Expect
+
+	it("finds the documents with the given extension, sorted", func() {
+		dir := t.TempDir()
+		for _, path := range []string{
+			filepath.Join("paketo-buildpacks_go-build", "targets", "sbom.cdx.json"),
+			filepath.Join("paketo-buildpacks_go-build", "targets", "sbom.spdx.json"),
+			filepath.Join("paketo-buildpacks_ca-certificates", "sbom.cdx.json"),
+		} {
+			Expect(os.MkdirAll(filepath.Join(dir, filepath.Dir(path)), os.ModePerm)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(dir, path), []byte("{}"), 0600)).To(Succeed())
+		}
+
+		paths, err := sbom.Find(dir, sbom.CycloneDXExtension)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(paths).To(Equal([]string{
+			filepath.Join(dir, "paketo-buildpacks_ca-certificates", "sbom.cdx.json"),
+			filepath.Join(dir, "paketo-buildpacks_go-build", "targets", "sbom.cdx.json"),
+		}))
+	})
+
+	context("failure cases", func() {
+		context("when the directory does not exist", func() {
+			it("returns an error", func() {
+				_, err := sbom.Find(filepath.Join(t.TempDir(), "missing"), sbom.CycloneDXExtension)
+				Expect(err).To(MatchError(ContainSubstring("no such file or directory")))
+			})
+		})
+	})
+}
+
+func testFindLaunch(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		layersDir string
+	)
+
+	write := func(path, content string) {
+		Expect(os.MkdirAll(filepath.Join(layersDir, filepath.Dir(path)), os.ModePerm)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(layersDir, path), []byte(content), 0600)).To(Succeed())
+	}
+
+	it.Before(func() {
+		layersDir = t.TempDir()
+
+		write(filepath.Join("paketo-buildpacks_go-build", "targets.toml"), "[types]\n  launch = true\n")
+		write(filepath.Join("paketo-buildpacks_go-build", "targets.sbom.cdx.json"), "{}")
+		write(filepath.Join("paketo-buildpacks_go-build", "targets.sbom.spdx.json"), "{}")
+		write(filepath.Join("paketo-buildpacks_go-dist", "go.toml"), "[types]\n  build = true\n  cache = true\n")
+		write(filepath.Join("paketo-buildpacks_go-dist", "go.sbom.cdx.json"), "{}")
+		write(filepath.Join("paketo-buildpacks_go-mod-vendor", "build.sbom.cdx.json"), "{}")
+		write(filepath.Join("paketo-buildpacks_ca-certificates", "launch.sbom.cdx.json"), "{}")
+		write(filepath.Join("paketo-buildpacks_sbom-aggregator", "launch.sbom.cdx.json"), "{}")
+	})
+
+	it("finds the launch documents of the other buildpacks, sorted", func() {
+		paths, err := sbom.FindLaunch(layersDir, "paketo-buildpacks_sbom-aggregator", sbom.CycloneDXExtension)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(paths).To(Equal([]string{
+			filepath.Join(layersDir, "paketo-buildpacks_ca-certificates", "launch.sbom.cdx.json"),
+			filepath.Join(layersDir, "paketo-buildpacks_go-build", "targets.sbom.cdx.json"),
+		}))
+	})
+
+	context("when a layer has no metadata", func() {
+		it.Before(func() {
+			Expect(os.Remove(filepath.Join(layersDir, "paketo-buildpacks_go-build", "targets.toml"))).To(Succeed())
+		})
+
+		it("skips its documents", func() {
+			paths, err := sbom.FindLaunch(layersDir, "paketo-buildpacks_sbom-aggregator", sbom.CycloneDXExtension)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(paths).To(Equal([]string{
+				filepath.Join(layersDir, "paketo-buildpacks_ca-certificates", "launch.sbom.cdx.json"),
+			}))
+		})
+	})
+
+	context("failure cases", func() {
+		context("when the layer metadata cannot be parsed", func() {
+			it.Before(func() {
+				write(filepath.Join("paketo-buildpacks_go-build", "targets.toml"), "%%%")
+			})
+
+			it("returns an error", func() {
+				_, err := sbom.FindLaunch(layersDir, "paketo-buildpacks_sbom-aggregator", sbom.CycloneDXExtension)
+				Expect(err).To(MatchError(ContainSubstring("failed to parse layer metadata")))
+			})
+		})
+
+		context("when the layers directory does not exist", func() {
+			it("returns an error", func() {
+				_, err := sbom.FindLaunch(filepath.Join(layersDir, "missing"), "paketo-buildpacks_sbom-aggregator", sbom.CycloneDXExtension)
+				Expect(err).To(MatchError(ContainSubstring("no such file or directory")))
+			})
+		})
+	})
+}
@@ -0,0 +1,220 @@
+package sbom
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+)
+
+//go:embed schemas/*.json
+var schemas embed.FS
+
+// schemaIDs maps the bundled schema files that other schemas reference to
+// the URLs they are referenced by.
+var schemaIDs = map[string]string{
+	"spdx.schema.json":     "http://cyclonedx.org/schema/spdx.schema.json",
+	"jsf-0.82.schema.json": "http://cyclonedx.org/schema/jsf-0.82.schema.json",
+}
+
+// Violation is a single place where a document does not match its schema.
+type Violation struct {
+	// Path is the JSON pointer of the offending value in the document.
+	Path    string
+	Message string
+}
+
+// SchemaError is returned by Validate when a document does not match the
+// schema for its format and version.
+type SchemaError struct {
+	Schema     string
+	Violations []Violation
+}
+
+func (e SchemaError) Error() string {
+	var lines []string
+	for _, violation := range e.Violations {
+		lines = append(lines, fmt.Sprintf("  %s: %s", violation.Path, violation.Message))
+	}
+
+	return fmt.Sprintf("document does not match schema %s:\n%s", e.Schema, strings.Join(lines, "\n"))
+}
+
+// Validate checks the given SBOM document against the official schema for
+// the format and version it declares: the specVersion of a CycloneDX
+// document, the spdxVersion of an SPDX document or the schema.version of a
+// Syft document.
+//
+// Syft only makes additive or backwards-compatible changes within a major
+// schema version, so Syft documents are checked against the newest bundled
+// schema with the same major version.
+func Validate(document []byte) error {
+	instance, err := jsonschema.UnmarshalJSON(bytes.NewReader(document))
+	if err != nil {
+		return fmt.Errorf("failed to parse SBOM: %w", err)
+	}
+
+	file, err := schemaFor(document)
+	if err != nil {
+		return err
+	}
+
+	schema, err := compile(file)
+	if err != nil {
+		return err
+	}
+
+	err = schema.Validate(instance)
+	if err != nil {
+		var validationErr *jsonschema.ValidationError
+		if !errors.As(err, &validationErr) {
+			return err
+		}
+
+		printer := message.NewPrinter(language.English)
+		violations := flatten(validationErr, printer)
+		sort.SliceStable(violations, func(i, j int) bool {
+			return violations[i].Path < violations[j].Path
+		})
+
+		return SchemaError{Schema: file, Violations: violations}
+	}
+
+	return nil
+}
+
+func schemaFor(document []byte) (string, error) {
+	var header struct {
+		BOMFormat   string `json:"bomFormat"`
+		SpecVersion string `json:"specVersion"`
+		SPDXVersion string `json:"spdxVersion"`
+		Schema      struct {
+			Version string `json:"version"`
+		} `json:"schema"`
+	}
+
+	err := json.Unmarshal(document, &header)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse SBOM: %w", err)
+	}
+
+	var file string
+	switch {
+	case header.BOMFormat == "CycloneDX":
+		file = fmt.Sprintf("bom-%s.schema.json", header.SpecVersion)
+
+	case header.SPDXVersion != "":
+		file = fmt.Sprintf("spdx-%s.schema.json", strings.TrimPrefix(header.SPDXVersion, "SPDX-"))
+
+	case header.Schema.Version != "":
+		version, err := semver.NewVersion(header.Schema.Version)
+		if err != nil {
+			return "", fmt.Errorf("failed to parse Syft schema version: %w", err)
+		}
+
+		file, err = latestSyftSchema(version.Major())
+		if err != nil {
+			return "", err
+		}
+
+	default:
+		return "", errors.New("failed to identify SBOM format: document is not CycloneDX, SPDX or Syft JSON")
+	}
+
+	_, err = schemas.Open(path.Join("schemas", file))
+	if err != nil {
+		return "", fmt.Errorf("no schema is bundled for this SBOM version: %s", file)
+	}
+
+	return file, nil
+}
+
+func latestSyftSchema(major uint64) (string, error) {
+	entries, err := schemas.ReadDir("schemas")
+	if err != nil {
+		return "", err
+	}
+
+	var latest *semver.Version
+	for _, entry := range entries {
+		version, err := semver.NewVersion(strings.TrimSuffix(strings.TrimPrefix(entry.Name(), "syft-"), ".schema.json"))
+		if err != nil || !strings.HasPrefix(entry.Name(), "syft-") || version.Major() != major {
+			continue
+		}
+
+		if latest == nil || version.GreaterThan(latest) {
+			latest = version
+		}
+	}
+
+	if latest == nil {
+		return "", fmt.Errorf("no schema is bundled for this SBOM version: Syft schema %d.x", major)
+	}
+
+	return fmt.Sprintf("syft-%s.schema.json", latest), nil
+}
+
+func compile(file string) (*jsonschema.Schema, error) {
+	compiler := jsonschema.NewCompiler()
+
+	entries, err := schemas.ReadDir("schemas")
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entry := range entries {
+		content, err := schemas.ReadFile(path.Join("schemas", entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		doc, err := jsonschema.UnmarshalJSON(bytes.NewReader(content))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse schema %s: %w", entry.Name(), err)
+		}
+
+		url, ok := schemaIDs[entry.Name()]
+		if !ok {
+			url = entry.Name()
+		}
+
+		err = compiler.AddResource(url, doc)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load schema %s: %w", entry.Name(), err)
+		}
+	}
+
+	schema, err := compiler.Compile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compile schema %s: %w", file, err)
+	}
+
+	return schema, nil
+}
+
+// flatten returns the innermost causes of a validation error, which point at
+// the offending values rather than at the document root.
+func flatten(err *jsonschema.ValidationError, printer *message.Printer) []Violation {
+	if len(err.Causes) == 0 {
+		return []Violation{{
+			Path:    "/" + strings.Join(err.InstanceLocation, "/"),
+			Message: err.ErrorKind.LocalizedString(printer),
+		}}
+	}
+
+	var violations []Violation
+	for _, cause := range err.Causes {
+		violations = append(violations, flatten(cause, printer)...)
+	}
+
+	return violations
+}
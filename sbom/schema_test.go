@@ -0,0 +1,104 @@
+package sbom_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/paketo-buildpacks/go/sbom"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testSchema(t *testing.T, context spec.G, it spec.S) {
+	var Expect = NewWithT(t).Expect
+
+	read := func(name string) []byte {
+		content, err := os.ReadFile(filepath.Join("testdata", name))
+		Expect(err).NotTo(HaveOccurred())
+		return content
+	}
+
+	it("accepts documents that match the schema for their version", func() {
+		for _, name := range []string{
+			"go-build.cdx.json",
+			"go-build.spdx.json",
+			"go-build.syft.json",
+			"go-mod-vendor.cdx.json",
+			"go-mod-vendor.spdx.json",
+		} {
+			Expect(sbom.Validate(read(name))).To(Succeed(), name)
+		}
+	})
+
+	it("accepts merged documents", func() {
+		cyclonedx, err := sbom.MergeCycloneDX("some-image", read("go-build.cdx.json"), read("go-mod-vendor.cdx.json"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(sbom.Validate(cyclonedx)).To(Succeed())
+
+		spdx, err := sbom.MergeSPDX("some-image", time.Unix(0, 0), read("go-build.spdx.json"), read("go-mod-vendor.spdx.json"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(sbom.Validate(spdx)).To(Succeed())
+	})
+
+	context("when a document does not match its schema", func() {
+		var document []byte
+
+		it.Before(func() {
+			var cyclonedx map[string]any
+			Expect(json.Unmarshal(read("go-build.cdx.json"), &cyclonedx)).To(Succeed())
+
+			component := cyclonedx["components"].([]any)[1].(map[string]any)
+			component["type"] = "some-type"
+			delete(component, "name")
+
+			var err error
+			document, err = json.Marshal(cyclonedx)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		it("reports the JSON path of every offending value", func() {
+			err := sbom.Validate(document)
+			Expect(err).To(MatchError(ContainSubstring("document does not match schema bom-1.3.schema.json")))
+
+			var schemaErr sbom.SchemaError
+			Expect(err).To(BeAssignableToTypeOf(schemaErr))
+			schemaErr = err.(sbom.SchemaError)
+
+			Expect(schemaErr.Violations).To(ContainElements(
+				HaveField("Path", "/components/1"),
+				HaveField("Path", "/components/1/type"),
+			))
+			Expect(err.Error()).To(ContainSubstring("/components/1/type: "))
+		})
+	})
+
+	context("failure cases", func() {
+		context("when the document is not JSON", func() {
+			it("returns an error", func() {
+				err := sbom.Validate([]byte("%%%"))
+				Expect(err).To(MatchError(ContainSubstring("failed to parse SBOM")))
+			})
+		})
+
+		context("when the document format is unknown", func() {
+			it("returns an error", func() {
+				err := sbom.Validate([]byte(`{"some": "document"}`))
+				Expect(err).To(MatchError("failed to identify SBOM format: document is not CycloneDX, SPDX or Syft JSON"))
+			})
+		})
+
+		context("when no schema is bundled for the document version", func() {
+			it("returns an error", func() {
+				err := sbom.Validate([]byte(`{"bomFormat": "CycloneDX", "specVersion": "0.9", "version": 1}`))
+				Expect(err).To(MatchError("no schema is bundled for this SBOM version: bom-0.9.schema.json"))
+
+				err = sbom.Validate([]byte(`{"schema": {"version": "3.2.1"}}`))
+				Expect(err).To(MatchError("no schema is bundled for this SBOM version: Syft schema 3.x"))
+			})
+		})
+	})
+}
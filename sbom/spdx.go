@@ -0,0 +1,191 @@
+package sbom
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+)
+
+const spdxDocumentID = "SPDXRef-DOCUMENT"
+
+// SPDXDocument is the subset of an SPDX 2.x JSON document that is needed to
+// merge documents. Packages and files are kept as generic objects so that
+// fields written by the component buildpacks survive the merge.
+type SPDXDocument struct {
+	SPDXVersion       string             `json:"spdxVersion"`
+	DataLicense       string             `json:"dataLicense"`
+	SPDXID            string             `json:"SPDXID"`
+	Name              string             `json:"name"`
+	DocumentNamespace string             `json:"documentNamespace"`
+	CreationInfo      SPDXCreationInfo   `json:"creationInfo"`
+	DocumentDescribes []string           `json:"documentDescribes,omitempty"`
+	Packages          []map[string]any   `json:"packages,omitempty"`
+	Files             []map[string]any   `json:"files,omitempty"`
+	Relationships     []SPDXRelationship `json:"relationships,omitempty"`
+}
+
+// SPDXCreationInfo is the creationInfo object of an SPDX document.
+type SPDXCreationInfo struct {
+	Created  string   `json:"created"`
+	Creators []string `json:"creators"`
+}
+
+// SPDXRelationship is a single relationship between two SPDX elements.
+type SPDXRelationship struct {
+	SPDXElementID      string `json:"spdxElementId"`
+	RelatedSPDXElement string `json:"relatedSpdxElement"`
+	RelationshipType   string `json:"relationshipType"`
+}
+
+// MergeSPDX merges the given SPDX documents into a single document describing
+// the whole image. Packages are de-duplicated by package URL (or by name and
+// version when they have none), SPDX identifiers that collide between
+// documents are renamed, and every relationship is rewritten to the merged
+// identifiers. The created timestamp is taken from the given time so that
+// merges can be reproducible.
+func MergeSPDX(name string, created time.Time, documents ...[]byte) ([]byte, error) {
+	merged := SPDXDocument{
+		SPDXVersion: "SPDX-2.3",
+		DataLicense: "CC0-1.0",
+		SPDXID:      spdxDocumentID,
+		Name:        name,
+		CreationInfo: SPDXCreationInfo{
+			Created:  created.UTC().Format(time.RFC3339),
+			Creators: []string{"Tool: paketo-buildpacks/sbom-aggregator"},
+		},
+	}
+
+	used := map[string]bool{spdxDocumentID: true}
+	packages := map[string]string{}
+	relationships := map[SPDXRelationship]bool{}
+	describes := map[string]bool{}
+
+	for i, content := range documents {
+		var document SPDXDocument
+		err := json.Unmarshal(content, &document)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse SPDX document %d: %w", i, err)
+		}
+
+		if document.SPDXVersion == "" {
+			return nil, fmt.Errorf("failed to parse SPDX document %d: missing spdxVersion", i)
+		}
+
+		ids := map[string]string{document.SPDXID: spdxDocumentID}
+		rename := func(id string) string {
+			unique := id
+			for n := 1; used[unique]; n++ {
+				unique = fmt.Sprintf("%s-%d", id, n)
+			}
+			used[unique] = true
+			return unique
+		}
+
+		for _, pkg := range document.Packages {
+			id, _ := pkg["SPDXID"].(string)
+			key := spdxPackageKey(pkg)
+
+			if existing, ok := packages[key]; ok {
+				ids[id] = existing
+				continue
+			}
+
+			unique := rename(id)
+			ids[id] = unique
+			packages[key] = unique
+
+			pkg = clone(pkg)
+			pkg["SPDXID"] = unique
+			merged.Packages = append(merged.Packages, pkg)
+		}
+
+		for _, file := range document.Files {
+			id, _ := file["SPDXID"].(string)
+			unique := rename(id)
+			ids[id] = unique
+
+			file = clone(file)
+			file["SPDXID"] = unique
+			merged.Files = append(merged.Files, file)
+		}
+
+		resolve := func(id string) string {
+			if merged, ok := ids[id]; ok {
+				return merged
+			}
+			return id
+		}
+
+		for _, id := range document.DocumentDescribes {
+			describes[resolve(id)] = true
+		}
+
+		for _, relationship := range document.Relationships {
+			relationship.SPDXElementID = resolve(relationship.SPDXElementID)
+			relationship.RelatedSPDXElement = resolve(relationship.RelatedSPDXElement)
+			if relationship.SPDXElementID == relationship.RelatedSPDXElement {
+				continue
+			}
+
+			if relationship.SPDXElementID == spdxDocumentID && relationship.RelationshipType == "DESCRIBES" {
+				describes[relationship.RelatedSPDXElement] = true
+				continue
+			}
+
+			relationships[relationship] = true
+		}
+	}
+
+	for id := range describes {
+		relationships[SPDXRelationship{
+			SPDXElementID:      spdxDocumentID,
+			RelatedSPDXElement: id,
+			RelationshipType:   "DESCRIBES",
+		}] = true
+		merged.DocumentDescribes = append(merged.DocumentDescribes, id)
+	}
+	sort.Strings(merged.DocumentDescribes)
+
+	for relationship := range relationships {
+		merged.Relationships = append(merged.Relationships, relationship)
+	}
+	sort.Slice(merged.Relationships, func(i, j int) bool {
+		a, b := merged.Relationships[i], merged.Relationships[j]
+		if a.SPDXElementID != b.SPDXElementID {
+			return a.SPDXElementID < b.SPDXElementID
+		}
+		if a.RelatedSPDXElement != b.RelatedSPDXElement {
+			return a.RelatedSPDXElement < b.RelatedSPDXElement
+		}
+		return a.RelationshipType < b.RelationshipType
+	})
+
+	sort.Slice(merged.Packages, func(i, j int) bool {
+		return fmt.Sprint(merged.Packages[i]["SPDXID"]) < fmt.Sprint(merged.Packages[j]["SPDXID"])
+	})
+
+	// The namespace must be unique per document, so derive it from the merged
+	// content rather than from a random value.
+	content, err := json.Marshal(merged)
+	if err != nil {
+		return nil, err
+	}
+	merged.DocumentNamespace = fmt.Sprintf("https://paketo.io/spdx/%s-%x", name, sha256.Sum256(content))
+
+	return json.MarshalIndent(merged, "", "  ")
+}
+
+func spdxPackageKey(pkg map[string]any) string {
+	if refs, ok := pkg["externalRefs"].([]any); ok {
+		for _, ref := range refs {
+			ref, ok := ref.(map[string]any)
+			if ok && ref["referenceType"] == "purl" {
+				return fmt.Sprint(ref["referenceLocator"])
+			}
+		}
+	}
+
+	return fmt.Sprintf("%v@%v", pkg["name"], pkg["versionInfo"])
+}
@@ -0,0 +1,93 @@
+package sbom_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/paketo-buildpacks/go/sbom"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testSPDX(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		documents [][]byte
+		created   time.Time
+	)
+
+	it.Before(func() {
+		documents = nil
+		for _, name := range []string{"go-build.spdx.json", "go-mod-vendor.spdx.json"} {
+			content, err := os.ReadFile(filepath.Join("testdata", name))
+			Expect(err).NotTo(HaveOccurred())
+			documents = append(documents, content)
+		}
+
+		created = time.Date(1980, time.January, 1, 0, 0, 1, 0, time.UTC)
+	})
+
+	it("merges the documents into one with de-duplicated packages", func() {
+		content, err := sbom.MergeSPDX("some-image", created, documents...)
+		Expect(err).NotTo(HaveOccurred())
+
+		var document sbom.SPDXDocument
+		Expect(json.Unmarshal(content, &document)).To(Succeed())
+
+		Expect(document.SPDXID).To(Equal("SPDXRef-DOCUMENT"))
+		Expect(document.Name).To(Equal("some-image"))
+		Expect(document.DocumentNamespace).To(HavePrefix("https://paketo.io/spdx/some-image-"))
+		Expect(document.CreationInfo.Created).To(Equal("1980-01-01T00:00:01Z"))
+
+		var packages []string
+		for _, pkg := range document.Packages {
+			packages = append(packages, pkg["SPDXID"].(string)+" "+pkg["name"].(string)+"@"+pkg["versionInfo"].(string))
+		}
+		Expect(packages).To(Equal([]string{
+			"SPDXRef-Package-stdlib stdlib@1.22.0",
+			"SPDXRef-Package-stdlib-1 stdlib@1.21.0",
+			"SPDXRef-Package-toml github.com/BurntSushi/toml@v1.2.0",
+		}))
+		Expect(document.Files).To(HaveLen(1))
+
+		Expect(document.DocumentDescribes).To(Equal([]string{"SPDXRef-File-binary", "SPDXRef-Package-toml"}))
+		Expect(document.Relationships).To(Equal([]sbom.SPDXRelationship{
+			{SPDXElementID: "SPDXRef-DOCUMENT", RelatedSPDXElement: "SPDXRef-File-binary", RelationshipType: "DESCRIBES"},
+			{SPDXElementID: "SPDXRef-DOCUMENT", RelatedSPDXElement: "SPDXRef-Package-toml", RelationshipType: "DESCRIBES"},
+			{SPDXElementID: "SPDXRef-File-binary", RelatedSPDXElement: "SPDXRef-Package-stdlib", RelationshipType: "CONTAINS"},
+			{SPDXElementID: "SPDXRef-File-binary", RelatedSPDXElement: "SPDXRef-Package-toml", RelationshipType: "CONTAINS"},
+			{SPDXElementID: "SPDXRef-Package-toml", RelatedSPDXElement: "SPDXRef-Package-stdlib-1", RelationshipType: "DEPENDS_ON"},
+		}))
+	})
+
+	it("is deterministic", func() {
+		first, err := sbom.MergeSPDX("some-image", created, documents...)
+		Expect(err).NotTo(HaveOccurred())
+
+		second, err := sbom.MergeSPDX("some-image", created, documents...)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(first).To(Equal(second))
+	})
+
+	context("failure cases", func() {
+		context("when a document is malformed", func() {
+			it("returns an error", func() {
+				_, err := sbom.MergeSPDX("some-image", created, []byte("%%%"))
+				Expect(err).To(MatchError(ContainSubstring("failed to parse SPDX document 0")))
+			})
+		})
+
+		context("when a document is not SPDX", func() {
+			it("returns an error", func() {
+				_, err := sbom.MergeSPDX("some-image", created, []byte(`{"bomFormat": "CycloneDX"}`))
+				Expect(err).To(MatchError("failed to parse SPDX document 0: missing spdxVersion"))
+			})
+		})
+	})
+}
@@ -15,8 +15,11 @@ source "${ROOT_DIR}/scripts/.util/tools.sh"
 source "${ROOT_DIR}/scripts/.util/print.sh"
 
 function main {
-  local version output token flags
+  local version output token flags layout registry lockfile
   token=""
+  layout=""
+  registry=""
+  lockfile="${ROOT_DIR}/package.lock.toml"
 
   while [[ "${#}" != 0 ]]; do
     case "${1}" in
@@ -40,6 +43,21 @@ function main {
         shift 2
         ;;
 
+      --components-layout)
+        layout="${2}"
+        shift 2
+        ;;
+
+      --components-registry)
+        registry="${2}"
+        shift 2
+        ;;
+
+      --lockfile)
+        lockfile="${2}"
+        shift 2
+        ;;
+
       --help|-h)
         shift 1
         usage
@@ -66,13 +84,43 @@ function main {
     output="${BUILD_DIR}/buildpackage.cnb"
   fi
 
+  if [[ -n "${layout}" && -n "${registry}" ]]; then
+    usage
+    echo
+    util::print::error "--components-layout and --components-registry are mutually exclusive"
+  fi
+
+  # buildpackage::create runs from a temporary directory, so resolve relative
+  # paths up front.
+  if [[ -n "${layout}" ]]; then
+    layout="$(cd "${layout}" && pwd)"
+  fi
+  lockfile="$(cd "$(dirname "${lockfile}")" && pwd)/$(basename "${lockfile}")"
+
+  # until a lockfile is committed, the components are packaged by tag, which
+  # offline packaging cannot verify
+  if [[ ! -f "${lockfile}" ]]; then
+    if [[ -n "${layout}" || -n "${registry}" ]]; then
+      util::print::error "${lockfile} does not exist, offline packaging verifies the component buildpackages against it, run \`go run ./cmd/components lock\` to record their digests"
+    fi
+
+    util::print::info "WARNING: ${lockfile} does not exist, the component buildpackages are packaged by tag and go-otel cannot pull its instrumentation image, run \`go run ./cmd/components lock\` to pin them to their digests"
+    lockfile=""
+  fi
+
   repo::prepare
 
   tools::install "${token}"
 
   buildpack::archive "${version}"
-  buildpack::release::archive
-  buildpackage::create "${output}" "${flags[@]}"
+  components::archive "${lockfile}"
+  buildpack::release::archive "${lockfile}" "${layout}${registry}"
+
+  if [[ -n "${layout}" || -n "${registry}" ]]; then
+    flags+=("--pull-policy" "never")
+  fi
+
+  buildpackage::create "${output}" "${layout}" "${registry}" "${flags[@]}"
 }
 
 function usage() {
@@ -86,6 +134,9 @@ OPTIONS
   --version <version>  -v <version>  specifies the version number to use when packaging the buildpack
   --output <output>    -o <output>   location to output the packaged buildpackage artifact (default: ${ROOT_DIR}/build/buildpackage.cnb)
   --token <token>                    Token used to download assets from GitHub (e.g. jam, pack, etc) (optional)
+  --components-layout <dir>          package offline, reading the component buildpackages from an OCI layout directory (optional)
+  --components-registry <host>       package offline, reading the component buildpackages from a local registry (e.g. localhost:5000) (optional)
+  --lockfile <path>                  lockfile the component digests are verified against (default: ${ROOT_DIR}/package.lock.toml)
 USAGE
 }
 
@@ -115,6 +166,11 @@ function tools::install() {
   util::tools::yj::install \
     --directory "${BIN_DIR}" \
     --token "${token}"
+
+  util::print::title "Building components tool..."
+  pushd "${ROOT_DIR}" > /dev/null
+    go build -o "${BIN_DIR}/components" ./cmd/components
+  popd > /dev/null
 }
 
 function buildpack::archive() {
@@ -130,8 +186,102 @@ function buildpack::archive() {
     --output "${BUILD_DIR}/buildpack.tgz"
 }
 
+# Compiles the component buildpacks kept in this repository for every target
+# in package.toml and archives each one into build/components/<name>.tgz,
+# which package.toml references. pack does not select a target for file
+# dependencies, so bin/detect and bin/build run the binary for the
+# architecture they find themselves on. The lockfile is packaged with every
+# component, so that a component that pulls an image during the build pulls
+# it by its locked digest.
+function components::archive() {
+  local lockfile dir name tmp_dir arch tool
+  lockfile="${1}"
+
+  util::print::title "Packaging component buildpacks into ${BUILD_DIR}/components..."
+
+  mkdir -p "${BUILD_DIR}/components"
+
+  for dir in "${ROOT_DIR}"/buildpacks/*/; do
+    name="$(basename "${dir}")"
+    util::print::info "Packaging ${name}..."
+
+    tmp_dir=$(mktemp -d -p $BUILD_DIR)
+    cp "${dir}/buildpack.toml" "${tmp_dir}/buildpack.toml"
+    if [[ -n "${lockfile}" ]]; then
+      cp "${lockfile}" "${tmp_dir}/package.lock.toml"
+    fi
+    mkdir -p "${tmp_dir}/bin"
+
+    for arch in $(yj -tj < "${ROOT_DIR}/package.toml" | jq -r '.targets[].arch'); do
+      pushd "${ROOT_DIR}" > /dev/null
+        GOOS=linux GOARCH="${arch}" CGO_ENABLED=0 go build \
+          -ldflags="-s -w" \
+          -trimpath \
+          -o "${tmp_dir}/bin/linux-${arch}/run" \
+          "./buildpacks/${name}/run"
+      popd > /dev/null
+
+      ln -s run "${tmp_dir}/bin/linux-${arch}/detect"
+      ln -s run "${tmp_dir}/bin/linux-${arch}/build"
+
+      # a component that runs tools during the build or ships launch
+      # helpers lists them in a tools file, as packages of the tool
+      # directives in go.mod or of this repository
+      if [[ -f "${dir}/tools" ]]; then
+        while read -r tool; do
+          if [[ -z "${tool}" || "${tool}" == \#* ]]; then
+            continue
+          fi
+
+          pushd "${ROOT_DIR}" > /dev/null
+            GOOS=linux GOARCH="${arch}" CGO_ENABLED=0 go build \
+              -ldflags="-s -w" \
+              -trimpath \
+              -o "${tmp_dir}/bin/linux-${arch}/tools/" \
+              "${tool}"
+          popd > /dev/null
+        done < "${dir}/tools"
+      fi
+    done
+
+    components::shim > "${tmp_dir}/bin/detect"
+    cp "${tmp_dir}/bin/detect" "${tmp_dir}/bin/build"
+    chmod 0755 "${tmp_dir}/bin/detect" "${tmp_dir}/bin/build"
+
+    tar -czf "${BUILD_DIR}/components/${name}.tgz" -C "${tmp_dir}" .
+    rm -rf "${tmp_dir}"
+  done
+}
+
+function components::shim() {
+  cat <<'SHIM_EOF'
+#!/bin/sh
+
+set -eu
+
+case "$(uname -m)" in
+  x86_64)
+    arch="amd64"
+    ;;
+
+  aarch64|arm64)
+    arch="arm64"
+    ;;
+
+  *)
+    echo "unsupported architecture $(uname -m)" >&2
+    exit 1
+    ;;
+esac
+
+exec "$(dirname "${0}")/linux-${arch}/$(basename "${0}")" "${@}"
+SHIM_EOF
+}
+
 function buildpack::release::archive() {
-  local tmp_dir
+  local lockfile offline tmp_dir
+  lockfile="${1}"
+  offline="${2}"
 
   util::print::title "Packaging buildpack into ${BUILD_DIR}/buildpack-release-artifact.tgz..."
 
@@ -148,6 +298,8 @@ It contains the following files:
 * `package.toml` - this is needed because it contains the dependencies (and URIs) that let pack know where to find the buildpacks referenced in `buildpack.toml`.
   * `package.toml` can contain targets (platforms) for multi-arch support
 * `build/buildpack.tgz` - this is added because it is referenced in `package.toml` by some buildpacks
+* `build/components/*.tgz` - the component buildpacks that are kept in the same repository as the composite, referenced in `package.toml` by file
+* `package.lock.toml` - this records the OCI digest of every dependency in `package.toml`, and of every image the component buildpacks pull during the build, so the component buildpacks that were tested can be told apart from a tag that was pushed again later. The `docker://` dependencies of `package.toml` reference those digests, so packaging and publishing this artifact ships exactly the locked component buildpacks
 
 ## package locally
 
@@ -173,7 +325,21 @@ README_EOF
 
   mkdir -p $tmp_dir/build
   cp ${BUILD_DIR}/buildpack.tgz $tmp_dir/build
+  cp -R ${BUILD_DIR}/components $tmp_dir/build
   cp ${ROOT_DIR}/package.toml $tmp_dir/
+  if [[ -n "${lockfile}" ]]; then
+    cp "${lockfile}" $tmp_dir/package.lock.toml
+
+    # the package.toml of the artifact is what publish.sh packages, so it
+    # references the locked digests instead of tags. Offline packaging
+    # verifies the digests while vendoring instead, so it keeps the tags.
+    pushd $tmp_dir > /dev/null
+      components::check
+      if [[ -z "${offline}" ]]; then
+        components::pin
+      fi
+    popd > /dev/null
+  fi
   # add the buildpack.toml from the tgz file because it has the version populated
   tar -xzf ${BUILD_DIR}/buildpack.tgz -C $tmp_dir/ buildpack.toml
 
@@ -182,9 +348,11 @@ README_EOF
 }
 
 function buildpackage::create() {
-  local output flags release_archive_path tmp_dir
+  local output layout registry flags release_archive_path tmp_dir
   output="${1}"
-  flags=("${@:2}")
+  layout="${2}"
+  registry="${3}"
+  flags=("${@:4}")
   release_archive_path="${BUILD_DIR}/buildpack-release-artifact.tgz"
 
   util::print::title "Packaging buildpack..."
@@ -206,6 +374,10 @@ function buildpackage::create() {
   # Use the local architecture to support running locally and in CI, which will be linux/amd64 by default.
   arch=$(util::tools::arch)
 
+  if [[ -n "${layout}" || -n "${registry}" ]]; then
+    components::vendor "${layout}" "${registry}" "${arch}"
+  fi
+
   # If package.toml has no targets we must specify one on the command line, otherwise pack will complain.
   # This is here for backward compatibility but eventually all package.toml files should have targets defined.
   if cat package.toml | yj -tj | jq -r .targets | grep -q null; then
@@ -226,4 +398,49 @@ function buildpackage::create() {
   rm -rf $tmp_dir
 }
 
+# Replaces the docker:// dependencies in the extracted package.toml with
+# buildpackages read from a local OCI layout or registry, after verifying their
+# digests against the lockfile, so that pack does not need network access.
+function components::vendor() {
+  local layout registry arch source
+  layout="${1}"
+  registry="${2}"
+  arch="${3}"
+
+  if [[ -n "${layout}" ]]; then
+    source=("--layout" "${layout}")
+    util::print::info "Vendoring component buildpackages from OCI layout ${layout}..."
+  else
+    source=("--registry" "${registry}")
+    util::print::info "Vendoring component buildpackages from registry ${registry}..."
+  fi
+
+  components vendor \
+    --package package.toml \
+    --target "linux/${arch}" \
+    "${source[@]}"
+}
+
+# Verifies that the lockfile records exactly the docker:// dependencies of the
+# package.toml of the release artifact and the images of the component
+# buildpacks, so that a package.toml or buildpack.toml changed without locking
+# it again fails before anything is packaged.
+function components::check() {
+  util::print::info "Checking package.lock.toml against package.toml and the component images..."
+
+  components check \
+    --package package.toml \
+    --buildpacks "${ROOT_DIR}/buildpacks"
+}
+
+# Verifies that every docker:// dependency in the package.toml of the release
+# artifact still resolves to the digest recorded in the lockfile, and pins it
+# to that digest so that a tag pushed again later cannot change what is
+# packaged or published.
+function components::pin() {
+  util::print::info "Verifying component buildpackages against package.lock.toml..."
+
+  components pin --package package.toml
+}
+
 main "${@:-}"
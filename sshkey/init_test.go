@@ -0,0 +1,14 @@
+package sshkey_test
+
+import (
+	"testing"
+
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+)
+
+func TestUnitSSHKey(t *testing.T) {
+	suite := spec.New("sshkey", spec.Report(report.Terminal{}), spec.Parallel())
+	suite("SSHKey", testSSHKey)
+	suite.Run(t)
+}
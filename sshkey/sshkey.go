@@ -0,0 +1,270 @@
+// Package sshkey makes the SSH key of an ssh-key service binding available to
+// git, so that go can fetch modules that are only reachable over SSH. The key
+// is installed in a directory that is neither cached nor exported, such as a
+// build-only layer.
+package sshkey
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/paketo-buildpacks/packit/v2/servicebindings"
+	"golang.org/x/crypto/ssh"
+)
+
+const (
+	// BindingType is the type of the service bindings that hold an SSH key.
+	BindingType = "ssh-key"
+
+	// PrivateKeyEntry holds the private key, in PEM or OpenSSH format. The name
+	// matches the key of Kubernetes ssh-auth secrets.
+	PrivateKeyEntry = "ssh-privatekey"
+
+	// KnownHostsEntry holds the known_hosts lines of the hosts the key is used
+	// for. Host keys are always checked.
+	KnownHostsEntry = "known_hosts"
+
+	// URLRewritesEntry optionally holds one rewrite per line, as
+	// <https prefix>=<ssh prefix>. Without it, https://<host>/ is rewritten to
+	// ssh://git@<host>/ for every host in known_hosts.
+	URLRewritesEntry = "url-rewrites"
+)
+
+// Rewrite makes git fetch URLs that start with From from To instead.
+type Rewrite struct {
+	From string
+	To   string
+}
+
+// Find returns the ssh-key binding of a build whose platform directory is
+// platformDir. The bindings are resolved by the packit servicebindings
+// resolver, from SERVICE_BINDING_ROOT, CNB_BINDINGS, VCAP_SERVICES or the
+// bindings directory of the platform.
+func Find(platformDir string) (servicebindings.Binding, bool, error) {
+	bindings, err := servicebindings.NewResolver().Resolve(BindingType, "", platformDir)
+	if err != nil {
+		return servicebindings.Binding{}, false, err
+	}
+
+	if len(bindings) == 0 {
+		return servicebindings.Binding{}, false, nil
+	}
+
+	binding := bindings[0]
+	for _, required := range []string{PrivateKeyEntry, KnownHostsEntry} {
+		if _, ok := binding.Entries[required]; !ok {
+			return servicebindings.Binding{}, false, fmt.Errorf("binding %s is missing its %s entry", binding.Name, required)
+		}
+	}
+
+	return binding, true, nil
+}
+
+// Installation is an SSH key installed for git.
+type Installation struct {
+	// Dir holds the key and known_hosts. It is removed by Remove.
+	Dir string
+
+	KeyPath        string
+	KnownHostsPath string
+	Rewrites       []Rewrite
+}
+
+// Install copies the key and known_hosts of a binding to a new directory in
+// tmpDir, which must be neither cached nor exported.
+func Install(binding servicebindings.Binding, tmpDir string) (Installation, error) {
+	key, err := readEntry(binding, PrivateKeyEntry)
+	if err != nil {
+		return Installation{}, err
+	}
+
+	_, err = ssh.ParsePrivateKey(key)
+	if err != nil {
+		var passphraseErr *ssh.PassphraseMissingError
+		if errors.As(err, &passphraseErr) {
+			return Installation{}, fmt.Errorf("binding %s holds a passphrase protected key, which is not supported", binding.Name)
+		}
+		return Installation{}, fmt.Errorf("binding %s does not hold a valid SSH private key: %w", binding.Name, err)
+	}
+
+	// ssh rejects keys without a trailing newline, which secrets often lack
+	if !strings.HasSuffix(string(key), "\n") {
+		key = append(key, '\n')
+	}
+
+	knownHosts, err := readEntry(binding, KnownHostsEntry)
+	if err != nil {
+		return Installation{}, err
+	}
+
+	rewrites, err := readRewrites(binding, knownHosts)
+	if err != nil {
+		return Installation{}, err
+	}
+
+	dir, err := os.MkdirTemp(tmpDir, "ssh-key")
+	if err != nil {
+		return Installation{}, err
+	}
+
+	installation := Installation{
+		Dir:            dir,
+		KeyPath:        filepath.Join(dir, "id"),
+		KnownHostsPath: filepath.Join(dir, "known_hosts"),
+		Rewrites:       rewrites,
+	}
+
+	err = os.WriteFile(installation.KeyPath, key, 0600)
+	if err != nil {
+		_ = os.RemoveAll(dir)
+		return Installation{}, err
+	}
+
+	err = os.WriteFile(installation.KnownHostsPath, knownHosts, 0600)
+	if err != nil {
+		_ = os.RemoveAll(dir)
+		return Installation{}, err
+	}
+
+	return installation, nil
+}
+
+// Environ adds the variables that make git use the key and the rewrites to an
+// environment. Git config entries already given through GIT_CONFIG_COUNT are
+// kept.
+func (i Installation) Environ(environ []string) ([]string, error) {
+	var result []string
+	count := 0
+	for _, variable := range environ {
+		name, value, _ := strings.Cut(variable, "=")
+		switch name {
+		case "GIT_SSH_COMMAND", "GIT_SSH":
+			continue
+		case "GIT_CONFIG_COUNT":
+			var err error
+			count, err = strconv.Atoi(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid GIT_CONFIG_COUNT %q: %w", value, err)
+			}
+			continue
+		}
+
+		result = append(result, variable)
+	}
+
+	result = append(result, fmt.Sprintf("GIT_SSH_COMMAND=ssh -F /dev/null -i '%s' -o IdentitiesOnly=yes -o UserKnownHostsFile='%s' -o StrictHostKeyChecking=yes", i.KeyPath, i.KnownHostsPath))
+
+	for _, rewrite := range i.Rewrites {
+		result = append(result,
+			fmt.Sprintf("GIT_CONFIG_KEY_%d=url.%s.insteadOf", count, rewrite.To),
+			fmt.Sprintf("GIT_CONFIG_VALUE_%d=%s", count, rewrite.From),
+		)
+		count++
+	}
+
+	return append(result, fmt.Sprintf("GIT_CONFIG_COUNT=%d", count)), nil
+}
+
+// Remove deletes the installed key.
+func (i Installation) Remove() error {
+	return os.RemoveAll(i.Dir)
+}
+
+// readEntry reads an entry of a binding, which is an error when the binding
+// does not have it.
+func readEntry(binding servicebindings.Binding, name string) ([]byte, error) {
+	entry, ok := binding.Entries[name]
+	if !ok {
+		return nil, fmt.Errorf("binding %s is missing its %s entry", binding.Name, name)
+	}
+
+	return entry.ReadBytes()
+}
+
+func readRewrites(binding servicebindings.Binding, knownHosts []byte) ([]Rewrite, error) {
+	var rewrites []Rewrite
+	if _, ok := binding.Entries[URLRewritesEntry]; ok {
+		content, err := readEntry(binding, URLRewritesEntry)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, line := range strings.Split(string(content), "\n") {
+			line = strings.TrimSpace(line)
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+
+			from, to, ok := strings.Cut(line, "=")
+			if !ok || from == "" || to == "" {
+				return nil, fmt.Errorf("binding %s has an invalid %s line %q, expected <https prefix>=<ssh prefix>", binding.Name, URLRewritesEntry, line)
+			}
+
+			rewrites = append(rewrites, Rewrite{From: strings.TrimSpace(from), To: strings.TrimSpace(to)})
+		}
+
+		return rewrites, nil
+	}
+
+	hosts, err := knownHostNames(knownHosts)
+	if err != nil {
+		return nil, fmt.Errorf("binding %s has an invalid %s entry: %w", binding.Name, KnownHostsEntry, err)
+	}
+
+	for _, host := range hosts {
+		rewrites = append(rewrites, Rewrite{
+			From: fmt.Sprintf("https://%s/", host),
+			To:   fmt.Sprintf("ssh://git@%s/", host),
+		})
+	}
+
+	return rewrites, nil
+}
+
+// knownHostNames lists the hosts of known_hosts lines that are neither hashed
+// nor on a port other than 22.
+func knownHostNames(content []byte) ([]string, error) {
+	names := map[string]bool{}
+	for len(content) > 0 {
+		_, hosts, _, _, rest, err := ssh.ParseKnownHosts(content)
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, err
+		}
+
+		for _, host := range hosts {
+			if strings.HasPrefix(host, "|") || strings.ContainsAny(host, "*?!") {
+				continue
+			}
+
+			if strings.HasPrefix(host, "[") {
+				h, port, err := net.SplitHostPort(host)
+				if err != nil || port != "22" {
+					continue
+				}
+				host = h
+			}
+
+			names[host] = true
+		}
+
+		content = rest
+	}
+
+	var sorted []string
+	for name := range names {
+		sorted = append(sorted, name)
+	}
+	sort.Strings(sorted)
+
+	return sorted, nil
+}
@@ -0,0 +1,208 @@
+package sshkey_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/paketo-buildpacks/go/sshkey"
+	"github.com/paketo-buildpacks/packit/v2/servicebindings"
+	"github.com/sclevine/spec"
+	"golang.org/x/crypto/ssh"
+
+	. "github.com/onsi/gomega"
+)
+
+func testSSHKey(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		platformDir string
+		root        string
+		key         []byte
+		knownHosts  []byte
+	)
+
+	writeBinding := func(name string, entries map[string][]byte) {
+		Expect(os.MkdirAll(filepath.Join(root, name), os.ModePerm)).To(Succeed())
+		for entry, content := range entries {
+			Expect(os.WriteFile(filepath.Join(root, name, entry), content, 0600)).To(Succeed())
+		}
+	}
+
+	find := func() servicebindings.Binding {
+		binding, ok, err := sshkey.Find(platformDir)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ok).To(BeTrue())
+		return binding
+	}
+
+	it.Before(func() {
+		platformDir = t.TempDir()
+		root = filepath.Join(platformDir, "bindings")
+
+		public, private, err := ed25519.GenerateKey(rand.Reader)
+		Expect(err).NotTo(HaveOccurred())
+
+		block, err := ssh.MarshalPrivateKey(private, "")
+		Expect(err).NotTo(HaveOccurred())
+		key = pem.EncodeToMemory(block)
+
+		hostKey, err := ssh.NewPublicKey(public)
+		Expect(err).NotTo(HaveOccurred())
+		knownHosts = []byte("github.com,[git.example.com]:2222,[gitlab.example.com]:22 " + string(ssh.MarshalAuthorizedKey(hostKey)))
+	})
+
+	context("Find", func() {
+		it("finds the ssh-key binding", func() {
+			writeBinding("other", map[string][]byte{"type": []byte("git-credentials")})
+			writeBinding("ssh", map[string][]byte{
+				"type":                 []byte("ssh-key\n"),
+				sshkey.PrivateKeyEntry: key,
+				sshkey.KnownHostsEntry: knownHosts,
+			})
+
+			binding, ok, err := sshkey.Find(platformDir)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ok).To(BeTrue())
+			Expect(binding.Name).To(Equal("ssh"))
+			Expect(binding.Path).To(Equal(filepath.Join(root, "ssh")))
+			Expect(binding.Entries).To(HaveKey(sshkey.PrivateKeyEntry))
+		})
+
+		it("finds nothing when the binding root does not exist", func() {
+			_, ok, err := sshkey.Find(platformDir)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ok).To(BeFalse())
+		})
+
+		it("returns an error when the binding has no known_hosts", func() {
+			writeBinding("ssh", map[string][]byte{"type": []byte("ssh-key"), sshkey.PrivateKeyEntry: key})
+
+			_, _, err := sshkey.Find(platformDir)
+			Expect(err).To(MatchError("binding ssh is missing its known_hosts entry"))
+		})
+	})
+
+	context("Install", func() {
+		var tmpDir string
+
+		it.Before(func() {
+			tmpDir = t.TempDir()
+		})
+
+		it("installs the key and rewrites the known hosts on port 22", func() {
+			writeBinding("ssh", map[string][]byte{
+				"type":                 []byte("ssh-key"),
+				sshkey.PrivateKeyEntry: []byte(strings.TrimSuffix(string(key), "\n")),
+				sshkey.KnownHostsEntry: knownHosts,
+			})
+
+			installation, err := sshkey.Install(find(), tmpDir)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(filepath.Dir(installation.Dir)).To(Equal(tmpDir))
+
+			info, err := os.Stat(installation.KeyPath)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(info.Mode().Perm()).To(Equal(os.FileMode(0600)))
+			Expect(os.ReadFile(installation.KeyPath)).To(Equal(key))
+			Expect(os.ReadFile(installation.KnownHostsPath)).To(Equal(knownHosts))
+
+			Expect(installation.Rewrites).To(Equal([]sshkey.Rewrite{
+				{From: "https://github.com/", To: "ssh://git@github.com/"},
+				{From: "https://gitlab.example.com/", To: "ssh://git@gitlab.example.com/"},
+			}))
+
+			Expect(installation.Remove()).To(Succeed())
+			Expect(installation.Dir).NotTo(BeAnExistingFile())
+		})
+
+		it("uses the rewrites of the binding", func() {
+			writeBinding("ssh", map[string][]byte{
+				"type":                  []byte("ssh-key"),
+				sshkey.PrivateKeyEntry:  key,
+				sshkey.KnownHostsEntry:  knownHosts,
+				sshkey.URLRewritesEntry: []byte("# internal modules\nhttps://git.example.com/=ssh://git@git.example.com:2222/\n"),
+			})
+
+			installation, err := sshkey.Install(find(), tmpDir)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(installation.Rewrites).To(Equal([]sshkey.Rewrite{
+				{From: "https://git.example.com/", To: "ssh://git@git.example.com:2222/"},
+			}))
+		})
+
+		it("returns an error for an invalid rewrite", func() {
+			writeBinding("ssh", map[string][]byte{
+				"type":                  []byte("ssh-key"),
+				sshkey.PrivateKeyEntry:  key,
+				sshkey.KnownHostsEntry:  knownHosts,
+				sshkey.URLRewritesEntry: []byte("https://git.example.com/\n"),
+			})
+
+			_, err := sshkey.Install(find(), tmpDir)
+			Expect(err).To(MatchError(`binding ssh has an invalid url-rewrites line "https://git.example.com/", expected <https prefix>=<ssh prefix>`))
+		})
+
+		it("returns an error for a passphrase protected key", func() {
+			_, private, err := ed25519.GenerateKey(rand.Reader)
+			Expect(err).NotTo(HaveOccurred())
+
+			block, err := ssh.MarshalPrivateKeyWithPassphrase(private, "", []byte("secret"))
+			Expect(err).NotTo(HaveOccurred())
+
+			writeBinding("ssh", map[string][]byte{
+				"type":                 []byte("ssh-key"),
+				sshkey.PrivateKeyEntry: pem.EncodeToMemory(block),
+				sshkey.KnownHostsEntry: knownHosts,
+			})
+
+			_, err = sshkey.Install(find(), tmpDir)
+			Expect(err).To(MatchError("binding ssh holds a passphrase protected key, which is not supported"))
+		})
+
+		it("returns an error for an invalid key and installs nothing", func() {
+			writeBinding("ssh", map[string][]byte{
+				"type":                 []byte("ssh-key"),
+				sshkey.PrivateKeyEntry: []byte("not a key"),
+				sshkey.KnownHostsEntry: knownHosts,
+			})
+
+			_, err := sshkey.Install(find(), tmpDir)
+			Expect(err).To(MatchError(ContainSubstring("binding ssh does not hold a valid SSH private key")))
+			Expect(os.ReadDir(tmpDir)).To(BeEmpty())
+		})
+	})
+
+	context("Environ", func() {
+		it("configures ssh and appends the rewrites to the existing git config", func() {
+			installation := sshkey.Installation{
+				KeyPath:        "/tmp/ssh-key/id",
+				KnownHostsPath: "/tmp/ssh-key/known_hosts",
+				Rewrites:       []sshkey.Rewrite{{From: "https://github.com/", To: "ssh://git@github.com/"}},
+			}
+
+			environ, err := installation.Environ([]string{
+				"PATH=/usr/bin",
+				"GIT_SSH_COMMAND=ssh -v",
+				"GIT_CONFIG_COUNT=1",
+				"GIT_CONFIG_KEY_0=credential.helper",
+				"GIT_CONFIG_VALUE_0=store",
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(environ).To(Equal([]string{
+				"PATH=/usr/bin",
+				"GIT_CONFIG_KEY_0=credential.helper",
+				"GIT_CONFIG_VALUE_0=store",
+				"GIT_SSH_COMMAND=ssh -F /dev/null -i '/tmp/ssh-key/id' -o IdentitiesOnly=yes -o UserKnownHostsFile='/tmp/ssh-key/known_hosts' -o StrictHostKeyChecking=yes",
+				"GIT_CONFIG_KEY_1=url.ssh://git@github.com/.insteadOf",
+				"GIT_CONFIG_VALUE_1=https://github.com/",
+				"GIT_CONFIG_COUNT=2",
+			}))
+		})
+	})
+}